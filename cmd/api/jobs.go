@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/usecase"
+)
+
+// job is background work the scheduler runs at startup and then every interval
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// runScheduler starts every job in its own goroutine. Jobs stop when ctx is
+// done. Runs of the same job never overlap.
+func runScheduler(ctx context.Context, jobs []job) {
+	for _, j := range jobs {
+		go func(j job) {
+			ticker := time.NewTicker(j.interval)
+			defer ticker.Stop()
+
+			for {
+				if err := j.run(ctx); err != nil {
+					log.Printf("job %s failed: %v", j.name, err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(j)
+		log.Printf("✓ Scheduled job %s every %s", j.name, j.interval)
+	}
+}
+
+// purgeTrashJob hard-deletes expenses that have been in the trash longer than
+// retention, then the attachments they leave behind
+func purgeTrashJob(expenseUC usecase.ExpenseUseCase, attachmentUC usecase.AttachmentUseCase, retention, interval time.Duration) job {
+	return job{
+		name:     "purge-trash",
+		interval: interval,
+		run: func(ctx context.Context) error {
+			purged, err := expenseUC.PurgeDeletedExpenses(ctx, retention)
+			if purged > 0 {
+				log.Printf("✓ Purged %d expenses deleted more than %s ago", purged, retention)
+			}
+			if err != nil {
+				return err
+			}
+
+			removed, err := attachmentUC.PurgeOrphanedAttachments(ctx)
+			if removed > 0 {
+				log.Printf("✓ Removed %d attachments of purged expenses", removed)
+			}
+			return err
+		},
+	}
+}
+
+// recurringExpensesJob creates the expenses for due recurring expense occurrences
+func recurringExpensesJob(recurringUC usecase.RecurringExpenseUseCase, interval time.Duration) job {
+	return job{
+		name:     "recurring-expenses",
+		interval: interval,
+		run: func(ctx context.Context) error {
+			created, err := recurringUC.MaterializeDue(ctx, time.Now())
+			if created > 0 {
+				log.Printf("✓ Created %d recurring expenses", created)
+			}
+			return err
+		},
+	}
+}
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/pavanrkadave/homies/config"
 	_ "github.com/pavanrkadave/homies/docs/swagger"
+	"github.com/pavanrkadave/homies/internal/domain"
 	"github.com/pavanrkadave/homies/internal/handler"
 	"github.com/pavanrkadave/homies/internal/middleware"
+	"github.com/pavanrkadave/homies/internal/notify"
+	"github.com/pavanrkadave/homies/internal/repository"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
 	"github.com/pavanrkadave/homies/internal/repository/postgres"
+	"github.com/pavanrkadave/homies/internal/storage"
 	"github.com/pavanrkadave/homies/internal/usecase"
 	"github.com/pavanrkadave/homies/pkg/database"
 	"github.com/pavanrkadave/homies/pkg/response"
@@ -32,11 +39,23 @@ import (
 
 // @schemes http https
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 Session token from /auth/login, sent as "Bearer <token>"
+
 func main() {
 
 	// Load Config
 	cfg := config.Load()
 
+	// Dates outside a household are read in the configured zone
+	loc, err := domain.LoadTimezone(cfg.Server.Timezone)
+	if err != nil {
+		log.Fatal("invalid TIMEZONE: ", err)
+	}
+	time.Local = loc
+
 	// Connect to the database
 	db, err := database.NewPostgresDB(cfg)
 	if err != nil {
@@ -53,14 +72,98 @@ func main() {
 	// Init Repositories
 	userRepo := postgres.NewUserPostgresRepository(db)
 	expenseRepo := postgres.NewExpensePostgresRepository(db)
+	expenseEventRepo := postgres.NewExpenseEventPostgresRepository(db)
+	groupRepo := postgres.NewGroupPostgresRepository(db)
+	categoryRepo := postgres.NewCategoryPostgresRepository(db)
+	budgetRepo := postgres.NewBudgetPostgresRepository(db)
+	rateRepo := postgres.NewExchangeRatePostgresRepository(db)
+	paymentRepo := postgres.NewPaymentPostgresRepository(db)
+	authRepo := postgres.NewAuthPostgresRepository(db)
+	recurringRepo := postgres.NewRecurringExpensePostgresRepository(db)
+	attachmentRepo := postgres.NewAttachmentPostgresRepository(db)
+
+	if cfg.Balances.Snapshots {
+		if err := expenseRepo.UseBalanceSnapshots(context.Background()); err != nil {
+			log.Fatal("failed to rebuild balance snapshots: ", err)
+		}
+		log.Println("✓ Reading balances from user_balances")
+	}
+
+	baseCurrency, err := domain.ParseCurrency(cfg.Currency.Base)
+	if err != nil {
+		log.Fatal("invalid BASE_CURRENCY: ", err)
+	}
+
+	// Rates come from the database unless a static rates file is configured
+	var rateProvider repository.ExchangeRateProvider = rateRepo
+	if cfg.Currency.RatesFile != "" {
+		rateProvider, err = memory.NewExchangeRateFileProvider(cfg.Currency.RatesFile)
+		if err != nil {
+			log.Fatal("failed to load exchange rates file: ", err)
+		}
+		log.Printf("✓ Loaded exchange rates from %s", cfg.Currency.RatesFile)
+	}
+
+	// Attachment contents live on local disk unless S3 storage is configured
+	var blobs repository.BlobStore
+	switch cfg.Storage.Backend {
+	case "s3":
+		blobs = storage.NewS3(storage.S3Config{
+			Endpoint:        cfg.Storage.S3Endpoint,
+			Region:          cfg.Storage.S3Region,
+			Bucket:          cfg.Storage.S3Bucket,
+			AccessKeyID:     cfg.Storage.S3AccessKeyID,
+			SecretAccessKey: cfg.Storage.S3SecretAccessKey,
+		}, &http.Client{Timeout: time.Minute})
+		log.Printf("✓ Storing attachments in bucket %s at %s", cfg.Storage.S3Bucket, cfg.Storage.S3Endpoint)
+	case "local":
+		if blobs, err = storage.NewFileSystem(cfg.Storage.LocalDir); err != nil {
+			log.Fatal("failed to open attachment storage: ", err)
+		}
+		log.Printf("✓ Storing attachments in %s", cfg.Storage.LocalDir)
+	default:
+		log.Fatalf("invalid STORAGE_BACKEND %q: must be local or s3", cfg.Storage.Backend)
+	}
 
 	// Init UseCase
-	userUC := usecase.NewUserUseCase(userRepo)
-	expenseUC := usecase.NewExpenseUseCase(expenseRepo, userRepo)
+	userUC := usecase.NewUserUseCase(userRepo, expenseRepo, paymentRepo, groupRepo, authRepo, notify.LogGuestClaimSender{BaseURL: cfg.Auth.ClaimURL}, cfg.Auth.MagicLinkTTL)
+	expenseUC := usecase.NewExpenseUseCase(expenseRepo, expenseEventRepo, userRepo, groupRepo, categoryRepo, paymentRepo, rateProvider, baseCurrency)
+	groupUC := usecase.NewGroupUseCase(groupRepo, userRepo, baseCurrency)
+	categoryUC := usecase.NewCategoryUseCase(categoryRepo, baseCurrency)
+	rateUC := usecase.NewExchangeRateUseCase(rateRepo)
+	paymentUC := usecase.NewPaymentUseCase(paymentRepo, userRepo, groupRepo, baseCurrency)
+	authUC := usecase.NewAuthUseCase(authRepo, userRepo, notify.LogMagicLinkSender{BaseURL: cfg.Auth.MagicLinkURL}, cfg.Auth.SessionTTL, cfg.Auth.MagicLinkTTL)
+	budgetUC := usecase.NewBudgetUseCase(budgetRepo, expenseRepo, categoryRepo, userRepo, groupRepo, rateProvider, notify.LogBudgetAlertNotifier{}, cfg.Budgets.AlertThresholds, baseCurrency)
+
+	// Expenses created through the API or from a recurring template are
+	// checked against the budgets
+	expenseUC = usecase.WithBudgetAlerts(expenseUC, budgetUC)
+
+	recurringUC := usecase.NewRecurringExpenseUseCase(recurringRepo, expenseUC, userRepo, groupRepo, categoryRepo, baseCurrency)
+	attachmentUC := usecase.NewAttachmentUseCase(attachmentRepo, expenseRepo, blobs)
+	exportUC := usecase.NewExportUseCase(expenseUC, userUC)
+
+	// Background jobs
+	jobs := []job{recurringExpensesJob(recurringUC, cfg.Recurring.Interval)}
+	if cfg.Trash.RetentionDays > 0 {
+		retention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+		jobs = append(jobs, purgeTrashJob(expenseUC, attachmentUC, retention, cfg.Trash.PurgeInterval))
+	}
+	runScheduler(context.Background(), jobs)
 
 	// Init Handlers
 	userHandler := handler.NewUserHandler(userUC)
 	expenseHandler := handler.NewExpenseHandler(expenseUC)
+	groupHandler := handler.NewGroupHandler(groupUC)
+	categoryHandler := handler.NewCategoryHandler(categoryUC)
+	budgetHandler := handler.NewBudgetHandler(budgetUC)
+	rateHandler := handler.NewExchangeRateHandler(rateUC)
+	paymentHandler := handler.NewPaymentHandler(paymentUC)
+	authHandler := handler.NewAuthHandler(authUC, userUC)
+	recurringHandler := handler.NewRecurringExpenseHandler(recurringUC)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentUC, cfg.Upload.MaxBytes, cfg.Upload.AllowedTypes)
+	exportHandler := handler.NewExportHandler(exportUC)
+	importHandler := handler.NewImportHandler(expenseUC, cfg.Upload.ImportMaxBytes)
 	healthHandler := handler.NewHealthHandler(db)
 
 	mux := http.NewServeMux()
@@ -68,6 +171,15 @@ func main() {
 	// Healthcheck
 	mux.HandleFunc("/health", healthHandler.Health)
 
+	// Auth Routes
+	mux.HandleFunc("/auth/register", authHandler.Register)
+	mux.HandleFunc("/auth/login", authHandler.Login)
+	mux.HandleFunc("/auth/magic-link", authHandler.RequestMagicLink)
+	mux.HandleFunc("/auth/magic-link/verify", authHandler.VerifyMagicLink)
+	mux.HandleFunc("/auth/logout", authHandler.Logout)
+	mux.HandleFunc("/auth/me", authHandler.Me)
+	mux.HandleFunc("/auth/password", authHandler.ChangePassword)
+
 	// API Routes
 	mux.HandleFunc("/users", func(writer http.ResponseWriter, request *http.Request) {
 		switch request.Method {
@@ -81,6 +193,8 @@ func main() {
 			userHandler.CreateUser(writer, request)
 		case http.MethodPut:
 			userHandler.UpdateUser(writer, request)
+		case http.MethodDelete:
+			userHandler.DeleteUser(writer, request)
 		default:
 			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
 		}
@@ -121,6 +235,29 @@ func main() {
 		}
 	})
 
+	mux.HandleFunc("/expenses/history", expenseHandler.GetExpenseHistory)
+	mux.HandleFunc("/expenses/tags", expenseHandler.SetExpenseTags)
+	mux.HandleFunc("/expenses/trash", expenseHandler.GetDeletedExpenses)
+	mux.HandleFunc("/expenses/restore", expenseHandler.RestoreExpense)
+	mux.HandleFunc("/expenses/import", importHandler.ImportExpenses)
+
+	mux.HandleFunc("/expenses/attachments", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			if request.URL.Query().Get("id") != "" {
+				attachmentHandler.DownloadAttachment(writer, request)
+			} else {
+				attachmentHandler.GetAttachments(writer, request)
+			}
+		case http.MethodPost:
+			attachmentHandler.UploadAttachment(writer, request)
+		case http.MethodDelete:
+			attachmentHandler.DeleteAttachment(writer, request)
+		default:
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
 	mux.HandleFunc("/balances", func(writer http.ResponseWriter, request *http.Request) {
 		switch request.Method {
 		case http.MethodGet:
@@ -130,6 +267,21 @@ func main() {
 		}
 	})
 
+	mux.HandleFunc("/balances/pairwise", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			expenseHandler.GetPairwiseBalances(writer, request)
+		} else {
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/users/deactivate", userHandler.DeactivateUser)
+	mux.HandleFunc("/users/reactivate", userHandler.ReactivateUser)
+	mux.HandleFunc("/users/merge", userHandler.MergeUsers)
+	mux.HandleFunc("/users/guests", userHandler.CreateGuest)
+	mux.HandleFunc("/users/claim", userHandler.ClaimGuest)
+	mux.HandleFunc("/users/claim/confirm", userHandler.ConfirmGuestClaim)
+
 	mux.HandleFunc("/users/stats", func(writer http.ResponseWriter, request *http.Request) {
 		if request.Method == http.MethodGet {
 			expenseHandler.GetUserStats(writer, request)
@@ -146,10 +298,132 @@ func main() {
 		}
 	})
 
+	mux.HandleFunc("/categories", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			categoryHandler.GetCategories(writer, request)
+		case http.MethodPost:
+			categoryHandler.CreateCategory(writer, request)
+		case http.MethodPut:
+			categoryHandler.UpdateCategory(writer, request)
+		case http.MethodDelete:
+			categoryHandler.DeleteCategory(writer, request)
+		default:
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/budgets", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			budgetHandler.GetBudgets(writer, request)
+		case http.MethodPost:
+			budgetHandler.CreateBudget(writer, request)
+		case http.MethodPut:
+			budgetHandler.UpdateBudget(writer, request)
+		case http.MethodDelete:
+			budgetHandler.DeleteBudget(writer, request)
+		default:
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/budgets/status", budgetHandler.GetBudgetStatus)
+	mux.HandleFunc("/budgets/alerts", budgetHandler.GetBudgetAlerts)
+
+	mux.HandleFunc("/groups", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			if request.URL.Query().Get("id") != "" {
+				groupHandler.GetGroupByID(writer, request)
+			} else {
+				groupHandler.GetAllGroups(writer, request)
+			}
+		case http.MethodPost:
+			groupHandler.CreateGroup(writer, request)
+		default:
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/groups/members", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodPost:
+			groupHandler.AddMember(writer, request)
+		case http.MethodDelete:
+			groupHandler.RemoveMember(writer, request)
+		default:
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/payments", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			if request.URL.Query().Get("id") != "" {
+				paymentHandler.GetPaymentByID(writer, request)
+			} else {
+				paymentHandler.GetAllPayments(writer, request)
+			}
+		case http.MethodPost:
+			paymentHandler.CreatePayment(writer, request)
+		case http.MethodPut:
+			paymentHandler.UpdatePayment(writer, request)
+		case http.MethodDelete:
+			paymentHandler.DeletePayment(writer, request)
+		default:
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/recurring-expenses", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			if request.URL.Query().Get("id") != "" {
+				recurringHandler.GetRecurringExpenseByID(writer, request)
+			} else {
+				recurringHandler.GetAllRecurringExpenses(writer, request)
+			}
+		case http.MethodPost:
+			recurringHandler.CreateRecurringExpense(writer, request)
+		case http.MethodDelete:
+			recurringHandler.DeleteRecurringExpense(writer, request)
+		default:
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/exchange-rates", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			rateHandler.GetExchangeRates(writer, request)
+		case http.MethodPost:
+			rateHandler.AddExchangeRate(writer, request)
+		default:
+			response.RespondWithError(writer, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/export", exportHandler.Export)
+
 	// Swagger UI
 	mux.HandleFunc("/swagger/", httpSwagger.WrapHandler)
 
-	middlewareHandler := middleware.Recovery(middleware.Logger(middleware.CORS(mux)))
+	publicPaths := []string{
+		"/health",
+		"/swagger/",
+		"/auth/register",
+		"/auth/login",
+		"/auth/magic-link",
+		"/auth/magic-link/verify",
+		"/users/claim/confirm",
+	}
+	authMiddleware := middleware.Auth(authUC, publicPaths...)
+	if cfg.Auth.Mode == "header" {
+		log.Printf("✓ Trusting %s header for the acting user", middleware.UserIDHeader)
+		authMiddleware = middleware.UserHeader(userUC, publicPaths...)
+	}
+	middlewareHandler := middleware.Recovery(middleware.Logger(middleware.CORS(authMiddleware(mux))))
 
 	log.Printf("✓ Server starting on :%s with middleware enabled", cfg.Server.Port)
 	log.Printf("✓ Swagger UI available at http://localhost:%s/swagger/", cfg.Server.Port)
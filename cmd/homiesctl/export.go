@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/pavanrkadave/homies/internal/export"
+	"github.com/pavanrkadave/homies/internal/usecase"
+)
+
+// runExport writes an export to a file or stdout, e.g.
+//
+//	homiesctl export -format xlsx -dataset all -o homies.xlsx
+func runExport(a *app, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := flags.String("format", "csv", "csv, ndjson or xlsx")
+	datasetName := flags.String("dataset", "expenses", "expenses, users, balances, settlements or all")
+	output := flags.String("o", "", "file to write (default stdout)")
+	var filter usecase.ExportFilter
+	flags.StringVar(&filter.GroupID, "group", "", "only this group's expenses and balances")
+	flags.StringVar(&filter.Category, "category", "", "only expenses in this category")
+	flags.StringVar(&filter.StartDate, "start", "", "only expenses on or after this date (YYYY-MM-DD)")
+	flags.StringVar(&filter.EndDate, "end", "", "only expenses on or before this date (YYYY-MM-DD)")
+	_ = flags.Parse(args)
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	dataset, err := usecase.ParseExportDataset(*datasetName)
+	if err != nil {
+		return err
+	}
+	if dataset == usecase.ExportAll && !format.MultipleTables() {
+		return errors.New("-dataset all needs -format ndjson or xlsx")
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	writer, err := export.NewWriter(format, out)
+	if err != nil {
+		return err
+	}
+	if err := usecase.NewExportUseCase(a.expenseUC, a.userUC).Export(context.Background(), writer, dataset, filter); err != nil {
+		return err
+	}
+	return writer.Close()
+}
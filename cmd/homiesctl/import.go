@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/importer"
+)
+
+// runImport imports expenses from a Splitwise export or bank statement and
+// prints a report, e.g.
+//
+//	homiesctl import -format splitwise -people people.json -dry-run splitwise.csv
+func runImport(a *app, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := flags.String("format", "splitwise", "splitwise or bank")
+	peopleFile := flags.String("people", "", "JSON file mapping Splitwise names to emails")
+	mappingFile := flags.String("mapping", "", "JSON file describing the bank statement's columns")
+	groupID := flags.String("group", "", "group to import the expenses into")
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without creating anything")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("usage: homiesctl import [flags] <file.csv>")
+	}
+
+	format, err := importer.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	people := make(map[string]string)
+	if *peopleFile != "" {
+		data, err := os.ReadFile(*peopleFile)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &people); err != nil {
+			return fmt.Errorf("%s: %w", *peopleFile, err)
+		}
+	}
+
+	var mapping *importer.BankMapping
+	if *mappingFile != "" {
+		file, err := os.Open(*mappingFile)
+		if err != nil {
+			return err
+		}
+		mapping, err = importer.ParseBankMapping(file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	batch, err := importer.Parse(format, file, people, mapping)
+	if err != nil {
+		return err
+	}
+	report, err := a.expenseUC.ImportExpenses(context.Background(), *groupID, batch, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	printImportReport(report)
+	if !report.Committed && !report.DryRun {
+		return fmt.Errorf("nothing imported: %d lines failed", len(report.Failed))
+	}
+	return nil
+}
+
+func printImportReport(report *domain.ImportReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, imported := range report.Created {
+		expense := imported.Expense
+		fmt.Fprintf(w, "line %d\tcreate\t%s\t%s\t%s %s\n", imported.Line, expense.Date.Format("2006-01-02"), expense.Description, expense.Amount, expense.Amount.Currency)
+	}
+	for _, problem := range report.Skipped {
+		fmt.Fprintf(w, "line %d\tskip\t%s\n", problem.Line, problem.Reason)
+	}
+	for _, problem := range report.Failed {
+		fmt.Fprintf(w, "line %d\tfail\t%s\n", problem.Line, problem.Reason)
+	}
+	w.Flush()
+
+	verb := "created"
+	if !report.Committed {
+		verb = "would be created"
+	}
+	fmt.Printf("\n%d expenses %s, %d lines skipped, %d failed\n", len(report.Created), verb, len(report.Skipped), len(report.Failed))
+}
@@ -0,0 +1,110 @@
+// Command homiesctl runs administrative tasks against the homies database.
+// It acts with no signed-in user, so it sees every expense.
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pavanrkadave/homies/config"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/notify"
+	"github.com/pavanrkadave/homies/internal/repository"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+	"github.com/pavanrkadave/homies/internal/repository/postgres"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/database"
+)
+
+const usage = `usage: homiesctl <command> [flags]
+
+commands:
+  export    write expenses, users or balances as CSV, NDJSON or XLSX
+  import    create expenses from a Splitwise export or bank statement CSV
+  users     deactivate, reactivate, delete or merge user accounts
+
+Run "homiesctl <command> -h" for a command's flags.`
+
+// app holds the use cases commands run against
+type app struct {
+	db        *sql.DB
+	expenseUC usecase.ExpenseUseCase
+	userUC    usecase.UserUseCase
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var run func(a *app, args []string) error
+	switch os.Args[1] {
+	case "export":
+		run = runExport
+	case "import":
+		run = runImport
+	case "users":
+		run = runUsers
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	a, err := newApp(config.Load())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer a.db.Close()
+
+	if err := run(a, os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newApp(cfg *config.Config) (*app, error) {
+	loc, err := domain.LoadTimezone(cfg.Server.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIMEZONE: %w", err)
+	}
+	time.Local = loc
+
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	baseCurrency, err := domain.ParseCurrency(cfg.Currency.Base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BASE_CURRENCY: %w", err)
+	}
+
+	userRepo := postgres.NewUserPostgresRepository(db)
+	expenseRepo := postgres.NewExpensePostgresRepository(db)
+	paymentRepo := postgres.NewPaymentPostgresRepository(db)
+	groupRepo := postgres.NewGroupPostgresRepository(db)
+	var rateProvider repository.ExchangeRateProvider = postgres.NewExchangeRatePostgresRepository(db)
+	if cfg.Currency.RatesFile != "" {
+		if rateProvider, err = memory.NewExchangeRateFileProvider(cfg.Currency.RatesFile); err != nil {
+			return nil, fmt.Errorf("failed to load exchange rates file: %w", err)
+		}
+	}
+
+	return &app{
+		db: db,
+		expenseUC: usecase.NewExpenseUseCase(
+			expenseRepo,
+			postgres.NewExpenseEventPostgresRepository(db),
+			userRepo,
+			groupRepo,
+			postgres.NewCategoryPostgresRepository(db),
+			paymentRepo,
+			rateProvider,
+			baseCurrency,
+		),
+		userUC: usecase.NewUserUseCase(userRepo, expenseRepo, paymentRepo, groupRepo, postgres.NewAuthPostgresRepository(db), notify.LogGuestClaimSender{BaseURL: cfg.Auth.ClaimURL}, cfg.Auth.MagicLinkTTL),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+const usersUsage = `usage: homiesctl users <deactivate|reactivate|delete> <user id>
+       homiesctl users merge -from <duplicate id> -into <kept id>
+       homiesctl users claim <guest id> <email>`
+
+// runUsers manages accounts that their owners can't, e.g. folding a duplicate
+// created by an import into the real account:
+//
+//	homiesctl users merge -from 4f1c... -into 9a2e...
+func runUsers(a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New(usersUsage)
+	}
+	ctx := context.Background()
+
+	if args[0] == "merge" {
+		flags := flag.NewFlagSet("users merge", flag.ExitOnError)
+		fromID := flags.String("from", "", "duplicate account to merge away")
+		intoID := flags.String("into", "", "account to keep")
+		_ = flags.Parse(args[1:])
+
+		user, err := a.userUC.MergeUsers(ctx, *fromID, *intoID, "")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("merged %s into %s (%s)\n", *fromID, user.ID, user.Email)
+		return nil
+	}
+
+	if args[0] == "claim" {
+		if len(args) != 3 {
+			return errors.New(usersUsage)
+		}
+		user, err := a.userUC.ClaimGuest(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("claimed %s as %s (%s)\n", args[1], user.ID, user.Email)
+		return nil
+	}
+
+	if len(args) != 2 {
+		return errors.New(usersUsage)
+	}
+	id := args[1]
+	switch args[0] {
+	case "deactivate":
+		if _, err := a.userUC.DeactivateUser(ctx, id); err != nil {
+			return err
+		}
+	case "reactivate":
+		if _, err := a.userUC.ReactivateUser(ctx, id); err != nil {
+			return err
+		}
+	case "delete":
+		if err := a.userUC.DeleteUser(ctx, id); err != nil {
+			return err
+		}
+	default:
+		return errors.New(usersUsage)
+	}
+	fmt.Printf("%sd %s\n", args[0], id)
+	return nil
+}
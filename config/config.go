@@ -4,19 +4,30 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logger   LoggerConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Logger    LoggerConfig
+	Currency  CurrencyConfig
+	Auth      AuthConfig
+	Trash     TrashConfig
+	Recurring RecurringConfig
+	Storage   StorageConfig
+	Upload    UploadConfig
+	Balances  BalancesConfig
+	Budgets   BudgetsConfig
 }
 
 type ServerConfig struct {
-	Port string
-	Env  string
+	Port     string
+	Env      string
+	Timezone string // IANA zone for expense dates outside a household, e.g. Asia/Kolkata
 }
 
 type DatabaseConfig struct {
@@ -28,6 +39,52 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+type CurrencyConfig struct {
+	Base      string // ISO 4217 code balances are reported in when no group applies
+	RatesFile string // optional JSON file of exchange rates, used instead of the database
+}
+
+type AuthConfig struct {
+	Mode         string        // token (bearer sessions) or header (trusted X-User-ID)
+	SessionTTL   time.Duration // how long a bearer token stays valid
+	MagicLinkTTL time.Duration // how long an emailed login link stays valid
+	MagicLinkURL string        // page that receives ?token= from a magic link
+	ClaimURL     string        // page that receives ?token= to confirm a guest claim
+}
+
+type TrashConfig struct {
+	RetentionDays int           // days a deleted expense stays restorable; 0 keeps them forever
+	PurgeInterval time.Duration // how often the purge job runs
+}
+
+type RecurringConfig struct {
+	Interval time.Duration // how often due recurring expenses are created
+}
+
+type StorageConfig struct {
+	Backend           string // local or s3
+	LocalDir          string // directory attachments are kept in by the local backend
+	S3Endpoint        string // S3-compatible endpoint, e.g. http://localhost:9000 for MinIO
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+}
+
+type UploadConfig struct {
+	MaxBytes       int64    // largest attachment accepted
+	AllowedTypes   []string // content types accepted, as detected from the file
+	ImportMaxBytes int64    // largest CSV accepted by the expense importer
+}
+
+type BalancesConfig struct {
+	Snapshots bool // read balances from the user_balances table instead of summing expenses
+}
+
+type BudgetsConfig struct {
+	AlertThresholds []int // percentages of a budget that raise an alert when an expense passes them
+}
+
 type LoggerConfig struct {
 	Level string // debug, info, warn, error, fatal
 	Mode  string // development or production
@@ -40,8 +97,9 @@ func Load() *Config {
 
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "3000"),
-			Env:  getEnv("APP_ENV", "development"),
+			Port:     getEnv("SERVER_PORT", "3000"),
+			Env:      getEnv("APP_ENV", "development"),
+			Timezone: getEnv("TIMEZONE", "UTC"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -55,6 +113,44 @@ func Load() *Config {
 			Level: getEnv("LOG_LEVEL", "info"),
 			Mode:  getEnv("LOG_MODE", "development"),
 		},
+		Currency: CurrencyConfig{
+			Base:      getEnv("BASE_CURRENCY", "INR"),
+			RatesFile: getEnv("EXCHANGE_RATES_FILE", ""),
+		},
+		Auth: AuthConfig{
+			Mode:         getEnv("AUTH_MODE", "token"),
+			SessionTTL:   GetEnvAsDuration("AUTH_SESSION_TTL", 30*24*time.Hour),
+			MagicLinkTTL: GetEnvAsDuration("AUTH_MAGIC_LINK_TTL", 15*time.Minute),
+			MagicLinkURL: getEnv("AUTH_MAGIC_LINK_URL", "http://localhost:3000/auth/magic-link/verify"),
+			ClaimURL:     getEnv("AUTH_CLAIM_URL", "http://localhost:3000/users/claim/confirm"),
+		},
+		Trash: TrashConfig{
+			RetentionDays: GetEnvAsInt("TRASH_RETENTION_DAYS", 30),
+			PurgeInterval: GetEnvAsDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		},
+		Recurring: RecurringConfig{
+			Interval: GetEnvAsDuration("RECURRING_EXPENSE_INTERVAL", 15*time.Minute),
+		},
+		Storage: StorageConfig{
+			Backend:           getEnv("STORAGE_BACKEND", "local"),
+			LocalDir:          getEnv("STORAGE_LOCAL_DIR", "./data/attachments"),
+			S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+			S3Region:          getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:          getEnv("S3_BUCKET", ""),
+			S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+		},
+		Upload: UploadConfig{
+			MaxBytes:       int64(GetEnvAsInt("ATTACHMENT_MAX_BYTES", 10<<20)),
+			AllowedTypes:   strings.Split(getEnv("ATTACHMENT_ALLOWED_TYPES", "image/jpeg,image/png,image/webp,image/gif,application/pdf"), ","),
+			ImportMaxBytes: int64(GetEnvAsInt("IMPORT_MAX_BYTES", 10<<20)),
+		},
+		Balances: BalancesConfig{
+			Snapshots: GetEnvAsBool("BALANCE_SNAPSHOTS", false),
+		},
+		Budgets: BudgetsConfig{
+			AlertThresholds: GetEnvAsInts("BUDGET_ALERT_THRESHOLDS", []int{80, 100}),
+		},
 	}
 }
 
@@ -72,3 +168,37 @@ func GetEnvAsInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+// GetEnvAsInts reads a comma-separated list of integers, falling back if any
+// of them doesn't parse
+func GetEnvAsInts(key string, fallback []int) []int {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return fallback
+	}
+	var values []int
+	for _, part := range strings.Split(valueStr, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return fallback
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func GetEnvAsBool(key string, fallback bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func GetEnvAsDuration(key string, fallback time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if value, err := time.ParseDuration(valueStr); err == nil {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// Attachment is a file, such as a receipt photo, kept as proof of an
+// expense. The content lives in blob storage under StorageKey.
+type Attachment struct {
+	ID          string    `json:"id"`
+	ExpenseID   string    `json:"expense_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	UploadedBy  string    `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (a *Attachment) Validate() error {
+	if a.ExpenseID == "" {
+		return errors.New("expense_id is required")
+	}
+	if a.FileName == "" {
+		return errors.New("file name is required")
+	}
+	if a.Size <= 0 {
+		return errors.New("attachment must not be empty")
+	}
+	if a.StorageKey == "" {
+		return errors.New("storage key is required")
+	}
+	return nil
+}
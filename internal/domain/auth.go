@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrUnauthenticated    = errors.New("authentication required")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrPasswordNotSet     = errors.New("password not set")
+	ErrForbidden          = errors.New("you do not have permission to perform this action")
+)
+
+// MinPasswordLength is the shortest password accepted
+const MinPasswordLength = 8
+
+// Session is a bearer token issued at login. Only a hash of the token is
+// stored; Token itself is set once, when the session is created.
+type Session struct {
+	Token     string    `json:"token,omitempty"`
+	TokenHash string    `json:"-"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s *Session) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// MagicLink is a single-use login token emailed to a user
+type MagicLink struct {
+	TokenHash string
+	UserID    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// GuestClaim is a single-use token emailed to confirm that whoever owns Email
+// takes over the guest's history
+type GuestClaim struct {
+	TokenHash string
+	GuestID   string
+	Email     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return errors.New("password must be at least 8 characters")
+	}
+	return nil
+}
@@ -1,17 +1,68 @@
 package domain
 
+import "fmt"
+
 type Balance struct {
-	UserID string  `json:"user_id"`
-	Amount float64 `json:"amount"`
+	UserID string `json:"user_id"`
+	Amount Money  `json:"amount"`
 }
 
 type Settlement struct {
-	From   string  `json:"from"`
-	To     string  `json:"to"`
-	Amount float64 `json:"amount"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount Money  `json:"amount"`
 }
 
 type BalanceSummary struct {
-	Balances    []Balance    `json:"balances"`
-	Settlements []Settlement `json:"settlements"`
+	Currency    Currency           `json:"currency"`
+	Strategy    SettlementStrategy `json:"strategy"`
+	Balances    []Balance          `json:"balances"`
+	Settlements []Settlement       `json:"settlements"`
+}
+
+// SettlementStrategy selects how suggested settlements are planned
+type SettlementStrategy string
+
+const (
+	// SettlementGreedy repeatedly pairs the largest debtor with the largest creditor
+	SettlementGreedy SettlementStrategy = "greedy"
+	// SettlementMinimal minimises the number of transfers
+	SettlementMinimal SettlementStrategy = "minimal"
+	// SettlementSharedOnly only suggests transfers between users who already
+	// share an expense or payment, routing through intermediaries if needed
+	SettlementSharedOnly SettlementStrategy = "shared-only"
+)
+
+// ParseSettlementStrategy validates a strategy name; empty selects minimal
+func ParseSettlementStrategy(s string) (SettlementStrategy, error) {
+	switch strategy := SettlementStrategy(s); strategy {
+	case "":
+		return SettlementMinimal, nil
+	case SettlementGreedy, SettlementMinimal, SettlementSharedOnly:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("invalid settlement strategy %q: must be greedy, minimal or shared-only", s)
+	}
+}
+
+// PairwiseLedger breaks a user's balance down by counterparty
+type PairwiseLedger struct {
+	UserID         string            `json:"user_id"`
+	Currency       Currency          `json:"currency"`
+	Counterparties []PairwiseBalance `json:"counterparties"`
+}
+
+// PairwiseBalance is what one counterparty owes the user; negative means the
+// user owes them. Expenses and Payments list what contributed to it.
+type PairwiseBalance struct {
+	UserID   string        `json:"user_id"`
+	Amount   Money         `json:"amount"`
+	Expenses []LedgerEntry `json:"expenses"`
+	Payments []LedgerEntry `json:"payments"`
+}
+
+// LedgerEntry is one expense or payment's signed contribution to a pairwise balance
+type LedgerEntry struct {
+	ID     string `json:"id"`
+	Amount Money  `json:"amount"`
 }
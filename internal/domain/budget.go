@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBudgetNotFound = errors.New("budget not found")
+	ErrBudgetExists   = errors.New("a budget already covers that category, period and scope")
+)
+
+// BudgetPeriod is how often a budget starts over
+type BudgetPeriod string
+
+const (
+	BudgetMonthly BudgetPeriod = "monthly"
+	BudgetYearly  BudgetPeriod = "yearly"
+)
+
+// ParseBudgetPeriod parses a period case-insensitively; empty means monthly
+func ParseBudgetPeriod(s string) (BudgetPeriod, error) {
+	switch period := BudgetPeriod(strings.ToLower(strings.TrimSpace(s))); period {
+	case "":
+		return BudgetMonthly, nil
+	case BudgetMonthly, BudgetYearly:
+		return period, nil
+	}
+	return "", fmt.Errorf("invalid budget period %q: must be monthly or yearly", s)
+}
+
+// Range returns the first day of the period containing date and the first
+// day of the next one, at midnight in date's location
+func (p BudgetPeriod) Range(date time.Time) (start, end time.Time) {
+	if p == BudgetYearly {
+		start = time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location())
+		return start, start.AddDate(1, 0, 0)
+	}
+	start = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Budget caps spending on a category, subcategories included, in each
+// period. A budget with a UserID caps that user's share of the spending
+// rather than the household's; one with a GroupID only counts that group's
+// expenses.
+type Budget struct {
+	ID        string       `json:"id"`
+	Category  string       `json:"category"`
+	Period    BudgetPeriod `json:"period"`
+	Amount    Money        `json:"amount"`
+	UserID    string       `json:"user_id,omitempty"`
+	GroupID   string       `json:"group_id,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+func (b *Budget) Validate() error {
+	if b.Category == "" {
+		return errors.New("budget category is required")
+	}
+	if b.Period != BudgetMonthly && b.Period != BudgetYearly {
+		return fmt.Errorf("invalid budget period %q: must be monthly or yearly", b.Period)
+	}
+	if !b.Amount.IsPositive() {
+		return errors.New("budget amount must be greater than zero")
+	}
+	return b.Amount.Currency.Validate()
+}
+
+// Covers reports whether the budget applies to the same expenses as other
+func (b *Budget) Covers(other *Budget) bool {
+	return b.Category == other.Category && b.Period == other.Period && b.UserID == other.UserID && b.GroupID == other.GroupID
+}
+
+// CrossedThresholds returns the alert thresholds, as percentages of the
+// budget, that spending passed on its way from before to after
+func (b *Budget) CrossedThresholds(before, after Money, thresholds []int) []int {
+	var crossed []int
+	for _, threshold := range thresholds {
+		limit := b.Amount.Minor * int64(threshold)
+		if before.Minor*100 < limit && after.Minor*100 >= limit {
+			crossed = append(crossed, threshold)
+		}
+	}
+	return crossed
+}
+
+// BudgetStatus is how a budget stands in the period containing a month
+type BudgetStatus struct {
+	// BudgetID is empty for a category's own monthly budget
+	BudgetID string       `json:"budget_id,omitempty"`
+	Category string       `json:"category"`
+	Period   BudgetPeriod `json:"period"`
+	UserID   string       `json:"user_id,omitempty"`
+	GroupID  string       `json:"group_id,omitempty"`
+	Currency Currency     `json:"currency"`
+	Amount   Money        `json:"amount"`
+	// PeriodStart and PeriodEnd are the period's first and last days,
+	// formatted YYYY-MM-DD
+	PeriodStart  string `json:"period_start"`
+	PeriodEnd    string `json:"period_end"`
+	DaysElapsed  int    `json:"days_elapsed"`
+	DaysInPeriod int    `json:"days_in_period"`
+	Spent        Money  `json:"spent"`
+	// Remaining is negative once the budget is overspent
+	Remaining     Money `json:"remaining"`
+	PercentUsed   int   `json:"percent_used"`
+	AveragePerDay Money `json:"average_per_day"`
+	// Projected is Spent carried on at AveragePerDay to the end of the period
+	Projected     Money `json:"projected"`
+	OverBudget    bool  `json:"over_budget"`
+	ProjectedOver bool  `json:"projected_over_budget"`
+	// ByUser is each user's share of the spending against a household budget
+	ByUser map[string]Money `json:"by_user,omitempty"`
+}
+
+// BudgetReport is the status of every budget in one month
+type BudgetReport struct {
+	Year    int             `json:"year"`
+	Month   int             `json:"month"`
+	Budgets []*BudgetStatus `json:"budgets"`
+}
+
+// BudgetAlert records an expense taking spending past an alert threshold
+type BudgetAlert struct {
+	ID string `json:"id"`
+	// BudgetID is empty for a category's own monthly budget
+	BudgetID  string       `json:"budget_id,omitempty"`
+	Category  string       `json:"category"`
+	Period    BudgetPeriod `json:"period"`
+	UserID    string       `json:"user_id,omitempty"`
+	GroupID   string       `json:"group_id,omitempty"`
+	ExpenseID string       `json:"expense_id"`
+	// PeriodStart is the first day of the period, formatted YYYY-MM-DD
+	PeriodStart string    `json:"period_start"`
+	Threshold   int       `json:"threshold"`
+	Currency    Currency  `json:"currency"`
+	Amount      Money     `json:"amount"`
+	Spent       Money     `json:"spent"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProjectSpend works out the daily run rate of spending over the days
+// elapsed so far and carries it on to the end of the period
+func ProjectSpend(spent Money, daysElapsed, daysInPeriod int) (perDay, projected Money) {
+	if daysElapsed <= 0 {
+		return Money{Currency: spent.Currency}, spent
+	}
+	if daysElapsed >= daysInPeriod {
+		return spent.DivRound(int64(daysInPeriod)), spent
+	}
+	perDay = spent.DivRound(int64(daysElapsed))
+	projected = Money{Minor: spent.Minor * int64(daysInPeriod), Currency: spent.Currency}.DivRound(int64(daysElapsed))
+	return perDay, projected
+}
+
+// PercentOf returns part as a whole percentage of total, rounded down
+func PercentOf(part, total Money) int {
+	if total.Minor == 0 {
+		return 0
+	}
+	return int(part.Minor * 100 / total.Minor)
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseBudgetPeriod(t *testing.T) {
+	tests := map[string]BudgetPeriod{
+		"":         BudgetMonthly,
+		"Monthly":  BudgetMonthly,
+		" yearly ": BudgetYearly,
+	}
+	for s, want := range tests {
+		if got, err := ParseBudgetPeriod(s); err != nil || got != want {
+			t.Errorf("ParseBudgetPeriod(%q) = %q, %v, want %q", s, got, err, want)
+		}
+	}
+	if _, err := ParseBudgetPeriod("weekly"); err == nil {
+		t.Error("Expected an unsupported period to be refused")
+	}
+}
+
+func TestBudgetPeriod_Range(t *testing.T) {
+	date := time.Date(2024, time.February, 17, 21, 30, 0, 0, time.UTC)
+
+	start, end := BudgetMonthly.Range(date)
+	if start.Format("2006-01-02") != "2024-02-01" || end.Format("2006-01-02") != "2024-03-01" {
+		t.Errorf("Monthly range = %s to %s", start, end)
+	}
+	start, end = BudgetYearly.Range(date)
+	if start.Format("2006-01-02") != "2024-01-01" || end.Format("2006-01-02") != "2025-01-01" {
+		t.Errorf("Yearly range = %s to %s", start, end)
+	}
+}
+
+func TestBudget_CrossedThresholds(t *testing.T) {
+	budget := &Budget{Category: "groceries", Period: BudgetMonthly, Amount: NewMoney(800000, DefaultCurrency)}
+	thresholds := []int{80, 100}
+
+	tests := []struct {
+		name          string
+		before, after int64
+		want          []int
+	}{
+		{name: "below both", before: 0, after: 639999},
+		{name: "onto 80%", before: 600000, after: 640000, want: []int{80}},
+		{name: "past both", before: 100000, after: 900000, want: []int{80, 100}},
+		{name: "already past 80%", before: 700000, after: 750000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := NewMoney(tt.before, DefaultCurrency)
+			after := NewMoney(tt.after, DefaultCurrency)
+			if got := budget.CrossedThresholds(before, after, thresholds); !slices.Equal(got, tt.want) {
+				t.Errorf("CrossedThresholds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectSpend(t *testing.T) {
+	spent := NewMoney(300000, DefaultCurrency)
+
+	perDay, projected := ProjectSpend(spent, 10, 30)
+	if perDay.Minor != 30000 || projected.Minor != 900000 {
+		t.Errorf("ProjectSpend(10 of 30 days) = %s a day, %s projected", perDay, projected)
+	}
+	perDay, projected = ProjectSpend(spent, 30, 30)
+	if perDay.Minor != 10000 || projected != spent {
+		t.Errorf("ProjectSpend(a whole period) = %s a day, %s projected", perDay, projected)
+	}
+	if _, projected = ProjectSpend(Money{}, 0, 30); !projected.IsZero() {
+		t.Errorf("Expected nothing projected before the period starts, got %s", projected)
+	}
+}
+
+func TestBudget_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  Budget
+		wantErr bool
+	}{
+		{name: "valid", budget: Budget{Category: "groceries", Period: BudgetMonthly, Amount: NewMoney(800000, DefaultCurrency)}},
+		{name: "no category", budget: Budget{Period: BudgetMonthly, Amount: NewMoney(800000, DefaultCurrency)}, wantErr: true},
+		{name: "no period", budget: Budget{Category: "groceries", Amount: NewMoney(800000, DefaultCurrency)}, wantErr: true},
+		{name: "zero amount", budget: Budget{Category: "groceries", Period: BudgetYearly, Amount: NewMoney(0, DefaultCurrency)}, wantErr: true},
+		{name: "no currency", budget: Budget{Category: "groceries", Period: BudgetYearly, Amount: NewMoney(100, "")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.budget.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
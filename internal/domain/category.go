@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrCategoryInUse    = errors.New("category is used by expenses, recurring expenses or subcategories")
+)
+
+// DefaultCategory is where expenses without a category go
+const DefaultCategory = "other"
+
+// DefaultCategories are created with a new database, so expenses can be
+// recorded before anyone manages categories
+var DefaultCategories = []string{
+	"food", "groceries", "dining", "rent", "housing", "utilities", "transport",
+	"travel", "entertainment", "shopping", "health", DefaultCategory,
+}
+
+const (
+	maxCategoryName = 50
+	maxCategoryIcon = 32
+)
+
+var categoryColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Category is a managed expense category. Expenses refer to it by its name,
+// which is normalized, see NormalizeCategory.
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// ParentID groups a subcategory under a top-level category; subcategories
+	// can't have subcategories of their own
+	ParentID string `json:"parent_id,omitempty"`
+	// Icon is an emoji or icon name and Color a "#rrggbb" hex colour, both
+	// for display only
+	Icon  string `json:"icon,omitempty"`
+	Color string `json:"color,omitempty"`
+	// MonthlyBudget is what the household means to spend on the category in
+	// a month, if anything
+	MonthlyBudget *Money    `json:"monthly_budget,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (c *Category) Validate() error {
+	if c.Name == "" {
+		return errors.New("category name is required")
+	}
+	if utf8.RuneCountInString(c.Name) > maxCategoryName {
+		return fmt.Errorf("category name must be at most %d characters", maxCategoryName)
+	}
+	if c.ParentID != "" && c.ParentID == c.ID {
+		return errors.New("a category can't be its own parent")
+	}
+	if utf8.RuneCountInString(c.Icon) > maxCategoryIcon {
+		return fmt.Errorf("category icon must be at most %d characters", maxCategoryIcon)
+	}
+	if c.Color != "" && !categoryColor.MatchString(c.Color) {
+		return fmt.Errorf("invalid colour %q: must be #rrggbb", c.Color)
+	}
+	if c.MonthlyBudget != nil {
+		if !c.MonthlyBudget.IsPositive() {
+			return errors.New("monthly budget must be greater than zero")
+		}
+		return c.MonthlyBudget.Currency.Validate()
+	}
+	return nil
+}
+
+// NormalizeCategory lowercases a category name, trims it and collapses runs of
+// spaces, so "Dining  Out" and "dining out" are the same category
+func NormalizeCategory(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
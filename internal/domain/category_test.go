@@ -0,0 +1,40 @@
+package domain
+
+import "testing"
+
+func TestNormalizeCategory(t *testing.T) {
+	tests := map[string]string{
+		"Groceries":       "groceries",
+		"  Dining   Out ": "dining out",
+		"":                "",
+	}
+	for name, want := range tests {
+		if got := NormalizeCategory(name); got != want {
+			t.Errorf("NormalizeCategory(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCategory_Validate(t *testing.T) {
+	budget := NewMoney(800000, DefaultCurrency)
+	zero := NewMoney(0, DefaultCurrency)
+
+	tests := []struct {
+		name     string
+		category Category
+		wantErr  bool
+	}{
+		{name: "valid", category: Category{ID: "1", Name: "groceries", Icon: "🛒", Color: "#4caf50", MonthlyBudget: &budget}},
+		{name: "no name", category: Category{ID: "1"}, wantErr: true},
+		{name: "own parent", category: Category{ID: "1", Name: "food", ParentID: "1"}, wantErr: true},
+		{name: "bad colour", category: Category{ID: "1", Name: "food", Color: "green"}, wantErr: true},
+		{name: "zero budget", category: Category{ID: "1", Name: "food", MonthlyBudget: &zero}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.category.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
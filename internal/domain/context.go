@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type contextKey string
+
+const userIDKey contextKey = "user_id"
+
+// WithUserID returns a context carrying the acting user's ID
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFromContext returns the acting user's ID, if the request has one
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey).(string)
+	return userID, ok && userID != ""
+}
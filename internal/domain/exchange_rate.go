@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+// ExchangeRate is the number of To units one From unit buys from EffectiveDate
+// until a newer rate for the same pair takes over
+type ExchangeRate struct {
+	From          Currency  `json:"from"`
+	To            Currency  `json:"to"`
+	Rate          string    `json:"rate"`
+	EffectiveDate time.Time `json:"effective_date"`
+}
+
+func (r *ExchangeRate) Validate() error {
+	if err := r.From.Validate(); err != nil {
+		return err
+	}
+	if err := r.To.Validate(); err != nil {
+		return err
+	}
+	if r.From == r.To {
+		return errors.New("exchange rate currencies must differ")
+	}
+	if r.EffectiveDate.IsZero() {
+		return errors.New("exchange rate effective date is required")
+	}
+	if _, err := r.Ratio(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Ratio returns the rate as an exact rational number
+func (r *ExchangeRate) Ratio() (*big.Rat, error) {
+	ratio, ok := new(big.Rat).SetString(r.Rate)
+	if !ok || ratio.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q: must be a positive decimal", r.Rate)
+	}
+	return ratio, nil
+}
@@ -2,35 +2,83 @@ package domain
 
 import (
 	"errors"
-	"math"
+	"fmt"
 	"time"
 )
 
+var ErrExpenseNotFound = errors.New("expense not found")
+
+// MaxExpenseBackdate is how far in the past an expense may be dated
+const MaxExpenseBackdate = 10 * 365 * 24 * time.Hour
+
+// expenseDateSlack allows dates slightly ahead of the server's clock, such as
+// "today" in a time zone east of it
+const expenseDateSlack = 24 * time.Hour
+
+// ParseExpenseDate parses an expense date given as RFC 3339 with an offset
+// ("2024-03-15T19:30:00+05:30"), a local date-time ("2024-03-15T19:30:00") or
+// a date ("2024-03-15"). Forms without an offset are read in loc. The result
+// is in loc and must lie between MaxExpenseBackdate before now and a day
+// after it.
+func ParseExpenseDate(value string, loc *time.Location, now time.Time) (time.Time, error) {
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		date, err = time.ParseInLocation("2006-01-02T15:04:05", value, loc)
+	}
+	if err != nil {
+		date, err = time.ParseInLocation("2006-01-02", value, loc)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: expected RFC 3339 or YYYY-MM-DD", value)
+	}
+
+	if date.Before(now.Add(-MaxExpenseBackdate)) {
+		return time.Time{}, fmt.Errorf("expense date %s is more than %d years ago", value, MaxExpenseBackdate/(365*24*time.Hour))
+	}
+	if date.After(now.Add(expenseDateSlack)) {
+		return time.Time{}, fmt.Errorf("expense date %s is in the future", value)
+	}
+	return date.In(loc), nil
+}
+
 type Expense struct {
-	ID          string    `json:"id"`
-	Description string    `json:"description"`
-	Amount      float64   `json:"amount"`
-	Category    string    `json:"category"`
-	PaidBy      string    `json:"paid_by"`
-	Date        time.Time `json:"date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Splits      []Split   `json:"splits"`
+	ID          string     `json:"id"`
+	GroupID     string     `json:"group_id,omitempty"`
+	Description string     `json:"description"`
+	Amount      Money      `json:"amount"`
+	Category    string     `json:"category"`
+	PaidBy      string     `json:"paid_by"`
+	Date        time.Time  `json:"date"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Splits      []Split    `json:"splits"`
+	// Items is the itemised receipt the splits were derived from, if any
+	Items []ExpenseItem `json:"items,omitempty"`
+	// Tags are normalized labels, see NormalizeTags
+	Tags []string `json:"tags,omitempty"`
+	// RecurringID and OccurrenceDate, formatted YYYY-MM-DD, are set on an
+	// expense created for one occurrence of a recurring expense
+	RecurringID    string `json:"recurring_id,omitempty"`
+	OccurrenceDate string `json:"occurrence_date,omitempty"`
 }
 
 type Split struct {
-	ExpenseID string  `json:"expense_id"`
-	UserID    string  `json:"user_id"`
-	Amount    float64 `json:"amount"`
+	ExpenseID string `json:"expense_id"`
+	UserID    string `json:"user_id"`
+	Amount    Money  `json:"amount"`
 }
 
 func (e *Expense) Validate() error {
 	if e.Description == "" {
 		return errors.New("expense description is required")
 	}
-	if e.Amount <= 0 {
+	if !e.Amount.IsPositive() {
 		return errors.New("expense amount must be greater than zero")
 	}
+	if !e.Amount.Storable() {
+		return fmt.Errorf("expense amount: %w", ErrAmountOutOfRange)
+	}
 	if e.PaidBy == "" {
 		return errors.New("paidBy is required")
 	}
@@ -38,30 +86,45 @@ func (e *Expense) Validate() error {
 		return errors.New("at least one split is required")
 	}
 
-	var sum float64
+	sum := Money{Currency: e.Amount.Currency}
 	for _, split := range e.Splits {
-		sum += split.Amount
+		if !split.Amount.Storable() {
+			return fmt.Errorf("split for user %s: %w", split.UserID, ErrAmountOutOfRange)
+		}
+		var err error
+		if sum, err = sum.CheckedAdd(split.Amount); err != nil {
+			return fmt.Errorf("split for user %s: %w", split.UserID, err)
+		}
 	}
 
-	if math.Abs(sum-e.Amount) > 0.01 {
+	if sum.Cmp(e.Amount) != 0 {
 		return errors.New("sum of splits must equal the expense amount")
 	}
 
+	if len(e.Items) > 0 {
+		return validateItems(e.Items, e.Amount)
+	}
+
 	return nil
 }
 
-func (e *Expense) Update(description, category string, amount float64, splits []Split) error {
+func (e *Expense) Update(description, category string, amount Money, date time.Time, splits []Split) error {
 	if description != "" {
 		e.Description = description
 	}
 	if category != "" {
 		e.Category = category
 	}
-	if amount > 0 {
+	if amount.IsPositive() {
 		e.Amount = amount
 	}
+	if !date.IsZero() {
+		e.Date = date
+	}
 	if len(splits) > 0 {
+		// Explicit splits replace any receipt they were derived from
 		e.Splits = splits
+		e.Items = nil
 	}
 	e.UpdatedAt = time.Now()
 
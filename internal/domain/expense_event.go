@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ExpenseAction is the kind of change an ExpenseEvent records
+type ExpenseAction string
+
+const (
+	ExpenseCreated  ExpenseAction = "created"
+	ExpenseUpdated  ExpenseAction = "updated"
+	ExpenseDeleted  ExpenseAction = "deleted"
+	ExpenseRestored ExpenseAction = "restored"
+)
+
+// ExpenseEvent is one entry in an expense's append-only change history.
+// Before is nil for a creation or restore and After is nil for a deletion.
+type ExpenseEvent struct {
+	ID        string           `json:"id"`
+	ExpenseID string           `json:"expense_id"`
+	Action    ExpenseAction    `json:"action"`
+	ActorID   string           `json:"actor_id,omitempty"`
+	Before    *ExpenseSnapshot `json:"before,omitempty"`
+	After     *ExpenseSnapshot `json:"after,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// ExpenseSnapshot is a self-contained copy of an expense and its splits at
+// one point in time. Unlike Expense it carries its currency in JSON.
+type ExpenseSnapshot struct {
+	GroupID     string          `json:"group_id,omitempty"`
+	Description string          `json:"description"`
+	Amount      Money           `json:"amount"`
+	Currency    Currency        `json:"currency"`
+	Category    string          `json:"category"`
+	PaidBy      string          `json:"paid_by"`
+	Date        time.Time       `json:"date"`
+	Splits      []SplitSnapshot `json:"splits"`
+	Tags        []string        `json:"tags,omitempty"`
+}
+
+type SplitSnapshot struct {
+	UserID string `json:"user_id"`
+	Amount Money  `json:"amount"`
+}
+
+// SnapshotOf copies the current state of an expense
+func SnapshotOf(expense *Expense) *ExpenseSnapshot {
+	splits := make([]SplitSnapshot, len(expense.Splits))
+	for i, split := range expense.Splits {
+		splits[i] = SplitSnapshot{UserID: split.UserID, Amount: split.Amount}
+	}
+	return &ExpenseSnapshot{
+		GroupID:     expense.GroupID,
+		Description: expense.Description,
+		Amount:      expense.Amount,
+		Currency:    expense.Amount.Currency,
+		Category:    expense.Category,
+		PaidBy:      expense.PaidBy,
+		Date:        expense.Date,
+		Splits:      splits,
+		Tags:        expense.Tags,
+	}
+}
+
+// UnmarshalJSON restores the snapshot's currency onto every amount
+func (s *ExpenseSnapshot) UnmarshalJSON(data []byte) error {
+	type plain ExpenseSnapshot
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	s.Amount.Currency = s.Currency
+	for i := range s.Splits {
+		s.Splits[i].Amount.Currency = s.Currency
+	}
+	return nil
+}
+
+// Involves reports whether the user paid for or shared the snapshotted expense
+func (s *ExpenseSnapshot) Involves(userID string) bool {
+	if s.PaidBy == userID {
+		return true
+	}
+	for _, split := range s.Splits {
+		if split.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpenseSnapshot_JSONRoundTrip(t *testing.T) {
+	snapshot := SnapshotOf(&Expense{
+		Description: "Groceries",
+		Amount:      NewMoney(1050, "USD"),
+		Category:    "food",
+		PaidBy:      "alice",
+		Date:        time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Splits: []Split{
+			{UserID: "alice", Amount: NewMoney(525, "USD")},
+			{UserID: "bob", Amount: NewMoney(525, "USD")},
+		},
+	})
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	var decoded ExpenseSnapshot
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(*snapshot, decoded) {
+		t.Errorf("Expected %+v, got %+v", *snapshot, decoded)
+	}
+}
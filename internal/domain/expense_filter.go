@@ -0,0 +1,341 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxExpenseTags bounds how many tags one expense may carry
+const MaxExpenseTags = 20
+
+const maxTagLength = 32
+
+// ExpenseFilter narrows an expense list. Zero fields match everything; set
+// fields must all match.
+type ExpenseFilter struct {
+	GroupID string
+	// Categories matches any of the categories, ignoring case
+	Categories []string
+	// StartDate and EndDate are inclusive YYYY-MM-DD bounds; either may be
+	// left open
+	StartDate string
+	EndDate   string
+	PaidBy    string
+	// Participant matches expenses the user has a split in
+	Participant string
+	// UserID matches expenses the user paid for or has a split in
+	UserID string
+	// MinAmount and MaxAmount are inclusive bounds on the amount in the
+	// expense's own currency
+	MinAmount *Money
+	MaxAmount *Money
+	// Search is free text matched against the description: every word, or
+	// "quoted phrase", must appear; see MatchesSearch
+	Search string
+	// Tags matches expenses carrying all of the tags
+	Tags          []string
+	HasAttachment *bool
+	// VisibleTo limits the list to expenses the user paid for or shares in
+	VisibleTo string
+}
+
+const filterDateLayout = "2006-01-02"
+
+// Validate checks the filter's dates and amount range
+func (f *ExpenseFilter) Validate() error {
+	for _, date := range []string{f.StartDate, f.EndDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(filterDateLayout, date); err != nil {
+			return fmt.Errorf("invalid date %q: must be YYYY-MM-DD", date)
+		}
+	}
+	if f.StartDate != "" && f.EndDate != "" && f.StartDate > f.EndDate {
+		return errors.New("start date must not be after end date")
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && f.MinAmount.Minor > f.MaxAmount.Minor {
+		return errors.New("minimum amount must not be greater than maximum amount")
+	}
+	return nil
+}
+
+// MatchesSearch reports whether text matches Search the way postgres's
+// websearch_to_tsquery reads it with the simple configuration. Words match
+// whole words, ignoring case. Every word and "quoted phrase" must appear, a
+// phrase's words next to each other and in order, and none prefixed with -
+// may. "or" between terms matches either side, binding more loosely than the
+// implicit and.
+func (f *ExpenseFilter) MatchesSearch(text string) bool {
+	terms, _ := splitQuery(f.Search)
+	words := searchWords(text)
+
+	// A match is any run of terms between ors that all hold
+	matched, inRun, empty := true, false, true
+	for _, term := range terms {
+		if strings.EqualFold(term, "or") {
+			if inRun && matched {
+				return true
+			}
+			matched, inRun = true, false
+			continue
+		}
+
+		negated := strings.HasPrefix(term, "-")
+		phrase := searchWords(strings.TrimPrefix(term, "-"))
+		if len(phrase) == 0 {
+			continue
+		}
+		inRun, empty = true, false
+		if containsPhrase(words, phrase) == negated {
+			matched = false
+		}
+	}
+	return empty || inRun && matched
+}
+
+// searchWords lowercases text and splits it into words of letters and digits
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsPhrase reports whether phrase appears as a run of words
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseExpenseQuery parses the search syntax of GET /expenses into a filter.
+// A query is a space-separated list of terms:
+//
+//	category:food,groceries   any of the categories; repeatable
+//	paid_by:<user id>         paid for by the user
+//	participant:<user id>     shared by the user
+//	user:<user id>            paid for or shared by the user
+//	group:<group id>          in the group
+//	amount:10..50             amount between 10 and 50 inclusive; either end
+//	                          may be left out, and amount:>=10, amount:<50 and
+//	                          amount:25 also work
+//	date:2024-01-01..2024-01-31
+//	                          dated within the range, with the same forms as
+//	                          amount
+//	tag:trip                  carrying the tag; repeatable, all must match
+//	has:attachment            with at least one attachment; -has:attachment
+//	                          for none
+//
+// Any other word or "quoted phrase" is searched for in the description.
+func ParseExpenseQuery(q string) (ExpenseFilter, error) {
+	var filter ExpenseFilter
+	terms, err := splitQuery(q)
+	if err != nil {
+		return filter, err
+	}
+
+	var search []string
+	for _, term := range terms {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || strings.HasPrefix(term, `"`) {
+			search = append(search, term)
+			continue
+		}
+		if value == "" {
+			return filter, fmt.Errorf("%s: missing value", term)
+		}
+
+		switch key {
+		case "category":
+			for _, category := range strings.Split(value, ",") {
+				if category = strings.TrimSpace(category); category != "" {
+					filter.Categories = append(filter.Categories, category)
+				}
+			}
+		case "paid_by":
+			filter.PaidBy = value
+		case "participant":
+			filter.Participant = value
+		case "user":
+			filter.UserID = value
+		case "group":
+			filter.GroupID = value
+		case "amount":
+			err = parseAmountRange(value, &filter)
+		case "date":
+			err = parseDateRange(value, &filter)
+		case "tag":
+			var tag string
+			if tag, err = NormalizeTag(value); err == nil {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		case "has", "-has":
+			if value != "attachment" {
+				return filter, fmt.Errorf("%s: only has:attachment is supported", term)
+			}
+			has := key == "has"
+			filter.HasAttachment = &has
+		default:
+			return filter, fmt.Errorf("unknown filter %q; quote text that contains a colon", key)
+		}
+		if err != nil {
+			return filter, fmt.Errorf("%s: %w", term, err)
+		}
+	}
+	filter.Search = strings.Join(search, " ")
+
+	return filter, filter.Validate()
+}
+
+// splitQuery splits a query on spaces, keeping quoted phrases, quotes
+// included, as one term
+func splitQuery(q string) ([]string, error) {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			term.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote in query")
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
+
+// cutRange splits "a..b", ">=a", ">a", "<=b", "<b" or "a" into its bounds.
+// A strict bound is reported through the strict flags.
+func cutRange(value string) (low, high string, strictLow, strictHigh bool) {
+	switch {
+	case strings.HasPrefix(value, ">="):
+		return value[2:], "", false, false
+	case strings.HasPrefix(value, ">"):
+		return value[1:], "", true, false
+	case strings.HasPrefix(value, "<="):
+		return "", value[2:], false, false
+	case strings.HasPrefix(value, "<"):
+		return "", value[1:], false, true
+	}
+	if low, high, ok := strings.Cut(value, ".."); ok {
+		return low, high, false, false
+	}
+	return value, value, false, false
+}
+
+func parseAmountRange(value string, filter *ExpenseFilter) error {
+	low, high, strictLow, strictHigh := cutRange(value)
+	if low == "" && high == "" {
+		return errors.New("empty range")
+	}
+	if low != "" {
+		amount, err := ParseMoney(low, "")
+		if err != nil {
+			return err
+		}
+		if strictLow {
+			amount.Minor++
+		}
+		filter.MinAmount = &amount
+	}
+	if high != "" {
+		amount, err := ParseMoney(high, "")
+		if err != nil {
+			return err
+		}
+		if strictHigh {
+			amount.Minor--
+		}
+		filter.MaxAmount = &amount
+	}
+	return nil
+}
+
+func parseDateRange(value string, filter *ExpenseFilter) error {
+	low, high, strictLow, strictHigh := cutRange(value)
+	if low == "" && high == "" {
+		return errors.New("empty range")
+	}
+	shift := func(date string, days int) (string, error) {
+		t, err := time.Parse(filterDateLayout, date)
+		if err != nil {
+			return "", fmt.Errorf("invalid date %q: must be YYYY-MM-DD", date)
+		}
+		return t.AddDate(0, 0, days).Format(filterDateLayout), nil
+	}
+
+	var err error
+	if low != "" {
+		if filter.StartDate, err = shift(low, boolInt(strictLow)); err != nil {
+			return err
+		}
+	}
+	if high != "" {
+		if filter.EndDate, err = shift(high, -boolInt(strictHigh)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// NormalizeTag lowercases and trims a tag and checks it is a single word of
+// letters, digits, '-' or '_'
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", errors.New("tag must not be empty")
+	}
+	if len(tag) > maxTagLength {
+		return "", fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+	}
+	for _, r := range tag {
+		if !(r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r > 127) {
+			return "", fmt.Errorf("tag %q may only contain letters, digits, '-' and '_'", tag)
+		}
+	}
+	return tag, nil
+}
+
+// NormalizeTags normalizes each tag, dropping duplicates and sorting them
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+	if len(normalized) > MaxExpenseTags {
+		return nil, fmt.Errorf("an expense can have at most %d tags", MaxExpenseTags)
+	}
+	return normalized, nil
+}
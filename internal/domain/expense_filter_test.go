@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExpenseQuery(t *testing.T) {
+	money := func(minor int64) *Money { return &Money{Minor: minor} }
+	yes, no := true, false
+
+	tests := []struct {
+		q       string
+		want    ExpenseFilter
+		wantErr bool
+	}{
+		{q: "", want: ExpenseFilter{}},
+		{q: "pizza night", want: ExpenseFilter{Search: "pizza night"}},
+		{q: `"pizza night" category:Food,drinks category:rent`, want: ExpenseFilter{Search: `"pizza night"`, Categories: []string{"Food", "drinks", "rent"}}},
+		{q: "paid_by:alice participant:bob user:carol group:g1", want: ExpenseFilter{PaidBy: "alice", Participant: "bob", UserID: "carol", GroupID: "g1"}},
+		{q: "amount:10..50.5", want: ExpenseFilter{MinAmount: money(1000), MaxAmount: money(5050)}},
+		{q: "amount:>10", want: ExpenseFilter{MinAmount: money(1001)}},
+		{q: "amount:<=20", want: ExpenseFilter{MaxAmount: money(2000)}},
+		{q: "amount:25", want: ExpenseFilter{MinAmount: money(2500), MaxAmount: money(2500)}},
+		{q: "date:2024-01-01..2024-01-31", want: ExpenseFilter{StartDate: "2024-01-01", EndDate: "2024-01-31"}},
+		{q: "date:<2024-03-01", want: ExpenseFilter{EndDate: "2024-02-29"}},
+		{q: "tag:Trip tag:goa", want: ExpenseFilter{Tags: []string{"trip", "goa"}}},
+		{q: "has:attachment", want: ExpenseFilter{HasAttachment: &yes}},
+		{q: "-has:attachment", want: ExpenseFilter{HasAttachment: &no}},
+		{q: "amount:50..10", wantErr: true},
+		{q: "amount:ten", wantErr: true},
+		{q: "date:2024-13-01", wantErr: true},
+		{q: "colour:red", wantErr: true},
+		{q: "has:receipt", wantErr: true},
+		{q: "category:", wantErr: true},
+		{q: `"unterminated`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			got, err := ParseExpenseQuery(tt.q)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExpenseQuery(%q) error = %v, wantErr %v", tt.q, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExpenseQuery(%q) = %+v, want %+v", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpenseFilter_MatchesSearch(t *testing.T) {
+	const text = "Pizza and movie-night snacks"
+	tests := []struct {
+		search string
+		want   bool
+	}{
+		{search: "pizza SNACKS", want: true},
+		{search: "pizza beer", want: false},
+		{search: "pizz", want: false},
+		{search: `"movie night"`, want: true},
+		{search: `"night movie"`, want: false},
+		{search: "pizza -beer", want: true},
+		{search: "pizza -snacks", want: false},
+		{search: `-"movie night"`, want: false},
+		{search: "beer or snacks", want: true},
+		{search: "beer or wine", want: false},
+		{search: "pizza beer or wine snacks", want: false},
+		{search: `"or"`, want: false},
+		{search: "!!", want: true},
+	}
+
+	for _, tt := range tests {
+		filter := ExpenseFilter{Search: tt.search}
+		if got := filter.MatchesSearch(text); got != tt.want {
+			t.Errorf("MatchesSearch(%q) with search %q = %v, want %v", text, tt.search, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := NormalizeTags([]string{" Trip", "goa", "trip", "road_trip-2024"})
+	if err != nil {
+		t.Fatalf("NormalizeTags() failed: %v", err)
+	}
+	if want := []string{"goa", "road_trip-2024", "trip"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeTags() = %q, want %q", got, want)
+	}
+
+	for _, tags := range [][]string{{""}, {"two words"}, {"semi;colon"}} {
+		if _, err := NormalizeTags(tags); err == nil {
+			t.Errorf("Expected error for tags %q", tags)
+		}
+	}
+}
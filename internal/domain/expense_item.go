@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ItemKind says how an expense item is charged to users
+type ItemKind string
+
+const (
+	// ItemProduct is charged equally to the item's users
+	ItemProduct ItemKind = "item"
+	// ItemTax and ItemTip are shared in proportion to what each user's
+	// products cost
+	ItemTax ItemKind = "tax"
+	ItemTip ItemKind = "tip"
+)
+
+// ExpenseItem is one line of an itemised receipt
+type ExpenseItem struct {
+	Name     string   `json:"name"`
+	Quantity int      `json:"quantity"`
+	Price    Money    `json:"price"`
+	Kind     ItemKind `json:"kind"`
+	UserIDs  []string `json:"user_ids,omitempty"`
+}
+
+// Total is the item's unit price times its quantity, or ErrAmountOutOfRange
+// where that overflows
+func (i ExpenseItem) Total() (Money, error) {
+	return i.Price.CheckedMul(int64(i.Quantity))
+}
+
+func (i ExpenseItem) Validate() error {
+	if i.Name == "" {
+		return errors.New("item name is required")
+	}
+	if i.Quantity < 1 {
+		return fmt.Errorf("item %q quantity must be at least 1", i.Name)
+	}
+	if !i.Price.IsPositive() {
+		return fmt.Errorf("item %q price must be greater than zero", i.Name)
+	}
+	if !i.Price.Storable() {
+		return fmt.Errorf("item %q price: %w", i.Name, ErrAmountOutOfRange)
+	}
+	if _, err := i.Total(); err != nil {
+		return fmt.Errorf("item %q: %w", i.Name, err)
+	}
+
+	switch i.Kind {
+	case ItemProduct:
+		if len(i.UserIDs) == 0 {
+			return fmt.Errorf("item %q must be assigned to at least one user", i.Name)
+		}
+	case ItemTax, ItemTip:
+		if len(i.UserIDs) > 0 {
+			return fmt.Errorf("%s %q is shared by everyone and cannot be assigned to users", i.Kind, i.Name)
+		}
+	default:
+		return fmt.Errorf("invalid item kind %q: must be item, tax or tip", i.Kind)
+	}
+	return nil
+}
+
+// ItemSplits derives an expense's splits from its items. Each product is
+// split equally between its users, then tax and tips are allocated in
+// proportion to each user's product subtotal. Leftover minor units go to
+// users in the order they first appear.
+func ItemSplits(items []ExpenseItem) ([]Split, error) {
+	var userIDs []string
+	subtotals := make(map[string]Money)
+	var shared []ExpenseItem
+
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return nil, err
+		}
+		if item.Kind != ItemProduct {
+			shared = append(shared, item)
+			continue
+		}
+
+		total, err := item.Total()
+		if err != nil {
+			return nil, err
+		}
+		for j, share := range total.Allocate(len(item.UserIDs)) {
+			userID := item.UserIDs[j]
+			if _, ok := subtotals[userID]; !ok {
+				userIDs = append(userIDs, userID)
+			}
+			subtotals[userID] = subtotals[userID].Add(share)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil, errors.New("at least one item must be assigned to users")
+	}
+
+	weights := make([]int64, len(userIDs))
+	for i, userID := range userIDs {
+		weights[i] = subtotals[userID].Minor
+	}
+
+	totals := make(map[string]Money, len(userIDs))
+	for userID, subtotal := range subtotals {
+		totals[userID] = subtotal
+	}
+	for _, item := range shared {
+		total, err := item.Total()
+		if err != nil {
+			return nil, err
+		}
+		shares, err := total.AllocateWeighted(weights)
+		if err != nil {
+			return nil, err
+		}
+		for i, share := range shares {
+			totals[userIDs[i]] = totals[userIDs[i]].Add(share)
+		}
+	}
+
+	splits := make([]Split, len(userIDs))
+	for i, userID := range userIDs {
+		splits[i] = Split{UserID: userID, Amount: totals[userID]}
+	}
+	return splits, nil
+}
+
+// validateItems checks that items are valid and add up to amount
+func validateItems(items []ExpenseItem, amount Money) error {
+	sum := Money{Currency: amount.Currency}
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+		total, err := item.Total()
+		if err != nil {
+			return fmt.Errorf("item %q: %w", item.Name, err)
+		}
+		if sum, err = sum.CheckedAdd(total); err != nil {
+			return fmt.Errorf("item %q: %w", item.Name, err)
+		}
+	}
+
+	if sum.Cmp(amount) != 0 {
+		return errors.New("sum of items must equal the expense amount")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestItemSplits(t *testing.T) {
+	usd := func(minor int64) Money { return NewMoney(minor, "USD") }
+	items := []ExpenseItem{
+		{Name: "Milk", Quantity: 2, Price: usd(150), Kind: ItemProduct, UserIDs: []string{"alice"}},
+		{Name: "Bread", Quantity: 1, Price: usd(300), Kind: ItemProduct, UserIDs: []string{"alice", "bob"}},
+		{Name: "Cheese", Quantity: 1, Price: usd(450), Kind: ItemProduct, UserIDs: []string{"bob"}},
+		{Name: "Sales tax", Quantity: 1, Price: usd(101), Kind: ItemTax},
+	}
+
+	splits, err := ItemSplits(items)
+	if err != nil {
+		t.Fatalf("ItemSplits() failed: %v", err)
+	}
+
+	// Subtotals are 4.50 and 6.00, so tax is shared 3:4 and the extra cent
+	// goes to the larger remainder
+	want := []Split{
+		{UserID: "alice", Amount: usd(450 + 43)},
+		{UserID: "bob", Amount: usd(600 + 58)},
+	}
+	if !reflect.DeepEqual(splits, want) {
+		t.Errorf("Expected %v, got %v", want, splits)
+	}
+}
+
+func TestItemSplits_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		item ExpenseItem
+	}{
+		{name: "unassigned item", item: ExpenseItem{Name: "Milk", Quantity: 1, Price: NewMoney(100, "USD"), Kind: ItemProduct}},
+		{name: "assigned tip", item: ExpenseItem{Name: "Tip", Quantity: 1, Price: NewMoney(100, "USD"), Kind: ItemTip, UserIDs: []string{"alice"}}},
+		{name: "zero quantity", item: ExpenseItem{Name: "Milk", Price: NewMoney(100, "USD"), Kind: ItemProduct, UserIDs: []string{"alice"}}},
+		{name: "unknown kind", item: ExpenseItem{Name: "Milk", Quantity: 1, Price: NewMoney(100, "USD"), Kind: "fee", UserIDs: []string{"alice"}}},
+		{name: "overflowing total", item: ExpenseItem{Name: "Milk", Quantity: 1 << 30, Price: NewMoney(99_999_999_99, "USD"), Kind: ItemProduct, UserIDs: []string{"alice"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ItemSplits([]ExpenseItem{tt.item}); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestExpense_ValidateItemsSum(t *testing.T) {
+	expense := Expense{
+		Description: "Groceries",
+		Amount:      NewMoney(500, "USD"),
+		PaidBy:      "alice",
+		Splits:      []Split{{UserID: "alice", Amount: NewMoney(500, "USD")}},
+		Items: []ExpenseItem{
+			{Name: "Milk", Quantity: 2, Price: NewMoney(200, "USD"), Kind: ItemProduct, UserIDs: []string{"alice"}},
+		},
+	}
+	if err := expense.Validate(); err == nil {
+		t.Error("Expected error when items do not add up to the amount")
+	}
+
+	expense.Items[0].Price = NewMoney(250, "USD")
+	if err := expense.Validate(); err != nil {
+		t.Errorf("Expected valid expense, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseExpenseDate(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2024-03-10", want: time.Date(2024, 3, 10, 0, 0, 0, 0, kolkata)},
+		{value: "2024-03-10T19:30:00", want: time.Date(2024, 3, 10, 19, 30, 0, 0, kolkata)},
+		{value: "2024-03-10T14:00:00Z", want: time.Date(2024, 3, 10, 19, 30, 0, 0, kolkata)},
+		{value: "2024-03-16T05:00:00+05:30", want: time.Date(2024, 3, 16, 5, 0, 0, 0, kolkata)},
+		{value: "2024-03-17", wantErr: true},
+		{value: "2010-01-01", wantErr: true},
+		{value: "10/03/2024", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseExpenseDate(tt.value, kolkata, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseExpenseDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (!got.Equal(tt.want) || got.Location() != kolkata) {
+			t.Errorf("ParseExpenseDate(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestExpense_Validate_OutOfRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Money
+		splits []Split
+	}{
+		{
+			// The splits wrap around to the amount without overflow checks
+			name:   "overflowing splits",
+			amount: NewMoney(10000, "INR"),
+			splits: []Split{
+				{UserID: "alice", Amount: NewMoney(math.MaxInt64, "INR")},
+				{UserID: "bob", Amount: NewMoney(math.MaxInt64, "INR")},
+				{UserID: "carol", Amount: NewMoney(10002, "INR")},
+			},
+		},
+		{
+			name:   "amount beyond the column",
+			amount: NewMoney(100_000_000_00, "INR"),
+			splits: []Split{{UserID: "alice", Amount: NewMoney(100_000_000_00, "INR")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expense := &Expense{Description: "Rent", Amount: tt.amount, PaidBy: "alice", Splits: tt.splits}
+			if err := expense.Validate(); !errors.Is(err, ErrAmountOutOfRange) {
+				t.Errorf("Expected ErrAmountOutOfRange, got %v", err)
+			}
+		})
+	}
+
+	largest := NewMoney(99_999_999_99, "INR")
+	expense := &Expense{Description: "Rent", Amount: largest, PaidBy: "alice", Splits: []Split{{UserID: "alice", Amount: largest}}}
+	if err := expense.Validate(); err != nil {
+		t.Errorf("Expected the largest column value to be valid, got %v", err)
+	}
+}
+
+func TestExpense_Validate_MixedCurrencySplits(t *testing.T) {
+	expense := &Expense{
+		Description: "Dinner",
+		Amount:      NewMoney(1000, "EUR"),
+		PaidBy:      "alice",
+		Splits: []Split{
+			{UserID: "alice", Amount: NewMoney(500, "EUR")},
+			{UserID: "bob", Amount: NewMoney(500, "INR")},
+		},
+	}
+	if err := expense.Validate(); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNotGroupMember = errors.New("user is not a member of the group")
+
+// Group is a household or trip whose members share expenses
+type Group struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	BaseCurrency Currency `json:"base_currency"`
+	// Timezone is the IANA zone the household's expense dates are read in;
+	// empty means the server's zone
+	Timezone  string    `json:"timezone,omitempty"`
+	Members   []string  `json:"members"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (g *Group) Validate() error {
+	if g.Name == "" {
+		return errors.New("group name is required")
+	}
+	if _, err := g.Location(); err != nil {
+		return err
+	}
+	return g.BaseCurrency.Validate()
+}
+
+// Location returns the group's time zone, or the server's if it has none
+func (g *Group) Location() (*time.Location, error) {
+	return LoadTimezone(g.Timezone)
+}
+
+// LoadTimezone loads an IANA time zone such as "Asia/Kolkata". Empty means
+// the server's zone.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("invalid time zone %q: must be an IANA name such as Asia/Kolkata", name)
+	}
+	return loc, nil
+}
+
+// HasMember reports whether the user belongs to the group
+func (g *Group) HasMember(userID string) bool {
+	for _, member := range g.Members {
+		if member == userID {
+			return true
+		}
+	}
+	return false
+}
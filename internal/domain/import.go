@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// ImportRow is an expense read from an import file. People are named by email
+// and matched to users when the row is imported.
+type ImportRow struct {
+	Line        int
+	Date        time.Time
+	Description string
+	Category    string
+	Amount      Money
+	PaidBy      string
+	Shares      []ImportShare
+}
+
+// ImportShare is what one person owes towards an imported expense
+type ImportShare struct {
+	Email  string
+	Amount Money
+}
+
+// ImportProblem explains why a line of an import file was not imported
+type ImportProblem struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ImportedExpense is an expense created, or that a dry run would create, from
+// a line of an import file
+type ImportedExpense struct {
+	Line    int      `json:"line"`
+	Expense *Expense `json:"expense"`
+}
+
+// ImportReport describes the outcome of an import. Expenses are only created
+// when no line failed, so Committed is false after a dry run or when Failed is
+// not empty, and Created then lists what would have been created. Skipped
+// lines, such as settle-up payments, never block an import.
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Committed bool              `json:"committed"`
+	Created   []ImportedExpense `json:"created"`
+	Failed    []ImportProblem   `json:"failed"`
+	Skipped   []ImportProblem   `json:"skipped"`
+}
+
+// ImportBatch is what was read from an import file: the rows to import and the
+// lines that could not be read or were left out
+type ImportBatch struct {
+	Rows    []ImportRow
+	Failed  []ImportProblem
+	Skipped []ImportProblem
+}
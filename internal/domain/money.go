@@ -0,0 +1,402 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+// DefaultCurrency is used for amounts that do not specify a currency
+const DefaultCurrency Currency = "INR"
+
+// ParseCurrency normalises a currency code such as "eur" to "EUR"
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if err := currency.Validate(); err != nil {
+		return "", err
+	}
+	return currency, nil
+}
+
+func (c Currency) Validate() error {
+	if len(c) != 3 {
+		return fmt.Errorf("invalid currency %q: must be a 3-letter ISO 4217 code", string(c))
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return fmt.Errorf("invalid currency %q: must be a 3-letter ISO 4217 code", string(c))
+		}
+	}
+	return nil
+}
+
+// minorUnitsPerMajor matches the two decimal places of the DECIMAL(10,2) columns
+const minorUnitsPerMajor = 100
+
+// maxStoredMinor is the largest amount, in minor units, the DECIMAL(10,2)
+// columns hold
+const maxStoredMinor = 99_999_999_99
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrAmountOutOfRange = errors.New("amount out of range")
+)
+
+// Money is an exact monetary amount held as an integer number of minor units
+// (paise, cents) of a currency. It encodes to JSON and SQL as a decimal with
+// two places, so no value ever passes through a float.
+type Money struct {
+	Minor    int64
+	Currency Currency
+}
+
+// NewMoney returns an amount of minor units in the given currency
+func NewMoney(minor int64, currency Currency) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "1234.5" or "-0.05" exactly.
+// At most two decimal places are accepted.
+func ParseMoney(s string, currency Currency) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, errors.New("invalid amount: empty")
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" && (!hasFrac || frac == "") {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(frac) > 2 {
+		return Money{}, fmt.Errorf("invalid amount %q: more than two decimal places", s)
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+
+	var major int64
+	if whole != "" {
+		var err error
+		major, err = strconv.ParseInt(whole, 10, 64)
+		if err != nil {
+			return Money{}, fmt.Errorf("invalid amount %q: %w", s, err)
+		}
+	}
+
+	frac += strings.Repeat("0", 2-len(frac))
+	minor, _ := strconv.ParseInt(frac, 10, 64)
+	if major > (math.MaxInt64-minor)/minorUnitsPerMajor {
+		return Money{}, fmt.Errorf("invalid amount %q: out of range", s)
+	}
+
+	total := major*minorUnitsPerMajor + minor
+	if negative {
+		total = -total
+	}
+	return Money{Minor: total, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add returns m + other. A zero-value currency adopts the other operand's
+// currency; adding two different currencies is a programming error.
+func (m Money) Add(other Money) Money {
+	return Money{Minor: m.Minor + other.Minor, Currency: m.commonCurrency(other)}
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) Money {
+	return Money{Minor: m.Minor - other.Minor, Currency: m.commonCurrency(other)}
+}
+
+// CheckedAdd returns m + other, or ErrCurrencyMismatch where Add would panic
+// and ErrAmountOutOfRange where the sum would overflow. Use it on amounts read
+// from requests or storage.
+func (m Money) CheckedAdd(other Money) (Money, error) {
+	currency, err := m.checkedCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	sum := m.Minor + other.Minor
+	if (other.Minor > 0 && sum < m.Minor) || (other.Minor < 0 && sum > m.Minor) {
+		return Money{}, ErrAmountOutOfRange
+	}
+	return Money{Minor: sum, Currency: currency}, nil
+}
+
+// CheckedMul returns m * n, or ErrAmountOutOfRange where the product would
+// overflow
+func (m Money) CheckedMul(n int64) (Money, error) {
+	product := m.Minor * n
+	if n != 0 && (product/n != m.Minor || (n == -1 && m.Minor == math.MinInt64)) {
+		return Money{}, ErrAmountOutOfRange
+	}
+	return Money{Minor: product, Currency: m.Currency}, nil
+}
+
+// Storable reports whether m fits the DECIMAL(10,2) columns
+func (m Money) Storable() bool {
+	return m.Minor >= -maxStoredMinor && m.Minor <= maxStoredMinor
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{Minor: -m.Minor, Currency: m.Currency}
+}
+
+// Abs returns |m|
+func (m Money) Abs() Money {
+	if m.Minor < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Min returns the smaller of m and other
+func (m Money) Min(other Money) Money {
+	if other.Minor < m.Minor {
+		return Money{Minor: other.Minor, Currency: m.commonCurrency(other)}
+	}
+	return Money{Minor: m.Minor, Currency: m.commonCurrency(other)}
+}
+
+// Cmp compares m and other and returns -1, 0 or +1
+func (m Money) Cmp(other Money) int {
+	m.commonCurrency(other)
+	switch {
+	case m.Minor < other.Minor:
+		return -1
+	case m.Minor > other.Minor:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether m is exactly zero
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// IsPositive reports whether m is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Minor > 0
+}
+
+// IsNegative reports whether m is less than zero
+func (m Money) IsNegative() bool {
+	return m.Minor < 0
+}
+
+// Allocate splits m into n parts that differ by at most one minor unit and sum
+// exactly to m. Earlier parts receive the leftover units.
+func (m Money) Allocate(n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+
+	base := m.Minor / int64(n)
+	remainder := m.Minor % int64(n)
+
+	parts := make([]Money, n)
+	for i := range parts {
+		parts[i] = Money{Minor: base, Currency: m.Currency}
+		if remainder > 0 {
+			parts[i].Minor++
+			remainder--
+		} else if remainder < 0 {
+			parts[i].Minor--
+			remainder++
+		}
+	}
+	return parts
+}
+
+// AllocateWeighted splits a non-negative m in proportion to non-negative
+// weights, so parts sum exactly to m. Each part is rounded down and the
+// leftover units go to the parts with the largest remainders, earlier parts
+// first on ties.
+func (m Money) AllocateWeighted(weights []int64) ([]Money, error) {
+	if m.IsNegative() {
+		return nil, fmt.Errorf("cannot allocate negative amount %s", m)
+	}
+	total := new(big.Int)
+	for _, weight := range weights {
+		if weight < 0 {
+			return nil, fmt.Errorf("cannot allocate by negative weight %d", weight)
+		}
+		total.Add(total, big.NewInt(weight))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("cannot allocate by weights that are all zero")
+	}
+
+	parts := make([]Money, len(weights))
+	remainders := make([]*big.Int, len(weights))
+	left := m.Minor
+	for i, weight := range weights {
+		share := new(big.Int).Mul(big.NewInt(m.Minor), big.NewInt(weight))
+		quotient, remainder := new(big.Int).QuoRem(share, total, new(big.Int))
+		parts[i] = Money{Minor: quotient.Int64(), Currency: m.Currency}
+		remainders[i] = remainder
+		left -= quotient.Int64()
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for _, i := range order[:left] {
+		parts[i].Minor++
+	}
+	return parts, nil
+}
+
+// DivRound divides m by n, rounding half away from zero
+func (m Money) DivRound(n int64) Money {
+	if n == 0 {
+		return Money{Currency: m.Currency}
+	}
+	quotient := m.Minor / n
+	remainder := m.Minor % n
+	if remainder < 0 {
+		remainder = -remainder
+	}
+	if remainder*2 >= abs64(n) {
+		if (m.Minor < 0) != (n < 0) {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return Money{Minor: quotient, Currency: m.Currency}
+}
+
+// Convert multiplies m by rate and returns the result in the target currency,
+// rounded half away from zero to the nearest minor unit
+func (m Money) Convert(rate *big.Rat, to Currency) Money {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Minor), rate)
+
+	quotient, remainder := new(big.Int).QuoRem(product.Num(), product.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(product.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(product.Sign())))
+	}
+	return Money{Minor: quotient.Int64(), Currency: to}
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// String formats m as a decimal with two places, e.g. "-12.05"
+func (m Money) String() string {
+	sign := ""
+	minor := m.Minor
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/minorUnitsPerMajor, minor%minorUnitsPerMajor)
+}
+
+func (m Money) commonCurrency(other Money) Currency {
+	currency, err := m.checkedCurrency(other)
+	if err != nil {
+		panic("domain: " + err.Error())
+	}
+	return currency
+}
+
+// checkedCurrency returns the currency of m and other, where an unset one
+// adopts the other's
+func (m Money) checkedCurrency(other Money) (Currency, error) {
+	switch {
+	case m.Currency == "":
+		return other.Currency, nil
+	case other.Currency == "" || other.Currency == m.Currency:
+		return m.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+}
+
+// MarshalJSON encodes m as a JSON number with two decimal places
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string. The currency is
+// left unset; callers attach it from the surrounding context.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		*m = Money{}
+		return nil
+	}
+	parsed, err := ParseMoney(s, "")
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer for DECIMAL columns
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns. The currency is left
+// unchanged.
+func (m *Money) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		m.Minor = v * minorUnitsPerMajor
+		return nil
+	case nil:
+		m.Minor = 0
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	parsed, err := ParseMoney(s, m.Currency)
+	if err != nil {
+		return err
+	}
+	m.Minor = parsed.Minor
+	return nil
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "100", want: 10000},
+		{input: "100.5", want: 10050},
+		{input: "0.05", want: 5},
+		{input: ".5", want: 50},
+		{input: "-12.34", want: -1234},
+		{input: "1.234", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "-", wantErr: true},
+		{input: "92233720368547758.07", want: math.MaxInt64},
+		{input: "92233720368547758.08", wantErr: true},
+		{input: "92233720368547759", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.input, DefaultCurrency)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) expected error, got %v", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got.Minor != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.input, got.Minor, tt.want)
+		}
+	}
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	parts := NewMoney(10000, DefaultCurrency).Allocate(3)
+
+	var sum Money
+	for _, part := range parts {
+		sum = sum.Add(part)
+	}
+	if sum != NewMoney(10000, DefaultCurrency) {
+		t.Fatalf("Expected parts to sum to 100.00, got %v", sum)
+	}
+	if parts[0].Minor != 3334 || parts[1].Minor != 3333 || parts[2].Minor != 3333 {
+		t.Fatalf("Expected 33.34, 33.33, 33.33, got %v", parts)
+	}
+}
+
+func TestMoney_AllocateWeighted(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{name: "exact", amount: 10000, weights: []int64{1, 3}, want: []int64{2500, 7500}},
+		{name: "largest remainder", amount: 1000, weights: []int64{3333, 3333, 3334}, want: []int64{333, 333, 334}},
+		{name: "ties go first", amount: 100, weights: []int64{1, 1, 1}, want: []int64{34, 33, 33}},
+		{name: "zero amount", amount: 0, weights: []int64{1, 2}, want: []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := NewMoney(tt.amount, DefaultCurrency).AllocateWeighted(tt.weights)
+			if err != nil {
+				t.Fatalf("AllocateWeighted returned error: %v", err)
+			}
+			if len(parts) != len(tt.want) {
+				t.Fatalf("Expected %d parts, got %d", len(tt.want), len(parts))
+			}
+			for i, part := range parts {
+				if part.Minor != tt.want[i] {
+					t.Errorf("Part %d = %d, want %d", i, part.Minor, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMoney_AllocateWeighted_Invalid(t *testing.T) {
+	if _, err := NewMoney(-100, DefaultCurrency).AllocateWeighted([]int64{1, 1}); err == nil {
+		t.Error("Expected an error allocating a negative amount")
+	}
+	if _, err := NewMoney(100, DefaultCurrency).AllocateWeighted([]int64{2, -1}); err == nil {
+		t.Error("Expected an error allocating by a negative weight")
+	}
+	if _, err := NewMoney(100, DefaultCurrency).AllocateWeighted([]int64{0, 0}); err == nil {
+		t.Error("Expected an error allocating by zero weights")
+	}
+}
+
+func TestMoney_CheckedAdd(t *testing.T) {
+	sum, err := NewMoney(100, "").CheckedAdd(NewMoney(50, "EUR"))
+	if err != nil || sum != NewMoney(150, "EUR") {
+		t.Errorf("Expected 1.50 EUR, got %v %s, %v", sum, sum.Currency, err)
+	}
+	if _, err := NewMoney(100, "INR").CheckedAdd(NewMoney(50, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := NewMoney(math.MaxInt64, "INR").CheckedAdd(NewMoney(1, "INR")); !errors.Is(err, ErrAmountOutOfRange) {
+		t.Errorf("Expected ErrAmountOutOfRange, got %v", err)
+	}
+	if _, err := NewMoney(math.MinInt64, "INR").CheckedAdd(NewMoney(-1, "INR")); !errors.Is(err, ErrAmountOutOfRange) {
+		t.Errorf("Expected ErrAmountOutOfRange, got %v", err)
+	}
+}
+
+func TestMoney_CheckedMul(t *testing.T) {
+	product, err := NewMoney(250, "INR").CheckedMul(3)
+	if err != nil || product != NewMoney(750, "INR") {
+		t.Errorf("Expected 7.50 INR, got %v, %v", product, err)
+	}
+	for _, tt := range []struct {
+		minor, n int64
+	}{
+		{math.MaxInt64 / 2, 3},
+		{math.MinInt64, -1},
+		{-1, math.MinInt64},
+	} {
+		if _, err := NewMoney(tt.minor, "INR").CheckedMul(tt.n); !errors.Is(err, ErrAmountOutOfRange) {
+			t.Errorf("%d * %d: expected ErrAmountOutOfRange, got %v", tt.minor, tt.n, err)
+		}
+	}
+}
+
+func TestMoney_DivRound(t *testing.T) {
+	if got := NewMoney(1000, DefaultCurrency).DivRound(3); got.Minor != 333 {
+		t.Errorf("Expected 10.00/3 = 3.33, got %v", got)
+	}
+	if got := NewMoney(-3, DefaultCurrency).DivRound(2); got.Minor != -2 {
+		t.Errorf("Expected -0.03/2 = -0.02, got %v", got)
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(NewMoney(-1205, DefaultCurrency))
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if string(data) != "-12.05" {
+		t.Fatalf("Expected -12.05, got %s", data)
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`33.33`), &m); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if m.Minor != 3333 {
+		t.Fatalf("Expected 3333 minor units, got %d", m.Minor)
+	}
+}
+
+func TestMoney_AddCurrencyMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic when adding different currencies")
+		}
+	}()
+	NewMoney(100, "INR").Add(NewMoney(100, "EUR"))
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Fields lists are sorted by. Ties are always broken by ID, so every order is
+// total and a page boundary is never ambiguous.
+const (
+	SortByDate      = "date"
+	SortByAmount    = "amount"
+	SortByCreatedAt = "created_at"
+	SortByName      = "name"
+)
+
+// ExpenseSortFields and UserSortFields are the fields each list can be sorted
+// by; the first is the default
+var (
+	ExpenseSortFields = []string{SortByDate, SortByAmount, SortByCreatedAt}
+	UserSortFields    = []string{SortByCreatedAt, SortByName}
+)
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// Sort orders a list by one field
+type Sort struct {
+	Field string    `json:"f"`
+	Order SortOrder `json:"o"`
+}
+
+// ParseSort parses "field" or "field:asc|desc", where field is one of fields.
+// An empty string means def.
+func ParseSort(s string, fields []string, def Sort) (Sort, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	field, order, _ := strings.Cut(s, ":")
+	if !slices.Contains(fields, field) {
+		return Sort{}, fmt.Errorf("invalid sort field %q: must be one of %s", field, strings.Join(fields, ", "))
+	}
+
+	sort := Sort{Field: field, Order: SortOrder(order)}
+	switch sort.Order {
+	case "":
+		sort.Order = def.Order
+	case SortAsc, SortDesc:
+	default:
+		return Sort{}, fmt.Errorf("invalid sort order %q: must be asc or desc", order)
+	}
+	return sort, nil
+}
+
+// PageRequest asks for up to Limit items following Cursor, in Sort order
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	Sort   Sort
+}
+
+// Validate checks the limit, defaulting a zero limit to DefaultPageLimit
+func (p *PageRequest) Validate() error {
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxPageLimit)
+	}
+	if p.Sort.Order != SortAsc && p.Sort.Order != SortDesc {
+		return fmt.Errorf("invalid sort order %q", p.Sort.Order)
+	}
+	return nil
+}
+
+// PageCursor marks the last item of a page by its sort value and ID. Clients
+// see it only as an opaque string.
+type PageCursor struct {
+	Sort  Sort   `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// Encode returns the cursor as a URL-safe string
+func (c PageCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor from a page ordered by sort. A cursor from a
+// page with a different order is rejected, since its position means nothing
+// in this one. An empty string decodes to nil, the start of the list.
+func DecodeCursor(s string, sort Sort) (*PageCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != sort {
+		return nil, fmt.Errorf("%w: it was issued for sort %s:%s", ErrInvalidCursor, cursor.Sort.Field, cursor.Sort.Order)
+	}
+	return &cursor, nil
+}
+
+// cursorTimeLayout keeps the full precision of stored timestamps
+const cursorTimeLayout = time.RFC3339Nano
+
+// ExpenseCursor returns the cursor marking expense in a list ordered by sort
+func ExpenseCursor(expense *Expense, sort Sort) string {
+	var value string
+	switch sort.Field {
+	case SortByAmount:
+		value = expense.Amount.String()
+	case SortByCreatedAt:
+		value = expense.CreatedAt.UTC().Format(cursorTimeLayout)
+	default:
+		value = expense.Date.UTC().Format(cursorTimeLayout)
+	}
+	return PageCursor{Sort: sort, Value: value, ID: expense.ID}.Encode()
+}
+
+// UserCursor returns the cursor marking user in a list ordered by sort
+func UserCursor(user *User, sort Sort) string {
+	value := user.CreatedAt.UTC().Format(cursorTimeLayout)
+	if sort.Field == SortByName {
+		value = user.Name
+	}
+	return PageCursor{Sort: sort, Value: value, ID: user.ID}.Encode()
+}
+
+// Time parses the cursor's value for a date or created_at order
+func (c *PageCursor) Time() (time.Time, error) {
+	t, err := time.Parse(cursorTimeLayout, c.Value)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// ExpensePage is one page of expenses. NextCursor is empty on the last page.
+type ExpensePage struct {
+	Expenses   []*Expense
+	NextCursor string
+}
+
+// UserPage is one page of users. NextCursor is empty on the last page.
+type UserPage struct {
+	Users      []*User
+	NextCursor string
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	def := Sort{Field: SortByDate, Order: SortDesc}
+	tests := []struct {
+		in      string
+		want    Sort
+		wantErr bool
+	}{
+		{in: "", want: def},
+		{in: "amount", want: Sort{Field: SortByAmount, Order: SortDesc}},
+		{in: "created_at:asc", want: Sort{Field: SortByCreatedAt, Order: SortAsc}},
+		{in: "name", wantErr: true},
+		{in: "date:up", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSort(tt.in, ExpenseSortFields, def)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSort(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSort(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageRequest_Validate(t *testing.T) {
+	page := PageRequest{Sort: Sort{Field: SortByDate, Order: SortAsc}}
+	if err := page.Validate(); err != nil || page.Limit != DefaultPageLimit {
+		t.Errorf("Expected default limit, got %d (%v)", page.Limit, err)
+	}
+
+	page.Limit = MaxPageLimit + 1
+	if err := page.Validate(); err == nil {
+		t.Error("Expected error for a limit over the maximum")
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	sort := Sort{Field: SortByAmount, Order: SortAsc}
+	encoded := ExpenseCursor(&Expense{ID: "e1", Amount: NewMoney(1250, "USD")}, sort)
+
+	cursor, err := DecodeCursor(encoded, sort)
+	if err != nil {
+		t.Fatalf("DecodeCursor() failed: %v", err)
+	}
+	if cursor.ID != "e1" || cursor.Value != "12.50" {
+		t.Errorf("Unexpected cursor: %+v", cursor)
+	}
+
+	if _, err := DecodeCursor(encoded, Sort{Field: SortByAmount, Order: SortDesc}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for another order, got %v", err)
+	}
+	if _, err := DecodeCursor("not a cursor", sort); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for garbage, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Payment records money one user actually handed another to settle up
+type Payment struct {
+	ID        string    `json:"id"`
+	GroupID   string    `json:"group_id,omitempty"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Amount    Money     `json:"amount"`
+	Date      time.Time `json:"date"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (p *Payment) Validate() error {
+	if p.From == "" || p.To == "" {
+		return errors.New("payment from and to are required")
+	}
+	if p.From == p.To {
+		return errors.New("payment from and to must be different users")
+	}
+	if !p.Amount.IsPositive() {
+		return errors.New("payment amount must be greater than zero")
+	}
+	if p.Date.IsZero() {
+		return errors.New("payment date is required")
+	}
+	return p.Amount.Currency.Validate()
+}
+
+func (p *Payment) Update(amount Money, date time.Time, note string) error {
+	if amount.IsPositive() {
+		p.Amount = amount
+	}
+	if !date.IsZero() {
+		p.Date = date
+	}
+	if note != "" {
+		p.Note = note
+	}
+	p.UpdatedAt = time.Now()
+
+	return p.Validate()
+}
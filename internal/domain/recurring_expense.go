@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRecurringExpenseNotFound = errors.New("recurring expense not found")
+	ErrOccurrenceExists         = errors.New("an expense already exists for this occurrence")
+)
+
+// Frequency is how often a Schedule repeats
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// Schedule is a subset of an iCalendar RRULE: FREQ, INTERVAL and, for
+// monthly schedules, BYMONTHDAY. Days past the end of a month fall on its
+// last day, so BYMONTHDAY=31 means the last day of every month.
+type Schedule struct {
+	Frequency Frequency
+	Interval  int
+	MonthDay  int
+}
+
+// ParseSchedule parses a rule such as "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=1"
+func ParseSchedule(rule string) (Schedule, error) {
+	schedule := Schedule{Interval: 1}
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"), ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Schedule{}, fmt.Errorf("invalid schedule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			schedule.Frequency = Frequency(strings.ToUpper(value))
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return Schedule{}, fmt.Errorf("invalid schedule interval %q", value)
+			}
+			schedule.Interval = interval
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil || day < 1 || day > 31 {
+				return Schedule{}, fmt.Errorf("invalid schedule month day %q", value)
+			}
+			schedule.MonthDay = day
+		default:
+			return Schedule{}, fmt.Errorf("unsupported schedule part %q", key)
+		}
+	}
+
+	switch schedule.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyYearly:
+		if schedule.MonthDay != 0 {
+			return Schedule{}, errors.New("BYMONTHDAY is only supported for monthly schedules")
+		}
+	case FrequencyMonthly:
+	case "":
+		return Schedule{}, errors.New("schedule FREQ is required")
+	default:
+		return Schedule{}, fmt.Errorf("unsupported schedule frequency %q", schedule.Frequency)
+	}
+	return schedule, nil
+}
+
+func (s Schedule) String() string {
+	rule := fmt.Sprintf("FREQ=%s;INTERVAL=%d", s.Frequency, s.Interval)
+	if s.MonthDay != 0 {
+		rule += fmt.Sprintf(";BYMONTHDAY=%d", s.MonthDay)
+	}
+	return rule
+}
+
+// occurrence returns the date of the n-th occurrence counted from start
+func (s Schedule) occurrence(start time.Time, n int) time.Time {
+	step := n * s.Interval
+	switch s.Frequency {
+	case FrequencyDaily:
+		return start.AddDate(0, 0, step)
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*step)
+	case FrequencyYearly:
+		return dateInMonth(start.Year()+step, start.Month(), start.Day())
+	default:
+		day := s.MonthDay
+		if day == 0 {
+			day = start.Day()
+		}
+		return dateInMonth(start.Year(), start.Month()+time.Month(step), day)
+	}
+}
+
+// dateInMonth is the given day of the month, or the month's last day if it
+// is shorter. Months past December roll into the following years.
+func dateInMonth(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// RecurringExpense is a template for an expense that repeats on a schedule,
+// such as rent. Occurrences either use fixed Splits or split the amount
+// equally between UserIDs.
+type RecurringExpense struct {
+	ID          string     `json:"id"`
+	GroupID     string     `json:"group_id,omitempty"`
+	Description string     `json:"description"`
+	Amount      Money      `json:"amount"`
+	Category    string     `json:"category"`
+	PaidBy      string     `json:"paid_by"`
+	Splits      []Split    `json:"splits,omitempty"`
+	UserIDs     []string   `json:"user_ids,omitempty"`
+	Schedule    Schedule   `json:"-"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+	// LastOccurrence is the latest occurrence already turned into an expense
+	LastOccurrence *time.Time `json:"last_occurrence,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (r *RecurringExpense) Validate() error {
+	if r.Description == "" {
+		return errors.New("expense description is required")
+	}
+	if !r.Amount.IsPositive() {
+		return errors.New("expense amount must be greater than zero")
+	}
+	if r.PaidBy == "" {
+		return errors.New("paidBy is required")
+	}
+	if r.StartDate.IsZero() {
+		return errors.New("start date is required")
+	}
+	if r.EndDate != nil && r.EndDate.Before(r.StartDate) {
+		return errors.New("end date must not be before the start date")
+	}
+	if r.Schedule.Frequency == "" {
+		return errors.New("schedule is required")
+	}
+
+	switch {
+	case len(r.Splits) > 0 && len(r.UserIDs) > 0:
+		return errors.New("give either splits or user_ids, not both")
+	case len(r.UserIDs) > 0:
+		return nil
+	default:
+		expense := Expense{Description: r.Description, Amount: r.Amount, PaidBy: r.PaidBy, Splits: r.Splits}
+		return expense.Validate()
+	}
+}
+
+// DueOccurrences returns the occurrence dates up to and including now that
+// have not been turned into expenses yet, oldest first
+func (r *RecurringExpense) DueOccurrences(now time.Time) []time.Time {
+	start := dateInMonth(r.StartDate.Year(), r.StartDate.Month(), r.StartDate.Day())
+	today := dateInMonth(now.Year(), now.Month(), now.Day())
+
+	var due []time.Time
+	for n := 0; ; n++ {
+		date := r.Schedule.occurrence(start, n)
+		if date.After(today) || r.EndDate != nil && date.After(*r.EndDate) {
+			return due
+		}
+		if !date.Before(start) && (r.LastOccurrence == nil || date.After(*r.LastOccurrence)) {
+			due = append(due, date)
+		}
+	}
+}
+
+// NextOccurrence returns the first occurrence after now, or nil once the
+// schedule has ended
+func (r *RecurringExpense) NextOccurrence(now time.Time) *time.Time {
+	start := dateInMonth(r.StartDate.Year(), r.StartDate.Month(), r.StartDate.Day())
+	today := dateInMonth(now.Year(), now.Month(), now.Day())
+
+	for n := 0; ; n++ {
+		date := r.Schedule.occurrence(start, n)
+		if r.EndDate != nil && date.After(*r.EndDate) {
+			return nil
+		}
+		if date.After(today) && !date.Before(start) {
+			return &date
+		}
+	}
+}
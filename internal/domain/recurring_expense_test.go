@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    Schedule
+		wantErr bool
+	}{
+		{rule: "FREQ=MONTHLY", want: Schedule{Frequency: FrequencyMonthly, Interval: 1}},
+		{rule: "RRULE:FREQ=weekly;INTERVAL=2", want: Schedule{Frequency: FrequencyWeekly, Interval: 2}},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=31", want: Schedule{Frequency: FrequencyMonthly, Interval: 1, MonthDay: 31}},
+		{rule: "", wantErr: true},
+		{rule: "FREQ=HOURLY", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{rule: "FREQ=MONTHLY;COUNT=3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := ParseSchedule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSchedule(%q) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseSchedule(%q) = %+v, want %+v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurringExpense_DueOccurrences(t *testing.T) {
+	jan3 := date(2025, time.January, 3)
+	mar10 := date(2025, time.March, 10)
+
+	tests := []struct {
+		name      string
+		rule      string
+		start     time.Time
+		end       *time.Time
+		last      *time.Time
+		now       time.Time
+		wantDates []time.Time
+	}{
+		{
+			name:      "weekly",
+			rule:      "FREQ=WEEKLY",
+			start:     date(2025, time.January, 1),
+			now:       date(2025, time.January, 20),
+			wantDates: []time.Time{date(2025, time.January, 1), date(2025, time.January, 8), date(2025, time.January, 15)},
+		},
+		{
+			name:      "last day of the month",
+			rule:      "FREQ=MONTHLY;BYMONTHDAY=31",
+			start:     date(2025, time.January, 1),
+			now:       date(2025, time.April, 30),
+			wantDates: []time.Time{date(2025, time.January, 31), date(2025, time.February, 28), date(2025, time.March, 31), date(2025, time.April, 30)},
+		},
+		{
+			name:      "month day before start is skipped",
+			rule:      "FREQ=MONTHLY;BYMONTHDAY=1",
+			start:     date(2025, time.January, 15),
+			now:       date(2025, time.March, 1),
+			wantDates: []time.Time{date(2025, time.February, 1), date(2025, time.March, 1)},
+		},
+		{
+			name:      "already materialised",
+			rule:      "FREQ=DAILY",
+			start:     date(2025, time.January, 1),
+			last:      &jan3,
+			now:       date(2025, time.January, 5),
+			wantDates: []time.Time{date(2025, time.January, 4), date(2025, time.January, 5)},
+		},
+		{
+			name:      "ended",
+			rule:      "FREQ=MONTHLY;INTERVAL=2",
+			start:     date(2025, time.January, 10),
+			end:       &mar10,
+			now:       date(2025, time.December, 31),
+			wantDates: []time.Time{date(2025, time.January, 10), date(2025, time.March, 10)},
+		},
+		{
+			name:  "not started",
+			rule:  "FREQ=YEARLY",
+			start: date(2026, time.January, 1),
+			now:   date(2025, time.June, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) failed: %v", tt.rule, err)
+			}
+			recurring := &RecurringExpense{Schedule: schedule, StartDate: tt.start, EndDate: tt.end, LastOccurrence: tt.last}
+
+			got := recurring.DueOccurrences(tt.now)
+			if !reflect.DeepEqual(got, tt.wantDates) {
+				t.Errorf("DueOccurrences() = %v, want %v", got, tt.wantDates)
+			}
+		})
+	}
+}
+
+func TestRecurringExpense_NextOccurrence(t *testing.T) {
+	schedule, _ := ParseSchedule("FREQ=YEARLY")
+	recurring := &RecurringExpense{Schedule: schedule, StartDate: date(2024, time.February, 29)}
+
+	next := recurring.NextOccurrence(date(2024, time.March, 1))
+	if next == nil || !next.Equal(date(2025, time.February, 28)) {
+		t.Errorf("Expected a leap day to recur on 28 February, got %v", next)
+	}
+
+	end := date(2024, time.December, 31)
+	recurring.EndDate = &end
+	if next := recurring.NextOccurrence(date(2024, time.March, 1)); next != nil {
+		t.Errorf("Expected no occurrence after the end date, got %v", next)
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// SplitMode is how an expense's amount is divided between participants
+type SplitMode string
+
+const (
+	// SplitExact takes the split amounts as given
+	SplitExact SplitMode = "exact"
+	// SplitEqual divides the amount equally between users
+	SplitEqual SplitMode = "equal"
+	// SplitPercentage divides the amount by percentages that total 100
+	SplitPercentage SplitMode = "percentage"
+	// SplitShares divides the amount in proportion to each user's shares
+	SplitShares SplitMode = "shares"
+	// SplitItemised charges each item to its users and splits the rest equally
+	SplitItemised SplitMode = "itemised"
+)
+
+// ParseSplitMode accepts a split mode name; empty means exact
+func ParseSplitMode(mode string) (SplitMode, error) {
+	switch SplitMode(mode) {
+	case "":
+		return SplitExact, nil
+	case SplitExact, SplitEqual, SplitPercentage, SplitShares, SplitItemised:
+		return SplitMode(mode), nil
+	default:
+		return "", fmt.Errorf("invalid split mode %q: must be exact, equal, percentage, shares or itemised", mode)
+	}
+}
+
+// SplitWeight is one user's percentage or number of shares, in hundredths so
+// that 12.5% or 1.5 shares stay exact
+type SplitWeight struct {
+	UserID     string
+	Hundredths int64
+}
+
+// maxSplitWeight bounds a percentage or number of shares, so that the
+// weights of an expense add up without overflowing
+const maxSplitWeight = 1_000_000
+
+// NewSplitWeight rounds a user's percentage or number of shares to
+// hundredths. Values that aren't finite or lie outside 0 to 1,000,000 are
+// rejected.
+func NewSplitWeight(userID string, value float64) (SplitWeight, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 || value > maxSplitWeight {
+		return SplitWeight{}, fmt.Errorf("invalid split weight %v for user %s: must be between 0 and %d", value, userID, maxSplitWeight)
+	}
+	return SplitWeight{UserID: userID, Hundredths: int64(math.Round(value * 100))}, nil
+}
+
+// SplitItem is part of an expense charged equally to the listed users
+type SplitItem struct {
+	Description string
+	Amount      Money
+	UserIDs     []string
+}
+
+// SplitSpec describes how to divide an expense. Which fields are read
+// depends on Mode: Splits for exact, UserIDs for equal, Weights for
+// percentage and shares, and Items plus UserIDs for the rest when itemised.
+type SplitSpec struct {
+	Mode    SplitMode
+	Splits  []Split
+	UserIDs []string
+	Weights []SplitWeight
+	Items   []SplitItem
+	// Receipt is an itemised receipt that must add up to the whole amount,
+	// given instead of Items and UserIDs when itemised. The expense keeps it.
+	Receipt []ExpenseItem
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewSplitWeight(t *testing.T) {
+	weight, err := NewSplitWeight("alice", 12.5)
+	if err != nil || weight != (SplitWeight{UserID: "alice", Hundredths: 1250}) {
+		t.Errorf("Expected 12.5 as 1250 hundredths, got %+v, %v", weight, err)
+	}
+
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), -1, 1e300} {
+		if _, err := NewSplitWeight("alice", value); err == nil {
+			t.Errorf("Expected error for weight %v", value)
+		}
+	}
+}
@@ -2,21 +2,55 @@ package domain
 
 // UserStats represents spending statistics for a user
 type UserStats struct {
-	UserID       string             `json:"user_id"`
-	TotalPaid    float64            `json:"total_paid"`
-	TotalOwed    float64            `json:"total_owed"`
-	NetBalance   float64            `json:"net_balance"`
-	ExpenseCount int                `json:"expense_count"`
-	ByCategory   map[string]float64 `json:"by_category"`
+	UserID           string           `json:"user_id"`
+	Currency         Currency         `json:"currency"`
+	TotalPaid        Money            `json:"total_paid"`
+	TotalOwed        Money            `json:"total_owed"`
+	PaymentsSent     Money            `json:"payments_sent"`
+	PaymentsReceived Money            `json:"payments_received"`
+	NetBalance       Money            `json:"net_balance"`
+	ExpenseCount     int              `json:"expense_count"`
+	ByCategory       map[string]Money `json:"by_category"`
 }
 
 // MonthlySummary represents expense summary for a specific month
 type MonthlySummary struct {
-	Year          int                `json:"year"`
-	Month         int                `json:"month"`
-	TotalExpenses float64            `json:"total_expenses"`
-	ExpenseCount  int                `json:"expense_count"`
-	ByCategory    map[string]float64 `json:"by_category"`
-	TopCategory   string             `json:"top_category"`
-	AveragePerDay float64            `json:"average_per_day"`
+	Year          int              `json:"year"`
+	Month         int              `json:"month"`
+	Currency      Currency         `json:"currency"`
+	TotalExpenses Money            `json:"total_expenses"`
+	ExpenseCount  int              `json:"expense_count"`
+	ByCategory    map[string]Money `json:"by_category"`
+	TopCategory   string           `json:"top_category"`
+	AveragePerDay Money            `json:"average_per_day"`
 }
+
+// TotalsQuery selects the expenses to total and how to break the totals down
+type TotalsQuery struct {
+	// GroupID limits the totals to one group; empty totals every expense
+	GroupID string
+	// UserID limits the totals to one user; empty totals every user
+	UserID string
+	// StartDate and EndDate are inclusive YYYY-MM-DD bounds; either may be
+	// left open
+	StartDate  string
+	EndDate    string
+	ByCategory bool
+	ByMonth    bool
+}
+
+// ExpenseTotal is what one user paid and owes in one currency, within one
+// category and month when the query breaks totals down by them
+type ExpenseTotal struct {
+	UserID   string `json:"user_id"`
+	Category string `json:"category,omitempty"`
+	// Month is formatted YYYY-MM
+	Month string `json:"month,omitempty"`
+	Paid  Money  `json:"paid"`
+	Owed  Money  `json:"owed"`
+	// ExpensesPaid counts the expenses the user paid for
+	ExpensesPaid int `json:"expenses_paid"`
+}
+
+// TotalMonthLayout formats ExpenseTotal.Month
+const TotalMonthLayout = "2006-01"
@@ -5,7 +5,13 @@ import (
 	"time"
 )
 
-var ErrEmailAlreadyExists = errors.New("email already exists")
+var (
+	ErrEmailAlreadyExists = errors.New("email already exists")
+	ErrUserDeactivated    = errors.New("user is deactivated")
+	ErrUserHasBalance     = errors.New("user has an outstanding balance; settle up first")
+	ErrUserHasHistory     = errors.New("user is part of recorded expenses or payments; deactivate or merge them instead")
+	ErrNotGuest           = errors.New("user is not a guest")
+)
 
 type User struct {
 	ID        string    `json:"id"`
@@ -13,12 +19,32 @@ type User struct {
 	Email     string    `json:"email"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// DeactivatedAt is set once the user has left. Their history is kept, but
+	// they can't be part of new expenses.
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	// Guest marks someone without an account, such as a friend who joined one
+	// dinner. Guests have no email and can't sign in, but can pay and share in
+	// expenses like anyone else until they are claimed.
+	Guest bool `json:"guest,omitempty"`
+	// Contact is an optional way to reach a guest, such as a phone number
+	Contact string `json:"contact,omitempty"`
+}
+
+// IsActive reports whether the user may take part in new expenses
+func (u *User) IsActive() bool {
+	return u.DeactivatedAt == nil
 }
 
 func (u *User) Validate() error {
 	if u.Name == "" {
 		return errors.New("user name is required")
 	}
+	if u.Guest {
+		if u.Email != "" {
+			return errors.New("a guest has no email; claim them to add one")
+		}
+		return nil
+	}
 	if u.Email == "" {
 		return errors.New("user email is required")
 	}
@@ -0,0 +1,53 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+type csvWriter struct {
+	w       *csv.Writer
+	written bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteTable(table Table) error {
+	if c.written {
+		return ErrSingleTable
+	}
+	c.written = true
+
+	if err := c.w.Write(table.Columns); err != nil {
+		return err
+	}
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, cell := range row {
+			record[i] = csvCell(cell)
+		}
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvCell formats a cell, quoting text that a spreadsheet would otherwise
+// run as a formula
+func csvCell(cell any) string {
+	text := formatCell(cell)
+	if _, ok := cell.(string); ok && text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
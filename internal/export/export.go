@@ -0,0 +1,98 @@
+// Package export writes tables of records as CSV, NDJSON or XLSX.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// ErrSingleTable is returned when a second table is written in a format that
+// holds only one
+var ErrSingleTable = errors.New("format holds a single table")
+
+// Format is an export file format
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+// ParseFormat accepts a format name; empty means CSV
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatNDJSON, FormatXLSX:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid export format %q: must be csv, ndjson or xlsx", s)
+	}
+}
+
+// ContentType is the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// MultipleTables reports whether one file can hold several tables
+func (f Format) MultipleTables() bool {
+	return f != FormatCSV
+}
+
+// Table is a named set of rows. Cells are strings, ints, domain.Money or
+// time.Time; nil is an empty cell.
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+// Writer writes tables as they are produced. Close must be called to finish
+// the file.
+type Writer interface {
+	WriteTable(table Table) error
+	Close() error
+}
+
+// NewWriter returns a writer for format that writes to w
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w), nil
+	default:
+		return nil, fmt.Errorf("invalid export format %q", format)
+	}
+}
+
+// formatCell renders a cell as text
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case domain.Money:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func testTables() []Table {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []Table{
+		{
+			Name:    "expenses",
+			Columns: []string{"id", "date", "description", "amount"},
+			Rows: [][]any{
+				{"e1", date, "Milk, bread", domain.NewMoney(1050, "INR")},
+				{"e2", date, "=HYPERLINK()", domain.NewMoney(-5, "INR")},
+			},
+		},
+		{
+			Name:    "users",
+			Columns: []string{"id", "name"},
+			Rows:    [][]any{{"u1", "Alice & Bob"}},
+		},
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatCSV, &buf)
+	tables := testTables()
+
+	if err := w.WriteTable(tables[0]); err != nil {
+		t.Fatalf("WriteTable() failed: %v", err)
+	}
+	if err := w.WriteTable(tables[1]); !errors.Is(err, ErrSingleTable) {
+		t.Errorf("Expected ErrSingleTable for a second table, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	want := "id,date,description,amount\n" +
+		"e1,2024-03-01T00:00:00Z,\"Milk, bread\",10.50\n" +
+		"e2,2024-03-01T00:00:00Z,'=HYPERLINK(),-0.05\n"
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatNDJSON, &buf)
+	for _, table := range testTables() {
+		if err := w.WriteTable(table); err != nil {
+			t.Fatalf("WriteTable() failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %s", len(lines), buf.String())
+	}
+	if lines[0] != `{"table":"expenses","id":"e1","date":"2024-03-01T00:00:00Z","description":"Milk, bread","amount":10.50}` {
+		t.Errorf("Unexpected first line: %s", lines[0])
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("Invalid JSON line: %s", line)
+		}
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatXLSX, &buf)
+	for _, table := range testTables() {
+		if err := w.WriteTable(table); err != nil {
+			t.Fatalf("WriteTable() failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Output is not a zip file: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, file := range archive.File {
+		r, _ := file.Open()
+		content, _ := io.ReadAll(r)
+		r.Close()
+		parts[file.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Missing part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="users" sheetId="2" r:id="rId2"/>`) {
+		t.Errorf("Workbook does not list the users sheet: %s", parts["xl/workbook.xml"])
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet1.xml"], `<c r="D2"><v>10.50</v></c>`) {
+		t.Errorf("Expected amount as a number in D2: %s", parts["xl/worksheets/sheet1.xml"])
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet2.xml"], "Alice &amp; Bob") {
+		t.Errorf("Expected escaped text: %s", parts["xl/worksheets/sheet2.xml"])
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// ndjsonWriter writes one JSON object per row, keyed by column, with a
+// "table" key naming the table the row belongs to
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{w: bufio.NewWriter(w)}
+}
+
+func (n *ndjsonWriter) WriteTable(table Table) error {
+	name, err := json.Marshal(table.Name)
+	if err != nil {
+		return err
+	}
+	keys := make([][]byte, len(table.Columns))
+	for i, column := range table.Columns {
+		if keys[i], err = json.Marshal(column); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range table.Rows {
+		// Objects are built by hand to keep keys in column order
+		n.w.WriteString(`{"table":`)
+		n.w.Write(name)
+		for i, cell := range row {
+			value, err := json.Marshal(cell)
+			if err != nil {
+				return err
+			}
+			n.w.WriteByte(',')
+			n.w.Write(keys[i])
+			n.w.WriteByte(':')
+			n.w.Write(value)
+		}
+		if _, err := n.w.WriteString("}\n"); err != nil {
+			return err
+		}
+	}
+	return n.w.Flush()
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.w.Flush()
+}
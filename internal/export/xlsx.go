@@ -0,0 +1,117 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+// xlsxWriter writes an Office Open XML workbook with one worksheet per table.
+// Worksheets are streamed into the zip as they are written; the workbook
+// parts that list them are added on Close.
+type xlsxWriter struct {
+	zip    *zip.Writer
+	sheets []string
+}
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	return &xlsxWriter{zip: zip.NewWriter(w)}
+}
+
+func (x *xlsxWriter) WriteTable(table Table) error {
+	x.sheets = append(x.sheets, table.Name)
+	part, err := x.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(x.sheets)))
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(part)
+
+	w.WriteString(xmlHeader)
+	w.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	header := make([]any, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = column
+	}
+	writeRow(w, 1, header)
+	for i, row := range table.Rows {
+		writeRow(w, i+2, row)
+	}
+	w.WriteString(`</sheetData></worksheet>`)
+	return w.Flush()
+}
+
+func (x *xlsxWriter) Close() error {
+	var sheets, rels, overrides strings.Builder
+	for i, name := range x.sheets {
+		n := i + 1
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(name), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			overrides.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			rels.String() + `</Relationships>`},
+	}
+	for _, p := range parts {
+		part, err := x.zip.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(part, xmlHeader+p.content); err != nil {
+			return err
+		}
+	}
+	return x.zip.Close()
+}
+
+func writeRow(w *bufio.Writer, n int, row []any) {
+	fmt.Fprintf(w, `<row r="%d">`, n)
+	for i, cell := range row {
+		ref := columnName(i) + strconv.Itoa(n)
+		switch v := cell.(type) {
+		case nil:
+			continue
+		case domain.Money:
+			fmt.Fprintf(w, `<c r="%s"><v>%s</v></c>`, ref, v.String())
+		case int, int64:
+			fmt.Fprintf(w, `<c r="%s"><v>%d</v></c>`, ref, v)
+		default:
+			fmt.Fprintf(w, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escapeXML(formatCell(v)))
+		}
+	}
+	w.WriteString(`</row>`)
+}
+
+// columnName turns a zero-based column index into a spreadsheet column, e.g. 27 is AB
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
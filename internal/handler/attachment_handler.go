@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+// multipartOverhead is room for the multipart headers around the file
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	attachmentUC usecase.AttachmentUseCase
+	maxBytes     int64
+	allowedTypes []string
+}
+
+// NewAttachmentHandler accepts uploads up to maxBytes whose sniffed content
+// type is one of allowedTypes
+func NewAttachmentHandler(attachmentUC usecase.AttachmentUseCase, maxBytes int64, allowedTypes []string) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentUC: attachmentUC,
+		maxBytes:     maxBytes,
+		allowedTypes: allowedTypes,
+	}
+}
+
+type AttachmentResponse struct {
+	ID          string    `json:"id"`
+	ExpenseID   string    `json:"expense_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UploadedBy  string    `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UploadAttachment godoc
+// @Summary      Upload an attachment
+// @Description  Attach a file, such as a receipt photo, to an expense. The content type is detected from the file itself
+// @Description  and must be one of the configured types; files over the size limit are rejected with 413.
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        expense_id  query     string  true  "Expense ID"
+// @Param        file        formData  file    true  "File to attach"
+// @Success      201         {object}  AttachmentResponse
+// @Failure      400         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Failure      413         {object}  map[string]string
+// @Failure      415         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /expenses/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	expenseID := r.URL.Query().Get("expense_id")
+	if expenseID == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "expense_id parameter is required")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.RespondWithError(w, http.StatusRequestEntityTooLarge, "file exceeds the "+strconv.FormatInt(h.maxBytes, 10)+" byte limit")
+			return
+		}
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid multipart body")
+		return
+	}
+	defer func() {
+		if err := r.MultipartForm.RemoveAll(); err != nil {
+			log.Printf("failed to remove multipart files: %v", err)
+		}
+	}()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.maxBytes {
+		response.RespondWithError(w, http.StatusRequestEntityTooLarge, "file exceeds the "+strconv.FormatInt(h.maxBytes, 10)+" byte limit")
+		return
+	}
+	if header.Size == 0 {
+		response.RespondWithError(w, http.StatusBadRequest, "file is empty")
+		return
+	}
+
+	// Trust the bytes, not the client's Content-Type
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		response.RespondWithError(w, http.StatusBadRequest, "failed to read file")
+		return
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(sniff[:n]))
+	if !slices.Contains(h.allowedTypes, contentType) {
+		response.RespondWithError(w, http.StatusUnsupportedMediaType, "file type "+contentType+" is not allowed")
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		response.RespondWithError(w, http.StatusInternalServerError, "failed to read file")
+		return
+	}
+
+	attachment, err := h.attachmentUC.UploadAttachment(r.Context(), expenseID, filepath.Base(header.Filename), contentType, header.Size, file)
+	if err != nil {
+		respondWithAttachmentError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToAttachmentResponse(attachment))
+}
+
+// GetAttachments godoc
+// @Summary      List attachments
+// @Description  List the files attached to an expense, oldest first
+// @Tags         attachments
+// @Produce      json
+// @Param        expense_id  query     string  true  "Expense ID"
+// @Success      200         {array}   AttachmentResponse
+// @Failure      400         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /expenses/attachments [get]
+func (h *AttachmentHandler) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	expenseID := r.URL.Query().Get("expense_id")
+	if expenseID == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "expense_id parameter is required")
+		return
+	}
+
+	attachments, err := h.attachmentUC.GetAttachments(r.Context(), expenseID)
+	if err != nil {
+		respondWithAttachmentError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToAttachmentResponses(attachments))
+}
+
+// DownloadAttachment godoc
+// @Summary      Download an attachment
+// @Description  Download the content of an attachment
+// @Tags         attachments
+// @Produce      application/octet-stream
+// @Param        id   query     string  true  "Attachment ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /expenses/attachments [get]
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	attachment, content, err := h.attachmentUC.OpenAttachment(r.Context(), id)
+	if err != nil {
+		respondWithAttachmentError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("failed to send attachment %s: %v", attachment.ID, err)
+	}
+}
+
+// DeleteAttachment godoc
+// @Summary      Delete an attachment
+// @Description  Remove an attachment. Only its uploader or the expense's payer may do so.
+// @Tags         attachments
+// @Param        id   query     string  true  "Attachment ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /expenses/attachments [delete]
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	if err := h.attachmentUC.DeleteAttachment(r.Context(), id); err != nil {
+		respondWithAttachmentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func respondWithAttachmentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		response.RespondWithError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrExpenseNotFound), errors.Is(err, domain.ErrAttachmentNotFound):
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/middleware"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type AuthHandler struct {
+	authUC usecase.AuthUseCase
+	userUC usecase.UserUseCase
+}
+
+func NewAuthHandler(authUC usecase.AuthUseCase, userUC usecase.UserUseCase) *AuthHandler {
+	return &AuthHandler{
+		authUC: authUC,
+		userUC: userUC,
+	}
+}
+
+type RegisterRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+type VerifyMagicLinkRequest struct {
+	Token string `json:"token"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password,omitempty"`
+	NewPassword     string `json:"new_password"`
+}
+
+type SessionResponse struct {
+	Token     string    `json:"token"`
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Register godoc
+// @Summary      Register a new user
+// @Description  Create a user with a password and start a session
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        user  body      RegisterRequest  true  "Registration data"
+// @Success      201   {object}  SessionResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Router       /auth/register [post]
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	session, err := h.authUC.Register(r.Context(), req.Name, req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailAlreadyExists) {
+			response.RespondWithError(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToSessionResponse(session))
+}
+
+// Login godoc
+// @Summary      Log in with a password
+// @Description  Exchange an email and password for a bearer token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      LoginRequest  true  "Login credentials"
+// @Success      200          {object}  SessionResponse
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Router       /auth/login [post]
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	session, err := h.authUC.Login(r.Context(), req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			response.RespondWithError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrUserDeactivated) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToSessionResponse(session))
+}
+
+// RequestMagicLink godoc
+// @Summary      Request a magic login link
+// @Description  Send a single-use login link to the user's email. Always succeeds so emails cannot be probed.
+// @Tags         auth
+// @Accept       json
+// @Param        request  body  MagicLinkRequest  true  "Email to send the link to"
+// @Success      202      "Accepted"
+// @Failure      400      {object}  map[string]string
+// @Router       /auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req MagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.authUC.RequestMagicLink(r.Context(), req.Email); err != nil {
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// VerifyMagicLink godoc
+// @Summary      Log in with a magic link
+// @Description  Exchange a magic link token for a bearer token. Each link works once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      VerifyMagicLinkRequest  true  "Magic link token"
+// @Success      200      {object}  SessionResponse
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Router       /auth/magic-link/verify [post]
+func (h *AuthHandler) VerifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req VerifyMagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	session, err := h.authUC.VerifyMagicLink(r.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) {
+			response.RespondWithError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrUserDeactivated) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToSessionResponse(session))
+}
+
+// Logout godoc
+// @Summary      Log out
+// @Description  Revoke the bearer token used for this request
+// @Tags         auth
+// @Success      204  "No Content"
+// @Failure      401  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	token, ok := middleware.BearerToken(r)
+	if !ok {
+		response.RespondWithError(w, http.StatusUnauthorized, domain.ErrUnauthenticated.Error())
+		return
+	}
+
+	if err := h.authUC.Logout(r.Context(), token); err != nil {
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me godoc
+// @Summary      Get the current user
+// @Description  Retrieve the user the bearer token belongs to
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  UserResponse
+// @Failure      401  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /auth/me [get]
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID, ok := domain.UserIDFromContext(r.Context())
+	if !ok {
+		response.RespondWithError(w, http.StatusUnauthorized, domain.ErrUnauthenticated.Error())
+		return
+	}
+
+	user, err := h.userUC.GetUser(r.Context(), userID)
+	if err != nil {
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToUserResponse(user))
+}
+
+// ChangePassword godoc
+// @Summary      Set or change password
+// @Description  Set a password for the current user. current_password is required once a password exists.
+// @Tags         auth
+// @Accept       json
+// @Param        request  body  ChangePasswordRequest  true  "Passwords"
+// @Success      204      "No Content"
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /auth/password [put]
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID, ok := domain.UserIDFromContext(r.Context())
+	if !ok {
+		response.RespondWithError(w, http.StatusUnauthorized, domain.ErrUnauthenticated.Error())
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.authUC.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			response.RespondWithError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type BudgetHandler struct {
+	budgetUC usecase.BudgetUseCase
+}
+
+func NewBudgetHandler(budgetUC usecase.BudgetUseCase) *BudgetHandler {
+	return &BudgetHandler{
+		budgetUC: budgetUC,
+	}
+}
+
+// BudgetRequest creates a budget or replaces all of its fields
+type BudgetRequest struct {
+	Category string `json:"category" example:"groceries"`
+	// Period is monthly or yearly; empty means monthly
+	Period string       `json:"period,omitempty" example:"monthly"`
+	Amount domain.Money `json:"amount"`
+	// Currency defaults to the group's base currency, else the server's
+	Currency string `json:"currency,omitempty"`
+	// UserID limits the budget to that user's share of the spending
+	UserID string `json:"user_id,omitempty"`
+	// GroupID limits the budget to that group's expenses
+	GroupID string `json:"group_id,omitempty"`
+}
+
+type BudgetResponse struct {
+	ID        string       `json:"id"`
+	Category  string       `json:"category"`
+	Period    string       `json:"period"`
+	Amount    domain.Money `json:"amount"`
+	Currency  string       `json:"currency"`
+	UserID    string       `json:"user_id,omitempty"`
+	GroupID   string       `json:"group_id,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// amount returns the request's amount with its currency
+func (req *BudgetRequest) amount() domain.Money {
+	amount := req.Amount
+	amount.Currency = domain.Currency(req.Currency)
+	return amount
+}
+
+// CreateBudget godoc
+// @Summary      Create a budget
+// @Description  Cap spending on a category and its subcategories per month or year, for the household or one user's share, optionally within a group. Only the group's members, or the user, may create it.
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        budget  body      BudgetRequest  true  "Budget"
+// @Success      201     {object}  BudgetResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      409     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /budgets [post]
+func (h *BudgetHandler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req BudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	budget, err := h.budgetUC.CreateBudget(r.Context(), req.Category, req.Period, req.amount(), req.UserID, req.GroupID)
+	if err != nil {
+		respondWithBudgetError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToBudgetResponse(budget))
+}
+
+// GetBudgets godoc
+// @Summary      List budgets
+// @Description  List the budgets of your groups, your own, and those outside any group or user, by category, or get one by ID
+// @Tags         budgets
+// @Produce      json
+// @Param        id   query     string  false  "Budget ID"
+// @Success      200  {array}   BudgetResponse
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /budgets [get]
+func (h *BudgetHandler) GetBudgets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if id := r.URL.Query().Get("id"); id != "" {
+		budget, err := h.budgetUC.GetBudget(r.Context(), id)
+		if err != nil {
+			respondWithBudgetError(w, err)
+			return
+		}
+		response.RespondWithJSON(w, http.StatusOK, ToBudgetResponse(budget))
+		return
+	}
+
+	budgets, err := h.budgetUC.ListBudgets(r.Context())
+	if err != nil {
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	responses := make([]BudgetResponse, len(budgets))
+	for i, budget := range budgets {
+		responses[i] = ToBudgetResponse(budget)
+	}
+	response.RespondWithJSON(w, http.StatusOK, responses)
+}
+
+// UpdateBudget godoc
+// @Summary      Update a budget
+// @Description  Replace every field of a budget. Only the group's members, or the user, may change it.
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        id      query     string         true  "Budget ID"
+// @Param        budget  body      BudgetRequest  true  "Budget"
+// @Success      200     {object}  BudgetResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      409     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /budgets [put]
+func (h *BudgetHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "Budget ID is required")
+		return
+	}
+
+	var req BudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	budget, err := h.budgetUC.UpdateBudget(r.Context(), id, req.Category, req.Period, req.amount(), req.UserID, req.GroupID)
+	if err != nil {
+		respondWithBudgetError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToBudgetResponse(budget))
+}
+
+// DeleteBudget godoc
+// @Summary      Delete a budget
+// @Description  Delete a budget. Only the group's members, or the user, may delete it. Alerts it raised are kept.
+// @Tags         budgets
+// @Param        id   query     string  true  "Budget ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /budgets [delete]
+func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "Budget ID is required")
+		return
+	}
+
+	if err := h.budgetUC.DeleteBudget(r.Context(), id); err != nil {
+		respondWithBudgetError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBudgetStatus godoc
+// @Summary      Get budget status
+// @Description  Spent against every budget you can see, and each category's own monthly budget, in the period containing a month: spending counts up to the end of the month or today, with the per-user breakdown and the spend projected to the end of the period at the daily run rate. Budgets outside a group and user count just the expenses you take part in.
+// @Tags         budgets
+// @Produce      json
+// @Param        year   query     int  false  "Year, defaults to this year"
+// @Param        month  query     int  false  "Month (1-12), defaults to this month"
+// @Success      200    {object}  domain.BudgetReport
+// @Failure      400    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /budgets/status [get]
+func (h *BudgetHandler) GetBudgetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	year, month, err := parseYearMonth(r)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.budgetUC.GetBudgetStatus(r.Context(), year, month)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, report)
+}
+
+// GetBudgetAlerts godoc
+// @Summary      List budget alerts
+// @Description  List the alerts raised in a month on your groups' budgets and your own, newest first. An alert is raised when a new expense takes spending past one of the configured percentages of a budget.
+// @Tags         budgets
+// @Produce      json
+// @Param        year   query     int  false  "Year, defaults to this year"
+// @Param        month  query     int  false  "Month (1-12), defaults to this month"
+// @Success      200    {array}   domain.BudgetAlert
+// @Failure      400    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /budgets/alerts [get]
+func (h *BudgetHandler) GetBudgetAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	year, month, err := parseYearMonth(r)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	alerts, err := h.budgetUC.GetBudgetAlerts(r.Context(), year, month)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if alerts == nil {
+		alerts = []*domain.BudgetAlert{}
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, alerts)
+}
+
+// parseYearMonth reads the year and month query parameters, each defaulting
+// to the current one
+func parseYearMonth(r *http.Request) (year, month int, err error) {
+	now := time.Now()
+	year, month = now.Year(), int(now.Month())
+
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		if _, err := fmt.Sscanf(yearStr, "%d", &year); err != nil {
+			return 0, 0, errors.New("invalid year format")
+		}
+	}
+	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
+		if _, err := fmt.Sscanf(monthStr, "%d", &month); err != nil {
+			return 0, 0, errors.New("invalid month format")
+		}
+	}
+	return year, month, nil
+}
+
+// respondWithBudgetError maps budget errors to status codes. A missing
+// category, user or group is a bad request, not a missing budget.
+func respondWithBudgetError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		response.RespondWithError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrBudgetExists):
+		response.RespondWithError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrBudgetNotFound):
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type CategoryHandler struct {
+	categoryUC usecase.CategoryUseCase
+}
+
+func NewCategoryHandler(categoryUC usecase.CategoryUseCase) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUC: categoryUC,
+	}
+}
+
+// CategoryRequest creates a category or replaces all of its fields
+type CategoryRequest struct {
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id,omitempty"`
+	Icon     string `json:"icon,omitempty" example:"🛒"`
+	Color    string `json:"color,omitempty" example:"#4caf50"`
+	// MonthlyBudget is left out for no budget; Currency defaults to the
+	// server's base currency
+	MonthlyBudget *domain.Money `json:"monthly_budget,omitempty"`
+	Currency      string        `json:"currency,omitempty"`
+}
+
+type CategoryResponse struct {
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	ParentID      string        `json:"parent_id,omitempty"`
+	Icon          string        `json:"icon,omitempty"`
+	Color         string        `json:"color,omitempty"`
+	MonthlyBudget *domain.Money `json:"monthly_budget,omitempty"`
+	Currency      string        `json:"currency,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// budget returns the request's budget with its currency, or nil
+func (req *CategoryRequest) budget() *domain.Money {
+	if req.MonthlyBudget == nil {
+		return nil
+	}
+	budget := *req.MonthlyBudget
+	budget.Currency = domain.Currency(req.Currency)
+	return &budget
+}
+
+// CreateCategory godoc
+// @Summary      Create a category
+// @Description  Create an expense category, optionally under a top-level parent and with a monthly budget. Names are stored lowercase.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        category  body      CategoryRequest  true  "Category"
+// @Success      201       {object}  CategoryResponse
+// @Failure      400       {object}  map[string]string
+// @Failure      409       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /categories [post]
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	category, err := h.categoryUC.CreateCategory(r.Context(), req.Name, req.ParentID, req.Icon, req.Color, req.budget())
+	if err != nil {
+		respondWithCategoryError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToCategoryResponse(category))
+}
+
+// GetCategories godoc
+// @Summary      List categories
+// @Description  List all categories by name, or get one by ID
+// @Tags         categories
+// @Produce      json
+// @Param        id   query     string  false  "Category ID"
+// @Success      200  {array}   CategoryResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /categories [get]
+func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if id := r.URL.Query().Get("id"); id != "" {
+		category, err := h.categoryUC.GetCategory(r.Context(), id)
+		if err != nil {
+			respondWithCategoryError(w, err)
+			return
+		}
+		response.RespondWithJSON(w, http.StatusOK, ToCategoryResponse(category))
+		return
+	}
+
+	categories, err := h.categoryUC.ListCategories(r.Context())
+	if err != nil {
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	responses := make([]CategoryResponse, len(categories))
+	for i, category := range categories {
+		responses[i] = ToCategoryResponse(category)
+	}
+	response.RespondWithJSON(w, http.StatusOK, responses)
+}
+
+// UpdateCategory godoc
+// @Summary      Update a category
+// @Description  Replace every field of a category. Expenses in it follow a rename.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id        query     string           true  "Category ID"
+// @Param        category  body      CategoryRequest  true  "Category"
+// @Success      200       {object}  CategoryResponse
+// @Failure      400       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      409       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /categories [put]
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "Category ID is required")
+		return
+	}
+
+	var req CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	category, err := h.categoryUC.UpdateCategory(r.Context(), id, req.Name, req.ParentID, req.Icon, req.Color, req.budget())
+	if err != nil {
+		respondWithCategoryError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToCategoryResponse(category))
+}
+
+// DeleteCategory godoc
+// @Summary      Delete a category
+// @Description  Delete a category that no expense, recurring expense or subcategory uses
+// @Tags         categories
+// @Param        id   query     string  true  "Category ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /categories [delete]
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "Category ID is required")
+		return
+	}
+
+	if err := h.categoryUC.DeleteCategory(r.Context(), id); err != nil {
+		respondWithCategoryError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondWithCategoryError maps category errors to status codes. A missing
+// parent is a bad request, not a missing category.
+func respondWithCategoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCategoryExists), errors.Is(err, domain.ErrCategoryInUse):
+		response.RespondWithError(w, http.StatusConflict, err.Error())
+	case err == domain.ErrCategoryNotFound:
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+	}
+}
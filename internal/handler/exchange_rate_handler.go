@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type ExchangeRateHandler struct {
+	rateUC usecase.ExchangeRateUseCase
+}
+
+func NewExchangeRateHandler(rateUC usecase.ExchangeRateUseCase) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		rateUC: rateUC,
+	}
+}
+
+type ExchangeRateRequest struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Rate          string `json:"rate"`
+	EffectiveDate string `json:"effective_date"`
+}
+
+type ExchangeRateResponse struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Rate          string `json:"rate"`
+	EffectiveDate string `json:"effective_date"`
+}
+
+// AddExchangeRate godoc
+// @Summary      Record an exchange rate
+// @Description  Record the rate for a currency pair effective from a date (YYYY-MM-DD). Rate is a decimal string, e.g. "89.95".
+// @Tags         exchange-rates
+// @Accept       json
+// @Produce      json
+// @Param        rate  body      ExchangeRateRequest  true  "Exchange rate"
+// @Success      201   {object}  ExchangeRateResponse
+// @Failure      400   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /exchange-rates [post]
+func (h *ExchangeRateHandler) AddExchangeRate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req ExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rate, err := h.rateUC.AddRate(r.Context(), req.From, req.To, req.Rate, req.EffectiveDate)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToExchangeRateResponse(rate))
+}
+
+// GetExchangeRates godoc
+// @Summary      Get exchange rate history
+// @Description  Retrieve every recorded rate for a currency pair, newest first
+// @Tags         exchange-rates
+// @Produce      json
+// @Param        from  query     string  true  "Source currency (ISO 4217)"
+// @Param        to    query     string  true  "Target currency (ISO 4217)"
+// @Success      200   {array}   ExchangeRateResponse
+// @Failure      400   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /exchange-rates [get]
+func (h *ExchangeRateHandler) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "from and to parameters are required")
+		return
+	}
+
+	rates, err := h.rateUC.GetRates(r.Context(), from, to)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToExchangeRateResponses(rates))
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -20,41 +21,104 @@ func NewExpenseHandler(expenseUc usecase.ExpenseUseCase) *ExpenseHandler {
 }
 
 type ExpenseRequest struct {
-	Description string         `json:"description"`
-	Amount      float64        `json:"amount"`
-	Category    string         `json:"category"`
-	PaidBy      string         `json:"paid_by"`
-	Splits      []SplitRequest `json:"splits"`
+	GroupID     string       `json:"group_id,omitempty"`
+	Description string       `json:"description"`
+	Amount      domain.Money `json:"amount"`
+	Currency    string       `json:"currency,omitempty"`
+	// Category names an existing category in any case; empty means "other"
+	Category string `json:"category"`
+	PaidBy   string `json:"paid_by"`
+	// Date is RFC 3339 or YYYY-MM-DD, read in the group's time zone when it
+	// has no offset; it defaults to now
+	Date      string         `json:"date,omitempty" example:"2024-03-15T19:30:00+05:30"`
+	SplitMode string         `json:"split_mode,omitempty" enums:"exact,equal,percentage,shares,itemised"`
+	Splits    []SplitRequest `json:"splits,omitempty"`
+	UserIDs   []string       `json:"user_ids,omitempty"`
+	Shares    []ShareRequest `json:"shares,omitempty"`
+	Items     []ItemRequest  `json:"items,omitempty"`
+	// LineItems is an itemised receipt to split itemised, given instead of
+	// items; splits are derived from who each line is assigned to
+	LineItems []LineItemRequest `json:"line_items,omitempty"`
 }
 type SplitRequest struct {
-	UserId string  `json:"user_id"`
-	Amount float64 `json:"amount"`
+	UserId string       `json:"user_id"`
+	Amount domain.Money `json:"amount"`
+}
+
+// ShareRequest is a user's percentage, or number of shares up to 1,000,000, to
+// two decimal places
+type ShareRequest struct {
+	UserID string  `json:"user_id"`
+	Value  float64 `json:"value" example:"50"`
+}
+
+type ItemRequest struct {
+	Description string       `json:"description"`
+	Amount      domain.Money `json:"amount"`
+	UserIDs     []string     `json:"user_ids"`
+}
+
+// LineItemRequest is one receipt line. Quantity defaults to 1 and kind to
+// item; tax and tip lines are shared in proportion to each user's items.
+type LineItemRequest struct {
+	Name     string       `json:"name"`
+	Quantity int          `json:"quantity,omitempty" example:"1"`
+	Price    domain.Money `json:"price"`
+	Kind     string       `json:"kind,omitempty" enums:"item,tax,tip"`
+	UserIDs  []string     `json:"user_ids,omitempty"`
 }
 
 type ExpenseResponse struct {
-	ID          string          `json:"id"`
-	Description string          `json:"description"`
-	Amount      float64         `json:"amount"`
-	Category    string          `json:"category"`
-	PaidBy      string          `json:"paid_by"`
-	Date        time.Time       `json:"date"`
-	CreatedAt   time.Time       `json:"created_at"`
-	Splits      []SplitResponse `json:"splits"`
+	ID          string             `json:"id"`
+	GroupID     string             `json:"group_id,omitempty"`
+	Description string             `json:"description"`
+	Amount      domain.Money       `json:"amount"`
+	Currency    string             `json:"currency"`
+	Category    string             `json:"category"`
+	PaidBy      string             `json:"paid_by"`
+	Date        time.Time          `json:"date"`
+	CreatedAt   time.Time          `json:"created_at"`
+	DeletedAt   *time.Time         `json:"deleted_at,omitempty"`
+	Splits      []SplitResponse    `json:"splits"`
+	LineItems   []LineItemResponse `json:"line_items,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	// RecurringID and OccurrenceDate are set on an expense created by a
+	// recurring expense
+	RecurringID    string `json:"recurring_id,omitempty"`
+	OccurrenceDate string `json:"occurrence_date,omitempty"`
 }
 type SplitResponse struct {
-	UserId string  `json:"user_id"`
-	Amount float64 `json:"amount"`
+	UserId string       `json:"user_id"`
+	Amount domain.Money `json:"amount"`
+}
+
+type LineItemResponse struct {
+	Name     string       `json:"name"`
+	Quantity int          `json:"quantity"`
+	Price    domain.Money `json:"price"`
+	Total    domain.Money `json:"total"`
+	Kind     string       `json:"kind"`
+	UserIDs  []string     `json:"user_ids,omitempty"`
 }
 
 // CreateExpense godoc
 // @Summary      Create a new expense
-// @Description  Create a new expense with custom splits
+// @Description  Create a new expense. Currency defaults to the group's base currency.
+// @Description  split_mode picks how the amount is divided: exact (default) takes splits as given, equal splits it between user_ids,
+// @Description  percentage and shares divide it by each entry in shares, and itemised charges each item to its user_ids and splits the rest between user_ids.
+// @Description  Rounding is to the cent; leftover cents go to users in the order given.
+// @Description  An itemised split may give line_items instead of items and user_ids: a receipt that must add up to amount, with splits derived from who each line is assigned to.
+// @Description  split_mode defaults to itemised when line_items are given, and line_items cannot be combined with items, splits, shares or user_ids.
+// @Description  date backdates the expense: RFC 3339, or YYYY-MM-DD read in the group's time zone. It may be up to 10 years in the past and defaults to now.
+// @Description  You must be the payer or one of the users it is split between.
 // @Tags         expenses
 // @Accept       json
 // @Produce      json
 // @Param        expense  body      ExpenseRequest  true  "Expense data"
 // @Success      201      {object}  ExpenseResponse
 // @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /expenses [post]
 func (h *ExpenseHandler) CreateExpense(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -69,15 +133,19 @@ func (h *ExpenseHandler) CreateExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	splits := make([]domain.Split, len(req.Splits))
-	for i, split := range req.Splits {
-		splits[i] = domain.Split{
-			UserID: split.UserId,
-			Amount: split.Amount,
-		}
+	req.Amount.Currency = domain.Currency(req.Currency)
+	spec, err := toSplitSpec(req)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	expense, err := h.expenseUc.CreateExpense(r.Context(), req.Description, req.Category, req.PaidBy, req.Amount, splits)
+
+	expense, err := h.expenseUc.CreateSplitExpense(r.Context(), req.GroupID, req.Description, req.Category, req.PaidBy, req.Amount, req.Date, spec)
 	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -85,23 +153,85 @@ func (h *ExpenseHandler) CreateExpense(w http.ResponseWriter, r *http.Request) {
 	response.RespondWithJSON(w, http.StatusCreated, ToExpenseResponse(expense))
 }
 
+// toSplitSpec reads the split fields of req for its split mode. A receipt
+// is split itemised, and on its own.
+func toSplitSpec(req ExpenseRequest) (domain.SplitSpec, error) {
+	if len(req.LineItems) > 0 {
+		if len(req.Items) > 0 || len(req.Splits) > 0 || len(req.Shares) > 0 || len(req.UserIDs) > 0 {
+			return domain.SplitSpec{}, errors.New("give either line_items or items, splits, shares and user_ids, not both")
+		}
+		if req.SplitMode == "" {
+			req.SplitMode = string(domain.SplitItemised)
+		}
+	}
+
+	mode, err := domain.ParseSplitMode(req.SplitMode)
+	if err != nil {
+		return domain.SplitSpec{}, err
+	}
+
+	spec := domain.SplitSpec{Mode: mode, UserIDs: req.UserIDs}
+	for _, split := range req.Splits {
+		spec.Splits = append(spec.Splits, domain.Split{UserID: split.UserId, Amount: split.Amount})
+	}
+	for _, share := range req.Shares {
+		weight, err := domain.NewSplitWeight(share.UserID, share.Value)
+		if err != nil {
+			return domain.SplitSpec{}, err
+		}
+		spec.Weights = append(spec.Weights, weight)
+	}
+	for _, item := range req.Items {
+		spec.Items = append(spec.Items, domain.SplitItem{Description: item.Description, Amount: item.Amount, UserIDs: item.UserIDs})
+	}
+	if len(req.LineItems) > 0 {
+		spec.Receipt = toExpenseItems(req.LineItems)
+	}
+	return spec, nil
+}
+
+func toExpenseItems(lines []LineItemRequest) []domain.ExpenseItem {
+	items := make([]domain.ExpenseItem, len(lines))
+	for i, line := range lines {
+		items[i] = domain.ExpenseItem{
+			Name:     line.Name,
+			Quantity: line.Quantity,
+			Price:    line.Price,
+			Kind:     domain.ItemKind(line.Kind),
+			UserIDs:  line.UserIDs,
+		}
+		if items[i].Quantity == 0 {
+			items[i].Quantity = 1
+		}
+		if items[i].Kind == "" {
+			items[i].Kind = domain.ItemProduct
+		}
+	}
+	return items
+}
+
 type EqualSplitRequest struct {
-	Description string   `json:"description"`
-	Amount      float64  `json:"amount"`
-	Category    string   `json:"category"`
-	PaidBy      string   `json:"paid_by"`
-	UserIDs     []string `json:"user_ids"`
+	GroupID     string       `json:"group_id,omitempty"`
+	Description string       `json:"description"`
+	Amount      domain.Money `json:"amount"`
+	Currency    string       `json:"currency,omitempty"`
+	Category    string       `json:"category"`
+	PaidBy      string       `json:"paid_by"`
+	Date        string       `json:"date,omitempty" example:"2024-03-15"`
+	UserIDs     []string     `json:"user_ids"`
 }
 
 // CreateExpenseWithEqualSplit godoc
 // @Summary      Create expense with equal split
-// @Description  Create a new expense with equal splits among specified users
+// @Description  Create a new expense with equal splits among specified users. You must be the payer or one of the users.
 // @Tags         expenses
 // @Accept       json
 // @Produce      json
 // @Param        expense  body      EqualSplitRequest  true  "Equal split expense data"
 // @Success      201      {object}  ExpenseResponse
 // @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /expenses/equal-split [post]
 func (h *ExpenseHandler) CreateExpenseWithEqualSplit(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -116,8 +246,13 @@ func (h *ExpenseHandler) CreateExpenseWithEqualSplit(w http.ResponseWriter, r *h
 		return
 	}
 
-	expense, err := h.expenseUc.CreateExpenseWithEqualSplit(r.Context(), req.Description, req.Category, req.PaidBy, req.Amount, req.UserIDs)
+	req.Amount.Currency = domain.Currency(req.Currency)
+	expense, err := h.expenseUc.CreateExpenseWithEqualSplit(r.Context(), req.GroupID, req.Description, req.Category, req.PaidBy, req.Amount, req.Date, req.UserIDs)
 	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -125,16 +260,35 @@ func (h *ExpenseHandler) CreateExpenseWithEqualSplit(w http.ResponseWriter, r *h
 	response.RespondWithJSON(w, http.StatusCreated, ToExpenseResponse(expense))
 }
 
+// ExpenseListResponse is one page of expenses. Pass next_cursor back as
+// cursor to get the next page; it is omitted on the last page.
+type ExpenseListResponse struct {
+	Data       []ExpenseResponse `json:"data"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// defaultExpenseSort lists the newest expenses first
+var defaultExpenseSort = domain.Sort{Field: domain.SortByDate, Order: domain.SortDesc}
+
 // GetAllExpenses godoc
 // @Summary      Get all expenses
-// @Description  Retrieve all expenses with optional filters (category, date range)
+// @Description  Retrieve a page of expenses with optional filters. q takes a search query such as
+// @Description  `pizza category:food,drinks paid_by:<id> participant:<id> amount:10..50 date:>=2024-01-01 tag:trip has:attachment`;
+// @Description  see the README for the full syntax. The other filter parameters are combined with it.
 // @Tags         expenses
 // @Produce      json
+// @Param        q           query     string  false  "Search query"
+// @Param        group_id    query     string  false  "Filter by group"
 // @Param        category    query     string  false  "Filter by category"
 // @Param        start_date  query     string  false  "Start date (YYYY-MM-DD)"
 // @Param        end_date    query     string  false  "End date (YYYY-MM-DD)"
-// @Success      200         {array}   ExpenseResponse
+// @Param        limit       query     int     false  "Page size (max 200)"  default(50)
+// @Param        cursor      query     string  false  "next_cursor of the previous page"
+// @Param        sort        query     string  false  "date, amount or created_at, optionally with :asc or :desc"  default(date:desc)
+// @Success      200         {object}  ExpenseListResponse
 // @Failure      400         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /expenses [get]
 func (h *ExpenseHandler) GetAllExpenses(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -142,36 +296,69 @@ func (h *ExpenseHandler) GetAllExpenses(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Check for filter query parameters
-	category := r.URL.Query().Get("category")
-	startDate := r.URL.Query().Get("start_date")
-	endDate := r.URL.Query().Get("end_date")
-
-	var expenses []*domain.Expense
-	var err error
+	page, err := parsePageRequest(r, domain.ExpenseSortFields, defaultExpenseSort)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	// Use filters if any are provided
-	if category != "" || startDate != "" || endDate != "" {
-		expenses, err = h.expenseUc.GetExpensesByFilters(r.Context(), category, startDate, endDate)
-	} else {
-		expenses, err = h.expenseUc.GetAllExpenses(r.Context())
+	filter, err := expenseFilterFromQuery(r)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
+	result, err := h.expenseUc.ListExpenses(r.Context(), filter, page)
 	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	response.RespondWithJSON(w, http.StatusOK, ToExpenseResponses(expenses))
+	response.RespondWithJSON(w, http.StatusOK, ToExpenseListResponse(result))
+}
+
+// expenseFilterFromQuery parses the q search query and adds the standalone
+// filter parameters to it
+func expenseFilterFromQuery(r *http.Request) (domain.ExpenseFilter, error) {
+	query := r.URL.Query()
+	filter, err := domain.ParseExpenseQuery(query.Get("q"))
+	if err != nil {
+		return filter, err
+	}
+
+	if groupID := query.Get("group_id"); groupID != "" {
+		filter.GroupID = groupID
+	}
+	if category := query.Get("category"); category != "" {
+		filter.Categories = append(filter.Categories, category)
+	}
+	if startDate := query.Get("start_date"); startDate != "" {
+		filter.StartDate = startDate
+	}
+	if endDate := query.Get("end_date"); endDate != "" {
+		filter.EndDate = endDate
+	}
+	return filter, nil
 }
 
 // GetBalances godoc
 // @Summary      Get all balances
-// @Description  Calculate and retrieve balances between all users
+// @Description  Calculate and retrieve balances across the expenses and payments you take part in, or between the members of a group you belong to.
+// @Description  Amounts are converted to the group's base currency at the rate in effect on each expense date.
 // @Tags         balances
 // @Produce      json
-// @Success      200  {array}   domain.Balance
-// @Failure      500  {object}  map[string]string
+// @Param        group_id  query     string  false  "Group ID"
+// @Param        strategy  query     string  false  "Settlement strategy"  Enums(minimal, greedy, shared-only)  default(minimal)
+// @Success      200       {object}  domain.BalanceSummary
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /balances [get]
 func (h *ExpenseHandler) GetBalances(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -179,7 +366,28 @@ func (h *ExpenseHandler) GetBalances(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	balances, err := h.expenseUc.CalculateBalances(r.Context())
+	strategy, err := domain.ParseSettlementStrategy(r.URL.Query().Get("strategy"))
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	groupID := r.URL.Query().Get("group_id")
+	if groupID != "" {
+		balances, err := h.expenseUc.CalculateGroupBalances(r.Context(), groupID, strategy)
+		if err != nil {
+			if errors.Is(err, domain.ErrForbidden) {
+				response.RespondWithError(w, http.StatusForbidden, err.Error())
+				return
+			}
+			response.RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		response.RespondWithJSON(w, http.StatusOK, balances)
+		return
+	}
+
+	balances, err := h.expenseUc.CalculateBalances(r.Context(), strategy)
 	if err != nil {
 		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -188,6 +396,46 @@ func (h *ExpenseHandler) GetBalances(w http.ResponseWriter, r *http.Request) {
 	response.RespondWithJSON(w, http.StatusOK, balances)
 }
 
+// GetPairwiseBalances godoc
+// @Summary      Get pairwise balances
+// @Description  Break a user's balance down by counterparty, listing the expenses and payments behind each amount.
+// @Description  Across all expenses you can only see your own; within a group you belong to, any member's.
+// @Description  Positive amounts are owed to the user; negative amounts are owed by the user.
+// @Tags         balances
+// @Produce      json
+// @Param        user_id   query     string  true   "User ID"
+// @Param        group_id  query     string  false  "Group ID"
+// @Success      200       {object}  domain.PairwiseLedger
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /balances/pairwise [get]
+func (h *ExpenseHandler) GetPairwiseBalances(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "user_id parameter is required")
+		return
+	}
+
+	ledger, err := h.expenseUc.GetPairwiseBalances(r.Context(), userID, r.URL.Query().Get("group_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotGroupMember) || errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ledger)
+}
+
 func (h *ExpenseHandler) GetExpenseByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -202,6 +450,10 @@ func (h *ExpenseHandler) GetExpenseByID(w http.ResponseWriter, r *http.Request)
 
 	expense, err := h.expenseUc.GetExpense(r.Context(), id)
 	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusNotFound, err.Error())
 		return
 	}
@@ -211,13 +463,17 @@ func (h *ExpenseHandler) GetExpenseByID(w http.ResponseWriter, r *http.Request)
 
 // GetExpenseByUser godoc
 // @Summary      Get expenses by user
-// @Description  Retrieve all expenses for a specific user
+// @Description  Retrieve a page of the expenses a user paid for or has a split in
 // @Tags         expenses
 // @Produce      json
-// @Param        user_id  query     string  true  "User ID"
-// @Success      200      {array}   ExpenseResponse
+// @Param        user_id  query     string  true   "User ID"
+// @Param        limit    query     int     false  "Page size (max 200)"  default(50)
+// @Param        cursor   query     string  false  "next_cursor of the previous page"
+// @Param        sort     query     string  false  "date, amount or created_at, optionally with :asc or :desc"  default(date:desc)
+// @Success      200      {object}  ExpenseListResponse
 // @Failure      400      {object}  map[string]string
 // @Failure      404      {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /expenses/user [get]
 func (h *ExpenseHandler) GetExpenseByUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -231,18 +487,28 @@ func (h *ExpenseHandler) GetExpenseByUser(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	expenses, err := h.expenseUc.GetExpensesByUser(r.Context(), userID)
+	page, err := parsePageRequest(r, domain.ExpenseSortFields, defaultExpenseSort)
 	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.expenseUc.ListExpenses(r.Context(), domain.ExpenseFilter{UserID: userID}, page)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			response.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusNotFound, err.Error())
 		return
 	}
 
-	response.RespondWithJSON(w, http.StatusOK, ToExpenseResponses(expenses))
+	response.RespondWithJSON(w, http.StatusOK, ToExpenseListResponse(result))
 }
 
 // UpdateExpense godoc
 // @Summary      Update an expense
-// @Description  Update an existing expense by ID
+// @Description  Update an existing expense by ID. New splits replace any line_items the expense was created from. A date moves the expense to that day.
 // @Tags         expenses
 // @Accept       json
 // @Produce      json
@@ -250,7 +516,9 @@ func (h *ExpenseHandler) GetExpenseByUser(w http.ResponseWriter, r *http.Request
 // @Param        expense  body      ExpenseRequest  true  "Updated expense data"
 // @Success      200      {object}  ExpenseResponse
 // @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
 // @Failure      404      {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /expenses [put]
 func (h *ExpenseHandler) UpdateExpense(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
@@ -270,7 +538,12 @@ func (h *ExpenseHandler) UpdateExpense(w http.ResponseWriter, r *http.Request) {
 		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if req.SplitMode != "" && domain.SplitMode(req.SplitMode) != domain.SplitExact {
+		response.RespondWithError(w, http.StatusBadRequest, "updates take exact splits only")
+		return
+	}
 
+	req.Amount.Currency = domain.Currency(req.Currency)
 	splits := make([]domain.Split, len(req.Splits))
 	for i, split := range req.Splits {
 		splits[i] = domain.Split{
@@ -279,9 +552,66 @@ func (h *ExpenseHandler) UpdateExpense(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	expense, err := h.expenseUc.UpdateExpense(r.Context(), id, req.Description, req.Category, req.Amount, splits)
+	expense, err := h.expenseUc.UpdateExpense(r.Context(), id, req.Description, req.Category, req.Amount, req.Date, splits)
 	if err != nil {
-		if err.Error() == "expense not found" {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrExpenseNotFound) {
+			response.RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToExpenseResponse(expense))
+}
+
+type ExpenseTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// SetExpenseTags godoc
+// @Summary      Set an expense's tags
+// @Description  Replace the tags of an expense. Tags are lowercased, deduplicated and may contain letters, digits, '-' and '_'.
+// @Tags         expenses
+// @Accept       json
+// @Produce      json
+// @Param        id    query     string              true  "Expense ID"
+// @Param        tags  body      ExpenseTagsRequest  true  "New tags"
+// @Success      200   {object}  ExpenseResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /expenses/tags [put]
+func (h *ExpenseHandler) SetExpenseTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	var req ExpenseTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	expense, err := h.expenseUc.SetExpenseTags(r.Context(), id, req.Tags)
+	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrExpenseNotFound) {
 			response.RespondWithError(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -294,12 +624,15 @@ func (h *ExpenseHandler) UpdateExpense(w http.ResponseWriter, r *http.Request) {
 
 // DeleteExpense godoc
 // @Summary      Delete an expense
-// @Description  Delete an expense by ID
+// @Description  Move an expense to the trash. It can be restored until the purge job removes it.
 // @Tags         expenses
 // @Param        id   query     string  true  "Expense ID"
 // @Success      204  "No Content"
 // @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /expenses [delete]
 func (h *ExpenseHandler) DeleteExpense(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
@@ -315,21 +648,135 @@ func (h *ExpenseHandler) DeleteExpense(w http.ResponseWriter, r *http.Request) {
 
 	err := h.expenseUc.DeleteExpense(r.Context(), id)
 	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrExpenseNotFound) {
+			response.RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// GetDeletedExpenses godoc
+// @Summary      List deleted expenses
+// @Description  List expenses in the trash, most recently deleted first
+// @Tags         expenses
+// @Produce      json
+// @Success      200  {array}   ExpenseResponse
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /expenses/trash [get]
+func (h *ExpenseHandler) GetDeletedExpenses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	expenses, err := h.expenseUc.GetDeletedExpenses(r.Context())
+	if err != nil {
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToExpenseResponses(expenses))
+}
+
+// RestoreExpense godoc
+// @Summary      Restore a deleted expense
+// @Description  Take an expense back out of the trash
+// @Tags         expenses
+// @Produce      json
+// @Param        id   query     string  true  "Expense ID"
+// @Success      200  {object}  ExpenseResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /expenses/restore [post]
+func (h *ExpenseHandler) RestoreExpense(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	expense, err := h.expenseUc.RestoreExpense(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrExpenseNotFound) {
+			response.RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToExpenseResponse(expense))
+}
+
+// GetExpenseHistory godoc
+// @Summary      Get expense history
+// @Description  List every change made to an expense, oldest first, with who made it and the expense before and after.
+// @Description  History stays available after the expense is deleted.
+// @Tags         expenses
+// @Produce      json
+// @Param        id   query     string  true  "Expense ID"
+// @Success      200  {array}   domain.ExpenseEvent
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /expenses/history [get]
+func (h *ExpenseHandler) GetExpenseHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	events, err := h.expenseUc.GetExpenseHistory(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, events)
+}
+
 // GetUserStats godoc
 // @Summary      Get user statistics
-// @Description  Get spending statistics for a specific user
+// @Description  Get your own spending statistics, or those of a member within a group you belong to
 // @Tags         statistics
 // @Produce      json
-// @Param        user_id  query     string  true  "User ID"
-// @Success      200      {object}  domain.UserStats
-// @Failure      400      {object}  map[string]string
-// @Failure      404      {object}  map[string]string
+// @Param        user_id   query     string  true   "User ID"
+// @Param        group_id  query     string  false  "Group ID"
+// @Success      200       {object}  domain.UserStats
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /users/stats [get]
 func (h *ExpenseHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -343,8 +790,18 @@ func (h *ExpenseHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stats, err := h.expenseUc.GetUserStats(r.Context(), userID)
+	var stats *domain.UserStats
+	var err error
+	if groupID := r.URL.Query().Get("group_id"); groupID != "" {
+		stats, err = h.expenseUc.GetGroupUserStats(r.Context(), groupID, userID)
+	} else {
+		stats, err = h.expenseUc.GetUserStats(r.Context(), userID)
+	}
 	if err != nil {
+		if errors.Is(err, domain.ErrNotGroupMember) || errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusNotFound, err.Error())
 		return
 	}
@@ -354,13 +811,16 @@ func (h *ExpenseHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
 
 // GetMonthlySummary godoc
 // @Summary      Get monthly summary
-// @Description  Get expense summary for a specific month
+// @Description  Sum the expenses you take part in for a specific month, or all of a group's if you belong to it
 // @Tags         statistics
 // @Produce      json
-// @Param        year   query     int  true  "Year"
-// @Param        month  query     int  true  "Month (1-12)"
-// @Success      200    {object}  domain.MonthlySummary
-// @Failure      400    {object}  map[string]string
+// @Param        year      query     int     true   "Year"
+// @Param        month     query     int     true   "Month (1-12)"
+// @Param        group_id  query     string  false  "Group ID"
+// @Success      200       {object}  domain.MonthlySummary
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /expenses/monthly [get]
 func (h *ExpenseHandler) GetMonthlySummary(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -388,8 +848,18 @@ func (h *ExpenseHandler) GetMonthlySummary(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	summary, err := h.expenseUc.GetMonthlySummary(r.Context(), year, month)
+	var summary *domain.MonthlySummary
+	var err error
+	if groupID := r.URL.Query().Get("group_id"); groupID != "" {
+		summary, err = h.expenseUc.GetGroupMonthlySummary(r.Context(), groupID, year, month)
+	} else {
+		summary, err = h.expenseUc.GetMonthlySummary(r.Context(), year, month)
+	}
 	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
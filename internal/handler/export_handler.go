@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/export"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type ExportHandler struct {
+	exportUC usecase.ExportUseCase
+}
+
+func NewExportHandler(exportUC usecase.ExportUseCase) *ExportHandler {
+	return &ExportHandler{exportUC: exportUC}
+}
+
+// startedWriter records whether any of the response body has been written,
+// after which an error can no longer be reported as JSON
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *startedWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Export godoc
+// @Summary      Export expenses, users and balances
+// @Description  Download expenses (one row per split), users, balances or suggested settlements as CSV, NDJSON or XLSX.
+// @Description  Expenses honour the same filters as GET /expenses; group_id also selects the group's balances.
+// @Description  dataset=all writes every table: one worksheet each in XLSX, or rows tagged by "table" in NDJSON. CSV holds a single table.
+// @Tags         export
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format      query     string  false  "csv (default), ndjson or xlsx"
+// @Param        dataset     query     string  false  "expenses (default), users, balances, settlements or all"
+// @Param        group_id    query     string  false  "Filter by group"
+// @Param        category    query     string  false  "Filter by category"
+// @Param        start_date  query     string  false  "Start date (YYYY-MM-DD)"
+// @Param        end_date    query     string  false  "End date (YYYY-MM-DD)"
+// @Success      200         {file}    file
+// @Failure      400         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /export [get]
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	format, err := export.ParseFormat(query.Get("format"))
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	dataset, err := usecase.ParseExportDataset(query.Get("dataset"))
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if dataset == usecase.ExportAll && !format.MultipleTables() {
+		response.RespondWithError(w, http.StatusBadRequest, "dataset=all needs format ndjson or xlsx")
+		return
+	}
+	filter := usecase.ExportFilter{
+		GroupID:   query.Get("group_id"),
+		Category:  query.Get("category"),
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+	}
+
+	fileName := fmt.Sprintf("homies-%s-%s.%s", dataset, time.Now().Format("20060102"), format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+
+	body := &startedWriter{ResponseWriter: w}
+	writer, err := export.NewWriter(format, body)
+	if err == nil {
+		if err = h.exportUC.Export(r.Context(), writer, dataset, filter); err == nil {
+			err = writer.Close()
+		}
+	}
+	if err != nil {
+		if body.started {
+			log.Printf("export failed after the response started: %v", err)
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+	}
+}
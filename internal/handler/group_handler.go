@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type GroupHandler struct {
+	groupUC usecase.GroupUseCase
+}
+
+func NewGroupHandler(groupUC usecase.GroupUseCase) *GroupHandler {
+	return &GroupHandler{
+		groupUC: groupUC,
+	}
+}
+
+type CreateGroupRequest struct {
+	Name         string `json:"name"`
+	BaseCurrency string `json:"base_currency,omitempty"`
+	// Timezone is an IANA name such as Asia/Kolkata; it defaults to the server's
+	Timezone  string   `json:"timezone,omitempty"`
+	MemberIDs []string `json:"member_ids"`
+}
+
+type GroupMemberRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type GroupResponse struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	BaseCurrency string   `json:"base_currency"`
+	Timezone     string   `json:"timezone,omitempty"`
+	Members      []string `json:"members"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+// CreateGroup godoc
+// @Summary      Create a new group
+// @Description  Create a household or trip group with an initial member list. Expense dates in the group are read in its timezone.
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        group  body      CreateGroupRequest  true  "Group data"
+// @Success      201    {object}  GroupResponse
+// @Failure      400    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /groups [post]
+func (h *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req CreateGroupRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	group, err := h.groupUC.CreateGroup(r.Context(), req.Name, domain.Currency(req.BaseCurrency), req.Timezone, req.MemberIDs)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToGroupResponse(group))
+}
+
+// GetAllGroups godoc
+// @Summary      Get all groups
+// @Description  Retrieve your groups, or the groups a user belongs to that you are also in
+// @Tags         groups
+// @Produce      json
+// @Param        user_id  query     string  false  "Filter by member"
+// @Success      200      {array}   GroupResponse
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /groups [get]
+func (h *GroupHandler) GetAllGroups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if userID := r.URL.Query().Get("user_id"); userID != "" {
+		groups, err := h.groupUC.GetGroupsByUser(r.Context(), userID)
+		if err != nil {
+			respondWithGroupError(w, err)
+			return
+		}
+		response.RespondWithJSON(w, http.StatusOK, ToGroupResponses(groups))
+		return
+	}
+
+	groups, err := h.groupUC.GetAllGroups(r.Context())
+	if err != nil {
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToGroupResponses(groups))
+}
+
+// GetGroupByID godoc
+// @Summary      Get group by ID
+// @Description  Retrieve a specific group and its members. Only members can see a group.
+// @Tags         groups
+// @Produce      json
+// @Param        id   query     string  true  "Group ID"
+// @Success      200  {object}  GroupResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /groups [get]
+func (h *GroupHandler) GetGroupByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "Group ID is required")
+		return
+	}
+
+	group, err := h.groupUC.GetGroup(r.Context(), id)
+	if err != nil {
+		respondWithGroupError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToGroupResponse(group))
+}
+
+// AddMember godoc
+// @Summary      Add a group member
+// @Description  Add an existing user to a group. Only members can add members.
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        group_id  query     string              true  "Group ID"
+// @Param        member    body      GroupMemberRequest  true  "Member to add"
+// @Success      200       {object}  GroupResponse
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /groups/members [post]
+func (h *GroupHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	groupID := r.URL.Query().Get("group_id")
+	if groupID == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "group_id parameter is required")
+		return
+	}
+
+	var req GroupMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	group, err := h.groupUC.AddMember(r.Context(), groupID, req.UserID)
+	if err != nil {
+		respondWithGroupError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToGroupResponse(group))
+}
+
+// RemoveMember godoc
+// @Summary      Remove a group member
+// @Description  Remove a user from a group; their existing expenses stay in the group. Only members can remove members.
+// @Tags         groups
+// @Produce      json
+// @Param        group_id  query     string  true  "Group ID"
+// @Param        user_id   query     string  true  "User ID"
+// @Success      200       {object}  GroupResponse
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /groups/members [delete]
+func (h *GroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	groupID := r.URL.Query().Get("group_id")
+	userID := r.URL.Query().Get("user_id")
+	if groupID == "" || userID == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "group_id and user_id parameters are required")
+		return
+	}
+
+	group, err := h.groupUC.RemoveMember(r.Context(), groupID, userID)
+	if err != nil {
+		respondWithGroupError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToGroupResponse(group))
+}
+
+func respondWithGroupError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		response.RespondWithError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrNotGroupMember):
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+	default:
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/importer"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type ImportHandler struct {
+	expenseUC usecase.ExpenseUseCase
+	maxBytes  int64
+}
+
+// NewImportHandler accepts import files up to maxBytes
+func NewImportHandler(expenseUC usecase.ExpenseUseCase, maxBytes int64) *ImportHandler {
+	return &ImportHandler{
+		expenseUC: expenseUC,
+		maxBytes:  maxBytes,
+	}
+}
+
+type ImportedExpenseResponse struct {
+	Line    int             `json:"line"`
+	Expense ExpenseResponse `json:"expense"`
+}
+
+type ImportReportResponse struct {
+	DryRun    bool                      `json:"dry_run"`
+	Committed bool                      `json:"committed"`
+	Created   []ImportedExpenseResponse `json:"created"`
+	Failed    []domain.ImportProblem    `json:"failed"`
+	Skipped   []domain.ImportProblem    `json:"skipped"`
+}
+
+// ImportExpenses godoc
+// @Summary      Import expenses from a CSV file
+// @Description  Import a Splitwise export or a bank statement. People are matched to users by email: a Splitwise import takes
+// @Description  a JSON object mapping the names in its header to emails, and a bank import takes a JSON column mapping.
+// @Description  Expenses are created in one transaction and only when no line fails; otherwise 422 returns the report.
+// @Description  With dry_run=true nothing is created and the report shows what would be.
+// @Tags         expenses
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        format    formData  string  true   "Import format"  Enums(splitwise, bank)
+// @Param        file      formData  file    true   "CSV file"
+// @Param        people    formData  string  false  "Splitwise names to emails, as a JSON object"
+// @Param        mapping   formData  string  false  "Bank statement column mapping, as JSON"
+// @Param        group_id  query     string  false  "Group to import into"
+// @Param        dry_run   query     bool    false  "Report without creating anything"
+// @Success      200       {object}  ImportReportResponse
+// @Success      201       {object}  ImportReportResponse
+// @Failure      400       {object}  map[string]string
+// @Failure      413       {object}  map[string]string
+// @Failure      422       {object}  ImportReportResponse
+// @Security     BearerAuth
+// @Router       /expenses/import [post]
+func (h *ImportHandler) ImportExpenses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			response.RespondWithError(w, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.RespondWithError(w, http.StatusRequestEntityTooLarge, "file exceeds the "+strconv.FormatInt(h.maxBytes, 10)+" byte limit")
+			return
+		}
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid multipart body")
+		return
+	}
+	defer func() {
+		if err := r.MultipartForm.RemoveAll(); err != nil {
+			log.Printf("failed to remove multipart files: %v", err)
+		}
+	}()
+
+	format, err := importer.ParseFormat(r.FormValue("format"))
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	batch, err := parseImportFile(format, file, r.FormValue("people"), r.FormValue("mapping"))
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.expenseUC.ImportExpenses(r.Context(), r.URL.Query().Get("group_id"), batch, dryRun)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	switch {
+	case report.Committed:
+		status = http.StatusCreated
+	case !report.DryRun:
+		status = http.StatusUnprocessableEntity
+	}
+	response.RespondWithJSON(w, status, ToImportReportResponse(report))
+}
+
+// parseImportFile reads an import file with the people or column mapping its
+// format needs, both given as JSON
+func parseImportFile(format importer.Format, file io.Reader, people, mapping string) (*domain.ImportBatch, error) {
+	names := make(map[string]string)
+	if people != "" {
+		if err := json.Unmarshal([]byte(people), &names); err != nil {
+			return nil, errors.New("people must be a JSON object of names to emails")
+		}
+	}
+
+	var bankMapping *importer.BankMapping
+	if mapping != "" {
+		var err error
+		if bankMapping, err = importer.ParseBankMapping(strings.NewReader(mapping)); err != nil {
+			return nil, err
+		}
+	}
+	return importer.Parse(format, file, names, bankMapping)
+}
@@ -8,12 +8,18 @@ import (
 
 // ToUserResponse converts a domain.User to UserResponse
 func ToUserResponse(user *domain.User) UserResponse {
-	return UserResponse{
+	response := UserResponse{
 		ID:        user.ID,
 		Name:      user.Name,
 		Email:     user.Email,
 		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+		Guest:     user.Guest,
+		Contact:   user.Contact,
 	}
+	if user.DeactivatedAt != nil {
+		response.DeactivatedAt = user.DeactivatedAt.Format(time.RFC3339)
+	}
+	return response
 }
 
 // ToUserResponses converts multiple users to response DTOs
@@ -36,17 +42,44 @@ func ToExpenseResponse(expense *domain.Expense) ExpenseResponse {
 	}
 
 	return ExpenseResponse{
-		ID:          expense.ID,
-		Description: expense.Description,
-		Amount:      expense.Amount,
-		Category:    expense.Category,
-		PaidBy:      expense.PaidBy,
-		Date:        expense.Date,
-		CreatedAt:   expense.CreatedAt,
-		Splits:      splits,
+		ID:             expense.ID,
+		GroupID:        expense.GroupID,
+		Description:    expense.Description,
+		Amount:         expense.Amount,
+		Currency:       string(expense.Amount.Currency),
+		Category:       expense.Category,
+		PaidBy:         expense.PaidBy,
+		Date:           expense.Date,
+		CreatedAt:      expense.CreatedAt,
+		DeletedAt:      expense.DeletedAt,
+		Splits:         splits,
+		LineItems:      toLineItemResponses(expense.Items),
+		Tags:           expense.Tags,
+		RecurringID:    expense.RecurringID,
+		OccurrenceDate: expense.OccurrenceDate,
 	}
 }
 
+func toLineItemResponses(items []domain.ExpenseItem) []LineItemResponse {
+	if len(items) == 0 {
+		return nil
+	}
+	lines := make([]LineItemResponse, len(items))
+	for i, item := range items {
+		// Stored items passed Validate, so their totals fit
+		total, _ := item.Total()
+		lines[i] = LineItemResponse{
+			Name:     item.Name,
+			Quantity: item.Quantity,
+			Price:    item.Price,
+			Total:    total,
+			Kind:     string(item.Kind),
+			UserIDs:  item.UserIDs,
+		}
+	}
+	return lines
+}
+
 // ToExpenseResponses converts multiple expenses to response DTOs
 func ToExpenseResponses(expenses []*domain.Expense) []ExpenseResponse {
 	responses := make([]ExpenseResponse, len(expenses))
@@ -55,3 +88,206 @@ func ToExpenseResponses(expenses []*domain.Expense) []ExpenseResponse {
 	}
 	return responses
 }
+
+// ToExpenseListResponse converts a page of expenses to ExpenseListResponse
+func ToExpenseListResponse(page *domain.ExpensePage) ExpenseListResponse {
+	return ExpenseListResponse{
+		Data:       ToExpenseResponses(page.Expenses),
+		NextCursor: page.NextCursor,
+	}
+}
+
+// ToImportReportResponse converts a domain.ImportReport to ImportReportResponse
+func ToImportReportResponse(report *domain.ImportReport) ImportReportResponse {
+	created := make([]ImportedExpenseResponse, len(report.Created))
+	for i, imported := range report.Created {
+		created[i] = ImportedExpenseResponse{
+			Line:    imported.Line,
+			Expense: ToExpenseResponse(imported.Expense),
+		}
+	}
+	return ImportReportResponse{
+		DryRun:    report.DryRun,
+		Committed: report.Committed,
+		Created:   created,
+		Failed:    report.Failed,
+		Skipped:   report.Skipped,
+	}
+}
+
+// ToGroupResponse converts a domain.Group to GroupResponse
+func ToGroupResponse(group *domain.Group) GroupResponse {
+	members := group.Members
+	if members == nil {
+		members = []string{}
+	}
+	return GroupResponse{
+		ID:           group.ID,
+		Name:         group.Name,
+		BaseCurrency: string(group.BaseCurrency),
+		Timezone:     group.Timezone,
+		Members:      members,
+		CreatedAt:    group.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToGroupResponses converts multiple groups to response DTOs
+func ToGroupResponses(groups []*domain.Group) []GroupResponse {
+	responses := make([]GroupResponse, len(groups))
+	for i, group := range groups {
+		responses[i] = ToGroupResponse(group)
+	}
+	return responses
+}
+
+// ToExchangeRateResponse converts a domain.ExchangeRate to ExchangeRateResponse
+func ToExchangeRateResponse(rate *domain.ExchangeRate) ExchangeRateResponse {
+	return ExchangeRateResponse{
+		From:          string(rate.From),
+		To:            string(rate.To),
+		Rate:          rate.Rate,
+		EffectiveDate: rate.EffectiveDate.Format("2006-01-02"),
+	}
+}
+
+// ToExchangeRateResponses converts multiple exchange rates to response DTOs
+func ToExchangeRateResponses(rates []*domain.ExchangeRate) []ExchangeRateResponse {
+	responses := make([]ExchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		responses[i] = ToExchangeRateResponse(rate)
+	}
+	return responses
+}
+
+// ToPaymentResponse converts a domain.Payment to PaymentResponse
+func ToPaymentResponse(payment *domain.Payment) PaymentResponse {
+	return PaymentResponse{
+		ID:        payment.ID,
+		GroupID:   payment.GroupID,
+		From:      payment.From,
+		To:        payment.To,
+		Amount:    payment.Amount,
+		Currency:  string(payment.Amount.Currency),
+		Date:      payment.Date,
+		Note:      payment.Note,
+		CreatedAt: payment.CreatedAt,
+	}
+}
+
+// ToPaymentResponses converts multiple payments to response DTOs
+func ToPaymentResponses(payments []*domain.Payment) []PaymentResponse {
+	responses := make([]PaymentResponse, len(payments))
+	for i, payment := range payments {
+		responses[i] = ToPaymentResponse(payment)
+	}
+	return responses
+}
+
+// ToSessionResponse converts a newly created domain.Session to SessionResponse
+func ToSessionResponse(session *domain.Session) SessionResponse {
+	return SessionResponse{
+		Token:     session.Token,
+		UserID:    session.UserID,
+		ExpiresAt: session.ExpiresAt,
+	}
+}
+
+// ToRecurringExpenseResponse converts a domain.RecurringExpense to RecurringExpenseResponse
+func ToRecurringExpenseResponse(recurring *domain.RecurringExpense, now time.Time) RecurringExpenseResponse {
+	var splits []SplitResponse
+	for _, split := range recurring.Splits {
+		splits = append(splits, SplitResponse{
+			UserId: split.UserID,
+			Amount: split.Amount,
+		})
+	}
+
+	resp := RecurringExpenseResponse{
+		ID:          recurring.ID,
+		GroupID:     recurring.GroupID,
+		Description: recurring.Description,
+		Amount:      recurring.Amount,
+		Currency:    string(recurring.Amount.Currency),
+		Category:    recurring.Category,
+		PaidBy:      recurring.PaidBy,
+		Splits:      splits,
+		UserIDs:     recurring.UserIDs,
+		Schedule:    recurring.Schedule.String(),
+		StartDate:   recurring.StartDate.Format("2006-01-02"),
+		CreatedAt:   recurring.CreatedAt,
+	}
+	if recurring.EndDate != nil {
+		resp.EndDate = recurring.EndDate.Format("2006-01-02")
+	}
+	if recurring.LastOccurrence != nil {
+		resp.LastOccurrence = recurring.LastOccurrence.Format("2006-01-02")
+	}
+	if next := recurring.NextOccurrence(now); next != nil {
+		resp.NextOccurrence = next.Format("2006-01-02")
+	}
+	return resp
+}
+
+// ToRecurringExpenseResponses converts multiple recurring expenses to response DTOs
+func ToRecurringExpenseResponses(all []*domain.RecurringExpense, now time.Time) []RecurringExpenseResponse {
+	responses := make([]RecurringExpenseResponse, len(all))
+	for i, recurring := range all {
+		responses[i] = ToRecurringExpenseResponse(recurring, now)
+	}
+	return responses
+}
+
+// ToAttachmentResponse converts an attachment to its response DTO
+func ToAttachmentResponse(attachment *domain.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          attachment.ID,
+		ExpenseID:   attachment.ExpenseID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		UploadedBy:  attachment.UploadedBy,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// ToAttachmentResponses converts multiple attachments to response DTOs
+func ToAttachmentResponses(attachments []*domain.Attachment) []AttachmentResponse {
+	responses := make([]AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		responses[i] = ToAttachmentResponse(attachment)
+	}
+	return responses
+}
+
+// ToCategoryResponse converts a domain.Category to CategoryResponse
+func ToCategoryResponse(category *domain.Category) CategoryResponse {
+	response := CategoryResponse{
+		ID:            category.ID,
+		Name:          category.Name,
+		ParentID:      category.ParentID,
+		Icon:          category.Icon,
+		Color:         category.Color,
+		MonthlyBudget: category.MonthlyBudget,
+		CreatedAt:     category.CreatedAt,
+		UpdatedAt:     category.UpdatedAt,
+	}
+	if category.MonthlyBudget != nil {
+		response.Currency = string(category.MonthlyBudget.Currency)
+	}
+	return response
+}
+
+// ToBudgetResponse converts a domain.Budget to BudgetResponse
+func ToBudgetResponse(budget *domain.Budget) BudgetResponse {
+	return BudgetResponse{
+		ID:        budget.ID,
+		Category:  budget.Category,
+		Period:    string(budget.Period),
+		Amount:    budget.Amount,
+		Currency:  string(budget.Amount.Currency),
+		UserID:    budget.UserID,
+		GroupID:   budget.GroupID,
+		CreatedAt: budget.CreatedAt,
+		UpdatedAt: budget.UpdatedAt,
+	}
+}
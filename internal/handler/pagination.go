@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// parsePageRequest reads the limit, cursor and sort query parameters. sort is
+// one of fields, optionally suffixed with ":asc" or ":desc".
+func parsePageRequest(r *http.Request, fields []string, def domain.Sort) (domain.PageRequest, error) {
+	query := r.URL.Query()
+
+	page := domain.PageRequest{Cursor: query.Get("cursor")}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return page, errors.New("limit must be a positive integer")
+		}
+		page.Limit = limit
+	}
+
+	sort, err := domain.ParseSort(query.Get("sort"), fields, def)
+	if err != nil {
+		return page, err
+	}
+	page.Sort = sort
+	return page, nil
+}
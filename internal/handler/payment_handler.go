@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type PaymentHandler struct {
+	paymentUC usecase.PaymentUseCase
+}
+
+func NewPaymentHandler(paymentUC usecase.PaymentUseCase) *PaymentHandler {
+	return &PaymentHandler{
+		paymentUC: paymentUC,
+	}
+}
+
+type PaymentRequest struct {
+	GroupID  string       `json:"group_id,omitempty"`
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Amount   domain.Money `json:"amount"`
+	Currency string       `json:"currency,omitempty"`
+	Date     string       `json:"date,omitempty"`
+	Note     string       `json:"note,omitempty"`
+}
+
+type PaymentResponse struct {
+	ID        string       `json:"id"`
+	GroupID   string       `json:"group_id,omitempty"`
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Amount    domain.Money `json:"amount"`
+	Currency  string       `json:"currency"`
+	Date      time.Time    `json:"date"`
+	Note      string       `json:"note,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// CreatePayment godoc
+// @Summary      Record a payment
+// @Description  Record that one user paid another back. Only the payer or the payee may record it. Date is YYYY-MM-DD and defaults to today.
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        payment  body      PaymentRequest  true  "Payment data"
+// @Success      201      {object}  PaymentResponse
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /payments [post]
+func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req PaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Amount.Currency = domain.Currency(req.Currency)
+	payment, err := h.paymentUC.CreatePayment(r.Context(), req.GroupID, req.From, req.To, req.Amount, req.Date, req.Note)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotGroupMember) || errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToPaymentResponse(payment))
+}
+
+// GetAllPayments godoc
+// @Summary      Get all payments
+// @Description  Retrieve the payments you sent or received, newest first, or those of a group you belong to
+// @Tags         payments
+// @Produce      json
+// @Param        user_id   query     string  false  "Payments sent or received by user"
+// @Param        group_id  query     string  false  "Payments within group"
+// @Success      200       {array}   PaymentResponse
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /payments [get]
+func (h *PaymentHandler) GetAllPayments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var payments []*domain.Payment
+	var err error
+	if userID := r.URL.Query().Get("user_id"); userID != "" {
+		payments, err = h.paymentUC.GetPaymentsByUser(r.Context(), userID)
+	} else if groupID := r.URL.Query().Get("group_id"); groupID != "" {
+		payments, err = h.paymentUC.GetGroupPayments(r.Context(), groupID)
+	} else {
+		payments, err = h.paymentUC.GetAllPayments(r.Context())
+		if err != nil {
+			response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToPaymentResponses(payments))
+}
+
+// GetPaymentByID godoc
+// @Summary      Get payment by ID
+// @Description  Retrieve a specific recorded payment you sent or received
+// @Tags         payments
+// @Produce      json
+// @Param        id   query     string  true  "Payment ID"
+// @Success      200  {object}  PaymentResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /payments [get]
+func (h *PaymentHandler) GetPaymentByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	payment, err := h.paymentUC.GetPayment(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToPaymentResponse(payment))
+}
+
+// UpdatePayment godoc
+// @Summary      Update a payment
+// @Description  Correct the amount, date or note of a recorded payment. Only the payer may.
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        id       query     string          true  "Payment ID"
+// @Param        payment  body      PaymentRequest  true  "Updated payment data"
+// @Success      200      {object}  PaymentResponse
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /payments [put]
+func (h *PaymentHandler) UpdatePayment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	var req PaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Amount.Currency = domain.Currency(req.Currency)
+	payment, err := h.paymentUC.UpdatePayment(r.Context(), id, req.Amount, req.Date, req.Note)
+	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if err.Error() == "payment not found" {
+			response.RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToPaymentResponse(payment))
+}
+
+// DeletePayment godoc
+// @Summary      Delete a payment
+// @Description  Delete a recorded payment by ID; balances revert accordingly. Only the payer may.
+// @Tags         payments
+// @Param        id   query     string  true  "Payment ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /payments [delete]
+func (h *PaymentHandler) DeletePayment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	if err := h.paymentUC.DeletePayment(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if err.Error() == "payment not found" {
+			response.RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/usecase"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+type RecurringExpenseHandler struct {
+	recurringUC usecase.RecurringExpenseUseCase
+}
+
+func NewRecurringExpenseHandler(recurringUC usecase.RecurringExpenseUseCase) *RecurringExpenseHandler {
+	return &RecurringExpenseHandler{
+		recurringUC: recurringUC,
+	}
+}
+
+type RecurringExpenseRequest struct {
+	GroupID     string         `json:"group_id,omitempty"`
+	Description string         `json:"description"`
+	Amount      domain.Money   `json:"amount"`
+	Currency    string         `json:"currency,omitempty"`
+	Category    string         `json:"category"`
+	PaidBy      string         `json:"paid_by"`
+	Splits      []SplitRequest `json:"splits,omitempty"`
+	UserIDs     []string       `json:"user_ids,omitempty"`
+	Schedule    string         `json:"schedule" example:"FREQ=MONTHLY;BYMONTHDAY=1"`
+	StartDate   string         `json:"start_date" example:"2025-01-01"`
+	EndDate     string         `json:"end_date,omitempty"`
+}
+
+type RecurringExpenseResponse struct {
+	ID             string          `json:"id"`
+	GroupID        string          `json:"group_id,omitempty"`
+	Description    string          `json:"description"`
+	Amount         domain.Money    `json:"amount"`
+	Currency       string          `json:"currency"`
+	Category       string          `json:"category"`
+	PaidBy         string          `json:"paid_by"`
+	Splits         []SplitResponse `json:"splits,omitempty"`
+	UserIDs        []string        `json:"user_ids,omitempty"`
+	Schedule       string          `json:"schedule"`
+	StartDate      string          `json:"start_date"`
+	EndDate        string          `json:"end_date,omitempty"`
+	LastOccurrence string          `json:"last_occurrence,omitempty"`
+	NextOccurrence string          `json:"next_occurrence,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// CreateRecurringExpense godoc
+// @Summary      Create a recurring expense
+// @Description  Create a template that adds an expense on a schedule. Give either splits or user_ids to split equally.
+// @Description  The schedule is an RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY|YEARLY, INTERVAL and, for monthly, BYMONTHDAY.
+// @Description  You must be the payer or one of the participants.
+// @Tags         recurring-expenses
+// @Accept       json
+// @Produce      json
+// @Param        recurring  body      RecurringExpenseRequest  true  "Recurring expense data"
+// @Success      201        {object}  RecurringExpenseResponse
+// @Failure      400        {object}  map[string]string
+// @Failure      403        {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /recurring-expenses [post]
+func (h *RecurringExpenseHandler) CreateRecurringExpense(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req RecurringExpenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Amount.Currency = domain.Currency(req.Currency)
+	splits := make([]domain.Split, len(req.Splits))
+	for i, split := range req.Splits {
+		splits[i] = domain.Split{
+			UserID: split.UserId,
+			Amount: split.Amount,
+		}
+	}
+
+	recurring, err := h.recurringUC.CreateRecurringExpense(r.Context(), req.GroupID, req.Description, req.Category, req.PaidBy, req.Amount, splits, req.UserIDs, req.Schedule, req.StartDate, req.EndDate)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotGroupMember) || errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToRecurringExpenseResponse(recurring, time.Now()))
+}
+
+// GetAllRecurringExpenses godoc
+// @Summary      Get all recurring expenses
+// @Description  Retrieve every recurring expense template with its next occurrence
+// @Tags         recurring-expenses
+// @Produce      json
+// @Success      200  {array}   RecurringExpenseResponse
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /recurring-expenses [get]
+func (h *RecurringExpenseHandler) GetAllRecurringExpenses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	all, err := h.recurringUC.GetAllRecurringExpenses(r.Context())
+	if err != nil {
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToRecurringExpenseResponses(all, time.Now()))
+}
+
+// GetRecurringExpenseByID godoc
+// @Summary      Get recurring expense by ID
+// @Description  Retrieve a specific recurring expense template
+// @Tags         recurring-expenses
+// @Produce      json
+// @Param        id   query     string  true  "Recurring expense ID"
+// @Success      200  {object}  RecurringExpenseResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /recurring-expenses [get]
+func (h *RecurringExpenseHandler) GetRecurringExpenseByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	recurring, err := h.recurringUC.GetRecurringExpense(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToRecurringExpenseResponse(recurring, time.Now()))
+}
+
+// DeleteRecurringExpense godoc
+// @Summary      Delete a recurring expense
+// @Description  Stop a recurring expense. Expenses it already created are kept.
+// @Tags         recurring-expenses
+// @Param        id   query     string  true  "Recurring expense ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /recurring-expenses [delete]
+func (h *RecurringExpenseHandler) DeleteRecurringExpense(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "id parameter is required")
+		return
+	}
+
+	if err := h.recurringUC.DeleteRecurringExpense(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrRecurringExpenseNotFound) {
+			response.RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/pavanrkadave/homies/internal/domain"
 	"github.com/pavanrkadave/homies/internal/usecase"
 	"github.com/pavanrkadave/homies/pkg/response"
 )
@@ -29,10 +32,34 @@ type UpdateUserRequest struct {
 }
 
 type UserResponse struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"created_at"`
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	CreatedAt     string `json:"created_at"`
+	DeactivatedAt string `json:"deactivated_at,omitempty"`
+	Guest         bool   `json:"guest,omitempty"`
+	Contact       string `json:"contact,omitempty"`
+}
+
+type CreateGuestRequest struct {
+	Name    string `json:"name"`
+	Contact string `json:"contact"`
+}
+
+type ClaimGuestRequest struct {
+	Email string `json:"email"`
+}
+
+type ConfirmGuestClaimRequest struct {
+	Token string `json:"token"`
+}
+
+type MergeUsersRequest struct {
+	FromID string `json:"from_id"`
+	IntoID string `json:"into_id"`
+	// FromToken is a session token of from_id, from signing in to it, and
+	// proves the duplicate is the caller's
+	FromToken string `json:"from_token,omitempty"`
 }
 
 // CreateUser godoc
@@ -44,6 +71,7 @@ type UserResponse struct {
 // @Param        user  body      CreateUserRequest  true  "User data"
 // @Success      201   {object}  UserResponse
 // @Failure      400   {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /users [post]
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 
@@ -68,31 +96,57 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	response.RespondWithJSON(w, http.StatusCreated, ToUserResponse(user))
 }
 
+// UserListResponse is one page of users. Pass next_cursor back as cursor to
+// get the next page; it is omitted on the last page.
+type UserListResponse struct {
+	Data       []UserResponse `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
 // GetAllUsers godoc
 // @Summary      Get all users
-// @Description  Retrieve a list of all users
+// @Description  Retrieve a page of users, oldest first by default
 // @Tags         users
 // @Produce      json
-// @Success      200  {array}   UserResponse
-// @Failure      500  {object}  map[string]string
+// @Param        limit   query     int     false  "Page size (max 200)"  default(50)
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Param        sort    query     string  false  "created_at or name, optionally with :asc or :desc"  default(created_at:asc)
+// @Success      200     {object}  UserListResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /users [get]
 func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
-	users, err := h.userUC.GetAllUsers(r.Context())
+
+	page, err := parsePageRequest(r, domain.UserSortFields, domain.Sort{Field: domain.SortByCreatedAt, Order: domain.SortAsc})
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.userUC.ListUsers(r.Context(), page)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			response.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		response.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	response.RespondWithJSON(w, http.StatusOK, ToUserResponses(users))
+	response.RespondWithJSON(w, http.StatusOK, UserListResponse{
+		Data:       ToUserResponses(result.Users),
+		NextCursor: result.NextCursor,
+	})
 }
 
 // UpdateUser godoc
 // @Summary      Update a user
-// @Description  Update user information by ID
+// @Description  Update user information by ID. Signed-in users can only update their own account.
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -100,8 +154,10 @@ func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 // @Param        user  body      UpdateUserRequest  true  "Updated user data"
 // @Success      200   {object}  UserResponse
 // @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
 // @Failure      404   {object}  map[string]string
 // @Failure      409   {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /users [put]
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
@@ -124,6 +180,10 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.userUC.UpdateUser(r.Context(), id, req.Name, req.Email)
 	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			response.RespondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		if err.Error() == "user not found" {
 			response.RespondWithError(w, http.StatusNotFound, err.Error())
 			return
@@ -148,6 +208,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 // @Success      200  {object}  UserResponse
 // @Failure      400  {object}  map[string]string
 // @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
 // @Router       /users [get]
 func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -173,3 +234,241 @@ func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 
 	response.RespondWithJSON(w, http.StatusOK, ToUserResponse(user))
 }
+
+// DeactivateUser godoc
+// @Summary      Deactivate a user
+// @Description  Mark a user as having left. Their history is kept, but they are signed out, can't sign in, and can't be added to new expenses or groups.
+// @Tags         users
+// @Produce      json
+// @Param        id   query     string  true  "User ID"
+// @Success      200  {object}  UserResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/deactivate [post]
+func (h *UserHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	h.setDeactivated(w, r, h.userUC.DeactivateUser)
+}
+
+// ReactivateUser godoc
+// @Summary      Reactivate a user
+// @Description  Undo a deactivation
+// @Tags         users
+// @Produce      json
+// @Param        id   query     string  true  "User ID"
+// @Success      200  {object}  UserResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/reactivate [post]
+func (h *UserHandler) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	h.setDeactivated(w, r, h.userUC.ReactivateUser)
+}
+
+func (h *UserHandler) setDeactivated(w http.ResponseWriter, r *http.Request, set func(ctx context.Context, id string) (*domain.User, error)) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	user, err := set(r.Context(), id)
+	if err != nil {
+		respondWithUserError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToUserResponse(user))
+}
+
+// DeleteUser godoc
+// @Summary      Delete a user
+// @Description  Delete a settled-up user. One who is part of recorded expenses or payments is deactivated instead, keeping their history; one with an outstanding balance is refused with 409.
+// @Tags         users
+// @Param        id   query     string  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users [delete]
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	if err := h.userUC.DeleteUser(r.Context(), id); err != nil {
+		respondWithUserError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MergeUsers godoc
+// @Summary      Merge duplicate users
+// @Description  Move every expense, split, payment and group membership of from_id to into_id and delete from_id, in one transaction, recording an update in each moved expense's history. Only into_id may merge another account into itself, and only one it holds a session token for; guests are merged by claiming them, and homiesctl can merge any two accounts.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        merge  body      MergeUsersRequest  true  "Duplicate and kept user"
+// @Success      200    {object}  UserResponse
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/merge [post]
+func (h *UserHandler) MergeUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req MergeUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, err := h.userUC.MergeUsers(r.Context(), req.FromID, req.IntoID, req.FromToken)
+	if err != nil {
+		respondWithUserError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToUserResponse(user))
+}
+
+// CreateGuest godoc
+// @Summary      Add a guest
+// @Description  Add someone without an account, by name and an optional contact. Guests can pay and share in expenses and show up in balances, but can't sign in.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        guest  body      CreateGuestRequest  true  "Guest"
+// @Success      201    {object}  UserResponse
+// @Failure      400    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/guests [post]
+func (h *UserHandler) CreateGuest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req CreateGuestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	guest, err := h.userUC.CreateGuest(r.Context(), req.Name, req.Contact)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusCreated, ToUserResponse(guest))
+}
+
+// ClaimGuest godoc
+// @Summary      Claim a guest
+// @Description  Ask to turn a guest into a full user with the email, keeping their history. Only someone in one of the guest's groups may ask. A confirmation token is sent to the email, and nothing changes until its owner confirms.
+// @Tags         users
+// @Accept       json
+// @Param        id     query     string             true  "Guest ID"
+// @Param        claim  body      ClaimGuestRequest  true  "Email of the full user"
+// @Success      202
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/claim [post]
+func (h *UserHandler) ClaimGuest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		response.RespondWithError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	var req ClaimGuestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.userUC.RequestGuestClaim(r.Context(), id, req.Email); err != nil {
+		respondWithUserError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ConfirmGuestClaim godoc
+// @Summary      Confirm a guest claim
+// @Description  Complete a claim with the token sent to its email. If no account has the email, the guest becomes a user who can sign in with it; otherwise the guest is merged into that account. Each token works once.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        confirm  body      ConfirmGuestClaimRequest  true  "Claim token"
+// @Success      200      {object}  UserResponse
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Router       /users/claim/confirm [post]
+func (h *UserHandler) ConfirmGuestClaim(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req ConfirmGuestClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, err := h.userUC.ConfirmGuestClaim(r.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) {
+			response.RespondWithError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		respondWithUserError(w, err)
+		return
+	}
+
+	response.RespondWithJSON(w, http.StatusOK, ToUserResponse(user))
+}
+
+func respondWithUserError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		response.RespondWithError(w, http.StatusForbidden, err.Error())
+	case err.Error() == "user not found":
+		response.RespondWithError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrUserHasBalance), errors.Is(err, domain.ErrUserHasHistory):
+		response.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		response.RespondWithError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,211 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// BankMapping describes the columns of a bank statement CSV and who the
+// withdrawals in it are shared between. Column names are matched without
+// regard to case.
+type BankMapping struct {
+	Date       string `json:"date"`
+	DateFormat string `json:"date_format"` // Go time layout; defaults to 2006-01-02
+	// Description may list several columns, which are joined with " - "
+	Description []string `json:"description"`
+	// Amount is a signed column where withdrawals are negative. Statements
+	// with a separate withdrawals column name it in Debit instead.
+	Amount          string `json:"amount"`
+	Debit           string `json:"debit"`
+	Category        string `json:"category"`         // optional column
+	DefaultCategory string `json:"default_category"` // used when Category is unset or empty
+	Currency        string `json:"currency"`         // ISO 4217 code; defaults to the expense currency
+	// PaidBy is the email address of the account holder
+	PaidBy string `json:"paid_by"`
+	// SplitWith lists the email addresses every withdrawal is split equally
+	// between. Empty means the account holder bears it alone.
+	SplitWith []string `json:"split_with"`
+	Delimiter string   `json:"delimiter"` // defaults to a comma
+	SkipRows  int      `json:"skip_rows"` // preamble lines before the header
+}
+
+// ParseBankMapping decodes a mapping from JSON and validates it
+func ParseBankMapping(r io.Reader) (*BankMapping, error) {
+	var mapping BankMapping
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&mapping); err != nil {
+		return nil, fmt.Errorf("invalid column mapping: %w", err)
+	}
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+func (m *BankMapping) Validate() error {
+	if m.Date == "" {
+		return errors.New("mapping: date column is required")
+	}
+	if len(m.Description) == 0 {
+		return errors.New("mapping: description column is required")
+	}
+	if (m.Amount == "") == (m.Debit == "") {
+		return errors.New("mapping: exactly one of amount and debit columns is required")
+	}
+	if m.PaidBy == "" {
+		return errors.New("mapping: paid_by is required")
+	}
+	if m.Currency != "" {
+		if _, err := domain.ParseCurrency(m.Currency); err != nil {
+			return fmt.Errorf("mapping: %w", err)
+		}
+	}
+	if utf8.RuneCountInString(m.Delimiter) > 1 {
+		return errors.New("mapping: delimiter must be a single character")
+	}
+	if m.SkipRows < 0 {
+		return errors.New("mapping: skip_rows must not be negative")
+	}
+	return nil
+}
+
+// ParseBank reads withdrawals from a bank statement CSV as expenses paid by
+// the account holder. Deposits and rows without an amount are skipped.
+func ParseBank(r io.Reader, mapping *BankMapping) (*domain.ImportBatch, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	delimiter, _ := utf8.DecodeRuneInString(mapping.Delimiter)
+	if delimiter == utf8.RuneError {
+		delimiter = 0
+	}
+	t, err := newTable(r, delimiter, mapping.SkipRows)
+	if err != nil {
+		return nil, err
+	}
+
+	cols, err := bankColumnsOf(t, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := mapping.DateFormat
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	currency := domain.Currency(strings.ToUpper(mapping.Currency))
+	people := mapping.SplitWith
+	if len(people) == 0 {
+		people = []string{mapping.PaidBy}
+	}
+
+	batch := &domain.ImportBatch{}
+	for {
+		record, line, err := t.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		raw := field(record, cols.amount)
+		if raw == "" {
+			batch.Skipped = append(batch.Skipped, domain.ImportProblem{Line: line, Reason: "no amount"})
+			continue
+		}
+		amount, err := parseAmount(raw, currency)
+		if err != nil {
+			batch.Failed = append(batch.Failed, domain.ImportProblem{Line: line, Reason: err.Error()})
+			continue
+		}
+		if mapping.Amount != "" {
+			// Withdrawals are the negative amounts of a signed column
+			amount = amount.Neg()
+		}
+		if !amount.IsPositive() {
+			batch.Skipped = append(batch.Skipped, domain.ImportProblem{Line: line, Reason: "not a withdrawal"})
+			continue
+		}
+
+		date, err := time.Parse(layout, field(record, cols.date))
+		if err != nil {
+			batch.Failed = append(batch.Failed, domain.ImportProblem{Line: line, Reason: fmt.Sprintf("invalid date %q", field(record, cols.date))})
+			continue
+		}
+
+		var parts []string
+		for _, i := range cols.description {
+			if value := field(record, i); value != "" {
+				parts = append(parts, value)
+			}
+		}
+
+		category := strings.ToLower(field(record, cols.category))
+		if category == "" {
+			category = mapping.DefaultCategory
+		}
+
+		row := domain.ImportRow{
+			Line:        line,
+			Date:        date,
+			Description: strings.Join(parts, " - "),
+			Category:    category,
+			Amount:      amount,
+			PaidBy:      mapping.PaidBy,
+		}
+		for i, share := range amount.Allocate(len(people)) {
+			row.Shares = append(row.Shares, domain.ImportShare{Email: people[i], Amount: share})
+		}
+		batch.Rows = append(batch.Rows, row)
+	}
+	return batch, nil
+}
+
+// bankColumns holds the index of each mapped column; an unmapped optional
+// column is -1
+type bankColumns struct {
+	date        int
+	description []int
+	amount      int
+	category    int
+}
+
+func bankColumnsOf(t *table, mapping *BankMapping) (bankColumns, error) {
+	cols := bankColumns{category: -1}
+	var err error
+	if cols.date, err = t.column(mapping.Date); err != nil {
+		return cols, err
+	}
+	for _, name := range mapping.Description {
+		i, err := t.column(name)
+		if err != nil {
+			return cols, err
+		}
+		cols.description = append(cols.description, i)
+	}
+
+	amountColumn := mapping.Amount
+	if amountColumn == "" {
+		amountColumn = mapping.Debit
+	}
+	if cols.amount, err = t.column(amountColumn); err != nil {
+		return cols, err
+	}
+
+	if mapping.Category != "" {
+		if cols.category, err = t.column(mapping.Category); err != nil {
+			return cols, err
+		}
+	}
+	return cols, nil
+}
@@ -0,0 +1,135 @@
+// Package importer reads expenses from other tools' CSV files, such as
+// Splitwise exports and bank statements, into rows ready to be imported.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// Format is a kind of import file
+type Format string
+
+const (
+	FormatSplitwise Format = "splitwise"
+	FormatBank      Format = "bank"
+)
+
+// ParseFormat accepts an import format name
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case FormatSplitwise, FormatBank:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid import format %q: must be splitwise or bank", s)
+	}
+}
+
+// Parse reads an import file of the given format. Splitwise files need people,
+// mapping the names in their header to email addresses; bank statements need
+// a column mapping.
+func Parse(format Format, r io.Reader, people map[string]string, mapping *BankMapping) (*domain.ImportBatch, error) {
+	if format == FormatBank {
+		if mapping == nil {
+			return nil, errors.New("a column mapping is required for bank imports")
+		}
+		return ParseBank(r, mapping)
+	}
+	return ParseSplitwise(r, people)
+}
+
+// table is a CSV file read into its header and records
+type table struct {
+	reader  *csv.Reader
+	header  []string
+	columns map[string]int
+}
+
+// newTable reads the header of a CSV file, after skipping the given number of
+// preamble records. Column names are matched without regard to case or
+// surrounding space.
+func newTable(r io.Reader, delimiter rune, skip int) (*table, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if delimiter != 0 {
+		reader.Comma = delimiter
+	}
+
+	for range skip {
+		if _, err := reader.Read(); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read preamble: %w", err)
+		}
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	t := &table{reader: reader, header: header, columns: make(map[string]int, len(header))}
+	for i, name := range header {
+		// Excel prefixes UTF-8 files with a byte order mark
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		header[i] = name
+		t.columns[normalizeColumn(name)] = i
+	}
+	return t, nil
+}
+
+// next returns the next record and the line it started on, or io.EOF
+func (t *table) next() ([]string, int, error) {
+	record, err := t.reader.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+	line, _ := t.reader.FieldPos(0)
+	return record, line, nil
+}
+
+// column returns the index of a named column, or an error naming it
+func (t *table) column(name string) (int, error) {
+	i, ok := t.columns[normalizeColumn(name)]
+	if !ok {
+		return 0, fmt.Errorf("column %q not found", name)
+	}
+	return i, nil
+}
+
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// field returns a record's value in column i, which is empty when the record
+// is short
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// parseAmount parses a decimal amount, allowing thousands separators and a
+// currency symbol or code around it
+func parseAmount(s string, currency domain.Currency) (domain.Money, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == '-', r == '+':
+			return r
+		default:
+			return -1
+		}
+	}, s)
+	if cleaned == "" {
+		return domain.Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return domain.ParseMoney(cleaned, currency)
+}
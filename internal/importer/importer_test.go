@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func TestParseSplitwise(t *testing.T) {
+	file := `Date,Description,Category,Cost,Currency,Alice Smith,Bob Jones,carol@test.com
+2024-01-05,Groceries,Groceries,30.00,USD,20.00,-10.00,-10.00
+2024-01-06,Taxi,Taxi,12.50,USD,-12.50,12.50,0.00
+2024-01-07,Settle all balances,Payment,10.00,USD,-10.00,10.00,0.00
+2024-01-08,Dinner,Dining out,40.00,USD,10.00,10.00,-20.00
+2024-01-09,Cinema,Entertainment,20.00,USD,-10.00,0.00,0.00
+
+2024-01-10,Total balance, , ,USD,10.00,12.50,-30.00
+`
+	people := map[string]string{"alice smith": "alice@test.com", "Bob Jones": "bob@test.com"}
+
+	batch, err := ParseSplitwise(strings.NewReader(file), people)
+	if err != nil {
+		t.Fatalf("ParseSplitwise() failed: %v", err)
+	}
+
+	usd := func(minor int64) domain.Money { return domain.NewMoney(minor, "USD") }
+	want := []domain.ImportRow{
+		{
+			Line: 2, Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Description: "Groceries", Category: "groceries",
+			Amount: usd(3000), PaidBy: "alice@test.com",
+			Shares: []domain.ImportShare{{Email: "bob@test.com", Amount: usd(1000)}, {Email: "carol@test.com", Amount: usd(1000)}, {Email: "alice@test.com", Amount: usd(1000)}},
+		},
+		{
+			Line: 3, Date: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), Description: "Taxi", Category: "taxi",
+			Amount: usd(1250), PaidBy: "bob@test.com",
+			Shares: []domain.ImportShare{{Email: "alice@test.com", Amount: usd(1250)}},
+		},
+	}
+	if !reflect.DeepEqual(batch.Rows, want) {
+		t.Errorf("Expected rows %+v, got %+v", want, batch.Rows)
+	}
+
+	wantSkipped := []domain.ImportProblem{{Line: 4, Reason: "settle-up payments are not imported"}}
+	if !reflect.DeepEqual(batch.Skipped, wantSkipped) {
+		t.Errorf("Expected skipped %v, got %v", wantSkipped, batch.Skipped)
+	}
+
+	if len(batch.Failed) != 2 || batch.Failed[0].Line != 5 || batch.Failed[1].Line != 6 {
+		t.Fatalf("Expected lines 5 and 6 to fail, got %v", batch.Failed)
+	}
+	if !strings.Contains(batch.Failed[0].Reason, "more than one person") {
+		t.Errorf("Unexpected reason for a shared payment: %s", batch.Failed[0].Reason)
+	}
+}
+
+func TestParseSplitwise_NotSplitwise(t *testing.T) {
+	if _, err := ParseSplitwise(strings.NewReader("Date,Amount\n"), nil); err == nil {
+		t.Error("Expected error for a file without Splitwise columns")
+	}
+}
+
+func TestParseBank(t *testing.T) {
+	file := `Statement for account 1234
+Txn Date;Narration;Ref;Withdrawal Amt.;Deposit Amt.
+01/02/2024;UPI-GROCER;881;"1.234,50";
+02/02/2024;SALARY;882;;50000
+03/02/2024;ELECTRICITY;883;900.01;
+31/02/2024;BAD DATE;884;10;
+`
+	// Line 3 uses a decimal comma, which is ambiguous and must fail rather
+	// than be misread
+	mapping := &BankMapping{
+		Date:            "txn date",
+		DateFormat:      "02/01/2006",
+		Description:     []string{"Narration", "Ref"},
+		Debit:           "Withdrawal Amt.",
+		DefaultCategory: "bank",
+		Currency:        "inr",
+		PaidBy:          "alice@test.com",
+		SplitWith:       []string{"alice@test.com", "bob@test.com"},
+		Delimiter:       ";",
+		SkipRows:        1,
+	}
+
+	batch, err := ParseBank(strings.NewReader(file), mapping)
+	if err != nil {
+		t.Fatalf("ParseBank() failed: %v", err)
+	}
+
+	inr := func(minor int64) domain.Money { return domain.NewMoney(minor, "INR") }
+	want := []domain.ImportRow{{
+		Line: 5, Date: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), Description: "ELECTRICITY - 883", Category: "bank",
+		Amount: inr(90001), PaidBy: "alice@test.com",
+		Shares: []domain.ImportShare{{Email: "alice@test.com", Amount: inr(45001)}, {Email: "bob@test.com", Amount: inr(45000)}},
+	}}
+	if !reflect.DeepEqual(batch.Rows, want) {
+		t.Errorf("Expected rows %+v, got %+v", want, batch.Rows)
+	}
+
+	wantFailed := []int{3, 6}
+	if len(batch.Failed) != len(wantFailed) {
+		t.Fatalf("Expected lines %v to fail, got %v", wantFailed, batch.Failed)
+	}
+	for i, line := range wantFailed {
+		if batch.Failed[i].Line != line {
+			t.Errorf("Expected line %d to fail, got %v", line, batch.Failed[i])
+		}
+	}
+	if len(batch.Skipped) != 1 || batch.Skipped[0].Line != 4 {
+		t.Errorf("Expected the deposit on line 4 to be skipped, got %v", batch.Skipped)
+	}
+}
+
+func TestParseBankMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping string
+		wantErr bool
+	}{
+		{name: "valid", mapping: `{"date": "Date", "description": ["Details"], "amount": "Amount", "paid_by": "alice@test.com"}`},
+		{name: "both amount columns", mapping: `{"date": "Date", "description": ["Details"], "amount": "Amount", "debit": "Debit", "paid_by": "alice@test.com"}`, wantErr: true},
+		{name: "no payer", mapping: `{"date": "Date", "description": ["Details"], "amount": "Amount"}`, wantErr: true},
+		{name: "unknown field", mapping: `{"date": "Date", "description": ["Details"], "amount": "Amount", "paid_by": "a@test.com", "payer": "b"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBankMapping(strings.NewReader(tt.mapping))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBankMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// splitwiseColumns are the fixed columns of a Splitwise export. Every column
+// after them is a person, holding what that person is owed (positive) or owes
+// (negative) for the expense.
+var splitwiseColumns = []string{"Date", "Description", "Category", "Cost", "Currency"}
+
+const splitwiseDateLayout = "2006-01-02"
+
+// ParseSplitwise reads a Splitwise "Export as spreadsheet" CSV. people maps
+// the names in its header to email addresses; a header that is already an
+// email address needs no entry. Settle-up payments and the closing total
+// balance line are skipped.
+func ParseSplitwise(r io.Reader, people map[string]string) (*domain.ImportBatch, error) {
+	t, err := newTable(r, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, name := range splitwiseColumns {
+		if j, err := t.column(name); err != nil || j != i {
+			return nil, fmt.Errorf("not a Splitwise export: expected column %q at position %d", name, i+1)
+		}
+	}
+
+	// Resolve every person column up front; a row only fails on an unknown
+	// person if that person took part in it
+	names := t.header
+	emails := make([]string, len(names))
+	for i := len(splitwiseColumns); i < len(names); i++ {
+		emails[i] = lookupPerson(people, names[i])
+	}
+
+	batch := &domain.ImportBatch{}
+	for {
+		record, line, err := t.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		description := field(record, 1)
+		category := field(record, 2)
+		switch {
+		case strings.EqualFold(description, "Total balance"):
+			continue
+		case strings.EqualFold(category, "Payment"):
+			batch.Skipped = append(batch.Skipped, domain.ImportProblem{Line: line, Reason: "settle-up payments are not imported"})
+			continue
+		}
+
+		row, err := splitwiseRow(record, names, emails)
+		if err != nil {
+			batch.Failed = append(batch.Failed, domain.ImportProblem{Line: line, Reason: err.Error()})
+			continue
+		}
+		row.Line = line
+		batch.Rows = append(batch.Rows, row)
+	}
+	return batch, nil
+}
+
+// splitwiseRow turns the net amounts of one Splitwise expense into a payer
+// and shares. The payer is the only person owed money; their own share is
+// whatever of the cost the others do not owe.
+func splitwiseRow(record, names, emails []string) (domain.ImportRow, error) {
+	date, err := time.Parse(splitwiseDateLayout, field(record, 0))
+	if err != nil {
+		return domain.ImportRow{}, fmt.Errorf("invalid date %q", field(record, 0))
+	}
+	currency, err := domain.ParseCurrency(field(record, 4))
+	if err != nil {
+		return domain.ImportRow{}, err
+	}
+	cost, err := parseAmount(field(record, 3), currency)
+	if err != nil {
+		return domain.ImportRow{}, err
+	}
+
+	row := domain.ImportRow{
+		Date:        date,
+		Description: field(record, 1),
+		Category:    strings.ToLower(field(record, 2)),
+		Amount:      cost,
+	}
+
+	var payer int
+	payerShare := cost
+	for i := len(splitwiseColumns); i < len(names) && i < len(record); i++ {
+		if field(record, i) == "" {
+			continue
+		}
+		net, err := parseAmount(field(record, i), currency)
+		if err != nil {
+			return domain.ImportRow{}, fmt.Errorf("%s: %w", names[i], err)
+		}
+		if net.IsZero() {
+			continue
+		}
+		if emails[i] == "" {
+			return domain.ImportRow{}, fmt.Errorf("no email address for %q", names[i])
+		}
+
+		if net.IsPositive() {
+			if row.PaidBy != "" {
+				return domain.ImportRow{}, errors.New("expenses paid by more than one person cannot be imported")
+			}
+			row.PaidBy = emails[i]
+			payer = i
+			payerShare = payerShare.Sub(net)
+			continue
+		}
+		row.Shares = append(row.Shares, domain.ImportShare{Email: emails[i], Amount: net.Neg()})
+	}
+
+	if row.PaidBy == "" {
+		return domain.ImportRow{}, errors.New("no one is owed money for this expense")
+	}
+	if payerShare.IsNegative() {
+		return domain.ImportRow{}, fmt.Errorf("%s is owed more than the cost", names[payer])
+	}
+	if payerShare.IsPositive() {
+		row.Shares = append(row.Shares, domain.ImportShare{Email: row.PaidBy, Amount: payerShare})
+	}
+	return row, nil
+}
+
+// lookupPerson finds the email address of a Splitwise name, ignoring case
+func lookupPerson(people map[string]string, name string) string {
+	for person, email := range people {
+		if normalizeColumn(person) == normalizeColumn(name) {
+			return email
+		}
+	}
+	if strings.Contains(name, "@") {
+		return name
+	}
+	return ""
+}
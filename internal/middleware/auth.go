@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/pkg/response"
+)
+
+// Authenticator resolves a bearer token to the user it was issued to
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.User, error)
+}
+
+// Auth requires a valid "Authorization: Bearer <token>" header and puts the
+// authenticated user's ID in the request context. Paths in publicPaths are
+// let through unauthenticated; a path ending in "/" matches its whole subtree.
+func Auth(authenticator Authenticator, publicPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r.URL.Path, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := BearerToken(r)
+			if !ok {
+				response.RespondWithError(w, http.StatusUnauthorized, domain.ErrUnauthenticated.Error())
+				return
+			}
+
+			user, err := authenticator.Authenticate(r.Context(), token)
+			if err != nil {
+				response.RespondWithError(w, http.StatusUnauthorized, domain.ErrInvalidToken.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(domain.WithUserID(r.Context(), user.ID)))
+		})
+	}
+}
+
+// UserIDHeader carries the acting user's ID when a trusted proxy in front of
+// the API has already authenticated the caller
+const UserIDHeader = "X-User-ID"
+
+// UserFinder looks up the user a trusted header names
+type UserFinder interface {
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+}
+
+// UserHeader trusts the X-User-ID header as the acting user's identity and puts
+// it in the request context. It must only be used behind a proxy that sets
+// the header itself. IDs of unknown or deactivated users are refused;
+// publicPaths behave as in Auth.
+func UserHeader(users UserFinder, publicPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r.URL.Path, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID := strings.TrimSpace(r.Header.Get(UserIDHeader))
+			if userID == "" {
+				response.RespondWithError(w, http.StatusUnauthorized, domain.ErrUnauthenticated.Error())
+				return
+			}
+
+			user, err := users.GetUser(r.Context(), userID)
+			if err != nil {
+				response.RespondWithError(w, http.StatusUnauthorized, "unknown user")
+				return
+			}
+			if !user.IsActive() {
+				response.RespondWithError(w, http.StatusUnauthorized, domain.ErrUserDeactivated.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(domain.WithUserID(r.Context(), user.ID)))
+		})
+	}
+}
+
+// BearerToken extracts the token from an Authorization header
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, public := range publicPaths {
+		if path == public || strings.HasSuffix(public, "/") && strings.HasPrefix(path, public) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"context"
+	"log"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// LogBudgetAlertNotifier writes budget alerts to the server log instead of
+// sending them to the household
+type LogBudgetAlertNotifier struct{}
+
+func (LogBudgetAlertNotifier) NotifyBudgetAlert(ctx context.Context, alert *domain.BudgetAlert) error {
+	scope := "household"
+	if alert.UserID != "" {
+		scope = "user " + alert.UserID
+	}
+	if alert.GroupID != "" {
+		scope += " in group " + alert.GroupID
+	}
+	log.Printf("budget alert: %s %s budget for %s passed %d%% with expense %s (%s of %s %s since %s)",
+		alert.Period, alert.Category, scope, alert.Threshold, alert.ExpenseID,
+		alert.Spent, alert.Amount, alert.Currency, alert.PeriodStart)
+	return nil
+}
@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// LogGuestClaimSender writes guest claim confirmations to the server log
+// instead of emailing them, like LogMagicLinkSender
+type LogGuestClaimSender struct {
+	BaseURL string
+}
+
+func (s LogGuestClaimSender) SendGuestClaim(ctx context.Context, guest *domain.User, email, token string, expiresAt time.Time) error {
+	link := s.BaseURL + "?token=" + url.QueryEscape(token)
+	log.Printf("claim of guest %s for %s (expires %s): %s", guest.Name, email, expiresAt.Format(time.RFC3339), link)
+	return nil
+}
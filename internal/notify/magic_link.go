@@ -0,0 +1,23 @@
+// Package notify delivers messages to users outside the API.
+package notify
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// LogMagicLinkSender writes magic links to the server log instead of emailing
+// them. It is meant for development and single-household deployments.
+type LogMagicLinkSender struct {
+	BaseURL string
+}
+
+func (s LogMagicLinkSender) SendMagicLink(ctx context.Context, user *domain.User, token string, expiresAt time.Time) error {
+	link := s.BaseURL + "?token=" + url.QueryEscape(token)
+	log.Printf("magic link for %s (expires %s): %s", user.Email, expiresAt.Format(time.RFC3339), link)
+	return nil
+}
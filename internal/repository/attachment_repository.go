@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"io"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// AttachmentRepository stores attachment metadata. Rows outlive a purged
+// expense so that their blobs can still be found and removed.
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *domain.Attachment) error
+	GetByID(ctx context.Context, id string) (*domain.Attachment, error)
+	// GetByExpenseID returns an expense's attachments, oldest first
+	GetByExpenseID(ctx context.Context, expenseID string) ([]*domain.Attachment, error)
+	GetAll(ctx context.Context) ([]*domain.Attachment, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// BlobStore keeps file contents by key. Get returns
+// domain.ErrAttachmentNotFound for a key that is not stored; deleting such a
+// key is not an error.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// AuthRepository stores password hashes, sessions, magic links and guest
+// claims. Tokens are only ever looked up by their hash.
+type AuthRepository interface {
+	SetPasswordHash(ctx context.Context, userID, hash string) error
+	GetPasswordHash(ctx context.Context, userID string) (string, error)
+
+	CreateSession(ctx context.Context, session *domain.Session) error
+	GetSession(ctx context.Context, tokenHash string) (*domain.Session, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+	// DeleteUserSessions signs the user out everywhere
+	DeleteUserSessions(ctx context.Context, userID string) error
+
+	CreateMagicLink(ctx context.Context, link *domain.MagicLink) error
+	// ConsumeMagicLink deletes and returns an unexpired link, so it works once
+	ConsumeMagicLink(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLink, error)
+
+	CreateGuestClaim(ctx context.Context, claim *domain.GuestClaim) error
+	// ConsumeGuestClaim deletes and returns an unexpired claim, so it works once
+	ConsumeGuestClaim(ctx context.Context, tokenHash string, now time.Time) (*domain.GuestClaim, error)
+}
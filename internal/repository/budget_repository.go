@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type BudgetRepository interface {
+	// Create returns domain.ErrBudgetExists if another budget covers the same
+	// category, period, user and group
+	Create(ctx context.Context, budget *domain.Budget) error
+	GetByID(ctx context.Context, id string) (*domain.Budget, error)
+	GetAll(ctx context.Context) ([]*domain.Budget, error)
+	Update(ctx context.Context, budget *domain.Budget) error
+	Delete(ctx context.Context, id string) error
+
+	// AppendAlert records a raised alert; alerts are kept after their budget
+	// is deleted
+	AppendAlert(ctx context.Context, alert *domain.BudgetAlert) error
+	// GetAlerts returns the alerts raised in [from, to), newest first
+	GetAlerts(ctx context.Context, from, to time.Time) ([]*domain.BudgetAlert, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *domain.Category) error
+	GetByID(ctx context.Context, id string) (*domain.Category, error)
+	// GetByName finds a category by its normalized name
+	GetByName(ctx context.Context, name string) (*domain.Category, error)
+	GetAll(ctx context.Context) ([]*domain.Category, error)
+	// Update renames the category in every expense and recurring expense that
+	// uses it
+	Update(ctx context.Context, category *domain.Category) error
+	// Delete returns domain.ErrCategoryInUse while expenses, recurring
+	// expenses or subcategories refer to the category
+	Delete(ctx context.Context, id string) error
+}
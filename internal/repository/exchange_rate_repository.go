@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// ExchangeRateProvider looks up the rate in effect for a currency pair on a
+// given date, i.e. the latest rate whose effective date is not after it.
+// Implementations return domain.ErrExchangeRateNotFound when none applies.
+type ExchangeRateProvider interface {
+	GetRate(ctx context.Context, from, to domain.Currency, date time.Time) (*domain.ExchangeRate, error)
+}
+
+// ExchangeRateRepository stores historical rates and serves them as a provider
+type ExchangeRateRepository interface {
+	ExchangeRateProvider
+	Save(ctx context.Context, rate *domain.ExchangeRate) error
+	GetByPair(ctx context.Context, from, to domain.Currency) ([]*domain.ExchangeRate, error)
+}
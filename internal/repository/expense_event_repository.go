@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// ExpenseEventRepository stores the append-only history of expense changes.
+// Events are never updated or deleted, even when their expense is.
+type ExpenseEventRepository interface {
+	Append(ctx context.Context, event *domain.ExpenseEvent) error
+	// GetByExpenseID returns an expense's events, oldest first
+	GetByExpenseID(ctx context.Context, expenseID string) ([]*domain.ExpenseEvent, error)
+}
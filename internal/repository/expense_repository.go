@@ -2,18 +2,48 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/pavanrkadave/homies/internal/domain"
 )
 
+// ExpenseRepository stores expenses. Each write takes the history event that
+// records it and appends the event in the same transaction, so an expense
+// never changes without its history; a nil event records nothing.
 type ExpenseRepository interface {
-	Create(ctx context.Context, expense *domain.Expense) error
+	Create(ctx context.Context, expense *domain.Expense, event *domain.ExpenseEvent) error
+	// CreateBatch creates the expenses and appends the events in one
+	// transaction: either all of them are created or none are
+	CreateBatch(ctx context.Context, expenses []*domain.Expense, events []*domain.ExpenseEvent) error
 	GetByID(ctx context.Context, id string) (*domain.Expense, error)
 	GetAll(ctx context.Context) ([]*domain.Expense, error)
 	GetByUserID(ctx context.Context, userID string) ([]*domain.Expense, error)
+	GetByGroupID(ctx context.Context, groupID string) ([]*domain.Expense, error)
 	GetByDateRange(ctx context.Context, startDate, endDate string) ([]*domain.Expense, error)
 	GetByCategory(ctx context.Context, category string) ([]*domain.Expense, error)
-	GetByFilters(ctx context.Context, category, startDate, endDate string) ([]*domain.Expense, error)
-	Update(ctx context.Context, expense *domain.Expense) error
-	Delete(ctx context.Context, id string) error
+	// GetByFilters returns every expense matching filter, newest first
+	GetByFilters(ctx context.Context, filter domain.ExpenseFilter) ([]*domain.Expense, error)
+	// List returns one page of the expenses matching filter. The page request
+	// must already be validated.
+	List(ctx context.Context, filter domain.ExpenseFilter, page domain.PageRequest) (*domain.ExpensePage, error)
+	Update(ctx context.Context, expense *domain.Expense, event *domain.ExpenseEvent) error
+	// Delete moves an expense to the trash. Trashed expenses are left out of
+	// every query above until they are restored.
+	Delete(ctx context.Context, id string, event *domain.ExpenseEvent) error
+	GetDeleted(ctx context.Context) ([]*domain.Expense, error)
+	GetDeletedByID(ctx context.Context, id string) (*domain.Expense, error)
+	Restore(ctx context.Context, id string, event *domain.ExpenseEvent) error
+	// Purge permanently removes expenses trashed before the cutoff and returns
+	// how many were removed
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
+
+// ExpenseAggregator is implemented by expense repositories that can total
+// expenses where they are stored, without loading them
+type ExpenseAggregator interface {
+	// Totals sums what each user paid and owes over the live expenses
+	// matching query: one total per user and currency, and per category and
+	// month when the query asks for them. Totals are ordered by user,
+	// currency, category and month.
+	Totals(ctx context.Context, query domain.TotalsQuery) ([]domain.ExpenseTotal, error)
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type GroupRepository interface {
+	Create(ctx context.Context, group *domain.Group) error
+	GetByID(ctx context.Context, id string) (*domain.Group, error)
+	GetAll(ctx context.Context) ([]*domain.Group, error)
+	GetByUserID(ctx context.Context, userID string) ([]*domain.Group, error)
+	Update(ctx context.Context, group *domain.Group) error
+	AddMember(ctx context.Context, groupID, userID string) error
+	RemoveMember(ctx context.Context, groupID, userID string) error
+}
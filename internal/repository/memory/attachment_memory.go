@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type AttachmentMemoryRepository struct {
+	attachments map[string]*domain.Attachment
+	mu          sync.RWMutex
+}
+
+func NewAttachmentMemoryRepository() *AttachmentMemoryRepository {
+	return &AttachmentMemoryRepository{
+		attachments: make(map[string]*domain.Attachment),
+	}
+}
+
+func (repo *AttachmentMemoryRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored := *attachment
+	repo.attachments[attachment.ID] = &stored
+	return nil
+}
+
+func (repo *AttachmentMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Attachment, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	attachment, ok := repo.attachments[id]
+	if !ok {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	found := *attachment
+	return &found, nil
+}
+
+func (repo *AttachmentMemoryRepository) GetByExpenseID(ctx context.Context, expenseID string) ([]*domain.Attachment, error) {
+	all, _ := repo.GetAll(ctx)
+
+	attachments := make([]*domain.Attachment, 0)
+	for _, attachment := range all {
+		if attachment.ExpenseID == expenseID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (repo *AttachmentMemoryRepository) GetAll(ctx context.Context) ([]*domain.Attachment, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	all := make([]*domain.Attachment, 0, len(repo.attachments))
+	for _, attachment := range repo.attachments {
+		found := *attachment
+		all = append(all, &found)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].CreatedAt.Before(all[j].CreatedAt)
+	})
+	return all, nil
+}
+
+func (repo *AttachmentMemoryRepository) Delete(ctx context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.attachments[id]; !ok {
+		return domain.ErrAttachmentNotFound
+	}
+	delete(repo.attachments, id)
+	return nil
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type AuthMemoryRepository struct {
+	passwords  map[string]string
+	sessions   map[string]*domain.Session
+	magicLinks map[string]*domain.MagicLink
+	claims     map[string]*domain.GuestClaim
+	mu         sync.RWMutex
+}
+
+func NewAuthMemoryRepository() *AuthMemoryRepository {
+	return &AuthMemoryRepository{
+		passwords:  make(map[string]string),
+		sessions:   make(map[string]*domain.Session),
+		magicLinks: make(map[string]*domain.MagicLink),
+		claims:     make(map[string]*domain.GuestClaim),
+	}
+}
+
+func (repo *AuthMemoryRepository) SetPasswordHash(ctx context.Context, userID, hash string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.passwords[userID] = hash
+	return nil
+}
+
+func (repo *AuthMemoryRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	hash, ok := repo.passwords[userID]
+	if !ok {
+		return "", domain.ErrPasswordNotSet
+	}
+	return hash, nil
+}
+
+func (repo *AuthMemoryRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored := *session
+	stored.Token = ""
+	repo.sessions[session.TokenHash] = &stored
+	return nil
+}
+
+func (repo *AuthMemoryRepository) GetSession(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	session, ok := repo.sessions[tokenHash]
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+	return session, nil
+}
+
+func (repo *AuthMemoryRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.sessions, tokenHash)
+	return nil
+}
+
+func (repo *AuthMemoryRepository) DeleteUserSessions(ctx context.Context, userID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for tokenHash, session := range repo.sessions {
+		if session.UserID == userID {
+			delete(repo.sessions, tokenHash)
+		}
+	}
+	return nil
+}
+
+func (repo *AuthMemoryRepository) CreateMagicLink(ctx context.Context, link *domain.MagicLink) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.magicLinks[link.TokenHash] = link
+	return nil
+}
+
+func (repo *AuthMemoryRepository) ConsumeMagicLink(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLink, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	link, ok := repo.magicLinks[tokenHash]
+	if !ok || !now.Before(link.ExpiresAt) {
+		return nil, domain.ErrInvalidToken
+	}
+	delete(repo.magicLinks, tokenHash)
+	return link, nil
+}
+
+func (repo *AuthMemoryRepository) CreateGuestClaim(ctx context.Context, claim *domain.GuestClaim) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.claims[claim.TokenHash] = claim
+	return nil
+}
+
+func (repo *AuthMemoryRepository) ConsumeGuestClaim(ctx context.Context, tokenHash string, now time.Time) (*domain.GuestClaim, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	claim, ok := repo.claims[tokenHash]
+	if !ok || !now.Before(claim.ExpiresAt) {
+		return nil, domain.ErrInvalidToken
+	}
+	delete(repo.claims, tokenHash)
+	return claim, nil
+}
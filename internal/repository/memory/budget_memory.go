@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type BudgetMemoryRepository struct {
+	budgets map[string]*domain.Budget
+	alerts  []*domain.BudgetAlert
+	mu      sync.RWMutex
+}
+
+func NewBudgetMemoryRepository() *BudgetMemoryRepository {
+	return &BudgetMemoryRepository{
+		budgets: make(map[string]*domain.Budget),
+	}
+}
+
+func (repo *BudgetMemoryRepository) Create(ctx context.Context, budget *domain.Budget) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if repo.covering(budget) != nil {
+		return domain.ErrBudgetExists
+	}
+	stored := *budget
+	repo.budgets[budget.ID] = &stored
+	return nil
+}
+
+func (repo *BudgetMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Budget, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	budget, exists := repo.budgets[id]
+	if !exists {
+		return nil, domain.ErrBudgetNotFound
+	}
+	found := *budget
+	return &found, nil
+}
+
+func (repo *BudgetMemoryRepository) GetAll(ctx context.Context) ([]*domain.Budget, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	budgets := make([]*domain.Budget, 0, len(repo.budgets))
+	for _, budget := range repo.budgets {
+		found := *budget
+		budgets = append(budgets, &found)
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		a, b := budgets[i], budgets[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		if a.GroupID != b.GroupID {
+			return a.GroupID < b.GroupID
+		}
+		return a.UserID < b.UserID
+	})
+	return budgets, nil
+}
+
+func (repo *BudgetMemoryRepository) Update(ctx context.Context, budget *domain.Budget) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.budgets[budget.ID]; !exists {
+		return domain.ErrBudgetNotFound
+	}
+	if other := repo.covering(budget); other != nil && other.ID != budget.ID {
+		return domain.ErrBudgetExists
+	}
+	stored := *budget
+	repo.budgets[budget.ID] = &stored
+	return nil
+}
+
+func (repo *BudgetMemoryRepository) Delete(ctx context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.budgets[id]; !exists {
+		return domain.ErrBudgetNotFound
+	}
+	delete(repo.budgets, id)
+	return nil
+}
+
+// covering returns a budget over the same expenses as budget, if any
+func (repo *BudgetMemoryRepository) covering(budget *domain.Budget) *domain.Budget {
+	for _, other := range repo.budgets {
+		if other.Covers(budget) {
+			return other
+		}
+	}
+	return nil
+}
+
+// renameCategory moves the budgets on category from onto to
+func (repo *BudgetMemoryRepository) renameCategory(from, to string) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, budget := range repo.budgets {
+		if budget.Category == from {
+			budget.Category = to
+		}
+	}
+}
+
+// deleteCategory deletes the budgets on category
+func (repo *BudgetMemoryRepository) deleteCategory(category string) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for id, budget := range repo.budgets {
+		if budget.Category == category {
+			delete(repo.budgets, id)
+		}
+	}
+}
+
+func (repo *BudgetMemoryRepository) AppendAlert(ctx context.Context, alert *domain.BudgetAlert) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored := *alert
+	repo.alerts = append(repo.alerts, &stored)
+	return nil
+}
+
+func (repo *BudgetMemoryRepository) GetAlerts(ctx context.Context, from, to time.Time) ([]*domain.BudgetAlert, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var alerts []*domain.BudgetAlert
+	for i := len(repo.alerts) - 1; i >= 0; i-- {
+		alert := repo.alerts[i]
+		if alert.CreatedAt.Before(from) || !alert.CreatedAt.Before(to) {
+			continue
+		}
+		found := *alert
+		alerts = append(alerts, &found)
+	}
+	return alerts, nil
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type CategoryMemoryRepository struct {
+	categories map[string]*domain.Category
+	mu         sync.RWMutex
+	// Records that refer to categories by name, for Update and Delete; either
+	// may be nil
+	expenses  *ExpenseMemoryRepository
+	recurring *RecurringExpenseMemoryRepository
+	// budgets follow a rename and go with a deleted category; may be nil
+	budgets *BudgetMemoryRepository
+}
+
+// NewCategoryMemoryRepository returns a repository holding
+// domain.DefaultCategories, as a migrated database does
+func NewCategoryMemoryRepository() *CategoryMemoryRepository {
+	repo := &CategoryMemoryRepository{
+		categories: make(map[string]*domain.Category),
+	}
+	now := time.Now()
+	for _, name := range domain.DefaultCategories {
+		id := uuid.New().String()
+		repo.categories[id] = &domain.Category{ID: id, Name: name, CreatedAt: now, UpdatedAt: now}
+	}
+	return repo
+}
+
+// WithRecords links the repositories that refer to categories, so renames
+// reach them and deletes are refused while they are in use
+func (repo *CategoryMemoryRepository) WithRecords(expenses *ExpenseMemoryRepository, recurring *RecurringExpenseMemoryRepository) *CategoryMemoryRepository {
+	repo.expenses = expenses
+	repo.recurring = recurring
+	return repo
+}
+
+// WithBudgets links the budgets on categories, so they follow renames and are
+// deleted with their category
+func (repo *CategoryMemoryRepository) WithBudgets(budgets *BudgetMemoryRepository) *CategoryMemoryRepository {
+	repo.budgets = budgets
+	return repo
+}
+
+func (repo *CategoryMemoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if repo.byName(category.Name) != nil {
+		return domain.ErrCategoryExists
+	}
+	stored := *category
+	repo.categories[category.ID] = &stored
+	return nil
+}
+
+func (repo *CategoryMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	category, exists := repo.categories[id]
+	if !exists {
+		return nil, domain.ErrCategoryNotFound
+	}
+	found := *category
+	return &found, nil
+}
+
+func (repo *CategoryMemoryRepository) GetByName(ctx context.Context, name string) (*domain.Category, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	category := repo.byName(name)
+	if category == nil {
+		return nil, domain.ErrCategoryNotFound
+	}
+	found := *category
+	return &found, nil
+}
+
+func (repo *CategoryMemoryRepository) byName(name string) *domain.Category {
+	for _, category := range repo.categories {
+		if category.Name == name {
+			return category
+		}
+	}
+	return nil
+}
+
+func (repo *CategoryMemoryRepository) GetAll(ctx context.Context) ([]*domain.Category, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	categories := make([]*domain.Category, 0, len(repo.categories))
+	for _, category := range repo.categories {
+		found := *category
+		categories = append(categories, &found)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+func (repo *CategoryMemoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	current, exists := repo.categories[category.ID]
+	if !exists {
+		return domain.ErrCategoryNotFound
+	}
+	if other := repo.byName(category.Name); other != nil && other.ID != category.ID {
+		return domain.ErrCategoryExists
+	}
+
+	if current.Name != category.Name {
+		repo.rename(current.Name, category.Name)
+	}
+	stored := *category
+	repo.categories[category.ID] = &stored
+	return nil
+}
+
+// rename moves the expenses and recurring expenses in category from onto to
+func (repo *CategoryMemoryRepository) rename(from, to string) {
+	if repo.expenses != nil {
+		repo.expenses.mu.Lock()
+		for _, expenses := range []map[string]*domain.Expense{repo.expenses.expenses, repo.expenses.trash} {
+			for _, expense := range expenses {
+				if expense.Category == from {
+					expense.Category = to
+				}
+			}
+		}
+		repo.expenses.mu.Unlock()
+	}
+	if repo.recurring != nil {
+		repo.recurring.mu.Lock()
+		for _, recurring := range repo.recurring.recurring {
+			if recurring.Category == from {
+				recurring.Category = to
+			}
+		}
+		repo.recurring.mu.Unlock()
+	}
+	if repo.budgets != nil {
+		repo.budgets.renameCategory(from, to)
+	}
+}
+
+func (repo *CategoryMemoryRepository) Delete(ctx context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	category, exists := repo.categories[id]
+	if !exists {
+		return domain.ErrCategoryNotFound
+	}
+	if repo.inUse(category) {
+		return domain.ErrCategoryInUse
+	}
+	delete(repo.categories, id)
+	if repo.budgets != nil {
+		repo.budgets.deleteCategory(category.Name)
+	}
+	return nil
+}
+
+// inUse reports whether any subcategory, expense, deleted or not, or
+// recurring expense refers to the category
+func (repo *CategoryMemoryRepository) inUse(category *domain.Category) bool {
+	for _, other := range repo.categories {
+		if other.ParentID == category.ID {
+			return true
+		}
+	}
+	if repo.expenses != nil {
+		repo.expenses.mu.RLock()
+		defer repo.expenses.mu.RUnlock()
+		for _, expenses := range []map[string]*domain.Expense{repo.expenses.expenses, repo.expenses.trash} {
+			for _, expense := range expenses {
+				if expense.Category == category.Name {
+					return true
+				}
+			}
+		}
+	}
+	if repo.recurring != nil {
+		repo.recurring.mu.RLock()
+		defer repo.recurring.mu.RUnlock()
+		for _, recurring := range repo.recurring.recurring {
+			if recurring.Category == category.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
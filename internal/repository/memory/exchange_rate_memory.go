@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type ExchangeRateMemoryRepository struct {
+	rates []*domain.ExchangeRate
+	mu    sync.RWMutex
+}
+
+func NewExchangeRateMemoryRepository() *ExchangeRateMemoryRepository {
+	return &ExchangeRateMemoryRepository{}
+}
+
+// exchangeRateFileEntry is one rate in a static rates file, e.g.
+// {"from": "EUR", "to": "INR", "rate": "90.25", "date": "2025-01-01"}
+type exchangeRateFileEntry struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Rate string `json:"rate"`
+	Date string `json:"date"`
+}
+
+// NewExchangeRateFileProvider loads a static JSON array of rates from path
+func NewExchangeRateFileProvider(path string) (*ExchangeRateMemoryRepository, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates file: %w", err)
+	}
+
+	var entries []exchangeRateFileEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates file: %w", err)
+	}
+
+	repo := NewExchangeRateMemoryRepository()
+	for _, entry := range entries {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q in exchange rates file: %w", entry.Date, err)
+		}
+		rate := &domain.ExchangeRate{
+			From:          domain.Currency(entry.From),
+			To:            domain.Currency(entry.To),
+			Rate:          entry.Rate,
+			EffectiveDate: date,
+		}
+		if err := rate.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rate in exchange rates file: %w", err)
+		}
+		_ = repo.Save(context.Background(), rate)
+	}
+	return repo, nil
+}
+
+func (repo *ExchangeRateMemoryRepository) Save(ctx context.Context, rate *domain.ExchangeRate) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	// Replace an existing rate for the same pair and day
+	for i, existing := range repo.rates {
+		if existing.From == rate.From && existing.To == rate.To && existing.EffectiveDate.Equal(rate.EffectiveDate) {
+			repo.rates[i] = rate
+			return nil
+		}
+	}
+	repo.rates = append(repo.rates, rate)
+	return nil
+}
+
+func (repo *ExchangeRateMemoryRepository) GetRate(ctx context.Context, from, to domain.Currency, date time.Time) (*domain.ExchangeRate, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var best *domain.ExchangeRate
+	for _, rate := range repo.rates {
+		if rate.From != from || rate.To != to || rate.EffectiveDate.After(date) {
+			continue
+		}
+		if best == nil || rate.EffectiveDate.After(best.EffectiveDate) {
+			best = rate
+		}
+	}
+	if best == nil {
+		return nil, domain.ErrExchangeRateNotFound
+	}
+	return best, nil
+}
+
+func (repo *ExchangeRateMemoryRepository) GetByPair(ctx context.Context, from, to domain.Currency) ([]*domain.ExchangeRate, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	rates := make([]*domain.ExchangeRate, 0)
+	for _, rate := range repo.rates {
+		if rate.From == from && rate.To == to {
+			rates = append(rates, rate)
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].EffectiveDate.After(rates[j].EffectiveDate)
+	})
+	return rates, nil
+}
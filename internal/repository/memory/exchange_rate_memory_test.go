@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func TestExchangeRateMemoryRepository_GetRate_UsesLatestEffectiveRate(t *testing.T) {
+	repo := NewExchangeRateMemoryRepository()
+	ctx := context.Background()
+
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	_ = repo.Save(ctx, &domain.ExchangeRate{From: "EUR", To: "INR", Rate: "89.5", EffectiveDate: jan})
+	_ = repo.Save(ctx, &domain.ExchangeRate{From: "EUR", To: "INR", Rate: "91", EffectiveDate: feb})
+
+	rate, err := repo.GetRate(ctx, "EUR", "INR", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetRate() failed: %v", err)
+	}
+	if rate.Rate != "89.5" {
+		t.Errorf("Expected January rate 89.5, got %s", rate.Rate)
+	}
+
+	rate, err = repo.GetRate(ctx, "EUR", "INR", feb.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("GetRate() failed: %v", err)
+	}
+	if rate.Rate != "91" {
+		t.Errorf("Expected February rate 91, got %s", rate.Rate)
+	}
+
+	_, err = repo.GetRate(ctx, "EUR", "INR", jan.AddDate(0, 0, -1))
+	if !errors.Is(err, domain.ErrExchangeRateNotFound) {
+		t.Errorf("Expected ErrExchangeRateNotFound before the first rate, got %v", err)
+	}
+}
+
+func TestNewExchangeRateFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	data := `[{"from": "USD", "to": "INR", "rate": "83.12", "date": "2025-03-01"}]`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write rates file: %v", err)
+	}
+
+	provider, err := NewExchangeRateFileProvider(path)
+	if err != nil {
+		t.Fatalf("NewExchangeRateFileProvider() failed: %v", err)
+	}
+
+	rate, err := provider.GetRate(context.Background(), "USD", "INR", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetRate() failed: %v", err)
+	}
+	if rate.Rate != "83.12" {
+		t.Errorf("Expected rate 83.12, got %s", rate.Rate)
+	}
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type ExpenseEventMemoryRepository struct {
+	events map[string][]*domain.ExpenseEvent
+	mu     sync.RWMutex
+}
+
+func NewExpenseEventMemoryRepository() *ExpenseEventMemoryRepository {
+	return &ExpenseEventMemoryRepository{
+		events: make(map[string][]*domain.ExpenseEvent),
+	}
+}
+
+func (repo *ExpenseEventMemoryRepository) Append(ctx context.Context, event *domain.ExpenseEvent) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.events[event.ExpenseID] = append(repo.events[event.ExpenseID], event)
+	return nil
+}
+
+func (repo *ExpenseEventMemoryRepository) GetByExpenseID(ctx context.Context, expenseID string) ([]*domain.ExpenseEvent, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	events := make([]*domain.ExpenseEvent, len(repo.events[expenseID]))
+	copy(events, repo.events[expenseID])
+	return events, nil
+}
@@ -1,29 +1,99 @@
 package memory
 
 import (
+	"cmp"
 	"context"
-	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/pavanrkadave/homies/internal/domain"
 )
 
 type ExpenseMemoryRepository struct {
-	expenses map[string]*domain.Expense
-	mu       sync.RWMutex
+	expenses    map[string]*domain.Expense
+	trash       map[string]*domain.Expense
+	attachments *AttachmentMemoryRepository
+	events      *ExpenseEventMemoryRepository
+	mu          sync.RWMutex
 }
 
 func NewExpenseMemoryRepository() *ExpenseMemoryRepository {
 	return &ExpenseMemoryRepository{
 		expenses: make(map[string]*domain.Expense),
+		trash:    make(map[string]*domain.Expense),
 	}
 }
 
-func (repo *ExpenseMemoryRepository) Create(ctx context.Context, expense *domain.Expense) error {
+// WithAttachments lets the has-attachment filter see attachments. Without
+// it no expense has any.
+func (repo *ExpenseMemoryRepository) WithAttachments(attachments *AttachmentMemoryRepository) *ExpenseMemoryRepository {
+	repo.attachments = attachments
+	return repo
+}
+
+// WithEvents keeps the history events written with each change. Without it
+// they are dropped.
+func (repo *ExpenseMemoryRepository) WithEvents(events *ExpenseEventMemoryRepository) *ExpenseMemoryRepository {
+	repo.events = events
+	return repo
+}
+
+// appendEvents records events while the write they describe holds the lock
+func (repo *ExpenseMemoryRepository) appendEvents(ctx context.Context, events ...*domain.ExpenseEvent) {
+	if repo.events == nil {
+		return
+	}
+	for _, event := range events {
+		if event != nil {
+			_ = repo.events.Append(ctx, event)
+		}
+	}
+}
+
+// hasOccurrence reports whether an expense, trashed or not, was already
+// created for the same recurring occurrence as expense
+func (repo *ExpenseMemoryRepository) hasOccurrence(expense *domain.Expense) bool {
+	if expense.RecurringID == "" {
+		return false
+	}
+	for _, expenses := range []map[string]*domain.Expense{repo.expenses, repo.trash} {
+		for _, existing := range expenses {
+			if existing.RecurringID == expense.RecurringID && existing.OccurrenceDate == expense.OccurrenceDate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (repo *ExpenseMemoryRepository) Create(ctx context.Context, expense *domain.Expense, event *domain.ExpenseEvent) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
+	if repo.hasOccurrence(expense) {
+		return domain.ErrOccurrenceExists
+	}
 	repo.expenses[expense.ID] = expense
+	repo.appendEvents(ctx, event)
+	return nil
+}
+
+func (repo *ExpenseMemoryRepository) CreateBatch(ctx context.Context, expenses []*domain.Expense, events []*domain.ExpenseEvent) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, expense := range expenses {
+		if repo.hasOccurrence(expense) {
+			return domain.ErrOccurrenceExists
+		}
+	}
+	for _, expense := range expenses {
+		repo.expenses[expense.ID] = expense
+	}
+	repo.appendEvents(ctx, events...)
 	return nil
 }
 
@@ -32,7 +102,7 @@ func (repo *ExpenseMemoryRepository) GetByID(ctx context.Context, id string) (*d
 	defer repo.mu.RUnlock()
 	expense, ok := repo.expenses[id]
 	if !ok {
-		return nil, fmt.Errorf(`expense "%s" not found`, id)
+		return nil, domain.ErrExpenseNotFound
 	}
 	return expense, nil
 }
@@ -67,15 +137,29 @@ func (repo *ExpenseMemoryRepository) GetByUserID(ctx context.Context, userID str
 	return expenses, nil
 }
 
-func (repo *ExpenseMemoryRepository) Update(ctx context.Context, expense *domain.Expense) error {
+func (repo *ExpenseMemoryRepository) GetByGroupID(ctx context.Context, groupID string) ([]*domain.Expense, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	expenses := make([]*domain.Expense, 0)
+	for _, expense := range repo.expenses {
+		if expense.GroupID == groupID {
+			expenses = append(expenses, expense)
+		}
+	}
+	return expenses, nil
+}
+
+func (repo *ExpenseMemoryRepository) Update(ctx context.Context, expense *domain.Expense, event *domain.ExpenseEvent) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
 	if _, ok := repo.expenses[expense.ID]; !ok {
-		return fmt.Errorf("expense not found")
+		return domain.ErrExpenseNotFound
 	}
 
 	repo.expenses[expense.ID] = expense
+	repo.appendEvents(ctx, event)
 	return nil
 }
 
@@ -99,45 +183,313 @@ func (repo *ExpenseMemoryRepository) GetByCategory(ctx context.Context, category
 
 	expenses := make([]*domain.Expense, 0)
 	for _, expense := range repo.expenses {
-		if expense.Category == category {
+		// Categories compare case-insensitively, as in postgres
+		if strings.EqualFold(expense.Category, category) {
+			expenses = append(expenses, expense)
+		}
+	}
+	return expenses, nil
+}
+
+func (repo *ExpenseMemoryRepository) GetByFilters(ctx context.Context, filter domain.ExpenseFilter) ([]*domain.Expense, error) {
+	attached := repo.attachedExpenses(filter)
+
+	repo.mu.RLock()
+	expenses := make([]*domain.Expense, 0)
+	for _, expense := range repo.expenses {
+		if matchesFilter(expense, filter, attached) {
 			expenses = append(expenses, expense)
 		}
 	}
+	repo.mu.RUnlock()
+
+	newestFirst := domain.Sort{Field: domain.SortByDate, Order: domain.SortDesc}
+	sort.Slice(expenses, func(i, j int) bool {
+		return compareExpenses(expenses[i], expenses[j], newestFirst) < 0
+	})
 	return expenses, nil
 }
 
-func (repo *ExpenseMemoryRepository) GetByFilters(ctx context.Context, category, startDate, endDate string) ([]*domain.Expense, error) {
+func (repo *ExpenseMemoryRepository) Delete(ctx context.Context, id string, event *domain.ExpenseEvent) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	expense, ok := repo.expenses[id]
+	if !ok {
+		return domain.ErrExpenseNotFound
+	}
+	deletedAt := time.Now()
+	expense.DeletedAt = &deletedAt
+	repo.trash[id] = expense
+	delete(repo.expenses, id)
+	repo.appendEvents(ctx, event)
+	return nil
+}
+
+func (repo *ExpenseMemoryRepository) GetDeleted(ctx context.Context) ([]*domain.Expense, error) {
 	repo.mu.RLock()
 	defer repo.mu.RUnlock()
 
+	expenses := make([]*domain.Expense, 0, len(repo.trash))
+	for _, expense := range repo.trash {
+		expenses = append(expenses, expense)
+	}
+	return expenses, nil
+}
+
+func (repo *ExpenseMemoryRepository) GetDeletedByID(ctx context.Context, id string) (*domain.Expense, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	expense, ok := repo.trash[id]
+	if !ok {
+		return nil, domain.ErrExpenseNotFound
+	}
+	return expense, nil
+}
+
+func (repo *ExpenseMemoryRepository) Restore(ctx context.Context, id string, event *domain.ExpenseEvent) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	expense, ok := repo.trash[id]
+	if !ok {
+		return domain.ErrExpenseNotFound
+	}
+	expense.DeletedAt = nil
+	repo.expenses[id] = expense
+	delete(repo.trash, id)
+	repo.appendEvents(ctx, event)
+	return nil
+}
+
+func (repo *ExpenseMemoryRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	purged := 0
+	for id, expense := range repo.trash {
+		if expense.DeletedAt.Before(before) {
+			delete(repo.trash, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (repo *ExpenseMemoryRepository) List(ctx context.Context, filter domain.ExpenseFilter, page domain.PageRequest) (*domain.ExpensePage, error) {
+	cursor, err := domain.DecodeCursor(page.Cursor, page.Sort)
+	if err != nil {
+		return nil, err
+	}
+	var after *domain.Expense
+	if cursor != nil {
+		if after, err = cursorExpense(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	attached := repo.attachedExpenses(filter)
+
+	repo.mu.RLock()
 	expenses := make([]*domain.Expense, 0)
 	for _, expense := range repo.expenses {
-		match := true
+		if !matchesFilter(expense, filter, attached) {
+			continue
+		}
+		if after != nil && compareExpenses(expense, after, page.Sort) <= 0 {
+			continue
+		}
+		expenses = append(expenses, expense)
+	}
+	repo.mu.RUnlock()
+
+	sort.Slice(expenses, func(i, j int) bool {
+		return compareExpenses(expenses[i], expenses[j], page.Sort) < 0
+	})
 
-		if category != "" && expense.Category != category {
-			match = false
+	result := &domain.ExpensePage{Expenses: expenses}
+	if len(expenses) > page.Limit {
+		result.Expenses = expenses[:page.Limit]
+		result.NextCursor = domain.ExpenseCursor(result.Expenses[page.Limit-1], page.Sort)
+	}
+	return result, nil
+}
+
+// attachedExpenses returns the IDs of expenses with attachments, when the
+// filter needs them
+func (repo *ExpenseMemoryRepository) attachedExpenses(filter domain.ExpenseFilter) map[string]bool {
+	attached := make(map[string]bool)
+	if filter.HasAttachment == nil || repo.attachments == nil {
+		return attached
+	}
+	all, _ := repo.attachments.GetAll(context.Background())
+	for _, attachment := range all {
+		attached[attachment.ExpenseID] = true
+	}
+	return attached
+}
+
+func matchesFilter(expense *domain.Expense, filter domain.ExpenseFilter, attached map[string]bool) bool {
+	if filter.GroupID != "" && expense.GroupID != filter.GroupID {
+		return false
+	}
+	if len(filter.Categories) > 0 && !slices.ContainsFunc(filter.Categories, func(category string) bool {
+		return strings.EqualFold(expense.Category, category)
+	}) {
+		return false
+	}
+	// Dates are kept in their household's time zone, so this is the local day
+	date := expense.Date.Format("2006-01-02")
+	if filter.StartDate != "" && date < filter.StartDate {
+		return false
+	}
+	if filter.EndDate != "" && date > filter.EndDate {
+		return false
+	}
+	if filter.PaidBy != "" && expense.PaidBy != filter.PaidBy {
+		return false
+	}
+	if filter.Participant != "" && !slices.ContainsFunc(expense.Splits, func(split domain.Split) bool {
+		return split.UserID == filter.Participant
+	}) {
+		return false
+	}
+	if filter.UserID != "" && !involves(expense, filter.UserID) {
+		return false
+	}
+	if filter.VisibleTo != "" && !involves(expense, filter.VisibleTo) {
+		return false
+	}
+	if filter.MinAmount != nil && expense.Amount.Minor < filter.MinAmount.Minor {
+		return false
+	}
+	if filter.MaxAmount != nil && expense.Amount.Minor > filter.MaxAmount.Minor {
+		return false
+	}
+	if filter.Search != "" && !filter.MatchesSearch(expense.Description) {
+		return false
+	}
+	for _, tag := range filter.Tags {
+		if !slices.Contains(expense.Tags, tag) {
+			return false
 		}
+	}
+	if filter.HasAttachment != nil && attached[expense.ID] != *filter.HasAttachment {
+		return false
+	}
+	return true
+}
 
-		if startDate != "" || endDate != "" {
-			dateStr := expense.Date.Format("2006-01-02")
-			if startDate != "" && dateStr < startDate {
-				match = false
-			}
-			if endDate != "" && dateStr > endDate {
-				match = false
-			}
+// involves reports whether the user paid for or has a split in the expense
+func involves(expense *domain.Expense, userID string) bool {
+	if expense.PaidBy == userID {
+		return true
+	}
+	for _, split := range expense.Splits {
+		if split.UserID == userID {
+			return true
 		}
+	}
+	return false
+}
 
-		if match {
-			expenses = append(expenses, expense)
+// compareExpenses orders expenses by the sort field, then by ID
+func compareExpenses(a, b *domain.Expense, s domain.Sort) int {
+	var c int
+	switch s.Field {
+	case domain.SortByAmount:
+		c = cmp.Compare(a.Amount.Minor, b.Amount.Minor)
+	case domain.SortByCreatedAt:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	default:
+		c = a.Date.Compare(b.Date)
+	}
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if s.Order == domain.SortDesc {
+		c = -c
+	}
+	return c
+}
+
+// cursorExpense returns a stand-in for the expense a cursor points at, with
+// just its sort field and ID set
+func cursorExpense(cursor *domain.PageCursor) (*domain.Expense, error) {
+	expense := &domain.Expense{ID: cursor.ID}
+	var err error
+	switch cursor.Sort.Field {
+	case domain.SortByAmount:
+		if expense.Amount, err = domain.ParseMoney(cursor.Value, ""); err != nil {
+			return nil, domain.ErrInvalidCursor
 		}
+	case domain.SortByCreatedAt:
+		expense.CreatedAt, err = cursor.Time()
+	default:
+		expense.Date, err = cursor.Time()
 	}
-	return expenses, nil
+	if err != nil {
+		return nil, err
+	}
+	return expense, nil
 }
 
-func (repo *ExpenseMemoryRepository) Delete(ctx context.Context, id string) error {
-	repo.mu.Lock()
-	defer repo.mu.Unlock()
-	delete(repo.expenses, id)
-	return nil
+func (repo *ExpenseMemoryRepository) Totals(ctx context.Context, query domain.TotalsQuery) ([]domain.ExpenseTotal, error) {
+	filter := domain.ExpenseFilter{GroupID: query.GroupID, StartDate: query.StartDate, EndDate: query.EndDate}
+
+	type key struct {
+		userID, currency, category, month string
+	}
+	totals := make(map[key]*domain.ExpenseTotal)
+	add := func(userID string, expense *domain.Expense) *domain.ExpenseTotal {
+		k := key{userID: userID, currency: string(expense.Amount.Currency)}
+		if query.ByCategory {
+			k.category = expense.Category
+		}
+		if query.ByMonth {
+			k.month = expense.Date.Format(domain.TotalMonthLayout)
+		}
+		total, ok := totals[k]
+		if !ok {
+			zero := domain.NewMoney(0, expense.Amount.Currency)
+			total = &domain.ExpenseTotal{UserID: k.userID, Category: k.category, Month: k.month, Paid: zero, Owed: zero}
+			totals[k] = total
+		}
+		return total
+	}
+
+	repo.mu.RLock()
+	for _, expense := range repo.expenses {
+		if !matchesFilter(expense, filter, nil) {
+			continue
+		}
+		if query.UserID == "" || expense.PaidBy == query.UserID {
+			total := add(expense.PaidBy, expense)
+			total.Paid = total.Paid.Add(expense.Amount)
+			total.ExpensesPaid++
+		}
+		for _, split := range expense.Splits {
+			if query.UserID == "" || split.UserID == query.UserID {
+				total := add(split.UserID, expense)
+				total.Owed = total.Owed.Add(split.Amount)
+			}
+		}
+	}
+	repo.mu.RUnlock()
+
+	result := make([]domain.ExpenseTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		return cmp.Or(
+			strings.Compare(a.UserID, b.UserID),
+			strings.Compare(string(a.Paid.Currency), string(b.Paid.Currency)),
+			strings.Compare(a.Category, b.Category),
+			strings.Compare(a.Month, b.Month),
+		) < 0
+	})
+	return result, nil
 }
@@ -2,6 +2,9 @@ package memory
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -15,7 +18,7 @@ func TestExpenseMemoryRepository_CreateAndGetByID(t *testing.T) {
 	createExpense := &domain.Expense{
 		ID:          "1",
 		Description: "Subway",
-		Amount:      13.50,
+		Amount:      domain.NewMoney(1350, domain.DefaultCurrency),
 		Category:    "Food",
 		PaidBy:      "1",
 		Date:        time.Now(),
@@ -24,7 +27,7 @@ func TestExpenseMemoryRepository_CreateAndGetByID(t *testing.T) {
 		Splits:      []domain.Split{},
 	}
 
-	err := repo.Create(ctx, createExpense)
+	err := repo.Create(ctx, createExpense, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error creating expense: %s", err)
 	}
@@ -38,21 +41,39 @@ func TestExpenseMemoryRepository_CreateAndGetByID(t *testing.T) {
 	}
 }
 
+func TestExpenseMemoryRepository_GetByCategory(t *testing.T) {
+	repo := NewExpenseMemoryRepository()
+	ctx := context.Background()
+
+	expense := &domain.Expense{ID: "1", Description: "Subway", Amount: domain.NewMoney(1350, domain.DefaultCurrency), Category: "Food", PaidBy: "1", Date: time.Now()}
+	if err := repo.Create(ctx, expense, nil); err != nil {
+		t.Fatalf("Unexpected error creating expense: %s", err)
+	}
+
+	expenses, err := repo.GetByCategory(ctx, "food")
+	if err != nil {
+		t.Fatalf("Unexpected error getting expenses: %s", err)
+	}
+	if len(expenses) != 1 {
+		t.Errorf("Expected the category to match regardless of case, got %d expenses", len(expenses))
+	}
+}
+
 func TestExpenseMemoryRepository_CreateAndGetByUserID(t *testing.T) {
 	repo := NewExpenseMemoryRepository()
 	ctx := context.Background()
 
 	expenses := []domain.Expense{
-		{ID: "1", Description: "Subway", Amount: 13.50, Category: "Food", PaidBy: "1", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{}},
-		{ID: "2", Description: "KFC", Amount: 15.50, Category: "Food", PaidBy: "2", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{}},
-		{ID: "3", Description: "PizzaHut", Amount: 14, Category: "Food", PaidBy: "2", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{
-			{ExpenseID: "3", UserID: "1", Amount: 7},
-			{ExpenseID: "3", UserID: "2", Amount: 7},
+		{ID: "1", Description: "Subway", Amount: domain.NewMoney(1350, domain.DefaultCurrency), Category: "Food", PaidBy: "1", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{}},
+		{ID: "2", Description: "KFC", Amount: domain.NewMoney(1550, domain.DefaultCurrency), Category: "Food", PaidBy: "2", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{}},
+		{ID: "3", Description: "PizzaHut", Amount: domain.NewMoney(1400, domain.DefaultCurrency), Category: "Food", PaidBy: "2", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{
+			{ExpenseID: "3", UserID: "1", Amount: domain.NewMoney(700, domain.DefaultCurrency)},
+			{ExpenseID: "3", UserID: "2", Amount: domain.NewMoney(700, domain.DefaultCurrency)},
 		}},
 	}
 
 	for _, expense := range expenses {
-		err := repo.Create(ctx, &expense)
+		err := repo.Create(ctx, &expense, nil)
 		if err != nil {
 			t.Fatalf("should create expense but received error %+v", err)
 		}
@@ -80,22 +101,22 @@ func TestExpenseMemoryRepository_CreateAndDelete(t *testing.T) {
 	ctx := context.Background()
 
 	expenses := []domain.Expense{
-		{ID: "1", Description: "Subway", Amount: 13.50, Category: "Food", PaidBy: "1", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{}},
-		{ID: "2", Description: "KFC", Amount: 15.50, Category: "Food", PaidBy: "2", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{}},
-		{ID: "3", Description: "PizzaHut", Amount: 14, Category: "Food", PaidBy: "2", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{
-			{ExpenseID: "3", UserID: "1", Amount: 7},
-			{ExpenseID: "3", UserID: "2", Amount: 7},
+		{ID: "1", Description: "Subway", Amount: domain.NewMoney(1350, domain.DefaultCurrency), Category: "Food", PaidBy: "1", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{}},
+		{ID: "2", Description: "KFC", Amount: domain.NewMoney(1550, domain.DefaultCurrency), Category: "Food", PaidBy: "2", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{}},
+		{ID: "3", Description: "PizzaHut", Amount: domain.NewMoney(1400, domain.DefaultCurrency), Category: "Food", PaidBy: "2", Date: time.Now(), CreatedAt: time.Now(), UpdatedAt: time.Now(), Splits: []domain.Split{
+			{ExpenseID: "3", UserID: "1", Amount: domain.NewMoney(700, domain.DefaultCurrency)},
+			{ExpenseID: "3", UserID: "2", Amount: domain.NewMoney(700, domain.DefaultCurrency)},
 		}},
 	}
 
 	for _, expense := range expenses {
-		err := repo.Create(ctx, &expense)
+		err := repo.Create(ctx, &expense, nil)
 		if err != nil {
 			t.Fatalf("should create expense but received error %+v", err)
 		}
 	}
 
-	err := repo.Delete(ctx, "3")
+	err := repo.Delete(ctx, "3", nil)
 	if err != nil {
 		t.Fatalf("Unexpected error getting expense: %s", err)
 	}
@@ -110,3 +131,210 @@ func TestExpenseMemoryRepository_CreateAndDelete(t *testing.T) {
 	}
 
 }
+
+func TestExpenseMemoryRepository_SoftDelete(t *testing.T) {
+	repo := NewExpenseMemoryRepository()
+	ctx := context.Background()
+
+	for _, id := range []string{"1", "2"} {
+		if err := repo.Create(ctx, &domain.Expense{ID: id, PaidBy: "1", Date: time.Now()}, nil); err != nil {
+			t.Fatalf("Unexpected error creating expense: %s", err)
+		}
+	}
+
+	if err := repo.Delete(ctx, "1", nil); err != nil {
+		t.Fatalf("Unexpected error deleting expense: %s", err)
+	}
+	if err := repo.Delete(ctx, "1", nil); !errors.Is(err, domain.ErrExpenseNotFound) {
+		t.Errorf("Expected not found deleting twice, got: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "1"); !errors.Is(err, domain.ErrExpenseNotFound) {
+		t.Errorf("Expected deleted expense to be hidden, got: %v", err)
+	}
+	if expenses, _ := repo.GetByUserID(ctx, "1"); len(expenses) != 1 {
+		t.Errorf("Expected 1 live expense, got %d", len(expenses))
+	}
+
+	trash, _ := repo.GetDeleted(ctx)
+	if len(trash) != 1 || trash[0].DeletedAt == nil {
+		t.Fatalf("Expected 1 expense in the trash with deleted_at set, got: %v", trash)
+	}
+
+	if err := repo.Restore(ctx, "1", nil); err != nil {
+		t.Fatalf("Unexpected error restoring expense: %s", err)
+	}
+	expense, err := repo.GetByID(ctx, "1")
+	if err != nil || expense.DeletedAt != nil {
+		t.Errorf("Expected restored expense to be live, got %v, %v", expense, err)
+	}
+
+	_ = repo.Delete(ctx, "2", nil)
+	if purged, _ := repo.Purge(ctx, time.Now().Add(-time.Hour)); purged != 0 {
+		t.Errorf("Expected recent deletions to survive the purge, purged %d", purged)
+	}
+	if purged, _ := repo.Purge(ctx, time.Now().Add(time.Second)); purged != 1 {
+		t.Errorf("Expected 1 expense purged, got %d", purged)
+	}
+	if _, err := repo.GetDeletedByID(ctx, "2"); !errors.Is(err, domain.ErrExpenseNotFound) {
+		t.Errorf("Expected purged expense to be gone, got: %v", err)
+	}
+}
+
+func TestExpenseMemoryRepository_List(t *testing.T) {
+	repo := NewExpenseMemoryRepository()
+	ctx := context.Background()
+
+	// Two expenses share each date, so pages must break ties by ID
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		paidBy := "alice"
+		if id == "c" {
+			paidBy = "bob"
+		}
+		err := repo.Create(ctx, &domain.Expense{
+			ID:     id,
+			Amount: domain.NewMoney(int64(100*(5-i)), domain.DefaultCurrency),
+			PaidBy: paidBy,
+			Date:   base.AddDate(0, 0, i/2),
+			Splits: []domain.Split{{UserID: paidBy}},
+		}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create expense: %v", err)
+		}
+	}
+
+	collect := func(filter domain.ExpenseFilter, sort domain.Sort) []string {
+		var ids []string
+		page := domain.PageRequest{Limit: 2, Sort: sort}
+		for {
+			result, err := repo.List(ctx, filter, page)
+			if err != nil {
+				t.Fatalf("List() failed: %v", err)
+			}
+			for _, expense := range result.Expenses {
+				ids = append(ids, expense.ID)
+			}
+			if result.NextCursor == "" {
+				return ids
+			}
+			page.Cursor = result.NextCursor
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.ExpenseFilter
+		sort   domain.Sort
+		want   []string
+	}{
+		{name: "newest first", sort: domain.Sort{Field: domain.SortByDate, Order: domain.SortDesc}, want: []string{"e", "d", "c", "b", "a"}},
+		{name: "cheapest first", sort: domain.Sort{Field: domain.SortByAmount, Order: domain.SortAsc}, want: []string{"e", "d", "c", "b", "a"}},
+		{name: "oldest first", sort: domain.Sort{Field: domain.SortByDate, Order: domain.SortAsc}, want: []string{"a", "b", "c", "d", "e"}},
+		{name: "visible to alice", filter: domain.ExpenseFilter{VisibleTo: "alice"}, sort: domain.Sort{Field: domain.SortByDate, Order: domain.SortAsc}, want: []string{"a", "b", "d", "e"}},
+		{name: "date range", filter: domain.ExpenseFilter{StartDate: "2024-03-02", EndDate: "2024-03-02"}, sort: domain.Sort{Field: domain.SortByDate, Order: domain.SortAsc}, want: []string{"c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collect(tt.filter, tt.sort)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// A cursor only makes sense in the order it was issued for
+	first, _ := repo.List(ctx, domain.ExpenseFilter{}, domain.PageRequest{Limit: 2, Sort: domain.Sort{Field: domain.SortByDate, Order: domain.SortDesc}})
+	_, err := repo.List(ctx, domain.ExpenseFilter{}, domain.PageRequest{Limit: 2, Cursor: first.NextCursor, Sort: domain.Sort{Field: domain.SortByAmount, Order: domain.SortDesc}})
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestExpenseMemoryRepository_GetByFilters(t *testing.T) {
+	attachments := NewAttachmentMemoryRepository()
+	repo := NewExpenseMemoryRepository().WithAttachments(attachments)
+	ctx := context.Background()
+
+	expenses := []*domain.Expense{
+		{ID: "1", Description: "Pizza and movie night", Category: "Food", PaidBy: "alice", Amount: domain.NewMoney(1200, domain.DefaultCurrency), Splits: []domain.Split{{UserID: "alice"}, {UserID: "bob"}}, Tags: []string{"fun", "weekend"}},
+		{ID: "2", Description: "Groceries", Category: "groceries", PaidBy: "bob", Amount: domain.NewMoney(4500, domain.DefaultCurrency), Splits: []domain.Split{{UserID: "bob"}, {UserID: "carol"}}, Tags: []string{"weekend"}},
+		{ID: "3", Description: "Electricity bill", Category: "Utilities", PaidBy: "carol", Amount: domain.NewMoney(9000, domain.DefaultCurrency), Splits: []domain.Split{{UserID: "alice"}, {UserID: "carol"}}},
+	}
+	for i, expense := range expenses {
+		expense.Date = time.Date(2024, 5, i+1, 0, 0, 0, 0, time.UTC)
+		_ = repo.Create(ctx, expense, nil)
+	}
+	_ = attachments.Create(ctx, &domain.Attachment{ID: "a1", ExpenseID: "3"})
+
+	amount := func(minor int64) *domain.Money { return &domain.Money{Minor: minor} }
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter domain.ExpenseFilter
+		want   []string
+	}{
+		{name: "no filter, newest first", want: []string{"3", "2", "1"}},
+		{name: "categories ignore case", filter: domain.ExpenseFilter{Categories: []string{"food", "GROCERIES"}}, want: []string{"2", "1"}},
+		{name: "payer", filter: domain.ExpenseFilter{PaidBy: "bob"}, want: []string{"2"}},
+		{name: "participant", filter: domain.ExpenseFilter{Participant: "alice"}, want: []string{"3", "1"}},
+		{name: "amount range", filter: domain.ExpenseFilter{MinAmount: amount(1200), MaxAmount: amount(4500)}, want: []string{"2", "1"}},
+		{name: "search", filter: domain.ExpenseFilter{Search: `"movie night" PIZZA`}, want: []string{"1"}},
+		{name: "search matches whole words", filter: domain.ExpenseFilter{Search: "pizz"}},
+		{name: "search excludes", filter: domain.ExpenseFilter{Search: "-pizza"}, want: []string{"3", "2"}},
+		{name: "search either", filter: domain.ExpenseFilter{Search: "groceries or electricity"}, want: []string{"3", "2"}},
+		{name: "all tags", filter: domain.ExpenseFilter{Tags: []string{"weekend", "fun"}}, want: []string{"1"}},
+		{name: "has attachment", filter: domain.ExpenseFilter{HasAttachment: &yes}, want: []string{"3"}},
+		{name: "no attachment", filter: domain.ExpenseFilter{HasAttachment: &no, Tags: []string{"weekend"}}, want: []string{"2", "1"}},
+		{name: "open date range", filter: domain.ExpenseFilter{StartDate: "2024-05-02"}, want: []string{"3", "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.GetByFilters(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetByFilters() failed: %v", err)
+			}
+			var got []string
+			for _, expense := range found {
+				got = append(got, expense.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExpenseMemoryRepository_Totals(t *testing.T) {
+	repo := NewExpenseMemoryRepository()
+	ctx := context.Background()
+
+	inr := func(minor int64) domain.Money { return domain.NewMoney(minor, domain.DefaultCurrency) }
+	expenses := []*domain.Expense{
+		{ID: "1", Category: "food", PaidBy: "alice", Amount: inr(900), Date: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), Splits: []domain.Split{{UserID: "alice", Amount: inr(450)}, {UserID: "bob", Amount: inr(450)}}},
+		{ID: "2", Category: "rent", PaidBy: "bob", Amount: inr(2000), Date: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC), Splits: []domain.Split{{UserID: "alice", Amount: inr(1000)}, {UserID: "bob", Amount: inr(1000)}}},
+		{ID: "3", Category: "food", PaidBy: "alice", Amount: inr(300), Date: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Splits: []domain.Split{{UserID: "bob", Amount: inr(300)}}},
+	}
+	for _, expense := range expenses {
+		_ = repo.Create(ctx, expense, nil)
+	}
+
+	totals, _ := repo.Totals(ctx, domain.TotalsQuery{})
+	want := []domain.ExpenseTotal{
+		{UserID: "alice", Paid: inr(1200), Owed: inr(1450), ExpensesPaid: 2},
+		{UserID: "bob", Paid: inr(2000), Owed: inr(1750), ExpensesPaid: 1},
+	}
+	if !reflect.DeepEqual(totals, want) {
+		t.Errorf("Expected totals %+v, got %+v", want, totals)
+	}
+
+	totals, _ = repo.Totals(ctx, domain.TotalsQuery{UserID: "alice", EndDate: "2024-05-31", ByCategory: true, ByMonth: true})
+	want = []domain.ExpenseTotal{
+		{UserID: "alice", Category: "food", Month: "2024-05", Paid: inr(900), Owed: inr(450), ExpensesPaid: 1},
+		{UserID: "alice", Category: "rent", Month: "2024-05", Paid: inr(0), Owed: inr(1000)},
+	}
+	if !reflect.DeepEqual(totals, want) {
+		t.Errorf("Expected alice's May totals %+v, got %+v", want, totals)
+	}
+}
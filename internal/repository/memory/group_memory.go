@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type GroupMemoryRepository struct {
+	groups map[string]*domain.Group
+	mu     sync.RWMutex
+}
+
+func NewGroupMemoryRepository() *GroupMemoryRepository {
+	return &GroupMemoryRepository{
+		groups: make(map[string]*domain.Group),
+	}
+}
+
+func (repo *GroupMemoryRepository) Create(ctx context.Context, group *domain.Group) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.groups[group.ID] = group
+	return nil
+}
+
+func (repo *GroupMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Group, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	group, exists := repo.groups[id]
+	if !exists {
+		return nil, fmt.Errorf("group not found")
+	}
+	return group, nil
+}
+
+func (repo *GroupMemoryRepository) GetAll(ctx context.Context) ([]*domain.Group, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	groups := make([]*domain.Group, 0, len(repo.groups))
+	for _, group := range repo.groups {
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (repo *GroupMemoryRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Group, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	groups := make([]*domain.Group, 0)
+	for _, group := range repo.groups {
+		if group.HasMember(userID) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (repo *GroupMemoryRepository) Update(ctx context.Context, group *domain.Group) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.groups[group.ID]; !exists {
+		return fmt.Errorf("group not found")
+	}
+
+	repo.groups[group.ID] = group
+	return nil
+}
+
+func (repo *GroupMemoryRepository) AddMember(ctx context.Context, groupID, userID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	group, exists := repo.groups[groupID]
+	if !exists {
+		return fmt.Errorf("group not found")
+	}
+	if !group.HasMember(userID) {
+		group.Members = append(group.Members, userID)
+	}
+	return nil
+}
+
+func (repo *GroupMemoryRepository) RemoveMember(ctx context.Context, groupID, userID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	group, exists := repo.groups[groupID]
+	if !exists {
+		return fmt.Errorf("group not found")
+	}
+	members := make([]string, 0, len(group.Members))
+	for _, member := range group.Members {
+		if member != userID {
+			members = append(members, member)
+		}
+	}
+	group.Members = members
+	return nil
+}
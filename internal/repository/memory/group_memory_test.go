@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func TestGroupMemoryRepository_CreateAndGetByID(t *testing.T) {
+	repo := NewGroupMemoryRepository()
+	ctx := context.Background()
+
+	createGroup := &domain.Group{
+		ID:        "1",
+		Name:      "Flat 4B",
+		Members:   []string{"1", "2"},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := repo.Create(ctx, createGroup)
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	group, err := repo.GetByID(ctx, "1")
+	if err != nil {
+		t.Fatalf("GetByID() failed: %v", err)
+	}
+	if group.Name != createGroup.Name {
+		t.Errorf("Expected name '%s', got '%s'", createGroup.Name, group.Name)
+	}
+	if len(group.Members) != 2 {
+		t.Errorf("Expected 2 members, got %d", len(group.Members))
+	}
+}
+
+func TestGroupMemoryRepository_AddAndRemoveMember(t *testing.T) {
+	repo := NewGroupMemoryRepository()
+	ctx := context.Background()
+
+	_ = repo.Create(ctx, &domain.Group{ID: "1", Name: "Ski Trip", Members: []string{"1"}})
+
+	if err := repo.AddMember(ctx, "1", "2"); err != nil {
+		t.Fatalf("AddMember() failed: %v", err)
+	}
+	// Adding an existing member is a no-op
+	if err := repo.AddMember(ctx, "1", "2"); err != nil {
+		t.Fatalf("AddMember() failed: %v", err)
+	}
+
+	groups, err := repo.GetByUserID(ctx, "2")
+	if err != nil {
+		t.Fatalf("GetByUserID() failed: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Members) != 2 {
+		t.Fatalf("Expected user 2 in one group with 2 members, got %+v", groups)
+	}
+
+	if err := repo.RemoveMember(ctx, "1", "2"); err != nil {
+		t.Fatalf("RemoveMember() failed: %v", err)
+	}
+
+	groups, _ = repo.GetByUserID(ctx, "2")
+	if len(groups) != 0 {
+		t.Fatalf("Expected user 2 in no groups, got %d", len(groups))
+	}
+}
+
+func TestGroupMemoryRepository_GetByIDNotFound(t *testing.T) {
+	repo := NewGroupMemoryRepository()
+	ctx := context.Background()
+
+	group, err := repo.GetByID(ctx, "nonexistent")
+	if err == nil {
+		t.Fatal("Expected error for non-existent group, got nil")
+	}
+	if group != nil {
+		t.Fatal("Expected nil group, got a group")
+	}
+}
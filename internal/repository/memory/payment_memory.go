@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type PaymentMemoryRepository struct {
+	payments map[string]*domain.Payment
+	mu       sync.RWMutex
+}
+
+func NewPaymentMemoryRepository() *PaymentMemoryRepository {
+	return &PaymentMemoryRepository{
+		payments: make(map[string]*domain.Payment),
+	}
+}
+
+func (repo *PaymentMemoryRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.payments[payment.ID] = payment
+	return nil
+}
+
+func (repo *PaymentMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Payment, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	payment, ok := repo.payments[id]
+	if !ok {
+		return nil, fmt.Errorf("payment not found")
+	}
+	return payment, nil
+}
+
+func (repo *PaymentMemoryRepository) GetAll(ctx context.Context) ([]*domain.Payment, error) {
+	return repo.filter(func(*domain.Payment) bool { return true }), nil
+}
+
+func (repo *PaymentMemoryRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Payment, error) {
+	return repo.filter(func(payment *domain.Payment) bool {
+		return payment.From == userID || payment.To == userID
+	}), nil
+}
+
+func (repo *PaymentMemoryRepository) GetByGroupID(ctx context.Context, groupID string) ([]*domain.Payment, error) {
+	return repo.filter(func(payment *domain.Payment) bool {
+		return payment.GroupID == groupID
+	}), nil
+}
+
+func (repo *PaymentMemoryRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.payments[payment.ID]; !ok {
+		return fmt.Errorf("payment not found")
+	}
+	repo.payments[payment.ID] = payment
+	return nil
+}
+
+func (repo *PaymentMemoryRepository) Delete(ctx context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.payments[id]; !ok {
+		return fmt.Errorf("payment not found")
+	}
+	delete(repo.payments, id)
+	return nil
+}
+
+// filter returns the matching payments, newest first like the postgres repository
+func (repo *PaymentMemoryRepository) filter(match func(*domain.Payment) bool) []*domain.Payment {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	payments := make([]*domain.Payment, 0)
+	for _, payment := range repo.payments {
+		if match(payment) {
+			payments = append(payments, payment)
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool {
+		return payments[i].Date.After(payments[j].Date)
+	})
+	return payments
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func TestPaymentMemoryRepository_CRUD(t *testing.T) {
+	repo := NewPaymentMemoryRepository()
+	ctx := context.Background()
+
+	payment := &domain.Payment{
+		ID:     "1",
+		From:   "1",
+		To:     "2",
+		Amount: domain.NewMoney(5000, "INR"),
+		Date:   time.Now(),
+	}
+	if err := repo.Create(ctx, payment); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	for _, userID := range []string{"1", "2"} {
+		payments, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetByUserID() failed: %v", err)
+		}
+		if len(payments) != 1 {
+			t.Errorf("Expected 1 payment for user %s, got %d", userID, len(payments))
+		}
+	}
+
+	if err := repo.Delete(ctx, "1"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "1"); err == nil {
+		t.Error("Expected error after delete, got nil")
+	}
+	if err := repo.Delete(ctx, "1"); err == nil {
+		t.Error("Expected error deleting a missing payment, got nil")
+	}
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type RecurringExpenseMemoryRepository struct {
+	recurring map[string]*domain.RecurringExpense
+	mu        sync.RWMutex
+}
+
+func NewRecurringExpenseMemoryRepository() *RecurringExpenseMemoryRepository {
+	return &RecurringExpenseMemoryRepository{
+		recurring: make(map[string]*domain.RecurringExpense),
+	}
+}
+
+func (repo *RecurringExpenseMemoryRepository) Create(ctx context.Context, recurring *domain.RecurringExpense) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored := *recurring
+	repo.recurring[recurring.ID] = &stored
+	return nil
+}
+
+func (repo *RecurringExpenseMemoryRepository) GetByID(ctx context.Context, id string) (*domain.RecurringExpense, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	recurring, ok := repo.recurring[id]
+	if !ok {
+		return nil, domain.ErrRecurringExpenseNotFound
+	}
+	found := *recurring
+	return &found, nil
+}
+
+func (repo *RecurringExpenseMemoryRepository) GetAll(ctx context.Context) ([]*domain.RecurringExpense, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	all := make([]*domain.RecurringExpense, 0, len(repo.recurring))
+	for _, recurring := range repo.recurring {
+		found := *recurring
+		all = append(all, &found)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].CreatedAt.Before(all[j].CreatedAt)
+	})
+	return all, nil
+}
+
+func (repo *RecurringExpenseMemoryRepository) Delete(ctx context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.recurring[id]; !ok {
+		return domain.ErrRecurringExpenseNotFound
+	}
+	delete(repo.recurring, id)
+	return nil
+}
+
+func (repo *RecurringExpenseMemoryRepository) ClaimOccurrence(ctx context.Context, id string, date time.Time) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	recurring, ok := repo.recurring[id]
+	if !ok {
+		return false, domain.ErrRecurringExpenseNotFound
+	}
+	if recurring.LastOccurrence != nil && !recurring.LastOccurrence.Before(date) {
+		return false, nil
+	}
+	recurring.LastOccurrence = &date
+	return true, nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func TestRecurringExpenseMemoryRepository_ClaimOccurrence(t *testing.T) {
+	repo := NewRecurringExpenseMemoryRepository()
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &domain.RecurringExpense{ID: "1", PaidBy: "1"}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	jan := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	for _, tt := range []struct {
+		date time.Time
+		want bool
+	}{
+		{date: jan, want: true},
+		{date: jan, want: false},
+		{date: feb, want: true},
+		{date: jan, want: false},
+	} {
+		claimed, err := repo.ClaimOccurrence(ctx, "1", tt.date)
+		if err != nil {
+			t.Fatalf("ClaimOccurrence() failed: %v", err)
+		}
+		if claimed != tt.want {
+			t.Errorf("ClaimOccurrence(%s) = %v, want %v", tt.date.Format("2006-01-02"), claimed, tt.want)
+		}
+	}
+
+	recurring, _ := repo.GetByID(ctx, "1")
+	if recurring.LastOccurrence == nil || !recurring.LastOccurrence.Equal(feb) {
+		t.Errorf("Expected last occurrence %v, got %v", feb, recurring.LastOccurrence)
+	}
+
+	if err := repo.Delete(ctx, "1"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := repo.ClaimOccurrence(ctx, "1", feb); !errors.Is(err, domain.ErrRecurringExpenseNotFound) {
+		t.Errorf("Expected not found after delete, got: %v", err)
+	}
+}
@@ -3,14 +3,24 @@ package memory
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pavanrkadave/homies/internal/domain"
 )
 
 type UserMemoryRepository struct {
 	users map[string]*domain.User
 	mu    sync.RWMutex
+	// Records that refer to users, for Delete and Merge; any may be nil
+	expenses  *ExpenseMemoryRepository
+	payments  *PaymentMemoryRepository
+	groups    *GroupMemoryRepository
+	recurring *RecurringExpenseMemoryRepository
 }
 
 func NewUserMemoryRepository() *UserMemoryRepository {
@@ -19,6 +29,16 @@ func NewUserMemoryRepository() *UserMemoryRepository {
 	}
 }
 
+// WithRecords links the repositories that refer to users, so deletes can be
+// refused while a user has history and merges can move it
+func (repo *UserMemoryRepository) WithRecords(expenses *ExpenseMemoryRepository, payments *PaymentMemoryRepository, groups *GroupMemoryRepository, recurring *RecurringExpenseMemoryRepository) *UserMemoryRepository {
+	repo.expenses = expenses
+	repo.payments = payments
+	repo.groups = groups
+	repo.recurring = recurring
+	return repo
+}
+
 func (repo *UserMemoryRepository) Create(ctx context.Context, user *domain.User) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
@@ -42,7 +62,8 @@ func (repo *UserMemoryRepository) GetByEmail(ctx context.Context, email string)
 	repo.mu.RLock()
 	defer repo.mu.RUnlock()
 	for _, user := range repo.users {
-		if user.Email == email {
+		// Guests have no email, so an empty one matches no one
+		if email != "" && user.Email == email {
 			return user, nil
 		}
 	}
@@ -70,3 +91,213 @@ func (repo *UserMemoryRepository) Update(ctx context.Context, user *domain.User)
 	repo.users[user.ID] = user
 	return nil
 }
+
+func (repo *UserMemoryRepository) Delete(ctx context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.users[id]; !exists {
+		return fmt.Errorf("user not found")
+	}
+	if repo.hasHistory(id) {
+		return domain.ErrUserHasHistory
+	}
+	delete(repo.users, id)
+	if repo.groups != nil {
+		repo.groups.mu.Lock()
+		for _, group := range repo.groups.groups {
+			group.Members = slices.DeleteFunc(group.Members, func(member string) bool { return member == id })
+		}
+		repo.groups.mu.Unlock()
+	}
+	return nil
+}
+
+// hasHistory reports whether any expense, deleted or not, or payment refers
+// to the user
+func (repo *UserMemoryRepository) hasHistory(id string) bool {
+	if repo.expenses != nil {
+		repo.expenses.mu.RLock()
+		defer repo.expenses.mu.RUnlock()
+		for _, expenses := range []map[string]*domain.Expense{repo.expenses.expenses, repo.expenses.trash} {
+			for _, expense := range expenses {
+				if expense.PaidBy == id || slices.ContainsFunc(expense.Splits, func(split domain.Split) bool { return split.UserID == id }) {
+					return true
+				}
+			}
+		}
+	}
+	if repo.payments != nil {
+		repo.payments.mu.RLock()
+		defer repo.payments.mu.RUnlock()
+		for _, payment := range repo.payments.payments {
+			if payment.From == id || payment.To == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (repo *UserMemoryRepository) Merge(ctx context.Context, fromID, intoID, actorID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.users[fromID]; !exists {
+		return fmt.Errorf("user not found")
+	}
+	if _, exists := repo.users[intoID]; !exists {
+		return fmt.Errorf("user not found")
+	}
+
+	if repo.expenses != nil {
+		repo.expenses.mu.Lock()
+		now := time.Now()
+		var events []*domain.ExpenseEvent
+		for _, expenses := range []map[string]*domain.Expense{repo.expenses.expenses, repo.expenses.trash} {
+			for _, expense := range expenses {
+				before := domain.SnapshotOf(expense)
+				if !before.Involves(fromID) {
+					continue
+				}
+				if expense.PaidBy == fromID {
+					expense.PaidBy = intoID
+				}
+				expense.Splits = moveSplits(expense.Splits, fromID, intoID)
+				for i := range expense.Items {
+					expense.Items[i].UserIDs = moveUserID(expense.Items[i].UserIDs, fromID, intoID)
+				}
+				events = append(events, &domain.ExpenseEvent{
+					ID:        uuid.New().String(),
+					ExpenseID: expense.ID,
+					Action:    domain.ExpenseUpdated,
+					ActorID:   actorID,
+					Before:    before,
+					After:     domain.SnapshotOf(expense),
+					CreatedAt: now,
+				})
+			}
+		}
+		repo.expenses.appendEvents(ctx, events...)
+		repo.expenses.mu.Unlock()
+	}
+	if repo.payments != nil {
+		repo.payments.mu.Lock()
+		for id, payment := range repo.payments.payments {
+			switch {
+			case payment.From == fromID && payment.To == intoID, payment.From == intoID && payment.To == fromID:
+				// It would become a payment to oneself
+				delete(repo.payments.payments, id)
+			case payment.From == fromID:
+				payment.From = intoID
+			case payment.To == fromID:
+				payment.To = intoID
+			}
+		}
+		repo.payments.mu.Unlock()
+	}
+	if repo.groups != nil {
+		repo.groups.mu.Lock()
+		for _, group := range repo.groups.groups {
+			group.Members = moveUserID(group.Members, fromID, intoID)
+		}
+		repo.groups.mu.Unlock()
+	}
+	if repo.recurring != nil {
+		repo.recurring.mu.Lock()
+		for _, recurring := range repo.recurring.recurring {
+			if recurring.PaidBy == fromID {
+				recurring.PaidBy = intoID
+			}
+			recurring.Splits = moveSplits(recurring.Splits, fromID, intoID)
+			recurring.UserIDs = moveUserID(recurring.UserIDs, fromID, intoID)
+		}
+		repo.recurring.mu.Unlock()
+	}
+
+	delete(repo.users, fromID)
+	return nil
+}
+
+// moveSplits gives fromID's split to intoID, adding it to intoID's own split
+// if there is one
+func moveSplits(splits []domain.Split, fromID, intoID string) []domain.Split {
+	from := slices.IndexFunc(splits, func(split domain.Split) bool { return split.UserID == fromID })
+	if from < 0 {
+		return splits
+	}
+	into := slices.IndexFunc(splits, func(split domain.Split) bool { return split.UserID == intoID })
+	if into < 0 {
+		splits[from].UserID = intoID
+		return splits
+	}
+	splits[into].Amount = splits[into].Amount.Add(splits[from].Amount)
+	return slices.Delete(splits, from, from+1)
+}
+
+// moveUserID replaces fromID with intoID in ids, dropping it if intoID is
+// already there
+func moveUserID(ids []string, fromID, intoID string) []string {
+	from := slices.Index(ids, fromID)
+	if from < 0 {
+		return ids
+	}
+	if slices.Contains(ids, intoID) {
+		return slices.Delete(ids, from, from+1)
+	}
+	ids[from] = intoID
+	return ids
+}
+
+func (repo *UserMemoryRepository) List(ctx context.Context, page domain.PageRequest) (*domain.UserPage, error) {
+	cursor, err := domain.DecodeCursor(page.Cursor, page.Sort)
+	if err != nil {
+		return nil, err
+	}
+	var after *domain.User
+	if cursor != nil {
+		after = &domain.User{ID: cursor.ID, Name: cursor.Value}
+		if page.Sort.Field != domain.SortByName {
+			if after.CreatedAt, err = cursor.Time(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	repo.mu.RLock()
+	users := make([]*domain.User, 0, len(repo.users))
+	for _, user := range repo.users {
+		if after == nil || compareUsers(user, after, page.Sort) > 0 {
+			users = append(users, user)
+		}
+	}
+	repo.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool {
+		return compareUsers(users[i], users[j], page.Sort) < 0
+	})
+
+	result := &domain.UserPage{Users: users}
+	if len(users) > page.Limit {
+		result.Users = users[:page.Limit]
+		result.NextCursor = domain.UserCursor(result.Users[page.Limit-1], page.Sort)
+	}
+	return result, nil
+}
+
+// compareUsers orders users by the sort field, then by ID
+func compareUsers(a, b *domain.User, s domain.Sort) int {
+	var c int
+	if s.Field == domain.SortByName {
+		c = strings.Compare(a.Name, b.Name)
+	} else {
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if s.Order == domain.SortDesc {
+		c = -c
+	}
+	return c
+}
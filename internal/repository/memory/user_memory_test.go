@@ -148,3 +148,33 @@ func TestUserMemoryRepository_CreateAndGetAllNil(t *testing.T) {
 	}
 
 }
+
+func TestUserMemoryRepository_List(t *testing.T) {
+	repo := NewUserMemoryRepository()
+	ctx := context.Background()
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"carol", "alice", "bob"} {
+		if err := repo.Create(ctx, &domain.User{ID: name, Name: name, CreatedAt: created.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	page := domain.PageRequest{Limit: 2, Sort: domain.Sort{Field: domain.SortByName, Order: domain.SortAsc}}
+	first, err := repo.List(ctx, page)
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(first.Users) != 2 || first.Users[0].ID != "alice" || first.Users[1].ID != "bob" || first.NextCursor == "" {
+		t.Fatalf("Unexpected first page: %+v", first)
+	}
+
+	page.Cursor = first.NextCursor
+	second, err := repo.List(ctx, page)
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(second.Users) != 1 || second.Users[0].ID != "carol" || second.NextCursor != "" {
+		t.Errorf("Unexpected last page: %+v", second)
+	}
+}
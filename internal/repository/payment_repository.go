@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *domain.Payment) error
+	GetByID(ctx context.Context, id string) (*domain.Payment, error)
+	GetAll(ctx context.Context) ([]*domain.Payment, error)
+	GetByUserID(ctx context.Context, userID string) ([]*domain.Payment, error)
+	GetByGroupID(ctx context.Context, groupID string) ([]*domain.Payment, error)
+	Update(ctx context.Context, payment *domain.Payment) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.AttachmentRepository = (*AttachmentPostgresRepository)(nil)
+
+// attachmentColumns is the column list every attachment query selects, in scan order
+const attachmentColumns = `id, expense_id, file_name, content_type, size, storage_key, COALESCE(uploaded_by, ''), created_at`
+
+type AttachmentPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentPostgresRepository(db *sql.DB) *AttachmentPostgresRepository {
+	return &AttachmentPostgresRepository{db: db}
+}
+
+func (r *AttachmentPostgresRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO expense_attachments (id, expense_id, file_name, content_type, size, storage_key, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		attachment.ID,
+		attachment.ExpenseID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.StorageKey,
+		attachment.UploadedBy,
+		attachment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *AttachmentPostgresRepository) GetByID(ctx context.Context, id string) (*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM expense_attachments WHERE id = $1`
+
+	attachment, err := scanAttachment(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentPostgresRepository) GetByExpenseID(ctx context.Context, expenseID string) ([]*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM expense_attachments WHERE expense_id = $1 ORDER BY created_at`
+	return r.list(ctx, query, expenseID)
+}
+
+func (r *AttachmentPostgresRepository) GetAll(ctx context.Context) ([]*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM expense_attachments ORDER BY created_at`
+	return r.list(ctx, query)
+}
+
+func (r *AttachmentPostgresRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM expense_attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (r *AttachmentPostgresRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := make([]*domain.Attachment, 0)
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func scanAttachment(row rowScanner) (*domain.Attachment, error) {
+	attachment := &domain.Attachment{}
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.ExpenseID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.AuthRepository = (*AuthPostgresRepository)(nil)
+
+type AuthPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewAuthPostgresRepository(db *sql.DB) *AuthPostgresRepository {
+	return &AuthPostgresRepository{db: db}
+}
+
+func (r *AuthPostgresRepository) SetPasswordHash(ctx context.Context, userID, hash string) error {
+	query := `
+		INSERT INTO user_credentials (user_id, password_hash, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET password_hash = EXCLUDED.password_hash, updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, userID, hash, time.Now()); err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthPostgresRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	query := `SELECT password_hash FROM user_credentials WHERE user_id = $1`
+
+	var hash string
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrPasswordNotSet
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get password: %w", err)
+	}
+	return hash, nil
+}
+
+func (r *AuthPostgresRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	query := `
+		INSERT INTO sessions (token_hash, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.ExecContext(ctx, query, session.TokenHash, session.UserID, session.CreatedAt, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthPostgresRepository) GetSession(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	query := `SELECT token_hash, user_id, created_at, expires_at FROM sessions WHERE token_hash = $1`
+
+	session := &domain.Session{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&session.TokenHash,
+		&session.UserID,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+func (r *AuthPostgresRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_hash = $1`, tokenHash); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthPostgresRepository) DeleteUserSessions(ctx context.Context, userID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthPostgresRepository) CreateMagicLink(ctx context.Context, link *domain.MagicLink) error {
+	query := `
+		INSERT INTO magic_links (token_hash, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.ExecContext(ctx, query, link.TokenHash, link.UserID, link.CreatedAt, link.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create magic link: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthPostgresRepository) ConsumeMagicLink(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLink, error) {
+	// Deleting in the same statement makes the link single-use under concurrency
+	query := `
+		DELETE FROM magic_links
+		WHERE token_hash = $1 AND expires_at > $2
+		RETURNING token_hash, user_id, created_at, expires_at
+	`
+
+	link := &domain.MagicLink{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash, now).Scan(
+		&link.TokenHash,
+		&link.UserID,
+		&link.CreatedAt,
+		&link.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume magic link: %w", err)
+	}
+	return link, nil
+}
+
+func (r *AuthPostgresRepository) CreateGuestClaim(ctx context.Context, claim *domain.GuestClaim) error {
+	query := `
+		INSERT INTO guest_claims (token_hash, guest_id, email, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.ExecContext(ctx, query, claim.TokenHash, claim.GuestID, claim.Email, claim.CreatedAt, claim.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create guest claim: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthPostgresRepository) ConsumeGuestClaim(ctx context.Context, tokenHash string, now time.Time) (*domain.GuestClaim, error) {
+	// Deleting in the same statement makes the claim single-use under concurrency
+	query := `
+		DELETE FROM guest_claims
+		WHERE token_hash = $1 AND expires_at > $2
+		RETURNING token_hash, guest_id, email, created_at, expires_at
+	`
+
+	claim := &domain.GuestClaim{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash, now).Scan(
+		&claim.TokenHash,
+		&claim.GuestID,
+		&claim.Email,
+		&claim.CreatedAt,
+		&claim.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume guest claim: %w", err)
+	}
+	return claim, nil
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.BudgetRepository = (*BudgetPostgresRepository)(nil)
+
+// budgetColumns is the column list every budget query selects, in scan order
+const budgetColumns = `id, category, period, amount, currency, COALESCE(user_id, ''), COALESCE(group_id, ''), created_at, updated_at`
+
+// budgetAlertColumns is the column list every alert query selects, in scan
+// order
+const budgetAlertColumns = `id, COALESCE(budget_id, ''), category, period, COALESCE(user_id, ''), COALESCE(group_id, ''),
+	expense_id, TO_CHAR(period_start, 'YYYY-MM-DD'), threshold, amount, spent, currency, created_at`
+
+type BudgetPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewBudgetPostgresRepository(db *sql.DB) *BudgetPostgresRepository {
+	return &BudgetPostgresRepository{db: db}
+}
+
+func (r *BudgetPostgresRepository) Create(ctx context.Context, budget *domain.Budget) error {
+	query := `
+		INSERT INTO budgets (id, category, period, amount, currency, user_id, group_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		budget.ID,
+		budget.Category,
+		budget.Period,
+		budget.Amount,
+		budget.Amount.Currency,
+		budget.UserID,
+		budget.GroupID,
+		budget.CreatedAt,
+		budget.UpdatedAt,
+	)
+	if err != nil {
+		return budgetError("create", err)
+	}
+	return nil
+}
+
+func (r *BudgetPostgresRepository) GetByID(ctx context.Context, id string) (*domain.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE id = $1`
+
+	budget, err := scanBudget(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrBudgetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+	return budget, nil
+}
+
+func (r *BudgetPostgresRepository) GetAll(ctx context.Context) ([]*domain.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets
+		ORDER BY category, period, COALESCE(group_id, ''), COALESCE(user_id, '')`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var budgets []*domain.Budget
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (r *BudgetPostgresRepository) Update(ctx context.Context, budget *domain.Budget) error {
+	query := `
+		UPDATE budgets
+		SET category = $1, period = $2, amount = $3, currency = $4, user_id = NULLIF($5, ''), group_id = NULLIF($6, ''), updated_at = $7
+		WHERE id = $8
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		budget.Category,
+		budget.Period,
+		budget.Amount,
+		budget.Amount.Currency,
+		budget.UserID,
+		budget.GroupID,
+		budget.UpdatedAt,
+		budget.ID,
+	)
+	if err != nil {
+		return budgetError("update", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrBudgetNotFound
+	}
+	return nil
+}
+
+func (r *BudgetPostgresRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM budgets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrBudgetNotFound
+	}
+	return nil
+}
+
+func (r *BudgetPostgresRepository) AppendAlert(ctx context.Context, alert *domain.BudgetAlert) error {
+	query := `
+		INSERT INTO budget_alerts (id, budget_id, category, period, user_id, group_id, expense_id, period_start, threshold, amount, spent, currency, created_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		alert.ID,
+		alert.BudgetID,
+		alert.Category,
+		alert.Period,
+		alert.UserID,
+		alert.GroupID,
+		alert.ExpenseID,
+		alert.PeriodStart,
+		alert.Threshold,
+		alert.Amount,
+		alert.Spent,
+		alert.Currency,
+		alert.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record budget alert: %w", err)
+	}
+	return nil
+}
+
+func (r *BudgetPostgresRepository) GetAlerts(ctx context.Context, from, to time.Time) ([]*domain.BudgetAlert, error) {
+	query := `SELECT ` + budgetAlertColumns + ` FROM budget_alerts
+		WHERE created_at >= $1 AND created_at < $2
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*domain.BudgetAlert
+	for rows.Next() {
+		alert := &domain.BudgetAlert{}
+		err := rows.Scan(
+			&alert.ID,
+			&alert.BudgetID,
+			&alert.Category,
+			&alert.Period,
+			&alert.UserID,
+			&alert.GroupID,
+			&alert.ExpenseID,
+			&alert.PeriodStart,
+			&alert.Threshold,
+			&alert.Amount,
+			&alert.Spent,
+			&alert.Currency,
+			&alert.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		alert.Amount.Currency = alert.Currency
+		alert.Spent.Currency = alert.Currency
+		alerts = append(alerts, alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// budgetError maps a duplicate scope to domain.ErrBudgetExists
+func budgetError(action string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.ErrBudgetExists
+	}
+	return fmt.Errorf("failed to %s budget: %w", action, err)
+}
+
+func scanBudget(row rowScanner) (*domain.Budget, error) {
+	budget := &domain.Budget{}
+	var currency string
+	err := row.Scan(
+		&budget.ID,
+		&budget.Category,
+		&budget.Period,
+		&budget.Amount,
+		&currency,
+		&budget.UserID,
+		&budget.GroupID,
+		&budget.CreatedAt,
+		&budget.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	budget.Amount.Currency = domain.Currency(currency)
+	return budget, nil
+}
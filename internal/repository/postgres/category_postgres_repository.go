@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.CategoryRepository = (*CategoryPostgresRepository)(nil)
+
+// categoryColumns is the column list every category query selects, in scan
+// order
+const categoryColumns = `id, name, COALESCE(parent_id, ''), icon, color, monthly_budget, budget_currency, created_at, updated_at`
+
+// uniqueViolation is the Postgres error code for a duplicate unique key
+const uniqueViolation = "23505"
+
+type CategoryPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewCategoryPostgresRepository(db *sql.DB) *CategoryPostgresRepository {
+	return &CategoryPostgresRepository{db: db}
+}
+
+func (r *CategoryPostgresRepository) Create(ctx context.Context, category *domain.Category) error {
+	query := `
+		INSERT INTO categories (id, name, parent_id, icon, color, monthly_budget, budget_currency, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9)
+	`
+	budget, currency := categoryBudget(category)
+	_, err := r.db.ExecContext(ctx, query,
+		category.ID,
+		category.Name,
+		category.ParentID,
+		category.Icon,
+		category.Color,
+		budget,
+		currency,
+		category.CreatedAt,
+		category.UpdatedAt,
+	)
+	if err != nil {
+		return categoryError("create", err)
+	}
+	return nil
+}
+
+func (r *CategoryPostgresRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+func (r *CategoryPostgresRepository) GetByName(ctx context.Context, name string) (*domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE name = $1`
+	return r.get(ctx, query, name)
+}
+
+func (r *CategoryPostgresRepository) get(ctx context.Context, query string, arg string) (*domain.Category, error) {
+	category, err := scanCategory(r.db.QueryRowContext(ctx, query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return category, nil
+}
+
+func (r *CategoryPostgresRepository) GetAll(ctx context.Context) ([]*domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *CategoryPostgresRepository) Update(ctx context.Context, category *domain.Category) error {
+	// Expenses and recurring expenses follow a rename through ON UPDATE CASCADE
+	query := `
+		UPDATE categories
+		SET name = $1, parent_id = NULLIF($2, ''), icon = $3, color = $4, monthly_budget = $5, budget_currency = $6, updated_at = $7
+		WHERE id = $8
+	`
+	budget, currency := categoryBudget(category)
+	result, err := r.db.ExecContext(ctx, query,
+		category.Name,
+		category.ParentID,
+		category.Icon,
+		category.Color,
+		budget,
+		currency,
+		category.UpdatedAt,
+		category.ID,
+	)
+	if err != nil {
+		return categoryError("update", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (r *CategoryPostgresRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return categoryError("delete", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// categoryError maps constraint violations to the domain's category errors
+func categoryError(action string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return domain.ErrCategoryExists
+		case foreignKeyViolation:
+			if action == "delete" {
+				return domain.ErrCategoryInUse
+			}
+			return domain.ErrCategoryNotFound
+		}
+	}
+	return fmt.Errorf("failed to %s category: %w", action, err)
+}
+
+// categoryBudget returns the budget columns of a category, NULL if it has no
+// budget
+func categoryBudget(category *domain.Category) (budget, currency interface{}) {
+	if category.MonthlyBudget == nil {
+		return nil, nil
+	}
+	return *category.MonthlyBudget, category.MonthlyBudget.Currency
+}
+
+func scanCategory(row rowScanner) (*domain.Category, error) {
+	category := &domain.Category{}
+	var budget domain.Money
+	var currency sql.NullString
+	err := row.Scan(
+		&category.ID,
+		&category.Name,
+		&category.ParentID,
+		&category.Icon,
+		&category.Color,
+		&budget,
+		&currency,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if currency.Valid {
+		budget.Currency = domain.Currency(currency.String)
+		category.MonthlyBudget = &budget
+	}
+	return category, nil
+}
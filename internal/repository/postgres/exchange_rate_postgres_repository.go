@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.ExchangeRateRepository = (*ExchangeRatePostgresRepository)(nil)
+
+type ExchangeRatePostgresRepository struct {
+	db *sql.DB
+}
+
+func NewExchangeRatePostgresRepository(db *sql.DB) *ExchangeRatePostgresRepository {
+	return &ExchangeRatePostgresRepository{db: db}
+}
+
+func (r *ExchangeRatePostgresRepository) Save(ctx context.Context, rate *domain.ExchangeRate) error {
+	query := `
+		INSERT INTO exchange_rates (from_currency, to_currency, rate, effective_date)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (from_currency, to_currency, effective_date) DO UPDATE SET rate = EXCLUDED.rate
+	`
+	_, err := r.db.ExecContext(ctx, query, rate.From, rate.To, rate.Rate, rate.EffectiveDate)
+	if err != nil {
+		return fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+	return nil
+}
+
+func (r *ExchangeRatePostgresRepository) GetRate(ctx context.Context, from, to domain.Currency, date time.Time) (*domain.ExchangeRate, error) {
+	query := `
+		SELECT from_currency, to_currency, rate::TEXT, effective_date
+		FROM exchange_rates
+		WHERE from_currency = $1 AND to_currency = $2 AND effective_date <= $3
+		ORDER BY effective_date DESC
+		LIMIT 1
+	`
+
+	rate := &domain.ExchangeRate{}
+	err := r.db.QueryRowContext(ctx, query, from, to, date).Scan(
+		&rate.From,
+		&rate.To,
+		&rate.Rate,
+		&rate.EffectiveDate,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrExchangeRateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	return rate, nil
+}
+
+func (r *ExchangeRatePostgresRepository) GetByPair(ctx context.Context, from, to domain.Currency) ([]*domain.ExchangeRate, error) {
+	query := `
+		SELECT from_currency, to_currency, rate::TEXT, effective_date
+		FROM exchange_rates
+		WHERE from_currency = $1 AND to_currency = $2
+		ORDER BY effective_date DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make([]*domain.ExchangeRate, 0)
+	for rows.Next() {
+		rate := &domain.ExchangeRate{}
+		if err := rows.Scan(&rate.From, &rate.To, &rate.Rate, &rate.EffectiveDate); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.ExpenseEventRepository = (*ExpenseEventPostgresRepository)(nil)
+
+type ExpenseEventPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewExpenseEventPostgresRepository(db *sql.DB) *ExpenseEventPostgresRepository {
+	return &ExpenseEventPostgresRepository{db: db}
+}
+
+func (r *ExpenseEventPostgresRepository) Append(ctx context.Context, event *domain.ExpenseEvent) error {
+	return appendExpenseEvent(ctx, r.db, event)
+}
+
+// execer runs a statement on the database or inside a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// appendExpenseEvent inserts an event through db, which the expense
+// repository passes its transaction as
+func appendExpenseEvent(ctx context.Context, db execer, event *domain.ExpenseEvent) error {
+	before, err := marshalSnapshot(event.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalSnapshot(event.After)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO expense_events (id, expense_id, action, actor_id, before, after, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+	`
+	_, err = db.ExecContext(ctx, query,
+		event.ID,
+		event.ExpenseID,
+		event.Action,
+		event.ActorID,
+		before,
+		after,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to append expense event: %w", err)
+	}
+	return nil
+}
+
+func (r *ExpenseEventPostgresRepository) GetByExpenseID(ctx context.Context, expenseID string) ([]*domain.ExpenseEvent, error) {
+	query := `
+		SELECT id, expense_id, action, COALESCE(actor_id, ''), before, after, created_at
+		FROM expense_events
+		WHERE expense_id = $1
+		ORDER BY created_at, seq
+	`
+	rows, err := r.db.QueryContext(ctx, query, expenseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expense events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.ExpenseEvent
+	for rows.Next() {
+		var event domain.ExpenseEvent
+		var before, after []byte
+		if err := rows.Scan(&event.ID, &event.ExpenseID, &event.Action, &event.ActorID, &before, &after, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan expense event: %w", err)
+		}
+		if event.Before, err = unmarshalSnapshot(before); err != nil {
+			return nil, err
+		}
+		if event.After, err = unmarshalSnapshot(after); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate expense events: %w", err)
+	}
+	return events, nil
+}
+
+// marshalSnapshot encodes a snapshot for a JSONB column, or NULL for nil
+func marshalSnapshot(snapshot *domain.ExpenseSnapshot) ([]byte, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode expense snapshot: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalSnapshot(data []byte) (*domain.ExpenseSnapshot, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var snapshot domain.ExpenseSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode expense snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
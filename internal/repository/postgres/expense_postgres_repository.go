@@ -6,48 +6,98 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/pavanrkadave/homies/internal/domain"
 	"github.com/pavanrkadave/homies/internal/repository"
 )
 
-var _ repository.ExpenseRepository = (*ExpensePostgresRepository)(nil)
+var (
+	_ repository.ExpenseRepository = (*ExpensePostgresRepository)(nil)
+	_ repository.ExpenseAggregator = (*ExpensePostgresRepository)(nil)
+)
+
+// expenseColumns is the column list every expense query selects, in scan order
+const expenseColumns = `id, description, amount, currency, category, paid_by, COALESCE(group_id, ''), date, created_at, updated_at, deleted_at, tags,
+	COALESCE(recurring_id, ''), COALESCE(TO_CHAR(occurrence_date, 'YYYY-MM-DD'), '')`
 
 type ExpensePostgresRepository struct {
 	db *sql.DB
+	// balanceSnapshots is set once Totals may read from user_balances
+	balanceSnapshots bool
 }
 
 func NewExpensePostgresRepository(db *sql.DB) *ExpensePostgresRepository {
 	return &ExpensePostgresRepository{db: db}
 }
 
-func (r *ExpensePostgresRepository) Create(ctx context.Context, expense *domain.Expense) error {
+func (r *ExpensePostgresRepository) Create(ctx context.Context, expense *domain.Expense, event *domain.ExpenseEvent) error {
+	var events []*domain.ExpenseEvent
+	if event != nil {
+		events = append(events, event)
+	}
+	return r.CreateBatch(ctx, []*domain.Expense{expense}, events)
+}
+
+func (r *ExpensePostgresRepository) CreateBatch(ctx context.Context, expenses []*domain.Expense, events []*domain.ExpenseEvent) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func(tx *sql.Tx) {
 		err := tx.Rollback()
-		if err != nil {
-			log.Printf("failed to rollback transaction: %w", err)
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
 		}
 	}(tx)
 
+	for _, expense := range expenses {
+		if err := insertExpense(ctx, tx, expense); err != nil {
+			return err
+		}
+	}
+	for _, event := range events {
+		if err := appendExpenseEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertExpense inserts an expense with its splits and items
+func insertExpense(ctx context.Context, tx *sql.Tx, expense *domain.Expense) error {
 	// Insert expense
 	expenseQuery := `
-		INSERT INTO expenses (id, description, amount, category, paid_by, date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO expenses (id, description, amount, currency, category, paid_by, group_id, date, created_at, updated_at, tags, recurring_id, occurrence_date)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, '')::DATE)
 	`
-	_, err = tx.ExecContext(ctx, expenseQuery,
+	_, err := tx.ExecContext(ctx, expenseQuery,
 		expense.ID,
 		expense.Description,
 		expense.Amount,
+		expense.Amount.Currency,
 		expense.Category,
 		expense.PaidBy,
+		expense.GroupID,
 		expense.Date,
 		expense.CreatedAt,
 		expense.UpdatedAt,
+		tagsArray(expense.Tags),
+		expense.RecurringID,
+		expense.OccurrenceDate,
 	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "idx_expenses_occurrence" {
+		return domain.ErrOccurrenceExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create expense: %w", err)
 	}
@@ -68,78 +118,61 @@ func (r *ExpensePostgresRepository) Create(ctx context.Context, expense *domain.
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if err := insertItems(ctx, tx, expense); err != nil {
+		return err
 	}
-
-	return nil
+	return adjustUserBalances(ctx, tx, expense.ID, 1)
 }
 
 func (r *ExpensePostgresRepository) GetByID(ctx context.Context, id string) (*domain.Expense, error) {
-	expenseQuery := `
-		SELECT id, description, amount, category, paid_by, date, created_at, updated_at
-		FROM expenses
-		WHERE id = $1
-	`
+	expenseQuery := `SELECT ` + expenseColumns + ` FROM expenses WHERE id = $1 AND deleted_at IS NULL`
+	return r.getOne(ctx, expenseQuery, id)
+}
+
+func (r *ExpensePostgresRepository) GetDeletedByID(ctx context.Context, id string) (*domain.Expense, error) {
+	expenseQuery := `SELECT ` + expenseColumns + ` FROM expenses WHERE id = $1 AND deleted_at IS NOT NULL`
+	return r.getOne(ctx, expenseQuery, id)
+}
 
+// getOne loads the single expense selected by query, with its splits
+func (r *ExpensePostgresRepository) getOne(ctx context.Context, expenseQuery, id string) (*domain.Expense, error) {
 	expense := &domain.Expense{}
 	err := r.db.QueryRowContext(ctx, expenseQuery, id).Scan(
 		&expense.ID,
 		&expense.Description,
 		&expense.Amount,
+		&expense.Amount.Currency,
 		&expense.Category,
 		&expense.PaidBy,
+		&expense.GroupID,
 		&expense.Date,
 		&expense.CreatedAt,
 		&expense.UpdatedAt,
+		&expense.DeletedAt,
+		pq.Array(&expense.Tags),
+		&expense.RecurringID,
+		&expense.OccurrenceDate,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("expense not found")
+		return nil, domain.ErrExpenseNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expense: %w", err)
 	}
 
-	// Get splits for this expense
-	splitsQuery := `
-		SELECT expense_id, user_id, amount
-		FROM splits
-		WHERE expense_id = $1
-	`
-
-	rows, err := r.db.QueryContext(ctx, splitsQuery, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get splits: %w", err)
-	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-			log.Printf("failed to close rows: %v", err)
-		}
-	}(rows)
-
-	var splits []domain.Split
-	for rows.Next() {
-		var split domain.Split
-		if err := rows.Scan(&split.ExpenseID, &split.UserID, &split.Amount); err != nil {
-			return nil, fmt.Errorf("failed to scan split: %w", err)
-		}
-		splits = append(splits, split)
+	if err := r.loadDetails(ctx, []*domain.Expense{expense}); err != nil {
+		return nil, err
 	}
-
-	expense.Splits = splits
 	return expense, nil
 }
 
 func (r *ExpensePostgresRepository) GetAll(ctx context.Context) ([]*domain.Expense, error) {
-	allExpenseQuery := `SELECT id, description, amount, category, paid_by, date, created_at, updated_at FROM expenses`
+	allExpenseQuery := `SELECT ` + expenseColumns + ` FROM expenses WHERE deleted_at IS NULL`
 
-	var expenses []*domain.Expense
 	expenseRows, err := r.db.QueryContext(ctx, allExpenseQuery)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get users: %w", err)
+		return nil, fmt.Errorf("failed to get expenses: %w", err)
 	}
 
 	defer func(rows *sql.Rows) {
@@ -149,52 +182,17 @@ func (r *ExpensePostgresRepository) GetAll(ctx context.Context) ([]*domain.Expen
 		}
 	}(expenseRows)
 
-	for expenseRows.Next() {
-		expense := &domain.Expense{}
-		if err := expenseRows.Scan(&expense.ID,
-			&expense.Description,
-			&expense.Amount,
-			&expense.Category,
-			&expense.PaidBy,
-			&expense.Date,
-			&expense.CreatedAt,
-			&expense.UpdatedAt); err != nil {
-			return nil, err
-		}
-		expenses = append(expenses, expense)
-	}
-
-	if err := expenseRows.Err(); err != nil {
-		return nil, err
-	}
-
-	for _, expense := range expenses {
-		splitsQuery := `SELECT expense_id, user_id, amount FROM splits WHERE expense_id = $1`
-		rows, err := r.db.QueryContext(ctx, splitsQuery, expense.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get splits: %w", err)
-		}
-		var splits []domain.Split
-		for rows.Next() {
-			var split domain.Split
-			if err := rows.Scan(&split.ExpenseID, &split.UserID, &split.Amount); err != nil {
-				return nil, fmt.Errorf("failed to scan split: %w", err)
-			}
-			splits = append(splits, split)
-		}
-		expense.Splits = splits
-	}
-	return expenses, nil
+	return r.scanExpensesWithSplits(ctx, expenseRows)
 }
 
 func (r *ExpensePostgresRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Expense, error) {
-	// Get all unique expense IDs where user is involved
+	// Get all expenses where user is the payer or has a split
 	expenseQuery := `
-        SELECT DISTINCT e.id, e.description, e.amount, e.category, e.paid_by, e.date, e.created_at, e.updated_at
-        FROM expenses e
-        LEFT JOIN splits s ON e.id = s.expense_id
-        WHERE e.paid_by = $1 OR s.user_id = $1
-    `
+		SELECT ` + expenseColumns + `
+		FROM expenses
+		WHERE (paid_by = $1 OR id IN (SELECT expense_id FROM splits WHERE user_id = $1))
+		  AND deleted_at IS NULL
+	`
 
 	rows, err := r.db.QueryContext(ctx, expenseQuery, userID)
 	if err != nil {
@@ -207,78 +205,61 @@ func (r *ExpensePostgresRepository) GetByUserID(ctx context.Context, userID stri
 		}
 	}(rows)
 
-	var expenses []*domain.Expense
-	for rows.Next() {
-		expense := &domain.Expense{}
-		if err := rows.Scan(
-			&expense.ID,
-			&expense.Description,
-			&expense.Amount,
-			&expense.Category,
-			&expense.PaidBy,
-			&expense.Date,
-			&expense.CreatedAt,
-			&expense.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		expenses = append(expenses, expense)
-	}
+	return r.scanExpensesWithSplits(ctx, rows)
+}
 
-	// Get splits for each expense
-	for _, expense := range expenses {
-		splitsQuery := `SELECT expense_id, user_id, amount FROM splits WHERE expense_id = $1`
-		splitRows, err := r.db.QueryContext(ctx, splitsQuery, expense.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get splits: %w", err)
-		}
+func (r *ExpensePostgresRepository) GetByGroupID(ctx context.Context, groupID string) ([]*domain.Expense, error) {
+	query := `
+		SELECT ` + expenseColumns + `
+		FROM expenses
+		WHERE group_id = $1 AND deleted_at IS NULL
+		ORDER BY date DESC
+	`
 
-		var splits []domain.Split
-		for splitRows.Next() {
-			var split domain.Split
-			if err := splitRows.Scan(&split.ExpenseID, &split.UserID, &split.Amount); err != nil {
-				err := splitRows.Close()
-				if err != nil {
-					return nil, err
-				}
-				return nil, fmt.Errorf("failed to scan split: %w", err)
-			}
-			splits = append(splits, split)
-		}
-		err = splitRows.Close()
-		if err != nil {
-			return nil, err
-		}
-		expense.Splits = splits
+	rows, err := r.db.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses by group: %w", err)
 	}
+	defer rows.Close()
 
-	return expenses, nil
+	return r.scanExpensesWithSplits(ctx, rows)
 }
 
-func (r *ExpensePostgresRepository) Update(ctx context.Context, expense *domain.Expense) error {
+func (r *ExpensePostgresRepository) Update(ctx context.Context, expense *domain.Expense, event *domain.ExpenseEvent) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func(tx *sql.Tx) {
 		err := tx.Rollback()
-		if err != nil {
-			log.Printf("failed to rollback transaction: %w", err)
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
 		}
 	}(tx)
 
+	// Take the old amounts out of the balances; the new ones go in below
+	if err := lockExpense(ctx, tx, expense.ID); err != nil {
+		return err
+	}
+	if err := adjustUserBalances(ctx, tx, expense.ID, -1); err != nil {
+		return err
+	}
+
 	// Update expense
 	updateExpenseQuery := `
 		UPDATE expenses 
-		SET description = $1, amount = $2, category = $3, paid_by = $4, updated_at = $5
-		WHERE id = $6
+		SET description = $1, amount = $2, currency = $3, category = $4, paid_by = $5, date = $6, updated_at = $7, tags = $8
+		WHERE id = $9 AND deleted_at IS NULL
 	`
 	result, err := tx.ExecContext(ctx, updateExpenseQuery,
 		expense.Description,
 		expense.Amount,
+		expense.Amount.Currency,
 		expense.Category,
 		expense.PaidBy,
+		expense.Date,
 		expense.UpdatedAt,
+		tagsArray(expense.Tags),
 		expense.ID,
 	)
 	if err != nil {
@@ -290,7 +271,7 @@ func (r *ExpensePostgresRepository) Update(ctx context.Context, expense *domain.
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("expense not found")
+		return domain.ErrExpenseNotFound
 	}
 
 	// Delete old splits
@@ -316,6 +297,22 @@ func (r *ExpensePostgresRepository) Update(ctx context.Context, expense *domain.
 		}
 	}
 
+	// Replace items; their users go with them through ON DELETE CASCADE
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expense_items WHERE expense_id = $1`, expense.ID); err != nil {
+		return fmt.Errorf("failed to delete old items: %w", err)
+	}
+	if err := insertItems(ctx, tx, expense); err != nil {
+		return err
+	}
+	if err := adjustUserBalances(ctx, tx, expense.ID, 1); err != nil {
+		return err
+	}
+	if event != nil {
+		if err := appendExpenseEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -325,14 +322,14 @@ func (r *ExpensePostgresRepository) Update(ctx context.Context, expense *domain.
 }
 
 func (r *ExpensePostgresRepository) GetByDateRange(ctx context.Context, startDate, endDate string) ([]*domain.Expense, error) {
-	query := `
-		SELECT id, description, amount, category, paid_by, date, created_at, updated_at
-		FROM expenses
-		WHERE date >= $1 AND date <= $2
-		ORDER BY date DESC
-	`
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	query := `SELECT ` + expenseColumns + ` FROM expenses WHERE deleted_at IS NULL` + expenseFilterClause(domain.ExpenseFilter{StartDate: startDate, EndDate: endDate}, arg) + ` ORDER BY date DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, startDate, endDate)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expenses by date range: %w", err)
 	}
@@ -343,9 +340,9 @@ func (r *ExpensePostgresRepository) GetByDateRange(ctx context.Context, startDat
 
 func (r *ExpensePostgresRepository) GetByCategory(ctx context.Context, category string) ([]*domain.Expense, error) {
 	query := `
-		SELECT id, description, amount, category, paid_by, date, created_at, updated_at
+		SELECT ` + expenseColumns + `
 		FROM expenses
-		WHERE LOWER(category) = LOWER($1)
+		WHERE LOWER(category) = LOWER($1) AND deleted_at IS NULL
 		ORDER BY date DESC
 	`
 
@@ -358,30 +355,13 @@ func (r *ExpensePostgresRepository) GetByCategory(ctx context.Context, category
 	return r.scanExpensesWithSplits(ctx, rows)
 }
 
-func (r *ExpensePostgresRepository) GetByFilters(ctx context.Context, category, startDate, endDate string) ([]*domain.Expense, error) {
-	query := `SELECT id, description, amount, category, paid_by, date, created_at, updated_at FROM expenses WHERE 1=1`
-	args := make([]interface{}, 0)
-	argCount := 1
-
-	if category != "" {
-		query += fmt.Sprintf(" AND LOWER(category) = LOWER($%d)", argCount)
-		args = append(args, category)
-		argCount++
+func (r *ExpensePostgresRepository) GetByFilters(ctx context.Context, filter domain.ExpenseFilter) ([]*domain.Expense, error) {
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
 	}
-
-	if startDate != "" {
-		query += fmt.Sprintf(" AND date >= $%d", argCount)
-		args = append(args, startDate)
-		argCount++
-	}
-
-	if endDate != "" {
-		query += fmt.Sprintf(" AND date <= $%d", argCount)
-		args = append(args, endDate)
-		argCount++
-	}
-
-	query += " ORDER BY date DESC"
+	query := `SELECT ` + expenseColumns + ` FROM expenses WHERE deleted_at IS NULL` + expenseFilterClause(filter, arg) + ` ORDER BY date DESC, id DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -392,6 +372,86 @@ func (r *ExpensePostgresRepository) GetByFilters(ctx context.Context, category,
 	return r.scanExpensesWithSplits(ctx, rows)
 }
 
+// expenseLocalTime is an expense's date as wall-clock time in its household's
+// time zone. Expenses outside a group, or in a group without a zone, use the
+// session's, which the server sets from TIMEZONE.
+const expenseLocalTime = `(date AT TIME ZONE COALESCE((SELECT NULLIF(g.timezone, '') FROM groups g WHERE g.id = group_id), current_setting('TimeZone')))`
+
+// expenseLocalDate is the calendar day of expenseLocalTime
+const expenseLocalDate = expenseLocalTime + `::date`
+
+// expenseFilterClause returns the "AND ..." conditions selecting the
+// expenses that match filter, adding their parameters through arg
+func expenseFilterClause(filter domain.ExpenseFilter, arg func(interface{}) string) string {
+	var clause strings.Builder
+	and := func(condition string) {
+		clause.WriteString(" AND ")
+		clause.WriteString(condition)
+	}
+
+	if filter.GroupID != "" {
+		and("group_id = " + arg(filter.GroupID))
+	}
+	if len(filter.Categories) > 0 {
+		categories := make([]string, len(filter.Categories))
+		for i, category := range filter.Categories {
+			categories[i] = strings.ToLower(category)
+		}
+		and("LOWER(category) = ANY(" + arg(pq.Array(categories)) + ")")
+	}
+	// Dates are calendar days in each expense's household. The looser bound
+	// on the raw column lets the date index narrow the scan first; no two
+	// time zones are two days apart.
+	if filter.StartDate != "" {
+		p := arg(filter.StartDate)
+		and("date >= " + p + "::date - 2 AND " + expenseLocalDate + " >= " + p + "::date")
+	}
+	if filter.EndDate != "" {
+		p := arg(filter.EndDate)
+		and("date < " + p + "::date + 3 AND " + expenseLocalDate + " <= " + p + "::date")
+	}
+	if filter.PaidBy != "" {
+		and("paid_by = " + arg(filter.PaidBy))
+	}
+	if filter.Participant != "" {
+		and("id IN (SELECT expense_id FROM splits WHERE user_id = " + arg(filter.Participant) + ")")
+	}
+	for _, userID := range []string{filter.UserID, filter.VisibleTo} {
+		if userID != "" {
+			p := arg(userID)
+			and("(paid_by = " + p + " OR id IN (SELECT expense_id FROM splits WHERE user_id = " + p + "))")
+		}
+	}
+	if filter.MinAmount != nil {
+		and("amount >= " + arg(*filter.MinAmount))
+	}
+	if filter.MaxAmount != nil {
+		and("amount <= " + arg(*filter.MaxAmount))
+	}
+	if filter.Search != "" {
+		// websearch_to_tsquery understands the "quoted phrase" syntax and
+		// never fails on user input
+		and("to_tsvector('simple', description) @@ websearch_to_tsquery('simple', " + arg(filter.Search) + ")")
+	}
+	if len(filter.Tags) > 0 {
+		and("tags @> " + arg(pq.Array(filter.Tags)))
+	}
+	if filter.HasAttachment != nil {
+		exists := "EXISTS (SELECT 1 FROM expense_attachments a WHERE a.expense_id = expenses.id)"
+		if !*filter.HasAttachment {
+			exists = "NOT " + exists
+		}
+		and(exists)
+	}
+	return clause.String()
+}
+
+// tagsArray returns tags as a text[] parameter; no tags is an empty array
+// rather than NULL
+func tagsArray(tags []string) interface{} {
+	return pq.StringArray(append([]string{}, tags...))
+}
+
 func (r *ExpensePostgresRepository) scanExpensesWithSplits(ctx context.Context, rows *sql.Rows) ([]*domain.Expense, error) {
 	var expenses []*domain.Expense
 
@@ -401,11 +461,17 @@ func (r *ExpensePostgresRepository) scanExpensesWithSplits(ctx context.Context,
 			&expense.ID,
 			&expense.Description,
 			&expense.Amount,
+			&expense.Amount.Currency,
 			&expense.Category,
 			&expense.PaidBy,
+			&expense.GroupID,
 			&expense.Date,
 			&expense.CreatedAt,
 			&expense.UpdatedAt,
+			&expense.DeletedAt,
+			pq.Array(&expense.Tags),
+			&expense.RecurringID,
+			&expense.OccurrenceDate,
 		); err != nil {
 			return nil, err
 		}
@@ -416,35 +482,480 @@ func (r *ExpensePostgresRepository) scanExpensesWithSplits(ctx context.Context,
 		return nil, err
 	}
 
-	// Get splits for each expense
-	for _, expense := range expenses {
-		splitsQuery := `SELECT expense_id, user_id, amount FROM splits WHERE expense_id = $1`
-		splitRows, err := r.db.QueryContext(ctx, splitsQuery, expense.ID)
+	if err := r.loadDetails(ctx, expenses); err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
+
+func insertItems(ctx context.Context, tx *sql.Tx, expense *domain.Expense) error {
+	itemQuery := `
+		INSERT INTO expense_items (expense_id, position, name, quantity, price, kind)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	userQuery := `
+		INSERT INTO expense_item_users (item_id, user_id, position)
+		VALUES ($1, $2, $3)
+	`
+	for i, item := range expense.Items {
+		var itemID int64
+		err := tx.QueryRowContext(ctx, itemQuery, expense.ID, i, item.Name, item.Quantity, item.Price, item.Kind).Scan(&itemID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get splits: %w", err)
+			return fmt.Errorf("failed to create item: %w", err)
 		}
-
-		var splits []domain.Split
-		for splitRows.Next() {
-			var split domain.Split
-			if err := splitRows.Scan(&split.ExpenseID, &split.UserID, &split.Amount); err != nil {
-				splitRows.Close()
-				return nil, fmt.Errorf("failed to scan split: %w", err)
+		for j, userID := range item.UserIDs {
+			if _, err := tx.ExecContext(ctx, userQuery, itemID, userID, j); err != nil {
+				return fmt.Errorf("failed to assign item: %w", err)
 			}
-			splits = append(splits, split)
 		}
-		splitRows.Close()
-		expense.Splits = splits
 	}
+	return nil
+}
 
-	return expenses, nil
+// loadDetails loads the splits and items of the expenses with one query
+// each, however many expenses there are
+func (r *ExpensePostgresRepository) loadDetails(ctx context.Context, expenses []*domain.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+	byID := make(map[string]*domain.Expense, len(expenses))
+	ids := make([]string, len(expenses))
+	for i, expense := range expenses {
+		byID[expense.ID] = expense
+		ids[i] = expense.ID
+		expense.Splits = nil
+		expense.Items = nil
+	}
+
+	if err := r.loadSplits(ctx, ids, byID); err != nil {
+		return err
+	}
+	return r.loadItems(ctx, ids, byID)
+}
+
+func (r *ExpensePostgresRepository) loadSplits(ctx context.Context, ids []string, byID map[string]*domain.Expense) error {
+	query := `
+		SELECT expense_id, user_id, amount
+		FROM splits
+		WHERE expense_id = ANY($1)
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get splits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var split domain.Split
+		if err := rows.Scan(&split.ExpenseID, &split.UserID, &split.Amount); err != nil {
+			return fmt.Errorf("failed to scan split: %w", err)
+		}
+		expense := byID[split.ExpenseID]
+		split.Amount.Currency = expense.Amount.Currency
+		expense.Splits = append(expense.Splits, split)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate splits: %w", err)
+	}
+	return nil
+}
+
+func (r *ExpensePostgresRepository) loadItems(ctx context.Context, ids []string, byID map[string]*domain.Expense) error {
+	query := `
+		SELECT i.expense_id, i.id, i.name, i.quantity, i.price, i.kind, u.user_id
+		FROM expense_items i
+		LEFT JOIN expense_item_users u ON u.item_id = i.id
+		WHERE i.expense_id = ANY($1)
+		ORDER BY i.expense_id, i.position, u.position
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get items: %w", err)
+	}
+	defer rows.Close()
+
+	lastID := int64(-1)
+	for rows.Next() {
+		var expenseID string
+		var itemID int64
+		var item domain.ExpenseItem
+		var userID sql.NullString
+		if err := rows.Scan(&expenseID, &itemID, &item.Name, &item.Quantity, &item.Price, &item.Kind, &userID); err != nil {
+			return fmt.Errorf("failed to scan item: %w", err)
+		}
+		// One row per assigned user; the first row of each item starts it
+		expense := byID[expenseID]
+		if itemID != lastID {
+			item.Price.Currency = expense.Amount.Currency
+			expense.Items = append(expense.Items, item)
+			lastID = itemID
+		}
+		if userID.Valid {
+			last := &expense.Items[len(expense.Items)-1]
+			last.UserIDs = append(last.UserIDs, userID.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate items: %w", err)
+	}
+	return nil
+}
+
+func (r *ExpensePostgresRepository) Delete(ctx context.Context, id string, event *domain.ExpenseEvent) error {
+	query := `UPDATE expenses SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	return r.setDeletedAt(ctx, query, time.Now(), id, -1, event)
+}
+
+func (r *ExpensePostgresRepository) Restore(ctx context.Context, id string, event *domain.ExpenseEvent) error {
+	query := `UPDATE expenses SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NOT NULL`
+	return r.setDeletedAt(ctx, query, nil, id, 1, event)
+}
+
+// setDeletedAt trashes or restores an expense, taking it out of or adding it
+// back to the user balances as sign says
+func (r *ExpensePostgresRepository) setDeletedAt(ctx context.Context, query string, deletedAt interface{}, id string, sign int, event *domain.ExpenseEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}(tx)
+
+	if err := lockExpense(ctx, tx, id); err != nil {
+		return err
+	}
+
+	// Only live expenses count, so adjust while the expense is live
+	if sign < 0 {
+		if err := adjustUserBalances(ctx, tx, id, sign); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, query, deletedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update expense deletion: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrExpenseNotFound
+	}
+
+	if sign > 0 {
+		if err := adjustUserBalances(ctx, tx, id, sign); err != nil {
+			return err
+		}
+	}
+	if event != nil {
+		if err := appendExpenseEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *ExpensePostgresRepository) GetDeleted(ctx context.Context) ([]*domain.Expense, error) {
+	query := `
+		SELECT ` + expenseColumns + `
+		FROM expenses
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted expenses: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanExpensesWithSplits(ctx, rows)
+}
+
+func (r *ExpensePostgresRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	// Splits go with their expense through ON DELETE CASCADE
+	query := `DELETE FROM expenses WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted expenses: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(purged), nil
+}
+
+// expenseSortColumns maps sort fields to their column and SQL type
+var expenseSortColumns = map[string][2]string{
+	domain.SortByDate:      {"date", "timestamptz"},
+	domain.SortByAmount:    {"amount", "numeric"},
+	domain.SortByCreatedAt: {"created_at", "timestamp"},
+}
+
+func (r *ExpensePostgresRepository) List(ctx context.Context, filter domain.ExpenseFilter, page domain.PageRequest) (*domain.ExpensePage, error) {
+	cursor, err := domain.DecodeCursor(page.Cursor, page.Sort)
+	if err != nil {
+		return nil, err
+	}
+	sortColumn, ok := expenseSortColumns[page.Sort.Field]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort field %q", page.Sort.Field)
+	}
+
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	query := `SELECT ` + expenseColumns + ` FROM expenses WHERE deleted_at IS NULL` + expenseFilterClause(filter, arg)
+
+	direction, comparison := "ASC", ">"
+	if page.Sort.Order == domain.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+	if cursor != nil {
+		value, err := expenseCursorValue(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query += fmt.Sprintf(" AND (%s, id) %s (%s::%s, %s)", sortColumn[0], comparison, arg(value), sortColumn[1], arg(cursor.ID))
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT %s", sortColumn[0], direction, direction, arg(page.Limit+1))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expenses: %w", err)
+	}
+	defer rows.Close()
+
+	expenses, err := r.scanExpensesWithSplits(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ExpensePage{Expenses: expenses}
+	if len(expenses) > page.Limit {
+		result.Expenses = expenses[:page.Limit]
+		result.NextCursor = domain.ExpenseCursor(result.Expenses[page.Limit-1], page.Sort)
+	}
+	return result, nil
+}
+
+// expenseCursorValue parses a cursor's sort value into the column's type
+func expenseCursorValue(cursor *domain.PageCursor) (interface{}, error) {
+	if cursor.Sort.Field == domain.SortByAmount {
+		amount, err := domain.ParseMoney(cursor.Value, "")
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		return amount, nil
+	}
+	return cursor.Time()
 }
 
-func (r *ExpensePostgresRepository) Delete(ctx context.Context, id string) error {
-	deleteExpenseQuery := `DELETE FROM expenses WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, deleteExpenseQuery, id)
+func (r *ExpensePostgresRepository) Totals(ctx context.Context, query domain.TotalsQuery) ([]domain.ExpenseTotal, error) {
+	if r.balanceSnapshots && !query.ByCategory && !query.ByMonth && query.StartDate == "" && query.EndDate == "" {
+		return r.snapshotTotals(ctx, query)
+	}
+
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	condition := "e.deleted_at IS NULL" + expenseFilterClause(domain.ExpenseFilter{GroupID: query.GroupID, StartDate: query.StartDate, EndDate: query.EndDate}, arg)
+
+	columns := []string{"user_id", "currency"}
+	if query.ByCategory {
+		columns = append(columns, "category")
+	}
+	if query.ByMonth {
+		columns = append(columns, "month")
+	}
+	grouping := strings.Join(columns, ", ")
+
+	sqlQuery := `
+		SELECT ` + grouping + `, SUM(paid), SUM(owed), SUM(expenses_paid)
+		FROM (
+			SELECT e.paid_by AS user_id, e.currency, e.category, to_char(` + expenseLocalTime + `, 'YYYY-MM') AS month, e.amount AS paid, 0 AS owed, 1 AS expenses_paid
+			FROM expenses e
+			WHERE ` + condition + `
+			UNION ALL
+			SELECT s.user_id, e.currency, e.category, to_char(` + expenseLocalTime + `, 'YYYY-MM'), 0, s.amount, 0
+			FROM splits s
+			JOIN expenses e ON e.id = s.expense_id
+			WHERE ` + condition + `
+		) AS entries`
+	if query.UserID != "" {
+		sqlQuery += " WHERE user_id = " + arg(query.UserID)
+	}
+	sqlQuery += " GROUP BY " + grouping + " ORDER BY " + grouping
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total expenses: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []domain.ExpenseTotal
+	for rows.Next() {
+		var total domain.ExpenseTotal
+		var currency domain.Currency
+		dest := []interface{}{&total.UserID, &currency}
+		if query.ByCategory {
+			dest = append(dest, &total.Category)
+		}
+		if query.ByMonth {
+			dest = append(dest, &total.Month)
+		}
+		dest = append(dest, &total.Paid, &total.Owed, &total.ExpensesPaid)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan total: %w", err)
+		}
+		total.Paid.Currency, total.Owed.Currency = currency, currency
+		totals = append(totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate totals: %w", err)
+	}
+	return totals, nil
+}
+
+// UseBalanceSnapshots makes Totals read per-user totals from the
+// user_balances table where it can, rather than summing every expense. The
+// table is rebuilt first in case anything changed expenses behind the
+// repository's back.
+func (r *ExpensePostgresRepository) UseBalanceSnapshots(ctx context.Context) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete expense: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}(tx)
+
+	if err := rebuildUserBalances(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.balanceSnapshots = true
+	return nil
+}
+
+func (r *ExpensePostgresRepository) snapshotTotals(ctx context.Context, query domain.TotalsQuery) ([]domain.ExpenseTotal, error) {
+	sqlQuery := `
+		SELECT user_id, currency, SUM(paid), SUM(owed), SUM(expenses_paid)
+		FROM user_balances
+		WHERE ($1 = '' OR group_id = $1) AND ($2 = '' OR user_id = $2)
+		GROUP BY user_id, currency
+		ORDER BY user_id, currency
+	`
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query.GroupID, query.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user balances: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []domain.ExpenseTotal
+	for rows.Next() {
+		var total domain.ExpenseTotal
+		var currency domain.Currency
+		if err := rows.Scan(&total.UserID, &currency, &total.Paid, &total.Owed, &total.ExpensesPaid); err != nil {
+			return nil, fmt.Errorf("failed to scan user balance: %w", err)
+		}
+		total.Paid.Currency, total.Owed.Currency = currency, currency
+		totals = append(totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate user balances: %w", err)
+	}
+	return totals, nil
+}
+
+// balanceEntries selects what each user paid and owes per group and
+// currency across the live expenses matching condition, in the shape of the
+// user_balances table
+func balanceEntries(condition string) string {
+	return `
+		SELECT user_id, group_id, currency, SUM(paid) AS paid, SUM(owed) AS owed, SUM(expenses_paid) AS expenses_paid
+		FROM (
+			SELECT e.paid_by AS user_id, COALESCE(e.group_id, '') AS group_id, e.currency, e.amount AS paid, 0 AS owed, 1 AS expenses_paid
+			FROM expenses e
+			WHERE e.deleted_at IS NULL AND ` + condition + `
+			UNION ALL
+			SELECT s.user_id, COALESCE(e.group_id, ''), e.currency, 0, s.amount, 0
+			FROM splits s
+			JOIN expenses e ON e.id = s.expense_id
+			WHERE e.deleted_at IS NULL AND ` + condition + `
+		) AS entries
+		GROUP BY user_id, group_id, currency`
+}
+
+// lockExpense locks an expense's row until tx ends, so that a concurrent
+// change can't read the splits adjustUserBalances is about to take away
+func lockExpense(ctx context.Context, tx *sql.Tx, expenseID string) error {
+	var id string
+	err := tx.QueryRowContext(ctx, `SELECT id FROM expenses WHERE id = $1 FOR UPDATE`, expenseID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrExpenseNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock expense: %w", err)
+	}
+	return nil
+}
+
+// adjustUserBalances adds a live expense's amounts to user_balances, or
+// takes them away when sign is -1. Callers take an expense away before
+// changing or deleting it and add it back once it is stored again.
+func adjustUserBalances(ctx context.Context, tx *sql.Tx, expenseID string, sign int) error {
+	query := `
+		INSERT INTO user_balances (user_id, group_id, currency, paid, owed, expenses_paid)
+		SELECT user_id, group_id, currency, paid * $2, owed * $2, expenses_paid * $2
+		FROM (` + balanceEntries("e.id = $1") + `) AS totals
+		ON CONFLICT (user_id, group_id, currency) DO UPDATE
+		SET paid = user_balances.paid + EXCLUDED.paid,
+		    owed = user_balances.owed + EXCLUDED.owed,
+		    expenses_paid = user_balances.expenses_paid + EXCLUDED.expenses_paid
+	`
+	if _, err := tx.ExecContext(ctx, query, expenseID, sign); err != nil {
+		return fmt.Errorf("failed to update user balances: %w", err)
+	}
+	return nil
+}
+
+// rebuildUserBalances recomputes user_balances from the expenses
+func rebuildUserBalances(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_balances`); err != nil {
+		return fmt.Errorf("failed to clear user balances: %w", err)
+	}
+	query := `
+		INSERT INTO user_balances (user_id, group_id, currency, paid, owed, expenses_paid)
+		SELECT user_id, group_id, currency, paid, owed, expenses_paid
+		FROM (` + balanceEntries("TRUE") + `) AS totals
+	`
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to rebuild user balances: %w", err)
 	}
 	return nil
 }
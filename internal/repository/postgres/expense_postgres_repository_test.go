@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// TestExpensePostgresRepository_SearchMatchesMemory checks that the SQL text
+// search agrees with domain.ExpenseFilter.MatchesSearch, which the memory
+// repository uses
+func TestExpensePostgresRepository_SearchMatchesMemory(t *testing.T) {
+	db := testDB(t)
+	repo := NewExpensePostgresRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	userID := uuid.New().String()
+	user := &domain.User{ID: userID, Name: "Search", Email: userID + "@search.test", CreatedAt: now, UpdatedAt: now}
+	if err := NewUserPostgresRepository(db).Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	group := &domain.Group{ID: uuid.New().String(), Name: "Search", BaseCurrency: domain.DefaultCurrency, Members: []string{userID}, CreatedAt: now, UpdatedAt: now}
+	if err := NewGroupPostgresRepository(db).Create(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+
+	descriptions := []string{"Pizza and movie-night snacks", "Pizzas for the party", "Night bus home", "Movie tickets"}
+	expenses := make([]*domain.Expense, len(descriptions))
+	ids := make([]string, len(descriptions))
+	for i, description := range descriptions {
+		amount := domain.NewMoney(1000, domain.DefaultCurrency)
+		expenses[i] = &domain.Expense{
+			ID:          uuid.New().String(),
+			GroupID:     group.ID,
+			Description: description,
+			Amount:      amount,
+			Category:    domain.DefaultCategory,
+			PaidBy:      userID,
+			Splits:      []domain.Split{{UserID: userID, Amount: amount}},
+			Date:        now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		ids[i] = expenses[i].ID
+	}
+	if err := repo.CreateBatch(ctx, expenses, nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		for _, cleanup := range []struct {
+			query string
+			arg   interface{}
+		}{
+			{`DELETE FROM expenses WHERE id = ANY($1)`, pq.Array(ids)},
+			{`DELETE FROM groups WHERE id = $1`, group.ID},
+			{`DELETE FROM users WHERE id = $1`, userID},
+		} {
+			if _, err := db.ExecContext(ctx, cleanup.query, cleanup.arg); err != nil {
+				t.Errorf("failed to remove test data: %v", err)
+			}
+		}
+	})
+
+	searches := []string{
+		"pizza",
+		"PIZZA snacks",
+		"pizz",
+		`"movie night"`,
+		`"night movie"`,
+		"night -bus",
+		`-"movie night"`,
+		"party or tickets",
+		"pizza snacks or bus home",
+	}
+	for _, search := range searches {
+		t.Run(search, func(t *testing.T) {
+			filter := domain.ExpenseFilter{GroupID: group.ID, Search: search}
+			found, err := repo.GetByFilters(ctx, filter)
+			if err != nil {
+				t.Fatalf("GetByFilters() failed: %v", err)
+			}
+			var got, want []string
+			for _, expense := range found {
+				got = append(got, expense.Description)
+			}
+			for _, description := range descriptions {
+				if filter.MatchesSearch(description) {
+					want = append(want, description)
+				}
+			}
+			slices.Sort(got)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("postgres matched %q, memory matches %q", got, want)
+			}
+		})
+	}
+}
+
+// TestExpensePostgresRepository_ConcurrentUpdatesKeepBalances checks that
+// user_balances still matches the expense after racing updates
+func TestExpensePostgresRepository_ConcurrentUpdatesKeepBalances(t *testing.T) {
+	db := testDB(t)
+	repo := NewExpensePostgresRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	userID := uuid.New().String()
+	user := &domain.User{ID: userID, Name: "Balance", Email: userID + "@balance.test", CreatedAt: now, UpdatedAt: now}
+	if err := NewUserPostgresRepository(db).Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	group := &domain.Group{ID: uuid.New().String(), Name: "Balance", BaseCurrency: domain.DefaultCurrency, Members: []string{userID}, CreatedAt: now, UpdatedAt: now}
+	if err := NewGroupPostgresRepository(db).Create(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+
+	expenseID := uuid.New().String()
+	newExpense := func(minor int64) *domain.Expense {
+		amount := domain.NewMoney(minor, domain.DefaultCurrency)
+		return &domain.Expense{
+			ID:          expenseID,
+			GroupID:     group.ID,
+			Description: "Racing",
+			Amount:      amount,
+			Category:    domain.DefaultCategory,
+			PaidBy:      userID,
+			Splits:      []domain.Split{{UserID: userID, Amount: amount}},
+			Date:        now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+	if err := repo.Create(ctx, newExpense(1000), nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		for _, cleanup := range []struct {
+			query string
+			arg   interface{}
+		}{
+			{`DELETE FROM expenses WHERE id = $1`, expenseID},
+			{`DELETE FROM groups WHERE id = $1`, group.ID},
+			{`DELETE FROM users WHERE id = $1`, userID},
+		} {
+			if _, err := db.ExecContext(ctx, cleanup.query, cleanup.arg); err != nil {
+				t.Errorf("failed to remove test data: %v", err)
+			}
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 8; i++ {
+		wg.Add(1)
+		go func(minor int64) {
+			defer wg.Done()
+			if err := repo.Update(ctx, newExpense(minor), nil); err != nil {
+				t.Errorf("Update() failed: %v", err)
+			}
+		}(int64(i) * 1000)
+	}
+	wg.Wait()
+
+	stored, err := repo.GetByID(ctx, expenseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paid, owed domain.Money
+	var expensesPaid int
+	query := `SELECT paid, owed, expenses_paid FROM user_balances WHERE user_id = $1 AND group_id = $2`
+	if err := db.QueryRowContext(ctx, query, userID, group.ID).Scan(&paid, &owed, &expensesPaid); err != nil {
+		t.Fatal(err)
+	}
+	if paid.Minor != stored.Amount.Minor || owed.Minor != stored.Amount.Minor || expensesPaid != 1 {
+		t.Errorf("balance paid %d, owed %d over %d expenses, want %d over 1", paid.Minor, owed.Minor, expensesPaid, stored.Amount.Minor)
+	}
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.GroupRepository = (*GroupPostgresRepository)(nil)
+
+type GroupPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewGroupPostgresRepository(db *sql.DB) *GroupPostgresRepository {
+	return &GroupPostgresRepository{db: db}
+}
+
+func (r *GroupPostgresRepository) Create(ctx context.Context, group *domain.Group) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}(tx)
+
+	groupQuery := `
+		INSERT INTO groups (id, name, base_currency, timezone, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = tx.ExecContext(ctx, groupQuery, group.ID, group.Name, group.BaseCurrency, group.Timezone, group.CreatedAt, group.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	memberQuery := `INSERT INTO group_members (group_id, user_id) VALUES ($1, $2)`
+	for _, userID := range group.Members {
+		if _, err := tx.ExecContext(ctx, memberQuery, group.ID, userID); err != nil {
+			return fmt.Errorf("failed to add group member: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *GroupPostgresRepository) GetByID(ctx context.Context, id string) (*domain.Group, error) {
+	query := `
+		SELECT id, name, base_currency, timezone, created_at, updated_at
+		FROM groups
+		WHERE id = $1
+	`
+
+	group := &domain.Group{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&group.ID,
+		&group.Name,
+		&group.BaseCurrency,
+		&group.Timezone,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("group not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	if err := r.loadMembers(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (r *GroupPostgresRepository) GetAll(ctx context.Context) ([]*domain.Group, error) {
+	query := `SELECT id, name, base_currency, timezone, created_at, updated_at FROM groups ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanGroupsWithMembers(ctx, rows)
+}
+
+func (r *GroupPostgresRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Group, error) {
+	query := `
+		SELECT g.id, g.name, g.base_currency, g.timezone, g.created_at, g.updated_at
+		FROM groups g
+		JOIN group_members gm ON g.id = gm.group_id
+		WHERE gm.user_id = $1
+		ORDER BY g.created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups for user: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanGroupsWithMembers(ctx, rows)
+}
+
+func (r *GroupPostgresRepository) Update(ctx context.Context, group *domain.Group) error {
+	query := `UPDATE groups SET name = $1, base_currency = $2, timezone = $3, updated_at = $4 WHERE id = $5`
+	result, err := r.db.ExecContext(ctx, query, group.Name, group.BaseCurrency, group.Timezone, group.UpdatedAt, group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("group not found")
+	}
+	return nil
+}
+
+func (r *GroupPostgresRepository) AddMember(ctx context.Context, groupID, userID string) error {
+	query := `
+		INSERT INTO group_members (group_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (group_id, user_id) DO NOTHING
+	`
+	if _, err := r.db.ExecContext(ctx, query, groupID, userID); err != nil {
+		return fmt.Errorf("failed to add group member: %w", err)
+	}
+	return nil
+}
+
+func (r *GroupPostgresRepository) RemoveMember(ctx context.Context, groupID, userID string) error {
+	query := `DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, groupID, userID); err != nil {
+		return fmt.Errorf("failed to remove group member: %w", err)
+	}
+	return nil
+}
+
+func (r *GroupPostgresRepository) scanGroupsWithMembers(ctx context.Context, rows *sql.Rows) ([]*domain.Group, error) {
+	var groups []*domain.Group
+	for rows.Next() {
+		group := &domain.Group{}
+		if err := rows.Scan(&group.ID, &group.Name, &group.BaseCurrency, &group.Timezone, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if err := r.loadMembers(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+func (r *GroupPostgresRepository) loadMembers(ctx context.Context, group *domain.Group) error {
+	query := `SELECT user_id FROM group_members WHERE group_id = $1 ORDER BY joined_at`
+
+	rows, err := r.db.QueryContext(ctx, query, group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get group members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return fmt.Errorf("failed to scan group member: %w", err)
+		}
+		members = append(members, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	group.Members = members
+	return nil
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.PaymentRepository = (*PaymentPostgresRepository)(nil)
+
+// paymentColumns is the column list every payment query selects, in scan order
+const paymentColumns = `id, COALESCE(group_id, ''), from_user_id, to_user_id, amount, currency, date, note, created_at, updated_at`
+
+type PaymentPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentPostgresRepository(db *sql.DB) *PaymentPostgresRepository {
+	return &PaymentPostgresRepository{db: db}
+}
+
+func (r *PaymentPostgresRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	query := `
+		INSERT INTO payments (id, group_id, from_user_id, to_user_id, amount, currency, date, note, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		payment.ID,
+		payment.GroupID,
+		payment.From,
+		payment.To,
+		payment.Amount,
+		payment.Amount.Currency,
+		payment.Date,
+		payment.Note,
+		payment.CreatedAt,
+		payment.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+	return nil
+}
+
+func (r *PaymentPostgresRepository) GetByID(ctx context.Context, id string) (*domain.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE id = $1`
+
+	payment, err := scanPayment(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("payment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	return payment, nil
+}
+
+func (r *PaymentPostgresRepository) GetAll(ctx context.Context) ([]*domain.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments ORDER BY date DESC`
+	return r.queryPayments(ctx, query)
+}
+
+func (r *PaymentPostgresRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE from_user_id = $1 OR to_user_id = $1 ORDER BY date DESC`
+	return r.queryPayments(ctx, query, userID)
+}
+
+func (r *PaymentPostgresRepository) GetByGroupID(ctx context.Context, groupID string) ([]*domain.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE group_id = $1 ORDER BY date DESC`
+	return r.queryPayments(ctx, query, groupID)
+}
+
+func (r *PaymentPostgresRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	query := `
+		UPDATE payments
+		SET amount = $1, currency = $2, date = $3, note = $4, updated_at = $5
+		WHERE id = $6
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		payment.Amount,
+		payment.Amount.Currency,
+		payment.Date,
+		payment.Note,
+		payment.UpdatedAt,
+		payment.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment not found")
+	}
+	return nil
+}
+
+func (r *PaymentPostgresRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM payments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete payment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment not found")
+	}
+	return nil
+}
+
+func (r *PaymentPostgresRepository) queryPayments(ctx context.Context, query string, args ...any) ([]*domain.Payment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := make([]*domain.Payment, 0)
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPayment(row rowScanner) (*domain.Payment, error) {
+	payment := &domain.Payment{}
+	err := row.Scan(
+		&payment.ID,
+		&payment.GroupID,
+		&payment.From,
+		&payment.To,
+		&payment.Amount,
+		&payment.Amount.Currency,
+		&payment.Date,
+		&payment.Note,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.RecurringExpenseRepository = (*RecurringExpensePostgresRepository)(nil)
+
+// recurringColumns is the column list every recurring expense query selects, in scan order
+const recurringColumns = `id, COALESCE(group_id, ''), description, amount, currency, category, paid_by, schedule, start_date, end_date, last_occurrence, created_at, updated_at`
+
+type RecurringExpensePostgresRepository struct {
+	db *sql.DB
+}
+
+func NewRecurringExpensePostgresRepository(db *sql.DB) *RecurringExpensePostgresRepository {
+	return &RecurringExpensePostgresRepository{db: db}
+}
+
+func (r *RecurringExpensePostgresRepository) Create(ctx context.Context, recurring *domain.RecurringExpense) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}(tx)
+
+	query := `
+		INSERT INTO recurring_expenses (id, group_id, description, amount, currency, category, paid_by, schedule, start_date, end_date, last_occurrence, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		recurring.ID,
+		recurring.GroupID,
+		recurring.Description,
+		recurring.Amount,
+		recurring.Amount.Currency,
+		recurring.Category,
+		recurring.PaidBy,
+		recurring.Schedule.String(),
+		recurring.StartDate,
+		recurring.EndDate,
+		recurring.LastOccurrence,
+		recurring.CreatedAt,
+		recurring.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create recurring expense: %w", err)
+	}
+
+	splitQuery := `
+		INSERT INTO recurring_expense_splits (recurring_expense_id, user_id, amount, position)
+		VALUES ($1, $2, $3, $4)
+	`
+	for i, split := range recurring.Splits {
+		if _, err := tx.ExecContext(ctx, splitQuery, recurring.ID, split.UserID, split.Amount, i); err != nil {
+			return fmt.Errorf("failed to create recurring split: %w", err)
+		}
+	}
+	for i, userID := range recurring.UserIDs {
+		if _, err := tx.ExecContext(ctx, splitQuery, recurring.ID, userID, nil, i); err != nil {
+			return fmt.Errorf("failed to create recurring split: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurringExpensePostgresRepository) GetByID(ctx context.Context, id string) (*domain.RecurringExpense, error) {
+	query := `SELECT ` + recurringColumns + ` FROM recurring_expenses WHERE id = $1`
+
+	recurring, err := scanRecurringExpense(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrRecurringExpenseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring expense: %w", err)
+	}
+
+	if err := r.loadSplits(ctx, recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+func (r *RecurringExpensePostgresRepository) GetAll(ctx context.Context) ([]*domain.RecurringExpense, error) {
+	query := `SELECT ` + recurringColumns + ` FROM recurring_expenses ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring expenses: %w", err)
+	}
+	defer rows.Close()
+
+	var all []*domain.RecurringExpense
+	for rows.Next() {
+		recurring, err := scanRecurringExpense(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recurring expense: %w", err)
+		}
+		all = append(all, recurring)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate recurring expenses: %w", err)
+	}
+
+	for _, recurring := range all {
+		if err := r.loadSplits(ctx, recurring); err != nil {
+			return nil, err
+		}
+	}
+	return all, nil
+}
+
+func (r *RecurringExpensePostgresRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM recurring_expenses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring expense: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrRecurringExpenseNotFound
+	}
+	return nil
+}
+
+func (r *RecurringExpensePostgresRepository) ClaimOccurrence(ctx context.Context, id string, date time.Time) (bool, error) {
+	// The conditional update is atomic, so of several schedulers racing for
+	// the same occurrence exactly one sees a row affected
+	query := `
+		UPDATE recurring_expenses
+		SET last_occurrence = $1, updated_at = $2
+		WHERE id = $3 AND (last_occurrence IS NULL OR last_occurrence < $1)
+	`
+	result, err := r.db.ExecContext(ctx, query, date, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim recurring occurrence: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected == 1, nil
+}
+
+func (r *RecurringExpensePostgresRepository) loadSplits(ctx context.Context, recurring *domain.RecurringExpense) error {
+	query := `
+		SELECT user_id, amount
+		FROM recurring_expense_splits
+		WHERE recurring_expense_id = $1
+		ORDER BY position
+	`
+	rows, err := r.db.QueryContext(ctx, query, recurring.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get recurring splits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var amount *domain.Money
+		if err := rows.Scan(&userID, &amount); err != nil {
+			return fmt.Errorf("failed to scan recurring split: %w", err)
+		}
+		if amount == nil {
+			recurring.UserIDs = append(recurring.UserIDs, userID)
+			continue
+		}
+		amount.Currency = recurring.Amount.Currency
+		recurring.Splits = append(recurring.Splits, domain.Split{UserID: userID, Amount: *amount})
+	}
+	return rows.Err()
+}
+
+func scanRecurringExpense(row rowScanner) (*domain.RecurringExpense, error) {
+	recurring := &domain.RecurringExpense{}
+	var schedule string
+	err := row.Scan(
+		&recurring.ID,
+		&recurring.GroupID,
+		&recurring.Description,
+		&recurring.Amount,
+		&recurring.Amount.Currency,
+		&recurring.Category,
+		&recurring.PaidBy,
+		&schedule,
+		&recurring.StartDate,
+		&recurring.EndDate,
+		&recurring.LastOccurrence,
+		&recurring.CreatedAt,
+		&recurring.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurring.Schedule, err = domain.ParseSchedule(schedule); err != nil {
+		return nil, fmt.Errorf("invalid stored schedule: %w", err)
+	}
+	return recurring, nil
+}
@@ -6,13 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/pavanrkadave/homies/internal/domain"
 	"github.com/pavanrkadave/homies/internal/repository"
 )
 
 var _ repository.UserRepository = (*UserPostgresRepository)(nil)
 
+// Guests have no email or contact stored as NULL, read back as ""
+const userColumns = `id, name, COALESCE(email, ''), created_at, updated_at, deactivated_at, guest, COALESCE(contact, '')`
+
 type UserPostgresRepository struct {
 	db *sql.DB
 }
@@ -23,8 +29,8 @@ func NewUserPostgresRepository(db *sql.DB) *UserPostgresRepository {
 
 func (r *UserPostgresRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, name, email, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (id, name, email, created_at, updated_at, guest, contact)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''))
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -33,6 +39,8 @@ func (r *UserPostgresRepository) Create(ctx context.Context, user *domain.User)
 		user.Email,
 		user.CreatedAt,
 		user.UpdatedAt,
+		user.Guest,
+		user.Contact,
 	)
 
 	if err != nil {
@@ -44,7 +52,7 @@ func (r *UserPostgresRepository) Create(ctx context.Context, user *domain.User)
 
 func (r *UserPostgresRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	query := `
-		SELECT id, name, email, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE id = $1
 	`
@@ -56,6 +64,9 @@ func (r *UserPostgresRepository) GetByID(ctx context.Context, id string) (*domai
 		&user.Email,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DeactivatedAt,
+		&user.Guest,
+		&user.Contact,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -70,7 +81,7 @@ func (r *UserPostgresRepository) GetByID(ctx context.Context, id string) (*domai
 
 func (r *UserPostgresRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, name, email, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE email = $1
 		`
@@ -82,6 +93,9 @@ func (r *UserPostgresRepository) GetByEmail(ctx context.Context, email string) (
 		&user.Email,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DeactivatedAt,
+		&user.Guest,
+		&user.Contact,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("user not found")
@@ -93,7 +107,7 @@ func (r *UserPostgresRepository) GetByEmail(ctx context.Context, email string) (
 }
 
 func (r *UserPostgresRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
-	query := `SELECT id, name, email, created_at, updated_at FROM users`
+	query := `SELECT ` + userColumns + ` FROM users`
 
 	var users []*domain.User
 	rows, err := r.db.QueryContext(ctx, query)
@@ -108,7 +122,7 @@ func (r *UserPostgresRepository) GetAll(ctx context.Context) ([]*domain.User, er
 	}(rows)
 	for rows.Next() {
 		user := &domain.User{}
-		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt, &user.DeactivatedAt, &user.Guest, &user.Contact); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -120,19 +134,280 @@ func (r *UserPostgresRepository) GetAll(ctx context.Context) ([]*domain.User, er
 }
 
 func (r *UserPostgresRepository) Update(ctx context.Context, user *domain.User) error {
-	query := `UPDATE users SET name = $1, email = $2, updated_at = $3 WHERE id = $4`
-	_, err := r.db.ExecContext(ctx, query, user.Name, user.Email, user.UpdatedAt, user.ID)
+	query := `
+		UPDATE users
+		SET name = $1, email = NULLIF($2, ''), updated_at = $3, deactivated_at = $4, guest = $5, contact = NULLIF($6, '')
+		WHERE id = $7
+	`
+	_, err := r.db.ExecContext(ctx, query, user.Name, user.Email, user.UpdatedAt, user.DeactivatedAt, user.Guest, user.Contact, user.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 	return nil
 }
 
+// foreignKeyViolation is the Postgres error code for a delete that rows in
+// another table still refer to
+const foreignKeyViolation = "23503"
+
 func (r *UserPostgresRepository) Delete(ctx context.Context, id string) error {
+	// Expenses, splits and payments restrict the delete, so shared history
+	// is never removed along with a user
 	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.ExecContext(ctx, query, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return domain.ErrUserHasHistory
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// userMergeStatements move everything recorded for $1 onto $2. Rows both
+// users have for the same expense, item or template are combined first so
+// the unique keys hold. Expense history is append-only and keeps the
+// duplicate as the actor of what it did.
+var userMergeStatements = []string{
+	`UPDATE splits s SET amount = s.amount + d.amount FROM splits d
+		WHERE d.expense_id = s.expense_id AND d.user_id = $1 AND s.user_id = $2`,
+	`DELETE FROM splits d USING splits s
+		WHERE d.expense_id = s.expense_id AND d.user_id = $1 AND s.user_id = $2`,
+	`UPDATE splits SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE expenses SET paid_by = $2 WHERE paid_by = $1`,
+	`DELETE FROM expense_item_users d USING expense_item_users s
+		WHERE d.item_id = s.item_id AND d.user_id = $1 AND s.user_id = $2`,
+	`UPDATE expense_item_users SET user_id = $2 WHERE user_id = $1`,
+	// A payment between the two would become a payment to oneself
+	`DELETE FROM payments
+		WHERE (from_user_id = $1 AND to_user_id = $2) OR (from_user_id = $2 AND to_user_id = $1)`,
+	`UPDATE payments SET from_user_id = $2 WHERE from_user_id = $1`,
+	`UPDATE payments SET to_user_id = $2 WHERE to_user_id = $1`,
+	`INSERT INTO group_members (group_id, user_id)
+		SELECT group_id, $2 FROM group_members WHERE user_id = $1
+		ON CONFLICT (group_id, user_id) DO NOTHING`,
+	`UPDATE recurring_expenses SET paid_by = $2 WHERE paid_by = $1`,
+	`UPDATE recurring_expense_splits s SET amount = s.amount + d.amount FROM recurring_expense_splits d
+		WHERE d.recurring_expense_id = s.recurring_expense_id AND d.user_id = $1 AND s.user_id = $2`,
+	`DELETE FROM recurring_expense_splits d USING recurring_expense_splits s
+		WHERE d.recurring_expense_id = s.recurring_expense_id AND d.user_id = $1 AND s.user_id = $2`,
+	`UPDATE recurring_expense_splits SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE expense_attachments SET uploaded_by = $2 WHERE uploaded_by = $1`,
+	// A budget the survivor already has in the same scope goes with the duplicate
+	`UPDATE budgets b SET user_id = $2 WHERE b.user_id = $1 AND NOT EXISTS (
+		SELECT 1 FROM budgets o WHERE o.user_id = $2 AND o.category = b.category
+			AND o.period = b.period AND COALESCE(o.group_id, '') = COALESCE(b.group_id, ''))`,
+	`UPDATE budget_alerts SET user_id = $2 WHERE user_id = $1`,
+	// Memberships, sessions, balances and leftover budgets of the duplicate go with it
+	`DELETE FROM users WHERE id = $1`,
+}
+
+func (r *UserPostgresRepository) Merge(ctx context.Context, fromID, intoID, actorID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}(tx)
+
+	var found int
+	lockQuery := `SELECT COUNT(*) FROM (SELECT id FROM users WHERE id IN ($1, $2) FOR UPDATE) AS merging`
+	if err := tx.QueryRowContext(ctx, lockQuery, fromID, intoID).Scan(&found); err != nil {
+		return fmt.Errorf("failed to lock users: %w", err)
+	}
+	if found != 2 {
+		return fmt.Errorf("user not found")
+	}
+
+	// Every expense the duplicate paid for or shares changes, so each gets
+	// an entry in its history
+	expenseIDs, err := mergedExpenseIDs(ctx, tx, fromID)
+	if err != nil {
+		return err
+	}
+	before, err := expenseSnapshots(ctx, tx, expenseIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range userMergeStatements {
+		if _, err := tx.ExecContext(ctx, statement, fromID, intoID); err != nil {
+			return fmt.Errorf("failed to merge users: %w", err)
+		}
+	}
+
+	after, err := expenseSnapshots(ctx, tx, expenseIDs)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, expenseID := range expenseIDs {
+		event := &domain.ExpenseEvent{
+			ID:        uuid.New().String(),
+			ExpenseID: expenseID,
+			Action:    domain.ExpenseUpdated,
+			ActorID:   actorID,
+			Before:    before[expenseID],
+			After:     after[expenseID],
+			CreatedAt: now,
+		}
+		if err := appendExpenseEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
+	// Expenses changed hands, so the running balances are recomputed
+	if err := rebuildUserBalances(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
+
+// mergedExpenseIDs lists the expenses, trashed or not, that userID paid for
+// or has a split in
+func mergedExpenseIDs(ctx context.Context, tx *sql.Tx, userID string) ([]string, error) {
+	query := `SELECT id FROM expenses WHERE paid_by = $1 UNION SELECT expense_id FROM splits WHERE user_id = $1`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merged expenses: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan merged expense: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate merged expenses: %w", err)
+	}
+	return ids, nil
+}
+
+// expenseSnapshots reads the expenses with the given IDs as they stand in tx
+func expenseSnapshots(ctx context.Context, tx *sql.Tx, ids []string) (map[string]*domain.ExpenseSnapshot, error) {
+	expenseQuery := `
+		SELECT id, COALESCE(group_id, ''), description, amount, currency, category, paid_by, date, tags
+		FROM expenses
+		WHERE id = ANY($1)
+	`
+	rows, err := tx.QueryContext(ctx, expenseQuery, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[string]*domain.Expense, len(ids))
+	for rows.Next() {
+		expense := &domain.Expense{}
+		if err := rows.Scan(&expense.ID, &expense.GroupID, &expense.Description, &expense.Amount, &expense.Amount.Currency,
+			&expense.Category, &expense.PaidBy, &expense.Date, pq.Array(&expense.Tags)); err != nil {
+			return nil, fmt.Errorf("failed to scan expense: %w", err)
+		}
+		byID[expense.ID] = expense
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate expenses: %w", err)
+	}
+
+	splitQuery := `SELECT expense_id, user_id, amount FROM splits WHERE expense_id = ANY($1) ORDER BY id`
+	splitRows, err := tx.QueryContext(ctx, splitQuery, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get splits: %w", err)
+	}
+	defer splitRows.Close()
+
+	for splitRows.Next() {
+		var split domain.Split
+		if err := splitRows.Scan(&split.ExpenseID, &split.UserID, &split.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan split: %w", err)
+		}
+		expense := byID[split.ExpenseID]
+		split.Amount.Currency = expense.Amount.Currency
+		expense.Splits = append(expense.Splits, split)
+	}
+	if err := splitRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate splits: %w", err)
+	}
+
+	snapshots := make(map[string]*domain.ExpenseSnapshot, len(byID))
+	for id, expense := range byID {
+		snapshots[id] = domain.SnapshotOf(expense)
+	}
+	return snapshots, nil
+}
+
+func (r *UserPostgresRepository) List(ctx context.Context, page domain.PageRequest) (*domain.UserPage, error) {
+	cursor, err := domain.DecodeCursor(page.Cursor, page.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	column := "created_at"
+	if page.Sort.Field == domain.SortByName {
+		column = "name"
+	}
+	direction, comparison := "ASC", ">"
+	if page.Sort.Order == domain.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users`
+	args := []interface{}{page.Limit + 1}
+	if cursor != nil {
+		var value interface{} = cursor.Value
+		if column == "created_at" {
+			if value, err = cursor.Time(); err != nil {
+				return nil, err
+			}
+		}
+		query += fmt.Sprintf(" WHERE (%s, id) %s ($2, $3)", column, comparison)
+		args = append(args, value, cursor.ID)
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $1", column, direction, direction)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		user := &domain.User{}
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt, &user.DeactivatedAt, &user.Guest, &user.Contact); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &domain.UserPage{Users: users}
+	if len(users) > page.Limit {
+		result.Users = users[:page.Limit]
+		result.NextCursor = domain.UserCursor(result.Users[page.Limit-1], page.Sort)
+	}
+	return result, nil
+}
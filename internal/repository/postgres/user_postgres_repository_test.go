@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func TestUserPostgresRepository_MergeKeepsHistory(t *testing.T) {
+	db := testDB(t)
+	repo := NewUserPostgresRepository(db)
+	events := NewExpenseEventPostgresRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	users := make([]string, 2)
+	for i := range users {
+		users[i] = uuid.New().String()
+		user := &domain.User{ID: users[i], Name: "Merge", Email: users[i] + "@merge.test", CreatedAt: now, UpdatedAt: now}
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fromID, intoID := users[0], users[1]
+	t.Cleanup(func() {
+		if err := repo.Delete(ctx, intoID); err != nil {
+			t.Errorf("failed to remove test user: %v", err)
+		}
+	})
+
+	// History is append-only, so the event outlives the test
+	event := &domain.ExpenseEvent{
+		ID:        uuid.New().String(),
+		ExpenseID: uuid.New().String(),
+		Action:    domain.ExpenseCreated,
+		ActorID:   fromID,
+		CreatedAt: now,
+	}
+	if err := events.Append(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Merge(ctx, fromID, intoID, intoID); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if _, err := repo.GetByID(ctx, fromID); err == nil {
+		t.Error("merged user still exists")
+	}
+	history, err := events.GetByExpenseID(ctx, event.ExpenseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].ActorID != fromID {
+		t.Errorf("history = %+v, want the event by %s unchanged", history, fromID)
+	}
+}
+
+func TestUserPostgresRepository_MergeRecordsExpenseHistory(t *testing.T) {
+	db := testDB(t)
+	repo := NewUserPostgresRepository(db)
+	expenses := NewExpensePostgresRepository(db)
+	events := NewExpenseEventPostgresRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	users := make([]string, 2)
+	for i := range users {
+		users[i] = uuid.New().String()
+		user := &domain.User{ID: users[i], Name: "Merge", Email: users[i] + "@merge.test", CreatedAt: now, UpdatedAt: now}
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fromID, intoID := users[0], users[1]
+
+	amount := domain.NewMoney(1000, domain.DefaultCurrency)
+	expense := &domain.Expense{
+		ID:          uuid.New().String(),
+		Description: "Merged",
+		Amount:      amount,
+		Category:    domain.DefaultCategory,
+		PaidBy:      fromID,
+		Splits:      []domain.Split{{UserID: fromID, Amount: amount}},
+		Date:        now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := expenses.Create(ctx, expense, nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := db.ExecContext(ctx, `DELETE FROM expenses WHERE id = $1`, expense.ID); err != nil {
+			t.Errorf("failed to remove test expense: %v", err)
+		}
+		if err := repo.Delete(ctx, intoID); err != nil {
+			t.Errorf("failed to remove test user: %v", err)
+		}
+	})
+
+	if err := repo.Merge(ctx, fromID, intoID, intoID); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	history, err := events.GetByExpenseID(ctx, expense.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Action != domain.ExpenseUpdated || history[0].ActorID != intoID {
+		t.Fatalf("history = %+v, want one update by %s", history, intoID)
+	}
+	if before, after := history[0].Before, history[0].After; before.PaidBy != fromID || after.PaidBy != intoID || after.Splits[0].UserID != intoID {
+		t.Errorf("merge recorded %+v to %+v, want the expense moved from %s to %s", before, after, fromID, intoID)
+	}
+}
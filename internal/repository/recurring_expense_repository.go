@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type RecurringExpenseRepository interface {
+	Create(ctx context.Context, recurring *domain.RecurringExpense) error
+	GetByID(ctx context.Context, id string) (*domain.RecurringExpense, error)
+	GetAll(ctx context.Context) ([]*domain.RecurringExpense, error)
+	Delete(ctx context.Context, id string) error
+	// ClaimOccurrence moves LastOccurrence forward to date if it is still
+	// behind it and reports whether this call did so. It is called once the
+	// occurrence's expense exists; the expense's own unique occurrence key is
+	// what keeps it from being created twice.
+	ClaimOccurrence(ctx context.Context, id string, date time.Time) (bool, error)
+}
@@ -11,5 +11,17 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetAll(ctx context.Context) ([]*domain.User, error)
+	// List returns one page of users. The page request must already be
+	// validated.
+	List(ctx context.Context, page domain.PageRequest) (*domain.UserPage, error)
 	Update(ctx context.Context, user *domain.User) error
+	// Delete removes a user. It fails with domain.ErrUserHasHistory while
+	// expenses or payments still refer to them.
+	Delete(ctx context.Context, id string) error
+	// Merge moves everything recorded for fromID - expenses paid, splits,
+	// item assignments, payments, group memberships and recurring expenses -
+	// onto intoID and deletes fromID, all or nothing. Splits and items both
+	// users shared are combined, and payments between the two are dropped.
+	// Each expense that changes gets an updated event by actorID.
+	Merge(ctx context.Context, fromID, intoID, actorID string) error
 }
@@ -0,0 +1,88 @@
+// Package storage keeps attachment contents outside the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+var _ repository.BlobStore = (*FileSystem)(nil)
+
+// FileSystem stores blobs as files under a root directory. Keys are
+// slash-separated paths and cannot reach outside the root.
+type FileSystem struct {
+	root *os.Root
+}
+
+// NewFileSystem stores blobs under dir, creating it if needed
+func NewFileSystem(dir string) (*FileSystem, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage directory: %w", err)
+	}
+	return &FileSystem{root: root}, nil
+}
+
+func (s *FileSystem) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	name := filepath.FromSlash(path.Clean(key))
+	if err := s.root.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	// Write to a temporary file first so a failed upload never leaves a
+	// partial blob under key
+	tmp := filepath.Join(filepath.Dir(name), "."+uuid.New().String()+".tmp")
+	file, err := s.root.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %w", err)
+	}
+	written, err := io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written != size {
+		err = fmt.Errorf("wrote %d bytes, expected %d", written, size)
+	}
+	if err != nil {
+		_ = s.root.Remove(tmp)
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	if err := s.root.Rename(tmp, name); err != nil {
+		_ = s.root.Remove(tmp)
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+	return nil
+}
+
+func (s *FileSystem) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := s.root.Open(filepath.FromSlash(path.Clean(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return file, nil
+}
+
+func (s *FileSystem) Delete(ctx context.Context, key string) error {
+	err := s.root.Remove(filepath.FromSlash(path.Clean(key)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
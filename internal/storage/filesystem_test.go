@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func TestFileSystem_PutGetDelete(t *testing.T) {
+	store, err := NewFileSystem(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSystem() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "expenses/e1/a1", strings.NewReader("receipt"), 7, "text/plain"); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+
+	body, err := store.Get(ctx, "expenses/e1/a1")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	content, _ := io.ReadAll(body)
+	body.Close()
+	if string(content) != "receipt" {
+		t.Errorf("Expected content 'receipt', got %q", content)
+	}
+
+	if err := store.Delete(ctx, "expenses/e1/a1"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := store.Get(ctx, "expenses/e1/a1"); !errors.Is(err, domain.ErrAttachmentNotFound) {
+		t.Errorf("Expected ErrAttachmentNotFound, got %v", err)
+	}
+}
+
+func TestFileSystem_RejectsEscapingKeys(t *testing.T) {
+	store, err := NewFileSystem(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSystem() failed: %v", err)
+	}
+
+	if err := store.Put(context.Background(), "../outside", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Error("Expected error for a key outside the root")
+	}
+}
+
+func TestFileSystem_PutSizeMismatch(t *testing.T) {
+	store, err := NewFileSystem(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSystem() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "short", strings.NewReader("abc"), 10, "text/plain"); err == nil {
+		t.Fatal("Expected error when fewer bytes than size are written")
+	}
+	if _, err := store.Get(ctx, "short"); !errors.Is(err, domain.ErrAttachmentNotFound) {
+		t.Errorf("Expected no partial blob, got %v", err)
+	}
+}
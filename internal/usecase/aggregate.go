@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+// aggregatedTotals totals expenses inside the repository when it supports
+// that. ok is false when it does not, or when some expense is in a currency
+// other than currency: converting it needs the rate of its own date, so only
+// loading the expenses will do.
+func (e *expenseUseCase) aggregatedTotals(ctx context.Context, query domain.TotalsQuery, currency domain.Currency) (totals []domain.ExpenseTotal, ok bool, err error) {
+	aggregator, ok := e.expenseRepo.(repository.ExpenseAggregator)
+	if !ok {
+		return nil, false, nil
+	}
+
+	totals, err = aggregator.Totals(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, total := range totals {
+		if total.Paid.Currency != currency {
+			return nil, false, nil
+		}
+	}
+	return totals, true, nil
+}
+
+// aggregatedBalances nets the expenses in groupID, or all expenses, inside
+// the repository where aggregatedTotals can. Shared-only settlement needs to
+// know who shared each expense, so it always loads them.
+func (e *expenseUseCase) aggregatedBalances(ctx context.Context, groupID string, currency domain.Currency, strategy domain.SettlementStrategy) (map[string]domain.Money, bool, error) {
+	if strategy == domain.SettlementSharedOnly {
+		return nil, false, nil
+	}
+
+	totals, ok, err := e.aggregatedTotals(ctx, domain.TotalsQuery{GroupID: groupID}, currency)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	balances := make(map[string]domain.Money)
+	for _, total := range totals {
+		balances[total.UserID] = balances[total.UserID].Add(total.Paid).Sub(total.Owed)
+	}
+	return balances, true, nil
+}
+
+// userStatsFromTotals builds a user's stats from their per-category totals
+func userStatsFromTotals(userID string, currency domain.Currency, totals []domain.ExpenseTotal, payments []*domain.Payment) *domain.UserStats {
+	stats := &domain.UserStats{
+		UserID:     userID,
+		Currency:   currency,
+		ByCategory: make(map[string]domain.Money),
+	}
+	for _, total := range totals {
+		stats.TotalPaid = stats.TotalPaid.Add(total.Paid)
+		stats.TotalOwed = stats.TotalOwed.Add(total.Owed)
+		stats.ExpenseCount += total.ExpensesPaid
+		if total.ExpensesPaid > 0 {
+			stats.ByCategory[total.Category] = stats.ByCategory[total.Category].Add(total.Paid)
+		}
+	}
+
+	addPaymentStats(stats, payments)
+	return stats
+}
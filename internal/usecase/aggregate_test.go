@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+// countingExpenseRepository counts how often every expense is loaded
+type countingExpenseRepository struct {
+	*memory.ExpenseMemoryRepository
+	loads int
+}
+
+func (r *countingExpenseRepository) GetAll(ctx context.Context) ([]*domain.Expense, error) {
+	r.loads++
+	return r.ExpenseMemoryRepository.GetAll(ctx)
+}
+
+// loadingExpenseRepository hides Totals, so the use case has to load and
+// sum the expenses itself
+type loadingExpenseRepository struct {
+	repository.ExpenseRepository
+}
+
+func TestExpenseUseCase_AggregatedMatchesLoaded(t *testing.T) {
+	userRepo := newMockUserRepository()
+	paymentRepo := memory.NewPaymentMemoryRepository()
+	expenseRepo := &countingExpenseRepository{ExpenseMemoryRepository: memory.NewExpenseMemoryRepository()}
+	newUC := func(expenseRepo repository.ExpenseRepository) ExpenseUseCase {
+		return NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), paymentRepo, memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	}
+	aggregated, loaded := newUC(expenseRepo), newUC(&loadingExpenseRepository{expenseRepo})
+	paymentUC := NewPaymentUseCase(paymentRepo, userRepo, newMockGroupRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+	_, _ = loaded.CreateExpenseWithEqualSplit(ctx, "", "Rent", "housing", "alice", inr(300), "", []string{"alice", "bob", "carol"})
+	_, _ = loaded.CreateExpenseWithEqualSplit(ctx, "", "Dinner", "food", "bob", inr(90), "", []string{"bob", "carol"})
+	_, _ = loaded.CreateExpenseWithEqualSplit(ctx, "", "Snacks", "food", "alice", inr(20), "", []string{"alice", "carol"})
+	_, _ = paymentUC.CreatePayment(ctx, "", "carol", "alice", inr(50), "2025-01-15", "")
+	now := time.Now()
+
+	calls := []struct {
+		name string
+		call func(ExpenseUseCase) (interface{}, error)
+	}{
+		{"balances", func(uc ExpenseUseCase) (interface{}, error) {
+			return uc.CalculateBalances(ctx, domain.SettlementMinimal)
+		}},
+		{"user stats", func(uc ExpenseUseCase) (interface{}, error) { return uc.GetUserStats(ctx, "alice") }},
+		{"monthly summary", func(uc ExpenseUseCase) (interface{}, error) {
+			return uc.GetMonthlySummary(ctx, now.Year(), int(now.Month()))
+		}},
+	}
+	for _, tt := range calls {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := tt.call(loaded)
+			if err != nil {
+				t.Fatalf("Loaded %s failed: %v", tt.name, err)
+			}
+			loads := expenseRepo.loads
+			got, err := tt.call(aggregated)
+			if err != nil {
+				t.Fatalf("Aggregated %s failed: %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+			if expenseRepo.loads != loads {
+				t.Errorf("Expected the aggregated %s not to load expenses", tt.name)
+			}
+		})
+	}
+
+	// Shared-only settlement needs each expense's participants
+	loads := expenseRepo.loads
+	if _, err := aggregated.CalculateBalances(ctx, domain.SettlementSharedOnly); err != nil {
+		t.Fatalf("Failed to calculate shared-only balances: %v", err)
+	}
+	if expenseRepo.loads != loads+1 {
+		t.Error("Expected shared-only balances to load the expenses")
+	}
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+type AttachmentUseCase interface {
+	UploadAttachment(ctx context.Context, expenseID, fileName, contentType string, size int64, content io.Reader) (*domain.Attachment, error)
+	GetAttachments(ctx context.Context, expenseID string) ([]*domain.Attachment, error)
+	OpenAttachment(ctx context.Context, id string) (*domain.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, id string) error
+	PurgeOrphanedAttachments(ctx context.Context) (int, error)
+}
+
+type attachmentUseCase struct {
+	attachmentRepo repository.AttachmentRepository
+	expenseRepo    repository.ExpenseRepository
+	blobs          repository.BlobStore
+}
+
+// NewAttachmentUseCase creates the attachment use case. Metadata goes to
+// attachmentRepo and file contents to blobs.
+func NewAttachmentUseCase(attachmentRepo repository.AttachmentRepository, expenseRepo repository.ExpenseRepository, blobs repository.BlobStore) AttachmentUseCase {
+	return &attachmentUseCase{
+		attachmentRepo: attachmentRepo,
+		expenseRepo:    expenseRepo,
+		blobs:          blobs,
+	}
+}
+
+// UploadAttachment stores content against an expense the acting user may edit
+func (u *attachmentUseCase) UploadAttachment(ctx context.Context, expenseID, fileName, contentType string, size int64, content io.Reader) (*domain.Attachment, error) {
+	expense, err := u.expenseRepo.GetByID(ctx, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeExpenseEdit(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	attachment := &domain.Attachment{
+		ID:          id,
+		ExpenseID:   expense.ID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  "expenses/" + expense.ID + "/" + id,
+		CreatedAt:   time.Now(),
+	}
+	attachment.UploadedBy, _ = domain.UserIDFromContext(ctx)
+	if err := attachment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.blobs.Put(ctx, attachment.StorageKey, content, size, contentType); err != nil {
+		return nil, err
+	}
+	if err := u.attachmentRepo.Create(ctx, attachment); err != nil {
+		if delErr := u.blobs.Delete(ctx, attachment.StorageKey); delErr != nil {
+			log.Printf("failed to remove blob %s after failed upload: %v", attachment.StorageKey, delErr)
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (u *attachmentUseCase) GetAttachments(ctx context.Context, expenseID string) ([]*domain.Attachment, error) {
+	if _, err := u.readableExpense(ctx, expenseID); err != nil {
+		return nil, err
+	}
+	return u.attachmentRepo.GetByExpenseID(ctx, expenseID)
+}
+
+// OpenAttachment returns an attachment with its content; the caller closes it
+func (u *attachmentUseCase) OpenAttachment(ctx context.Context, id string) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := u.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := u.readableExpense(ctx, attachment.ExpenseID); err != nil {
+		return nil, nil, err
+	}
+
+	content, err := u.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+func (u *attachmentUseCase) DeleteAttachment(ctx context.Context, id string) error {
+	attachment, err := u.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	expense, err := u.readableExpense(ctx, attachment.ExpenseID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeAttachmentDelete(ctx, expense, attachment); err != nil {
+		return err
+	}
+
+	// Remove the row first: a blob without a row is harmless, a row without
+	// its blob is a broken download
+	if err := u.attachmentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return u.blobs.Delete(ctx, attachment.StorageKey)
+}
+
+// PurgeOrphanedAttachments removes the attachments of expenses that no
+// longer exist, live or in the trash, and returns how many were removed
+func (u *attachmentUseCase) PurgeOrphanedAttachments(ctx context.Context) (int, error) {
+	all, err := u.attachmentRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var errs []error
+	for _, attachment := range all {
+		orphaned, err := u.expenseGone(ctx, attachment.ExpenseID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !orphaned {
+			continue
+		}
+
+		if err := u.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			errs = append(errs, fmt.Errorf("attachment %s: %w", attachment.ID, err))
+			continue
+		}
+		if err := u.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+			errs = append(errs, fmt.Errorf("attachment %s: %w", attachment.ID, err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+// readableExpense returns the live expense if the acting user may see it
+func (u *attachmentUseCase) readableExpense(ctx context.Context, expenseID string) (*domain.Expense, error) {
+	expense, err := u.expenseRepo.GetByID(ctx, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeExpenseRead(ctx, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+func (u *attachmentUseCase) expenseGone(ctx context.Context, expenseID string) (bool, error) {
+	if _, err := u.expenseRepo.GetByID(ctx, expenseID); !errors.Is(err, domain.ErrExpenseNotFound) {
+		return false, err
+	}
+	if _, err := u.expenseRepo.GetDeletedByID(ctx, expenseID); !errors.Is(err, domain.ErrExpenseNotFound) {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+	"github.com/pavanrkadave/homies/internal/storage"
+)
+
+func newAttachmentFixture(t *testing.T) (AttachmentUseCase, *memory.ExpenseMemoryRepository, *domain.Expense) {
+	t.Helper()
+	repos := newTestRepos(t, "alice", "bob", "carol")
+	expense, err := repos.expenseUseCase().CreateExpenseWithEqualSplit(context.Background(), "", "Groceries", "food", "alice", inr(100), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	blobs, err := storage.NewFileSystem(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+	return NewAttachmentUseCase(repos.attachments, repos.expenses, blobs), repos.expenses, expense
+}
+
+func TestAttachmentUseCase_UploadOpenDelete(t *testing.T) {
+	attachmentUC, _, expense := newAttachmentFixture(t)
+	bob := domain.WithUserID(context.Background(), "bob")
+
+	attachment, err := attachmentUC.UploadAttachment(bob, expense.ID, "receipt.pdf", "application/pdf", 7, strings.NewReader("receipt"))
+	if err != nil {
+		t.Fatalf("Failed to upload attachment: %v", err)
+	}
+	if attachment.UploadedBy != "bob" {
+		t.Errorf("Expected uploader bob, got %q", attachment.UploadedBy)
+	}
+
+	_, content, err := attachmentUC.OpenAttachment(bob, attachment.ID)
+	if err != nil {
+		t.Fatalf("Failed to open attachment: %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if string(data) != "receipt" {
+		t.Errorf("Expected content 'receipt', got %q", data)
+	}
+
+	carol := domain.WithUserID(context.Background(), "carol")
+	if _, _, err := attachmentUC.OpenAttachment(carol, attachment.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for a non-participant, got %v", err)
+	}
+	if _, err := attachmentUC.UploadAttachment(carol, expense.ID, "x.pdf", "application/pdf", 1, strings.NewReader("x")); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden uploading as a non-participant, got %v", err)
+	}
+
+	if err := attachmentUC.DeleteAttachment(bob, attachment.ID); err != nil {
+		t.Fatalf("Failed to delete attachment: %v", err)
+	}
+	attachments, err := attachmentUC.GetAttachments(bob, expense.ID)
+	if err != nil {
+		t.Fatalf("Failed to list attachments: %v", err)
+	}
+	if len(attachments) != 0 {
+		t.Errorf("Expected no attachments, got %d", len(attachments))
+	}
+}
+
+func TestAttachmentUseCase_PurgeOrphaned(t *testing.T) {
+	attachmentUC, expenseRepo, expense := newAttachmentFixture(t)
+	ctx := context.Background()
+
+	if _, err := attachmentUC.UploadAttachment(ctx, expense.ID, "receipt.pdf", "application/pdf", 7, strings.NewReader("receipt")); err != nil {
+		t.Fatalf("Failed to upload attachment: %v", err)
+	}
+
+	// Trashed expenses keep their attachments
+	if err := expenseRepo.Delete(ctx, expense.ID, nil); err != nil {
+		t.Fatalf("Failed to delete expense: %v", err)
+	}
+	if purged, err := attachmentUC.PurgeOrphanedAttachments(ctx); err != nil || purged != 0 {
+		t.Fatalf("Expected nothing purged while the expense is in the trash, got %d, %v", purged, err)
+	}
+
+	if _, err := expenseRepo.Purge(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to purge expense: %v", err)
+	}
+	if purged, err := attachmentUC.PurgeOrphanedAttachments(ctx); err != nil || purged != 1 {
+		t.Errorf("Expected 1 attachment purged, got %d, %v", purged, err)
+	}
+}
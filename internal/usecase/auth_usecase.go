@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+	"github.com/pavanrkadave/homies/pkg/password"
+)
+
+// MagicLinkSender delivers a login token to a user, e.g. by email
+type MagicLinkSender interface {
+	SendMagicLink(ctx context.Context, user *domain.User, token string, expiresAt time.Time) error
+}
+
+type AuthUseCase interface {
+	Register(ctx context.Context, name, email, password string) (*domain.Session, error)
+	Login(ctx context.Context, email, password string) (*domain.Session, error)
+	RequestMagicLink(ctx context.Context, email string) error
+	VerifyMagicLink(ctx context.Context, token string) (*domain.Session, error)
+	Authenticate(ctx context.Context, token string) (*domain.User, error)
+	Logout(ctx context.Context, token string) error
+	ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error
+}
+
+type authUseCase struct {
+	authRepo     repository.AuthRepository
+	userRepo     repository.UserRepository
+	sender       MagicLinkSender
+	sessionTTL   time.Duration
+	magicLinkTTL time.Duration
+}
+
+func NewAuthUseCase(authRepo repository.AuthRepository, userRepo repository.UserRepository, sender MagicLinkSender, sessionTTL, magicLinkTTL time.Duration) AuthUseCase {
+	return &authUseCase{
+		authRepo:     authRepo,
+		userRepo:     userRepo,
+		sender:       sender,
+		sessionTTL:   sessionTTL,
+		magicLinkTTL: magicLinkTTL,
+	}
+}
+
+func (a *authUseCase) Register(ctx context.Context, name, email, pass string) (*domain.Session, error) {
+	if err := domain.ValidatePassword(pass); err != nil {
+		return nil, err
+	}
+	if _, err := a.userRepo.GetByEmail(ctx, email); err == nil {
+		return nil, domain.ErrEmailAlreadyExists
+	}
+
+	user := &domain.User{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Email:     email,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	hash, err := password.Hash(pass)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := a.authRepo.SetPasswordHash(ctx, user.ID, hash); err != nil {
+		return nil, err
+	}
+
+	return a.newSession(ctx, user.ID)
+}
+
+func (a *authUseCase) Login(ctx context.Context, email, pass string) (*domain.Session, error) {
+	user, err := a.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	hash, err := a.authRepo.GetPasswordHash(ctx, user.ID)
+	if errors.Is(err, domain.ErrPasswordNotSet) {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := password.Verify(pass, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if !user.IsActive() {
+		return nil, domain.ErrUserDeactivated
+	}
+
+	return a.newSession(ctx, user.ID)
+}
+
+// RequestMagicLink sends a login link if the email belongs to a user. It does
+// not report unknown emails, so callers cannot probe for accounts.
+func (a *authUseCase) RequestMagicLink(ctx context.Context, email string) error {
+	user, err := a.userRepo.GetByEmail(ctx, email)
+	if err != nil || !user.IsActive() {
+		return nil
+	}
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	link := &domain.MagicLink{
+		TokenHash: tokenHash,
+		UserID:    user.ID,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(a.magicLinkTTL),
+	}
+	if err := a.authRepo.CreateMagicLink(ctx, link); err != nil {
+		return err
+	}
+
+	return a.sender.SendMagicLink(ctx, user, token, link.ExpiresAt)
+}
+
+func (a *authUseCase) VerifyMagicLink(ctx context.Context, token string) (*domain.Session, error) {
+	link, err := a.authRepo.ConsumeMagicLink(ctx, hashToken(token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	// The user may have been deactivated since the link was sent
+	user, err := a.userRepo.GetByID(ctx, link.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, domain.ErrUserDeactivated
+	}
+	return a.newSession(ctx, user.ID)
+}
+
+func (a *authUseCase) Authenticate(ctx context.Context, token string) (*domain.User, error) {
+	tokenHash := hashToken(token)
+	session, err := a.authRepo.GetSession(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if session.Expired(time.Now()) {
+		_ = a.authRepo.DeleteSession(ctx, tokenHash)
+		return nil, domain.ErrInvalidToken
+	}
+	user, err := a.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, domain.ErrUserDeactivated
+	}
+	return user, nil
+}
+
+func (a *authUseCase) Logout(ctx context.Context, token string) error {
+	return a.authRepo.DeleteSession(ctx, hashToken(token))
+}
+
+// ChangePassword sets a new password. Users who already have one must
+// confirm it; users who only ever logged in by magic link need not.
+func (a *authUseCase) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	if err := domain.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	hash, err := a.authRepo.GetPasswordHash(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrPasswordNotSet):
+	case err != nil:
+		return err
+	default:
+		ok, err := password.Verify(currentPassword, hash)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return domain.ErrInvalidCredentials
+		}
+	}
+
+	newHash, err := password.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	return a.authRepo.SetPasswordHash(ctx, userID, newHash)
+}
+
+func (a *authUseCase) newSession(ctx context.Context, userID string) (*domain.Session, error) {
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		Token:     token,
+		TokenHash: tokenHash,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(a.sessionTTL),
+	}
+	if err := a.authRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// newToken returns a random URL-safe token and the hash stored in its place
+func newToken() (token, tokenHash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+// capturingSender records the last magic link or guest claim token instead
+// of sending it
+type capturingSender struct {
+	token string
+}
+
+func (s *capturingSender) SendMagicLink(ctx context.Context, user *domain.User, token string, expiresAt time.Time) error {
+	s.token = token
+	return nil
+}
+
+func (s *capturingSender) SendGuestClaim(ctx context.Context, guest *domain.User, email, token string, expiresAt time.Time) error {
+	s.token = token
+	return nil
+}
+
+func newTestAuthUseCase(sessionTTL time.Duration) (AuthUseCase, *mockUserRepository, *capturingSender) {
+	userRepo := newMockUserRepository()
+	sender := &capturingSender{}
+	authUC := NewAuthUseCase(memory.NewAuthMemoryRepository(), userRepo, sender, sessionTTL, 15*time.Minute)
+	return authUC, userRepo, sender
+}
+
+func TestAuthUseCase_RegisterAndLogin(t *testing.T) {
+	authUC, _, _ := newTestAuthUseCase(time.Hour)
+	ctx := context.Background()
+
+	registered, err := authUC.Register(ctx, "Alice", "alice@test.com", "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to register: %v", err)
+	}
+
+	user, err := authUC.Authenticate(ctx, registered.Token)
+	if err != nil {
+		t.Fatalf("Failed to authenticate registration token: %v", err)
+	}
+	if user.Email != "alice@test.com" {
+		t.Errorf("Expected alice@test.com, got: %v", user.Email)
+	}
+
+	if _, err := authUC.Login(ctx, "alice@test.com", "wrong password"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for wrong password, got: %v", err)
+	}
+	if _, err := authUC.Login(ctx, "nobody@test.com", "correct horse"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for unknown email, got: %v", err)
+	}
+
+	session, err := authUC.Login(ctx, "alice@test.com", "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+	if session.Token == registered.Token {
+		t.Error("Expected each login to issue a new token")
+	}
+
+	if err := authUC.Logout(ctx, session.Token); err != nil {
+		t.Fatalf("Failed to log out: %v", err)
+	}
+	if _, err := authUC.Authenticate(ctx, session.Token); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken after logout, got: %v", err)
+	}
+}
+
+func TestAuthUseCase_Register_Validation(t *testing.T) {
+	authUC, _, _ := newTestAuthUseCase(time.Hour)
+	ctx := context.Background()
+
+	if _, err := authUC.Register(ctx, "Alice", "alice@test.com", "short"); err == nil {
+		t.Error("Expected error for short password, got nil")
+	}
+	if _, err := authUC.Register(ctx, "Alice", "alice@test.com", "long enough"); err != nil {
+		t.Fatalf("Failed to register: %v", err)
+	}
+	if _, err := authUC.Register(ctx, "Alice 2", "alice@test.com", "long enough"); !errors.Is(err, domain.ErrEmailAlreadyExists) {
+		t.Errorf("Expected ErrEmailAlreadyExists, got: %v", err)
+	}
+}
+
+func TestAuthUseCase_MagicLink(t *testing.T) {
+	authUC, userRepo, sender := newTestAuthUseCase(time.Hour)
+	ctx := context.Background()
+
+	// Users created before auth existed have no password and use magic links
+	_ = userRepo.Create(ctx, &domain.User{ID: "bob", Name: "Bob", Email: "bob@test.com"})
+
+	if err := authUC.RequestMagicLink(ctx, "ghost@test.com"); err != nil {
+		t.Errorf("Expected unknown email to be ignored silently, got: %v", err)
+	}
+	if sender.token != "" {
+		t.Fatal("Expected no link to be sent for an unknown email")
+	}
+
+	if err := authUC.RequestMagicLink(ctx, "bob@test.com"); err != nil {
+		t.Fatalf("Failed to request magic link: %v", err)
+	}
+
+	session, err := authUC.VerifyMagicLink(ctx, sender.token)
+	if err != nil {
+		t.Fatalf("Failed to verify magic link: %v", err)
+	}
+	if session.UserID != "bob" {
+		t.Errorf("Expected session for bob, got: %v", session.UserID)
+	}
+
+	if _, err := authUC.VerifyMagicLink(ctx, sender.token); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected magic link to be single-use, got: %v", err)
+	}
+
+	// Bob can now set a password without knowing a current one
+	if err := authUC.ChangePassword(ctx, "bob", "", "bobs new password"); err != nil {
+		t.Fatalf("Failed to set password: %v", err)
+	}
+	if err := authUC.ChangePassword(ctx, "bob", "", "another password"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("Expected current password to be required once set, got: %v", err)
+	}
+	if _, err := authUC.Login(ctx, "bob@test.com", "bobs new password"); err != nil {
+		t.Errorf("Failed to log in with new password: %v", err)
+	}
+}
+
+func TestAuthUseCase_Authenticate_ExpiredSession(t *testing.T) {
+	authUC, _, _ := newTestAuthUseCase(-time.Minute)
+	ctx := context.Background()
+
+	session, err := authUC.Register(ctx, "Alice", "alice@test.com", "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to register: %v", err)
+	}
+
+	if _, err := authUC.Authenticate(ctx, session.Token); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for expired session, got: %v", err)
+	}
+}
+
+func TestAuthUseCase_DeactivatedUser(t *testing.T) {
+	authUC, userRepo, sender := newTestAuthUseCase(time.Hour)
+	ctx := context.Background()
+
+	session, err := authUC.Register(ctx, "Alice", "alice@test.com", "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to register: %v", err)
+	}
+	if err := authUC.RequestMagicLink(ctx, "alice@test.com"); err != nil {
+		t.Fatalf("Failed to request magic link: %v", err)
+	}
+
+	user, _ := userRepo.GetByEmail(ctx, "alice@test.com")
+	now := time.Now()
+	user.DeactivatedAt = &now
+
+	if _, err := authUC.Authenticate(ctx, session.Token); !errors.Is(err, domain.ErrUserDeactivated) {
+		t.Errorf("Expected ErrUserDeactivated for an existing session, got: %v", err)
+	}
+	if _, err := authUC.Login(ctx, "alice@test.com", "correct horse"); !errors.Is(err, domain.ErrUserDeactivated) {
+		t.Errorf("Expected ErrUserDeactivated on login, got: %v", err)
+	}
+	if _, err := authUC.VerifyMagicLink(ctx, sender.token); !errors.Is(err, domain.ErrUserDeactivated) {
+		t.Errorf("Expected ErrUserDeactivated for a link sent before deactivation, got: %v", err)
+	}
+}
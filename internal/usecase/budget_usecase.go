@@ -0,0 +1,539 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+// BudgetAlertNotifier tells the household a budget crossed an alert
+// threshold, e.g. by email
+type BudgetAlertNotifier interface {
+	NotifyBudgetAlert(ctx context.Context, alert *domain.BudgetAlert) error
+}
+
+type BudgetUseCase interface {
+	CreateBudget(ctx context.Context, category, period string, amount domain.Money, userID, groupID string) (*domain.Budget, error)
+	GetBudget(ctx context.Context, id string) (*domain.Budget, error)
+	ListBudgets(ctx context.Context) ([]*domain.Budget, error)
+	UpdateBudget(ctx context.Context, id, category, period string, amount domain.Money, userID, groupID string) (*domain.Budget, error)
+	DeleteBudget(ctx context.Context, id string) error
+	GetBudgetStatus(ctx context.Context, year, month int) (*domain.BudgetReport, error)
+	GetBudgetAlerts(ctx context.Context, year, month int) ([]*domain.BudgetAlert, error)
+	// CheckExpense raises an alert for each threshold a newly created expense
+	// took a budget past
+	CheckExpense(ctx context.Context, expense *domain.Expense) ([]*domain.BudgetAlert, error)
+}
+
+type budgetUseCase struct {
+	budgetRepo      repository.BudgetRepository
+	expenseRepo     repository.ExpenseRepository
+	categoryRepo    repository.CategoryRepository
+	userRepo        repository.UserRepository
+	groupRepo       repository.GroupRepository
+	converter       currencyConverter
+	notifier        BudgetAlertNotifier
+	thresholds      []int
+	defaultCurrency domain.Currency
+}
+
+// NewBudgetUseCase creates the budget use case. Spending is converted into
+// each budget's currency; budgets given without one are in their group's
+// base currency, else defaultCurrency. thresholds are the percentages of a
+// budget that raise an alert through notifier.
+func NewBudgetUseCase(budgetRepo repository.BudgetRepository, expenseRepo repository.ExpenseRepository, categoryRepo repository.CategoryRepository, userRepo repository.UserRepository, groupRepo repository.GroupRepository, rates repository.ExchangeRateProvider, notifier BudgetAlertNotifier, thresholds []int, defaultCurrency domain.Currency) BudgetUseCase {
+	thresholds = slices.Clone(thresholds)
+	slices.Sort(thresholds)
+	return &budgetUseCase{
+		budgetRepo:      budgetRepo,
+		expenseRepo:     expenseRepo,
+		categoryRepo:    categoryRepo,
+		userRepo:        userRepo,
+		groupRepo:       groupRepo,
+		converter:       currencyConverter{rates: rates},
+		notifier:        notifier,
+		thresholds:      thresholds,
+		defaultCurrency: defaultCurrency,
+	}
+}
+
+func (b *budgetUseCase) CreateBudget(ctx context.Context, category, period string, amount domain.Money, userID, groupID string) (*domain.Budget, error) {
+	now := time.Now()
+	budget := &domain.Budget{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := b.apply(ctx, budget, category, period, amount, userID, groupID); err != nil {
+		return nil, err
+	}
+	if err := b.authorize(ctx, budget); err != nil {
+		return nil, err
+	}
+
+	if err := b.budgetRepo.Create(ctx, budget); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+func (b *budgetUseCase) GetBudget(ctx context.Context, id string) (*domain.Budget, error) {
+	budget, err := b.budgetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.authorize(ctx, budget); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+// ListBudgets returns every budget the acting user may see
+func (b *budgetUseCase) ListBudgets(ctx context.Context) ([]*domain.Budget, error) {
+	budgets, err := b.budgetRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := b.actorGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return visibleBudgets(ctx, budgets, groups), nil
+}
+
+// UpdateBudget replaces every field of a budget
+func (b *budgetUseCase) UpdateBudget(ctx context.Context, id, category, period string, amount domain.Money, userID, groupID string) (*domain.Budget, error) {
+	budget, err := b.budgetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.authorize(ctx, budget); err != nil {
+		return nil, err
+	}
+
+	if err := b.apply(ctx, budget, category, period, amount, userID, groupID); err != nil {
+		return nil, err
+	}
+	if err := b.authorize(ctx, budget); err != nil {
+		return nil, err
+	}
+	budget.UpdatedAt = time.Now()
+
+	if err := b.budgetRepo.Update(ctx, budget); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+func (b *budgetUseCase) DeleteBudget(ctx context.Context, id string) error {
+	budget, err := b.budgetRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := b.authorize(ctx, budget); err != nil {
+		return err
+	}
+	return b.budgetRepo.Delete(ctx, id)
+}
+
+// authorize checks the acting user may see and change the budget
+func (b *budgetUseCase) authorize(ctx context.Context, budget *domain.Budget) error {
+	groups, err := b.actorGroups(ctx)
+	if err != nil {
+		return err
+	}
+	return authorizeBudget(ctx, budget, groups)
+}
+
+// actorGroups returns the acting user's groups, or none for internal callers
+func (b *budgetUseCase) actorGroups(ctx context.Context) ([]*domain.Group, error) {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	return b.groupRepo.GetByUserID(ctx, userID)
+}
+
+// apply resolves and validates the given fields and sets them on budget
+func (b *budgetUseCase) apply(ctx context.Context, budget *domain.Budget, category, period string, amount domain.Money, userID, groupID string) error {
+	if domain.NormalizeCategory(category) == "" {
+		return errors.New("budget category is required")
+	}
+	name, err := resolveCategory(ctx, b.categoryRepo, category)
+	if err != nil {
+		return err
+	}
+	budgetPeriod, err := domain.ParseBudgetPeriod(period)
+	if err != nil {
+		return err
+	}
+
+	if userID != "" {
+		if _, err := b.userRepo.GetByID(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	currency := b.defaultCurrency
+	if groupID != "" {
+		group, err := b.groupRepo.GetByID(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if userID != "" && !group.HasMember(userID) {
+			return errors.New("user is not a member of the group")
+		}
+		currency = group.BaseCurrency
+	}
+	if amount.Currency != "" {
+		if currency, err = domain.ParseCurrency(string(amount.Currency)); err != nil {
+			return err
+		}
+	}
+
+	budget.Category = name
+	budget.Period = budgetPeriod
+	budget.Amount = domain.NewMoney(amount.Minor, currency)
+	budget.UserID = userID
+	budget.GroupID = groupID
+	return budget.Validate()
+}
+
+// GetBudgetStatus reports every budget the acting user may see in the period
+// containing the month: what has been spent up to the end of the month, or
+// today if that is sooner, and where spending is heading at its daily run
+// rate. Against budgets outside a group and user, a signed-in user's status
+// counts just the expenses they take part in.
+func (b *budgetUseCase) GetBudgetStatus(ctx context.Context, year, month int) (*domain.BudgetReport, error) {
+	if month < 1 || month > 12 {
+		return nil, errors.New("month must be between 1 and 12")
+	}
+
+	budgets, categories, err := b.budgets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := b.actorGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	budgets = visibleBudgets(ctx, budgets, groups)
+
+	report := &domain.BudgetReport{
+		Year:    year,
+		Month:   month,
+		Budgets: make([]*domain.BudgetStatus, 0, len(budgets)),
+	}
+	now := time.Now()
+	for _, budget := range budgets {
+		status, err := b.status(ctx, budget, categories, year, month, now)
+		if err != nil {
+			return nil, err
+		}
+		report.Budgets = append(report.Budgets, status)
+	}
+	return report, nil
+}
+
+func (b *budgetUseCase) status(ctx context.Context, budget *domain.Budget, categories []*domain.Category, year, month int, now time.Time) (*domain.BudgetStatus, error) {
+	loc, err := b.location(ctx, budget.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	start, end := budget.Period.Range(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc))
+
+	// Spending counts up to the end of the month, or the end of today
+	asOf := time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, loc)
+	today := now.In(loc)
+	if tomorrow := time.Date(today.Year(), today.Month(), today.Day()+1, 0, 0, 0, 0, loc); tomorrow.Before(asOf) {
+		asOf = tomorrow
+	}
+
+	currency := budget.Amount.Currency
+	status := &domain.BudgetStatus{
+		BudgetID:     budget.ID,
+		Category:     budget.Category,
+		Period:       budget.Period,
+		UserID:       budget.UserID,
+		GroupID:      budget.GroupID,
+		Currency:     currency,
+		Amount:       budget.Amount,
+		PeriodStart:  start.Format("2006-01-02"),
+		PeriodEnd:    end.AddDate(0, 0, -1).Format("2006-01-02"),
+		DaysInPeriod: daysBetween(start, end),
+		Spent:        domain.NewMoney(0, currency),
+	}
+	if budget.UserID == "" {
+		status.ByUser = make(map[string]domain.Money)
+	}
+
+	if asOf.After(start) {
+		status.DaysElapsed = daysBetween(start, asOf)
+		var participant string
+		if budget.UserID == "" && budget.GroupID == "" {
+			participant, _ = domain.UserIDFromContext(ctx)
+		}
+		expenses, err := b.spending(ctx, budget, categoryNames(categories, budget.Category), participant, start, asOf)
+		if err != nil {
+			return nil, err
+		}
+		for _, expense := range expenses {
+			if status.Spent, err = addBudgetShare(status.Spent, budget, expense); err != nil {
+				return nil, err
+			}
+			if budget.UserID == "" {
+				for _, split := range expense.Splits {
+					if status.ByUser[split.UserID], err = status.ByUser[split.UserID].CheckedAdd(split.Amount); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	status.Remaining = budget.Amount.Sub(status.Spent)
+	status.PercentUsed = domain.PercentOf(status.Spent, budget.Amount)
+	status.AveragePerDay, status.Projected = domain.ProjectSpend(status.Spent, status.DaysElapsed, status.DaysInPeriod)
+	status.OverBudget = status.Spent.Cmp(budget.Amount) > 0
+	status.ProjectedOver = status.Projected.Cmp(budget.Amount) > 0
+	return status, nil
+}
+
+// GetBudgetAlerts returns the alerts raised in a month, newest first
+func (b *budgetUseCase) GetBudgetAlerts(ctx context.Context, year, month int) ([]*domain.BudgetAlert, error) {
+	if month < 1 || month > 12 {
+		return nil, errors.New("month must be between 1 and 12")
+	}
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	alerts, err := b.budgetRepo.GetAlerts(ctx, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+	groups, err := b.actorGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return visibleBudgetAlerts(ctx, alerts, groups), nil
+}
+
+func (b *budgetUseCase) CheckExpense(ctx context.Context, expense *domain.Expense) ([]*domain.BudgetAlert, error) {
+	budgets, categories, err := b.budgets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []*domain.BudgetAlert
+	for _, budget := range budgets {
+		if budget.GroupID != "" && budget.GroupID != expense.GroupID {
+			continue
+		}
+		names := categoryNames(categories, budget.Category)
+		if !slices.Contains(names, expense.Category) {
+			continue
+		}
+
+		// Expense dates are kept in their household's time zone, so the
+		// period is read off the date as it is
+		start, end := budget.Period.Range(expense.Date)
+		expenses, err := b.spending(ctx, budget, names, "", start, end)
+		if err != nil {
+			return alerts, err
+		}
+		var spent, added domain.Money
+		for _, other := range expenses {
+			before := spent
+			if spent, err = addBudgetShare(spent, budget, other); err != nil {
+				return alerts, err
+			}
+			if other.ID == expense.ID {
+				added = spent.Sub(before)
+			}
+		}
+		if !added.IsPositive() {
+			continue
+		}
+
+		for _, threshold := range budget.CrossedThresholds(spent.Sub(added), spent, b.thresholds) {
+			alert := &domain.BudgetAlert{
+				ID:          uuid.New().String(),
+				BudgetID:    budget.ID,
+				Category:    budget.Category,
+				Period:      budget.Period,
+				UserID:      budget.UserID,
+				GroupID:     budget.GroupID,
+				ExpenseID:   expense.ID,
+				PeriodStart: start.Format("2006-01-02"),
+				Threshold:   threshold,
+				Currency:    budget.Amount.Currency,
+				Amount:      budget.Amount,
+				Spent:       spent,
+				CreatedAt:   time.Now(),
+			}
+			if err := b.budgetRepo.AppendAlert(ctx, alert); err != nil {
+				return alerts, err
+			}
+			if err := b.notifier.NotifyBudgetAlert(ctx, alert); err != nil {
+				return alerts, err
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+// budgets returns every budget and category. A category's own monthly budget
+// counts as a household budget on it unless one has been set explicitly.
+func (b *budgetUseCase) budgets(ctx context.Context) ([]*domain.Budget, []*domain.Category, error) {
+	budgets, err := b.budgetRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	categories, err := b.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, category := range categories {
+		if category.MonthlyBudget == nil {
+			continue
+		}
+		implicit := &domain.Budget{Category: category.Name, Period: domain.BudgetMonthly, Amount: *category.MonthlyBudget}
+		if !slices.ContainsFunc(budgets, implicit.Covers) {
+			budgets = append(budgets, implicit)
+		}
+	}
+	sort.SliceStable(budgets, func(i, j int) bool {
+		return budgets[i].Category < budgets[j].Category
+	})
+	return budgets, categories, nil
+}
+
+// spending returns the expenses counting against a budget in [start, end),
+// in the budget's currency, and if userID is set just those the user paid for
+// or shares
+func (b *budgetUseCase) spending(ctx context.Context, budget *domain.Budget, categories []string, userID string, start, end time.Time) ([]*domain.Expense, error) {
+	expenses, err := b.expenseRepo.GetByFilters(ctx, domain.ExpenseFilter{
+		GroupID:     budget.GroupID,
+		Categories:  categories,
+		StartDate:   start.Format("2006-01-02"),
+		EndDate:     end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Participant: budget.UserID,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.converter.convertExpenses(ctx, expenses, budget.Amount.Currency)
+}
+
+// location returns the time zone of the group's household, or the server's
+// for budgets outside a group
+func (b *budgetUseCase) location(ctx context.Context, groupID string) (*time.Location, error) {
+	if groupID == "" {
+		return time.Local, nil
+	}
+	group, err := b.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return group.Location()
+}
+
+// categoryNames returns the name of a category and of its subcategories
+func categoryNames(categories []*domain.Category, name string) []string {
+	names := []string{name}
+	var id string
+	for _, category := range categories {
+		if category.Name == name {
+			id = category.ID
+		}
+	}
+	for _, category := range categories {
+		if id != "" && category.ParentID == id {
+			names = append(names, category.Name)
+		}
+	}
+	return names
+}
+
+// addBudgetShare adds to spent how much of an expense counts against a
+// budget: all of it for a household budget, the user's split for a user's
+// budget. An expense still in another currency is ErrCurrencyMismatch.
+func addBudgetShare(spent domain.Money, budget *domain.Budget, expense *domain.Expense) (domain.Money, error) {
+	if budget.UserID == "" {
+		return spent.CheckedAdd(expense.Amount)
+	}
+	for _, split := range expense.Splits {
+		if split.UserID != budget.UserID {
+			continue
+		}
+		var err error
+		if spent, err = spent.CheckedAdd(split.Amount); err != nil {
+			return domain.Money{}, err
+		}
+	}
+	return spent, nil
+}
+
+// daysBetween counts the calendar days in [start, end), which may be 23 or 25
+// hours long across a daylight saving change
+func daysBetween(start, end time.Time) int {
+	return int(math.Round(end.Sub(start).Hours() / 24))
+}
+
+// budgetAlertingExpenseUseCase checks the budgets after each expense it
+// creates
+type budgetAlertingExpenseUseCase struct {
+	ExpenseUseCase
+	budgetUC BudgetUseCase
+}
+
+// WithBudgetAlerts wraps expenseUC so every expense it creates, recurring ones
+// included, is checked against the budgets. Imported expenses are history and
+// raise no alerts.
+func WithBudgetAlerts(expenseUC ExpenseUseCase, budgetUC BudgetUseCase) ExpenseUseCase {
+	return &budgetAlertingExpenseUseCase{
+		ExpenseUseCase: expenseUC,
+		budgetUC:       budgetUC,
+	}
+}
+
+func (a *budgetAlertingExpenseUseCase) CreateExpense(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, splits []domain.Split) (*domain.Expense, error) {
+	expense, err := a.ExpenseUseCase.CreateExpense(ctx, groupID, description, category, paidBy, amount, date, splits)
+	return a.check(ctx, expense, err)
+}
+
+func (a *budgetAlertingExpenseUseCase) CreateExpenseWithEqualSplit(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, userIDs []string) (*domain.Expense, error) {
+	expense, err := a.ExpenseUseCase.CreateExpenseWithEqualSplit(ctx, groupID, description, category, paidBy, amount, date, userIDs)
+	return a.check(ctx, expense, err)
+}
+
+func (a *budgetAlertingExpenseUseCase) CreateSplitExpense(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, spec domain.SplitSpec) (*domain.Expense, error) {
+	expense, err := a.ExpenseUseCase.CreateSplitExpense(ctx, groupID, description, category, paidBy, amount, date, spec)
+	return a.check(ctx, expense, err)
+}
+
+func (a *budgetAlertingExpenseUseCase) CreateOccurrence(ctx context.Context, recurring *domain.RecurringExpense, due time.Time) (*domain.Expense, error) {
+	expense, err := a.ExpenseUseCase.CreateOccurrence(ctx, recurring, due)
+	return a.check(ctx, expense, err)
+}
+
+// check raises the budget alerts for a created expense. The expense is saved
+// by now, so a failed check is logged rather than returned.
+func (a *budgetAlertingExpenseUseCase) check(ctx context.Context, expense *domain.Expense, err error) (*domain.Expense, error) {
+	if err != nil {
+		return nil, err
+	}
+	if _, err := a.budgetUC.CheckExpense(ctx, expense); err != nil {
+		log.Printf("failed to check budgets for expense %s: %v", expense.ID, err)
+	}
+	return expense, nil
+}
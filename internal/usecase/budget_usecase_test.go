@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// recordingBudgetNotifier keeps the alerts it is given instead of sending them
+type recordingBudgetNotifier struct {
+	alerts []*domain.BudgetAlert
+}
+
+func (n *recordingBudgetNotifier) NotifyBudgetAlert(ctx context.Context, alert *domain.BudgetAlert) error {
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+type budgetFixture struct {
+	budgets    BudgetUseCase
+	categories CategoryUseCase
+	expenses   ExpenseUseCase
+	groups     GroupUseCase
+	notifier   *recordingBudgetNotifier
+}
+
+func newBudgetFixture(t *testing.T) *budgetFixture {
+	t.Helper()
+	repos := newTestRepos(t, "alice", "bob", "carol")
+	notifier := &recordingBudgetNotifier{}
+	budgets := NewBudgetUseCase(repos.budgets, repos.expenses, repos.categories, repos.users, repos.groups, repos.rates, notifier, []int{100, 80}, domain.DefaultCurrency)
+	return &budgetFixture{
+		budgets:    budgets,
+		categories: NewCategoryUseCase(repos.categories, domain.DefaultCurrency),
+		expenses:   WithBudgetAlerts(repos.expenseUseCase(), budgets),
+		groups:     NewGroupUseCase(repos.groups, repos.users, domain.DefaultCurrency),
+		notifier:   notifier,
+	}
+}
+
+func (f *budgetFixture) spend(t *testing.T, category, paidBy string, rupees int64, date string) *domain.Expense {
+	t.Helper()
+	expense, err := f.expenses.CreateExpenseWithEqualSplit(context.Background(), "", "Shopping", category, paidBy, inr(rupees), date, []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	return expense
+}
+
+func TestBudgetUseCase_CreateBudget(t *testing.T) {
+	f := newBudgetFixture(t)
+	ctx := context.Background()
+
+	amount := inr(8000)
+	amount.Currency = ""
+	budget, err := f.budgets.CreateBudget(ctx, " Groceries ", "", amount, "", "")
+	if err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+	if budget.Category != "groceries" || budget.Period != domain.BudgetMonthly || budget.Amount != inr(8000) {
+		t.Errorf("Expected a monthly groceries budget of %s, got %+v", inr(8000), budget)
+	}
+
+	if _, err := f.budgets.CreateBudget(ctx, "groceries", "monthly", inr(5000), "", ""); !errors.Is(err, domain.ErrBudgetExists) {
+		t.Errorf("Expected ErrBudgetExists, got %v", err)
+	}
+	if _, err := f.budgets.CreateBudget(ctx, "groceries", "monthly", inr(3000), "bob", ""); err != nil {
+		t.Errorf("Expected a user's budget alongside the household's, got %v", err)
+	}
+	if _, err := f.budgets.CreateBudget(ctx, "takeaway", "monthly", inr(3000), "", ""); !errors.Is(err, domain.ErrCategoryNotFound) {
+		t.Errorf("Expected ErrCategoryNotFound, got %v", err)
+	}
+	if _, err := f.budgets.CreateBudget(ctx, "", "monthly", inr(3000), "", ""); err == nil {
+		t.Error("Expected a budget without a category to be refused")
+	}
+	if _, err := f.budgets.CreateBudget(ctx, "rent", "weekly", inr(3000), "", ""); err == nil {
+		t.Error("Expected an unsupported period to be refused")
+	}
+}
+
+func TestBudgetUseCase_Policy(t *testing.T) {
+	f := newBudgetFixture(t)
+	ctx := context.Background()
+	alice := domain.WithUserID(ctx, "alice")
+	bob := domain.WithUserID(ctx, "bob")
+	carol := domain.WithUserID(ctx, "carol")
+
+	flat, err := f.groups.CreateGroup(ctx, "Flat", "", "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	if _, err := f.expenses.CreateExpenseWithEqualSplit(alice, flat.ID, "Shopping", "groceries", "alice", inr(1000), "2024-03-05", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	// Group budgets are for the group's members, user budgets for the user
+	if _, err := f.budgets.CreateBudget(carol, "groceries", "monthly", inr(8000), "", flat.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden creating another group's budget, got %v", err)
+	}
+	if _, err := f.budgets.CreateBudget(carol, "groceries", "monthly", inr(8000), "bob", ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden creating another user's budget, got %v", err)
+	}
+	shared, err := f.budgets.CreateBudget(alice, "groceries", "monthly", inr(8000), "", flat.ID)
+	if err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+	if _, err := f.budgets.CreateBudget(bob, "groceries", "monthly", inr(2000), "bob", ""); err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+	if _, err := f.budgets.CreateBudget(alice, "groceries", "monthly", inr(5000), "", ""); err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+
+	if _, err := f.budgets.GetBudget(carol, shared.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden reading another group's budget, got %v", err)
+	}
+	if _, err := f.budgets.UpdateBudget(carol, shared.ID, "groceries", "monthly", inr(1), "", flat.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden updating another group's budget, got %v", err)
+	}
+	if err := f.budgets.DeleteBudget(carol, shared.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden deleting another group's budget, got %v", err)
+	}
+	if _, err := f.budgets.UpdateBudget(bob, shared.ID, "groceries", "monthly", inr(9000), "", flat.ID); err != nil {
+		t.Errorf("Expected a member to update the group's budget, got %v", err)
+	}
+
+	budgets, err := f.budgets.ListBudgets(carol)
+	if err != nil {
+		t.Fatalf("ListBudgets() failed: %v", err)
+	}
+	if len(budgets) != 1 || budgets[0].GroupID != "" || budgets[0].UserID != "" {
+		t.Errorf("Expected carol to see only the budget outside any group and user, got %+v", budgets)
+	}
+
+	report, err := f.budgets.GetBudgetStatus(carol, 2024, 3)
+	if err != nil {
+		t.Fatalf("GetBudgetStatus() failed: %v", err)
+	}
+	if len(report.Budgets) != 1 || !report.Budgets[0].Spent.IsZero() {
+		t.Errorf("Expected carol's status to hold one budget with none of her spending, got %+v", report.Budgets)
+	}
+	if report, err = f.budgets.GetBudgetStatus(alice, 2024, 3); err != nil {
+		t.Fatalf("GetBudgetStatus() failed: %v", err)
+	}
+	if len(report.Budgets) != 2 {
+		t.Errorf("Expected alice to see the flat's budget and the everyone's budget, got %d", len(report.Budgets))
+	}
+	for _, status := range report.Budgets {
+		if status.Spent != inr(1000) {
+			t.Errorf("Expected alice's status on %s/%s to count the flat's groceries, got %s", status.GroupID, status.UserID, status.Spent)
+		}
+	}
+}
+
+func TestBudgetUseCase_GetBudgetStatus(t *testing.T) {
+	f := newBudgetFixture(t)
+	ctx := context.Background()
+
+	groceries := mustCategoryID(t, f.categories, "groceries")
+	if _, err := f.categories.CreateCategory(ctx, "vegetables", groceries, "", "", nil); err != nil {
+		t.Fatalf("CreateCategory() failed: %v", err)
+	}
+	diningBudget := inr(1000)
+	dining := mustCategoryID(t, f.categories, "dining")
+	if _, err := f.categories.UpdateCategory(ctx, dining, "dining", "", "", "", &diningBudget); err != nil {
+		t.Fatalf("UpdateCategory() failed: %v", err)
+	}
+
+	if _, err := f.budgets.CreateBudget(ctx, "groceries", "monthly", inr(8000), "", ""); err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+	if _, err := f.budgets.CreateBudget(ctx, "groceries", "monthly", inr(1500), "bob", ""); err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+
+	f.spend(t, "groceries", "alice", 3000, "2024-03-05")
+	f.spend(t, "vegetables", "bob", 1000, "2024-03-20")
+	f.spend(t, "dining", "alice", 500, "2024-03-21")
+	f.spend(t, "groceries", "alice", 900, "2024-04-01")
+
+	report, err := f.budgets.GetBudgetStatus(ctx, 2024, 3)
+	if err != nil {
+		t.Fatalf("GetBudgetStatus() failed: %v", err)
+	}
+	if len(report.Budgets) != 3 {
+		t.Fatalf("Expected 3 budgets, got %d", len(report.Budgets))
+	}
+
+	byScope := make(map[string]*domain.BudgetStatus)
+	for _, status := range report.Budgets {
+		byScope[status.Category+"/"+status.UserID] = status
+	}
+
+	household := byScope["groceries/"]
+	if household == nil {
+		t.Fatal("Expected the household groceries budget in the report")
+	}
+	if household.Spent != inr(4000) || household.PercentUsed != 50 || household.Remaining != inr(4000) {
+		t.Errorf("Expected %s spent including vegetables, 50%% used, got %s and %d%%", inr(4000), household.Spent, household.PercentUsed)
+	}
+	if household.ByUser["alice"] != inr(2000) || household.ByUser["bob"] != inr(2000) {
+		t.Errorf("Expected each user's share to be %s, got %v", inr(2000), household.ByUser)
+	}
+	if household.PeriodStart != "2024-03-01" || household.PeriodEnd != "2024-03-31" || household.DaysElapsed != 31 || household.Projected != inr(4000) {
+		t.Errorf("Expected a finished March to project what was spent, got %+v", household)
+	}
+
+	bob := byScope["groceries/bob"]
+	if bob == nil || bob.Spent != inr(2000) || !bob.OverBudget || bob.Remaining != inr(-500) {
+		t.Errorf("Expected bob's share of %s to overspend his budget by %s, got %+v", inr(2000), inr(500), bob)
+	}
+
+	diningStatus := byScope["dining/"]
+	if diningStatus == nil || diningStatus.BudgetID != "" || diningStatus.Spent != inr(500) {
+		t.Errorf("Expected dining's own monthly budget with %s spent, got %+v", inr(500), diningStatus)
+	}
+
+	if _, err := f.budgets.GetBudgetStatus(ctx, 2024, 13); err == nil {
+		t.Error("Expected an invalid month to be refused")
+	}
+}
+
+func TestBudgetUseCase_YearlyBudget(t *testing.T) {
+	f := newBudgetFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.budgets.CreateBudget(ctx, "travel", "yearly", inr(36600), "", ""); err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+	f.spend(t, "travel", "alice", 6000, "2024-01-10")
+	f.spend(t, "travel", "bob", 4000, "2024-02-10")
+	f.spend(t, "travel", "bob", 9000, "2024-03-10")
+
+	report, err := f.budgets.GetBudgetStatus(ctx, 2024, 2)
+	if err != nil {
+		t.Fatalf("GetBudgetStatus() failed: %v", err)
+	}
+	status := report.Budgets[0]
+	// 60 days into a 366 day year
+	if status.Spent != inr(10000) || status.DaysElapsed != 60 || status.DaysInPeriod != 366 {
+		t.Fatalf("Expected %s spent over 60 of 366 days, got %s over %d of %d", inr(10000), status.Spent, status.DaysElapsed, status.DaysInPeriod)
+	}
+	if want := inr(61000); status.Projected != want || !status.ProjectedOver {
+		t.Errorf("Expected a projected %s over budget, got %s", want, status.Projected)
+	}
+}
+
+func TestBudgetUseCase_Alerts(t *testing.T) {
+	f := newBudgetFixture(t)
+	ctx := context.Background()
+
+	budget, err := f.budgets.CreateBudget(ctx, "groceries", "monthly", inr(1000), "", "")
+	if err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+
+	f.spend(t, "groceries", "alice", 700, "2024-03-01")
+	if len(f.notifier.alerts) != 0 {
+		t.Fatalf("Expected no alert at 70%%, got %d", len(f.notifier.alerts))
+	}
+	f.spend(t, "dining", "alice", 900, "2024-03-02")
+	f.spend(t, "groceries", "alice", 700, "2024-04-01")
+	if len(f.notifier.alerts) != 0 {
+		t.Fatalf("Expected no alert for other categories or months, got %d", len(f.notifier.alerts))
+	}
+
+	crossing := f.spend(t, "groceries", "bob", 100, "2024-03-03")
+	f.spend(t, "groceries", "bob", 50, "2024-03-04")
+	f.spend(t, "groceries", "bob", 300, "2024-03-05")
+
+	var thresholds []int
+	for _, alert := range f.notifier.alerts {
+		thresholds = append(thresholds, alert.Threshold)
+	}
+	if !slices.Equal(thresholds, []int{80, 100}) {
+		t.Fatalf("Expected an alert at 80%% then 100%%, got %v", thresholds)
+	}
+	first := f.notifier.alerts[0]
+	if first.BudgetID != budget.ID || first.ExpenseID != crossing.ID || first.Spent != inr(800) || first.PeriodStart != "2024-03-01" {
+		t.Errorf("Expected the 80%% alert for the expense that reached %s, got %+v", inr(800), first)
+	}
+
+	now := time.Now()
+	alerts, err := f.budgets.GetBudgetAlerts(ctx, now.Year(), int(now.Month()))
+	if err != nil {
+		t.Fatalf("GetBudgetAlerts() failed: %v", err)
+	}
+	if len(alerts) != 2 || alerts[0].Threshold != 100 {
+		t.Errorf("Expected both alerts, newest first, got %d", len(alerts))
+	}
+}
+
+func TestBudgetUseCase_BudgetsFollowCategories(t *testing.T) {
+	f := newBudgetFixture(t)
+	ctx := context.Background()
+
+	category, err := f.categories.CreateCategory(ctx, "pets", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateCategory() failed: %v", err)
+	}
+	budget, err := f.budgets.CreateBudget(ctx, "pets", "monthly", inr(2000), "", "")
+	if err != nil {
+		t.Fatalf("CreateBudget() failed: %v", err)
+	}
+
+	if _, err := f.categories.UpdateCategory(ctx, category.ID, "pet care", "", "", "", nil); err != nil {
+		t.Fatalf("UpdateCategory() failed: %v", err)
+	}
+	budget, err = f.budgets.GetBudget(ctx, budget.ID)
+	if err != nil || budget.Category != "pet care" {
+		t.Fatalf("Expected the budget to follow the rename, got %v, %v", budget, err)
+	}
+
+	if err := f.categories.DeleteCategory(ctx, category.ID); err != nil {
+		t.Fatalf("DeleteCategory() failed: %v", err)
+	}
+	if _, err := f.budgets.GetBudget(ctx, budget.ID); !errors.Is(err, domain.ErrBudgetNotFound) {
+		t.Errorf("Expected the budget to go with its category, got %v", err)
+	}
+}
+
+func mustCategoryID(t *testing.T, categories CategoryUseCase, name string) string {
+	t.Helper()
+	all, err := categories.ListCategories(context.Background())
+	if err != nil {
+		t.Fatalf("ListCategories() failed: %v", err)
+	}
+	for _, category := range all {
+		if category.Name == name {
+			return category.ID
+		}
+	}
+	t.Fatalf("Category %q not found", name)
+	return ""
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+type CategoryUseCase interface {
+	CreateCategory(ctx context.Context, name, parentID, icon, color string, monthlyBudget *domain.Money) (*domain.Category, error)
+	GetCategory(ctx context.Context, id string) (*domain.Category, error)
+	ListCategories(ctx context.Context) ([]*domain.Category, error)
+	UpdateCategory(ctx context.Context, id, name, parentID, icon, color string, monthlyBudget *domain.Money) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, id string) error
+}
+
+type categoryUseCase struct {
+	categoryRepo    repository.CategoryRepository
+	defaultCurrency domain.Currency
+}
+
+// NewCategoryUseCase creates the category use case. Budgets given without a
+// currency are in defaultCurrency.
+func NewCategoryUseCase(categoryRepo repository.CategoryRepository, defaultCurrency domain.Currency) CategoryUseCase {
+	return &categoryUseCase{
+		categoryRepo:    categoryRepo,
+		defaultCurrency: defaultCurrency,
+	}
+}
+
+func (c *categoryUseCase) CreateCategory(ctx context.Context, name, parentID, icon, color string, monthlyBudget *domain.Money) (*domain.Category, error) {
+	now := time.Now()
+	category := &domain.Category{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := c.apply(ctx, category, name, parentID, icon, color, monthlyBudget); err != nil {
+		return nil, err
+	}
+
+	if err := c.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (c *categoryUseCase) GetCategory(ctx context.Context, id string) (*domain.Category, error) {
+	return c.categoryRepo.GetByID(ctx, id)
+}
+
+func (c *categoryUseCase) ListCategories(ctx context.Context) ([]*domain.Category, error) {
+	return c.categoryRepo.GetAll(ctx)
+}
+
+// UpdateCategory replaces every field of a category. Expenses in it follow a
+// rename.
+func (c *categoryUseCase) UpdateCategory(ctx context.Context, id, name, parentID, icon, color string, monthlyBudget *domain.Money) (*domain.Category, error) {
+	category, err := c.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != "" {
+		// Only top-level categories have subcategories
+		categories, err := c.categoryRepo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range categories {
+			if other.ParentID == id {
+				return nil, errors.New("a category with subcategories can't become a subcategory")
+			}
+		}
+	}
+
+	if err := c.apply(ctx, category, name, parentID, icon, color, monthlyBudget); err != nil {
+		return nil, err
+	}
+	category.UpdatedAt = time.Now()
+
+	if err := c.categoryRepo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// DeleteCategory deletes a category no expense, recurring expense or
+// subcategory uses
+func (c *categoryUseCase) DeleteCategory(ctx context.Context, id string) error {
+	return c.categoryRepo.Delete(ctx, id)
+}
+
+// apply normalizes and validates the given fields and sets them on category
+func (c *categoryUseCase) apply(ctx context.Context, category *domain.Category, name, parentID, icon, color string, monthlyBudget *domain.Money) error {
+	category.Name = domain.NormalizeCategory(name)
+	category.ParentID = parentID
+	category.Icon = strings.TrimSpace(icon)
+	category.Color = strings.ToLower(strings.TrimSpace(color))
+	category.MonthlyBudget = nil
+	if monthlyBudget != nil {
+		budget := *monthlyBudget
+		if budget.Currency == "" {
+			budget.Currency = c.defaultCurrency
+		}
+		category.MonthlyBudget = &budget
+	}
+	if err := category.Validate(); err != nil {
+		return err
+	}
+
+	if parentID != "" {
+		parent, err := c.categoryRepo.GetByID(ctx, parentID)
+		if err != nil {
+			return fmt.Errorf("parent %w", err)
+		}
+		if parent.ParentID != "" {
+			return errors.New("subcategories can't have subcategories of their own")
+		}
+	}
+	return nil
+}
+
+// resolveCategory returns the name expenses record for a category given by
+// any spelling of its name. Empty means domain.DefaultCategory.
+func resolveCategory(ctx context.Context, categoryRepo repository.CategoryRepository, name string) (string, error) {
+	name = domain.NormalizeCategory(name)
+	if name == "" {
+		name = domain.DefaultCategory
+	}
+	category, err := categoryRepo.GetByName(ctx, name)
+	if errors.Is(err, domain.ErrCategoryNotFound) {
+		return "", fmt.Errorf("%w: %q; create it first", domain.ErrCategoryNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return category.Name, nil
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+type categoryFixture struct {
+	categories CategoryUseCase
+	expenses   ExpenseUseCase
+}
+
+func newCategoryFixture(t *testing.T) *categoryFixture {
+	t.Helper()
+	repos := newTestRepos(t, "alice", "bob")
+	return &categoryFixture{
+		categories: NewCategoryUseCase(repos.categories, domain.DefaultCurrency),
+		expenses:   repos.expenseUseCase(),
+	}
+}
+
+func TestCategoryUseCase_CreateCategory(t *testing.T) {
+	f := newCategoryFixture(t)
+	ctx := context.Background()
+
+	budget := inr(8000)
+	budget.Currency = ""
+	category, err := f.categories.CreateCategory(ctx, "  Pet  Supplies ", "", "🐶", "#A0522D", &budget)
+	if err != nil {
+		t.Fatalf("CreateCategory() failed: %v", err)
+	}
+	if category.Name != "pet supplies" || category.Color != "#a0522d" {
+		t.Errorf("Expected a normalized name and colour, got %q and %q", category.Name, category.Color)
+	}
+	if category.MonthlyBudget == nil || *category.MonthlyBudget != inr(8000) {
+		t.Errorf("Expected a budget of %s in the default currency, got %v", inr(8000), category.MonthlyBudget)
+	}
+
+	if _, err := f.categories.CreateCategory(ctx, "pet supplies", "", "", "", nil); !errors.Is(err, domain.ErrCategoryExists) {
+		t.Errorf("Expected ErrCategoryExists, got %v", err)
+	}
+}
+
+func TestCategoryUseCase_Subcategories(t *testing.T) {
+	f := newCategoryFixture(t)
+	ctx := context.Background()
+
+	parent, err := f.categories.CreateCategory(ctx, "kids", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateCategory() failed: %v", err)
+	}
+	child, err := f.categories.CreateCategory(ctx, "school fees", parent.ID, "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateCategory() failed for a subcategory: %v", err)
+	}
+
+	if _, err := f.categories.CreateCategory(ctx, "uniforms", child.ID, "", "", nil); err == nil {
+		t.Error("Expected a subcategory of a subcategory to be refused")
+	}
+	if _, err := f.categories.CreateCategory(ctx, "toys", "missing", "", "", nil); err == nil {
+		t.Error("Expected a missing parent to be refused")
+	}
+	other, err := f.categories.CreateCategory(ctx, "pets", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateCategory() failed: %v", err)
+	}
+	if _, err := f.categories.UpdateCategory(ctx, parent.ID, "kids", other.ID, "", "", nil); err == nil {
+		t.Error("Expected a category with subcategories to be refused a parent")
+	}
+	if err := f.categories.DeleteCategory(ctx, parent.ID); !errors.Is(err, domain.ErrCategoryInUse) {
+		t.Errorf("Expected ErrCategoryInUse for a parent, got %v", err)
+	}
+}
+
+func TestCategoryUseCase_RenameAndDelete(t *testing.T) {
+	f := newCategoryFixture(t)
+	ctx := context.Background()
+
+	category, err := f.categories.CreateCategory(ctx, "pets", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateCategory() failed: %v", err)
+	}
+	expense, err := f.expenses.CreateExpenseWithEqualSplit(ctx, "", "Vet", "Pets", "alice", inr(100), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	if expense.Category != "pets" {
+		t.Errorf("Expected the expense to be filed under pets, got %q", expense.Category)
+	}
+
+	if _, err := f.categories.UpdateCategory(ctx, category.ID, "Pet Care", "", "", "", nil); err != nil {
+		t.Fatalf("UpdateCategory() failed: %v", err)
+	}
+	expense, err = f.expenses.GetExpense(ctx, expense.ID)
+	if err != nil {
+		t.Fatalf("GetExpense() failed: %v", err)
+	}
+	if expense.Category != "pet care" {
+		t.Errorf("Expected the expense to follow the rename, got %q", expense.Category)
+	}
+
+	if err := f.categories.DeleteCategory(ctx, category.ID); !errors.Is(err, domain.ErrCategoryInUse) {
+		t.Errorf("Expected ErrCategoryInUse, got %v", err)
+	}
+}
+
+func TestExpenseUseCase_CreateExpense_UnknownCategory(t *testing.T) {
+	f := newCategoryFixture(t)
+	ctx := context.Background()
+
+	_, err := f.expenses.CreateExpenseWithEqualSplit(ctx, "", "Pizza", "takeaway", "alice", inr(100), "", []string{"alice", "bob"})
+	if !errors.Is(err, domain.ErrCategoryNotFound) {
+		t.Fatalf("Expected ErrCategoryNotFound, got %v", err)
+	}
+
+	expense, err := f.expenses.CreateExpenseWithEqualSplit(ctx, "", "Pizza", "", "alice", inr(100), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense without a category: %v", err)
+	}
+	if expense.Category != domain.DefaultCategory {
+		t.Errorf("Expected an uncategorised expense to be filed under %q, got %q", domain.DefaultCategory, expense.Category)
+	}
+}
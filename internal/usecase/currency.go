@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+// currencyConverter converts amounts into a reporting currency using the
+// exchange rate in effect on the date each expense was incurred
+type currencyConverter struct {
+	rates repository.ExchangeRateProvider
+}
+
+// ratio returns the multiplier from one currency to another on a date,
+// falling back to the inverse of the opposite pair when only that is known
+func (c currencyConverter) ratio(ctx context.Context, from, to domain.Currency, date time.Time) (*big.Rat, error) {
+	rate, err := c.rates.GetRate(ctx, from, to, date)
+	if err == nil {
+		return rate.Ratio()
+	}
+	if !errors.Is(err, domain.ErrExchangeRateNotFound) {
+		return nil, err
+	}
+
+	inverse, err := c.rates.GetRate(ctx, to, from, date)
+	if err != nil {
+		if errors.Is(err, domain.ErrExchangeRateNotFound) {
+			return nil, fmt.Errorf("%w: %s to %s on %s", domain.ErrExchangeRateNotFound, from, to, date.Format("2006-01-02"))
+		}
+		return nil, err
+	}
+	ratio, err := inverse.Ratio()
+	if err != nil {
+		return nil, err
+	}
+	return ratio.Inv(ratio), nil
+}
+
+// cachedRatio memoises ratio per source currency and day for one conversion pass
+func (c currencyConverter) cachedRatio(ctx context.Context, cache map[string]*big.Rat, from, to domain.Currency, date time.Time) (*big.Rat, error) {
+	key := string(from) + date.Format("2006-01-02")
+	if ratio, ok := cache[key]; ok {
+		return ratio, nil
+	}
+	ratio, err := c.ratio(ctx, from, to, date)
+	if err != nil {
+		return nil, err
+	}
+	cache[key] = ratio
+	return ratio, nil
+}
+
+// convertExpenses returns the expenses with every amount expressed in the
+// target currency. Splits are converted individually and the expense amount
+// is their sum, so converted balances still net to exactly zero.
+func (c currencyConverter) convertExpenses(ctx context.Context, expenses []*domain.Expense, to domain.Currency) ([]*domain.Expense, error) {
+	ratios := make(map[string]*big.Rat)
+
+	converted := make([]*domain.Expense, 0, len(expenses))
+	for _, expense := range expenses {
+		from := expense.Amount.Currency
+		if from == to {
+			converted = append(converted, expense)
+			continue
+		}
+
+		ratio, err := c.cachedRatio(ctx, ratios, from, to, expense.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		copied := *expense
+		copied.Amount = domain.NewMoney(0, to)
+		copied.Splits = make([]domain.Split, len(expense.Splits))
+		for i, split := range expense.Splits {
+			split.Amount = split.Amount.Convert(ratio, to)
+			copied.Splits[i] = split
+			copied.Amount = copied.Amount.Add(split.Amount)
+		}
+		converted = append(converted, &copied)
+	}
+	return converted, nil
+}
+
+// convertPayments returns the payments with amounts in the target currency
+func (c currencyConverter) convertPayments(ctx context.Context, payments []*domain.Payment, to domain.Currency) ([]*domain.Payment, error) {
+	ratios := make(map[string]*big.Rat)
+
+	converted := make([]*domain.Payment, 0, len(payments))
+	for _, payment := range payments {
+		from := payment.Amount.Currency
+		if from == to {
+			converted = append(converted, payment)
+			continue
+		}
+
+		ratio, err := c.cachedRatio(ctx, ratios, from, to, payment.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		copied := *payment
+		copied.Amount = payment.Amount.Convert(ratio, to)
+		converted = append(converted, &copied)
+	}
+	return converted, nil
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+type ExchangeRateUseCase interface {
+	AddRate(ctx context.Context, from, to, rate, effectiveDate string) (*domain.ExchangeRate, error)
+	GetRates(ctx context.Context, from, to string) ([]*domain.ExchangeRate, error)
+}
+
+type exchangeRateUseCase struct {
+	rateRepo repository.ExchangeRateRepository
+}
+
+func NewExchangeRateUseCase(rateRepo repository.ExchangeRateRepository) ExchangeRateUseCase {
+	return &exchangeRateUseCase{
+		rateRepo: rateRepo,
+	}
+}
+
+func (x *exchangeRateUseCase) AddRate(ctx context.Context, from, to, rate, effectiveDate string) (*domain.ExchangeRate, error) {
+	date, err := time.Parse("2006-01-02", effectiveDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective_date %q: expected YYYY-MM-DD", effectiveDate)
+	}
+
+	fromCurrency, err := domain.ParseCurrency(from)
+	if err != nil {
+		return nil, err
+	}
+	toCurrency, err := domain.ParseCurrency(to)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRate := &domain.ExchangeRate{
+		From:          fromCurrency,
+		To:            toCurrency,
+		Rate:          rate,
+		EffectiveDate: date,
+	}
+
+	if err := exchangeRate.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := x.rateRepo.Save(ctx, exchangeRate); err != nil {
+		return nil, err
+	}
+	return exchangeRate, nil
+}
+
+func (x *exchangeRateUseCase) GetRates(ctx context.Context, from, to string) ([]*domain.ExchangeRate, error) {
+	fromCurrency, err := domain.ParseCurrency(from)
+	if err != nil {
+		return nil, err
+	}
+	toCurrency, err := domain.ParseCurrency(to)
+	if err != nil {
+		return nil, err
+	}
+	return x.rateRepo.GetByPair(ctx, fromCurrency, toCurrency)
+}
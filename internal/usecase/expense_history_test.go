@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+func TestExpenseUseCase_GetExpenseHistory(t *testing.T) {
+	userRepo := memory.NewUserMemoryRepository()
+	events := memory.NewExpenseEventMemoryRepository()
+	expenseUC := NewExpenseUseCase(memory.NewExpenseMemoryRepository().WithEvents(events), events, userRepo, memory.NewGroupMemoryRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+	for _, id := range []string{"alice", "bob", "carol"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+	alice := domain.WithUserID(ctx, "alice")
+	bob := domain.WithUserID(ctx, "bob")
+
+	expense, err := expenseUC.CreateExpenseWithEqualSplit(alice, "", "Electricity", "utilities", "alice", inr(3000), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	_, err = expenseUC.UpdateExpense(bob, expense.ID, "", "", inr(300), "", []domain.Split{{UserID: "alice", Amount: inr(150)}, {UserID: "bob", Amount: inr(150)}})
+	if err != nil {
+		t.Fatalf("Failed to update expense: %v", err)
+	}
+	if err := expenseUC.DeleteExpense(alice, expense.ID); err != nil {
+		t.Fatalf("Failed to delete expense: %v", err)
+	}
+
+	history, err := expenseUC.GetExpenseHistory(bob, expense.ID)
+	if err != nil {
+		t.Fatalf("Expected history to outlive the expense, got: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(history))
+	}
+
+	created, updated, deleted := history[0], history[1], history[2]
+	if created.Action != domain.ExpenseCreated || created.ActorID != "alice" || created.Before != nil || created.After.Amount != inr(3000) {
+		t.Errorf("Unexpected create event: %+v", created)
+	}
+	if updated.Action != domain.ExpenseUpdated || updated.ActorID != "bob" {
+		t.Errorf("Expected bob's update, got: %+v", updated)
+	}
+	if updated.Before.Amount != inr(3000) || updated.After.Amount != inr(300) {
+		t.Errorf("Expected update from 3000 to 300, got %v to %v", updated.Before.Amount, updated.After.Amount)
+	}
+	if updated.Before.Splits[0].Amount != inr(1500) || updated.After.Splits[0].Amount != inr(150) {
+		t.Errorf("Expected split snapshots before and after the update, got %v and %v", updated.Before.Splits, updated.After.Splits)
+	}
+	if deleted.Action != domain.ExpenseDeleted || deleted.ActorID != "alice" || deleted.After != nil {
+		t.Errorf("Unexpected delete event: %+v", deleted)
+	}
+
+	_, err = expenseUC.GetExpenseHistory(domain.WithUserID(ctx, "carol"), expense.ID)
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected non-participant to be forbidden, got: %v", err)
+	}
+	if _, err := expenseUC.GetExpenseHistory(alice, "missing"); err == nil {
+		t.Error("Expected error for unknown expense, got nil")
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// ImportExpenses creates the rows of an import batch as expenses, in groupID
+// if set. Each row is checked as CreateExpense would check it, and the
+// expenses are created, with their history, in one transaction only when no
+// line of the file failed. A dry run only reports what would be created.
+func (e *expenseUseCase) ImportExpenses(ctx context.Context, groupID string, batch *domain.ImportBatch, dryRun bool) (*domain.ImportReport, error) {
+	loc, err := e.expenseLocation(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ImportReport{
+		DryRun:  dryRun,
+		Created: []domain.ImportedExpense{},
+		Failed:  append([]domain.ImportProblem{}, batch.Failed...),
+		Skipped: append([]domain.ImportProblem{}, batch.Skipped...),
+	}
+
+	userIDs := make(map[string]string)
+	expenses := make([]*domain.Expense, 0, len(batch.Rows))
+	for _, row := range batch.Rows {
+		expense, err := e.importRow(ctx, groupID, loc, row, userIDs)
+		if err != nil {
+			report.Failed = append(report.Failed, domain.ImportProblem{Line: row.Line, Reason: err.Error()})
+			continue
+		}
+		expenses = append(expenses, expense)
+		report.Created = append(report.Created, domain.ImportedExpense{Line: row.Line, Expense: expense})
+	}
+	sort.SliceStable(report.Failed, func(i, j int) bool {
+		return report.Failed[i].Line < report.Failed[j].Line
+	})
+
+	if dryRun || len(report.Failed) > 0 {
+		return report, nil
+	}
+
+	events := make([]*domain.ExpenseEvent, len(expenses))
+	for i, expense := range expenses {
+		events[i] = newExpenseEvent(ctx, expense.ID, domain.ExpenseCreated, nil, expense)
+	}
+	if err := e.expenseRepo.CreateBatch(ctx, expenses, events); err != nil {
+		return nil, err
+	}
+	report.Committed = true
+	return report, nil
+}
+
+// importRow builds the expense for one imported row, resolving people by
+// email through userIDs, a cache of users already looked up. The row's
+// calendar date is placed at midnight in loc.
+func (e *expenseUseCase) importRow(ctx context.Context, groupID string, loc *time.Location, row domain.ImportRow, userIDs map[string]string) (*domain.Expense, error) {
+	paidBy, err := e.userIDByEmail(ctx, row.PaidBy, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	splits := make([]domain.Split, 0, len(row.Shares))
+	for _, share := range row.Shares {
+		userID, err := e.userIDByEmail(ctx, share.Email, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, domain.Split{UserID: userID, Amount: share.Amount})
+	}
+	splits = mergeSplits(splits)
+
+	if err := e.checkGroupMembership(ctx, groupID, paidBy, splits); err != nil {
+		return nil, err
+	}
+
+	category, err := resolveCategory(ctx, e.categoryRepo, row.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := e.expenseCurrency(ctx, groupID, row.Amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+	amount, splits := withCurrency(currency, row.Amount, splits)
+
+	now := time.Now()
+	year, month, day := row.Date.Date()
+	expense := &domain.Expense{
+		ID:          uuid.New().String(),
+		GroupID:     groupID,
+		Description: row.Description,
+		Category:    category,
+		PaidBy:      paidBy,
+		Amount:      amount,
+		Splits:      splits,
+		Date:        time.Date(year, month, day, 0, 0, 0, 0, loc),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := expense.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeExpenseCreate(ctx, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+func (e *expenseUseCase) userIDByEmail(ctx context.Context, email string, userIDs map[string]string) (string, error) {
+	if userID, ok := userIDs[email]; ok {
+		return userID, nil
+	}
+	user, err := e.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", email, err)
+	}
+	if !user.IsActive() {
+		return "", fmt.Errorf("%s: %w", email, domain.ErrUserDeactivated)
+	}
+	userIDs[email] = user.ID
+	return user.ID, nil
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+func newImportFixture(t *testing.T) (ExpenseUseCase, *memory.ExpenseMemoryRepository) {
+	t.Helper()
+	repos := newTestRepos(t, "alice", "bob")
+	return repos.expenseUseCase(), repos.expenses
+}
+
+func importRow(line int, paidBy string, shares ...domain.ImportShare) domain.ImportRow {
+	row := domain.ImportRow{
+		Line:        line,
+		Date:        time.Date(2024, 1, line, 0, 0, 0, 0, time.UTC),
+		Description: "Imported",
+		Category:    "food",
+		PaidBy:      paidBy,
+		Shares:      shares,
+	}
+	for _, share := range shares {
+		row.Amount = row.Amount.Add(share.Amount)
+	}
+	return row
+}
+
+func TestExpenseUseCase_ImportExpenses(t *testing.T) {
+	expenseUC, expenseRepo := newImportFixture(t)
+	ctx := context.Background()
+	batch := &domain.ImportBatch{
+		Rows: []domain.ImportRow{
+			importRow(2, "alice@test.com", domain.ImportShare{Email: "alice@test.com", Amount: inr(10)}, domain.ImportShare{Email: "bob@test.com", Amount: inr(20)}),
+			importRow(3, "bob@test.com", domain.ImportShare{Email: "alice@test.com", Amount: inr(5)}),
+		},
+		Skipped: []domain.ImportProblem{{Line: 4, Reason: "settle-up payments are not imported"}},
+	}
+
+	report, err := expenseUC.ImportExpenses(ctx, "", batch, true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if report.Committed || len(report.Created) != 2 || len(report.Skipped) != 1 {
+		t.Errorf("Unexpected dry run report: %+v", report)
+	}
+	if all, _ := expenseRepo.GetAll(ctx); len(all) != 0 {
+		t.Fatalf("Expected a dry run to create nothing, got %d expenses", len(all))
+	}
+
+	report, err = expenseUC.ImportExpenses(ctx, "", batch, false)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if !report.Committed {
+		t.Fatalf("Expected the import to be committed: %+v", report)
+	}
+
+	imported, err := expenseUC.GetExpense(ctx, report.Created[0].Expense.ID)
+	if err != nil {
+		t.Fatalf("Imported expense not found: %v", err)
+	}
+	if imported.PaidBy != "alice" || !imported.Date.Equal(batch.Rows[0].Date) || imported.Amount.Cmp(inr(30)) != 0 {
+		t.Errorf("Unexpected imported expense: %+v", imported)
+	}
+	if history, _ := expenseUC.GetExpenseHistory(ctx, imported.ID); len(history) != 1 {
+		t.Errorf("Expected a created event, got %d events", len(history))
+	}
+}
+
+func TestExpenseUseCase_ImportExpenses_AllOrNothing(t *testing.T) {
+	expenseUC, expenseRepo := newImportFixture(t)
+	ctx := context.Background()
+	batch := &domain.ImportBatch{
+		Rows: []domain.ImportRow{
+			importRow(2, "alice@test.com", domain.ImportShare{Email: "bob@test.com", Amount: inr(10)}),
+			importRow(3, "alice@test.com", domain.ImportShare{Email: "dave@test.com", Amount: inr(10)}),
+		},
+		Failed: []domain.ImportProblem{{Line: 1, Reason: "invalid date"}},
+	}
+
+	report, err := expenseUC.ImportExpenses(ctx, "", batch, false)
+	if err != nil {
+		t.Fatalf("ImportExpenses() failed: %v", err)
+	}
+	if report.Committed {
+		t.Error("Expected nothing to be committed when a line fails")
+	}
+	if len(report.Failed) != 2 || report.Failed[0].Line != 1 || report.Failed[1].Line != 3 {
+		t.Errorf("Expected lines 1 and 3 to fail, got %v", report.Failed)
+	}
+	if all, _ := expenseRepo.GetAll(ctx); len(all) != 0 {
+		t.Errorf("Expected no expenses, got %d", len(all))
+	}
+}
+
+func TestExpenseUseCase_ImportExpenses_OnlyOwnExpenses(t *testing.T) {
+	expenseUC, _ := newImportFixture(t)
+	bob := domain.WithUserID(context.Background(), "bob")
+	batch := &domain.ImportBatch{
+		Rows: []domain.ImportRow{
+			importRow(2, "alice@test.com", domain.ImportShare{Email: "alice@test.com", Amount: inr(10)}),
+		},
+	}
+
+	report, err := expenseUC.ImportExpenses(bob, "", batch, true)
+	if err != nil {
+		t.Fatalf("ImportExpenses() failed: %v", err)
+	}
+	if len(report.Failed) != 1 {
+		t.Errorf("Expected an expense bob is not part of to fail, got %+v", report)
+	}
+}
@@ -3,7 +3,8 @@ package usecase
 import (
 	"context"
 	"errors"
-	"math"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,66 +13,160 @@ import (
 )
 
 type ExpenseUseCase interface {
-	CreateExpense(ctx context.Context, description, category, paidBy string, amount float64, splits []domain.Split) (*domain.Expense, error)
-	CreateExpenseWithEqualSplit(ctx context.Context, description, category, paidBy string, amount float64, userIDs []string) (*domain.Expense, error)
+	CreateExpense(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, splits []domain.Split) (*domain.Expense, error)
+	CreateExpenseWithEqualSplit(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, userIDs []string) (*domain.Expense, error)
+	CreateSplitExpense(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, spec domain.SplitSpec) (*domain.Expense, error)
+	CreateOccurrence(ctx context.Context, recurring *domain.RecurringExpense, due time.Time) (*domain.Expense, error)
+	ImportExpenses(ctx context.Context, groupID string, batch *domain.ImportBatch, dryRun bool) (*domain.ImportReport, error)
 	GetExpense(ctx context.Context, id string) (*domain.Expense, error)
 	GetAllExpenses(ctx context.Context) ([]*domain.Expense, error)
 	GetExpensesByUser(ctx context.Context, userID string) ([]*domain.Expense, error)
 	GetExpensesByDateRange(ctx context.Context, startDate, endDate string) ([]*domain.Expense, error)
 	GetExpensesByCategory(ctx context.Context, category string) ([]*domain.Expense, error)
-	GetExpensesByFilters(ctx context.Context, category, startDate, endDate string) ([]*domain.Expense, error)
+	GetExpensesByFilters(ctx context.Context, groupID, category, startDate, endDate string) ([]*domain.Expense, error)
+	ListExpenses(ctx context.Context, filter domain.ExpenseFilter, page domain.PageRequest) (*domain.ExpensePage, error)
+	GetGroupExpenses(ctx context.Context, groupID string) ([]*domain.Expense, error)
 	GetUserStats(ctx context.Context, userID string) (*domain.UserStats, error)
+	GetGroupUserStats(ctx context.Context, groupID, userID string) (*domain.UserStats, error)
 	GetMonthlySummary(ctx context.Context, year, month int) (*domain.MonthlySummary, error)
-	UpdateExpense(ctx context.Context, id, description, category string, amount float64, splits []domain.Split) (*domain.Expense, error)
+	GetGroupMonthlySummary(ctx context.Context, groupID string, year, month int) (*domain.MonthlySummary, error)
+	UpdateExpense(ctx context.Context, id, description, category string, amount domain.Money, date string, splits []domain.Split) (*domain.Expense, error)
+	SetExpenseTags(ctx context.Context, id string, tags []string) (*domain.Expense, error)
 	DeleteExpense(ctx context.Context, id string) error
-	CalculateBalances(ctx context.Context) (*domain.BalanceSummary, error)
+	CalculateBalances(ctx context.Context, strategy domain.SettlementStrategy) (*domain.BalanceSummary, error)
+	CalculateGroupBalances(ctx context.Context, groupID string, strategy domain.SettlementStrategy) (*domain.BalanceSummary, error)
+	GetPairwiseBalances(ctx context.Context, userID, groupID string) (*domain.PairwiseLedger, error)
+	GetExpenseHistory(ctx context.Context, id string) ([]*domain.ExpenseEvent, error)
+	GetDeletedExpenses(ctx context.Context) ([]*domain.Expense, error)
+	RestoreExpense(ctx context.Context, id string) (*domain.Expense, error)
+	PurgeDeletedExpenses(ctx context.Context, olderThan time.Duration) (int, error)
 }
 
 type expenseUseCase struct {
-	expenseRepo repository.ExpenseRepository
-	userRepo    repository.UserRepository
+	expenseRepo  repository.ExpenseRepository
+	eventRepo    repository.ExpenseEventRepository
+	userRepo     repository.UserRepository
+	groupRepo    repository.GroupRepository
+	categoryRepo repository.CategoryRepository
+	paymentRepo  repository.PaymentRepository
+	converter    currencyConverter
+	baseCurrency domain.Currency
 }
 
-func NewExpenseUseCase(expenseRepo repository.ExpenseRepository, userRepo repository.UserRepository) ExpenseUseCase {
+// NewExpenseUseCase creates the expense use case. Expenses outside a group
+// default to, and are reported in, baseCurrency. Recorded payments are netted
+// against expense balances. Every change to an expense is appended to
+// eventRepo. Categories must exist in categoryRepo.
+func NewExpenseUseCase(expenseRepo repository.ExpenseRepository, eventRepo repository.ExpenseEventRepository, userRepo repository.UserRepository, groupRepo repository.GroupRepository, categoryRepo repository.CategoryRepository, paymentRepo repository.PaymentRepository, rates repository.ExchangeRateProvider, baseCurrency domain.Currency) ExpenseUseCase {
 	return &expenseUseCase{
-		expenseRepo: expenseRepo,
-		userRepo:    userRepo,
+		expenseRepo:  expenseRepo,
+		eventRepo:    eventRepo,
+		userRepo:     userRepo,
+		groupRepo:    groupRepo,
+		categoryRepo: categoryRepo,
+		paymentRepo:  paymentRepo,
+		converter:    currencyConverter{rates: rates},
+		baseCurrency: baseCurrency,
 	}
 }
 
-func (e *expenseUseCase) CreateExpense(ctx context.Context, description, category, paidBy string, amount float64, splits []domain.Split) (*domain.Expense, error) {
+func (e *expenseUseCase) CreateExpense(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, splits []domain.Split) (*domain.Expense, error) {
+	return e.create(ctx, groupID, description, category, paidBy, amount, date, splits, nil, "")
+}
+
+// CreateOccurrence creates the expense for one occurrence of a recurring
+// expense, dated on the day it fell due even when the scheduler catches up
+// late. It fails with domain.ErrOccurrenceExists if the occurrence already
+// has an expense.
+func (e *expenseUseCase) CreateOccurrence(ctx context.Context, recurring *domain.RecurringExpense, due time.Time) (*domain.Expense, error) {
+	// The expense keeps the slice it is given, so each one gets its own
+	splits := make([]domain.Split, len(recurring.Splits))
+	copy(splits, recurring.Splits)
+	if len(recurring.UserIDs) > 0 {
+		shares := recurring.Amount.Allocate(len(recurring.UserIDs))
+		splits = make([]domain.Split, len(recurring.UserIDs))
+		for i, userID := range recurring.UserIDs {
+			splits[i] = domain.Split{UserID: userID, Amount: shares[i]}
+		}
+	}
+
+	date := due.Format("2006-01-02")
+	return e.create(ctx, recurring.GroupID, recurring.Description, recurring.Category, recurring.PaidBy, recurring.Amount, date, splits, nil, recurring.ID)
+}
+
+// create validates and stores a new expense. recurringID is set when the
+// expense is an occurrence of that recurring expense, on the day date.
+func (e *expenseUseCase) create(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, splits []domain.Split, items []domain.ExpenseItem, recurringID string) (*domain.Expense, error) {
 	expenseId := uuid.New().String()
 
-	user, err := e.userRepo.GetByID(ctx, paidBy)
+	user, err := e.activeUser(ctx, paidBy)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, split := range splits {
-		_, err := e.userRepo.GetByID(ctx, split.UserID)
+		_, err := e.activeUser(ctx, split.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.checkGroupMembership(ctx, groupID, paidBy, splits); err != nil {
+		return nil, err
+	}
+
+	category, err = resolveCategory(ctx, e.categoryRepo, category)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := e.expenseCurrency(ctx, groupID, amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+	amount, splits = withCurrency(currency, amount, splits)
+
+	expenseDate, err := e.expenseDate(ctx, groupID, date)
+	if err != nil {
+		return nil, err
+	}
+	if expenseDate.IsZero() {
+		// Default to now as the group's clock reads it, like a date given
+		// without an offset
+		loc, err := e.expenseLocation(ctx, groupID)
 		if err != nil {
 			return nil, err
 		}
+		expenseDate = time.Now().In(loc)
 	}
 
 	expense := &domain.Expense{
 		ID:          expenseId,
+		GroupID:     groupID,
 		Description: description,
 		Category:    category,
 		PaidBy:      user.ID,
 		Amount:      amount,
 		Splits:      splits,
-		Date:        time.Now(),
+		Items:       items,
+		Date:        expenseDate,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	if recurringID != "" {
+		expense.RecurringID = recurringID
+		expense.OccurrenceDate = date
+	}
 
 	err = expense.Validate()
 	if err != nil {
 		return nil, err
 	}
+	if err := authorizeExpenseCreate(ctx, expense); err != nil {
+		return nil, err
+	}
 
-	err = e.expenseRepo.Create(ctx, expense)
+	err = e.expenseRepo.Create(ctx, expense, newExpenseEvent(ctx, expense.ID, domain.ExpenseCreated, nil, expense))
 	if err != nil {
 		return nil, err
 	}
@@ -79,53 +174,76 @@ func (e *expenseUseCase) CreateExpense(ctx context.Context, description, categor
 	return expense, nil
 }
 
-func (e *expenseUseCase) CreateExpenseWithEqualSplit(ctx context.Context, description, category, paidBy string, amount float64, userIDs []string) (*domain.Expense, error) {
+func (e *expenseUseCase) CreateExpenseWithEqualSplit(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, userIDs []string) (*domain.Expense, error) {
 	if len(userIDs) == 0 {
 		return nil, errors.New("at least one user is required for equal split")
 	}
 
 	// Validate all users exist
 	for _, userID := range userIDs {
-		_, err := e.userRepo.GetByID(ctx, userID)
+		_, err := e.activeUser(ctx, userID)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	// Calculate equal split amount
-	splitAmount := amount / float64(len(userIDs))
-
-	// Handle rounding: calculate remainder and add to last user
-	var splits []domain.Split
-	var totalAllocated float64
+	// Allocate the amount exactly; leftover minor units go to the first users
+	shares := amount.Allocate(len(userIDs))
 
+	splits := make([]domain.Split, len(userIDs))
 	for i, userID := range userIDs {
-		if i == len(userIDs)-1 {
-			// Last user gets the remainder to ensure exact total
-			splits = append(splits, domain.Split{
-				UserID: userID,
-				Amount: amount - totalAllocated,
-			})
-		} else {
-			// Round to 2 decimal places
-			roundedAmount := math.Round(splitAmount*100) / 100
-			splits = append(splits, domain.Split{
-				UserID: userID,
-				Amount: roundedAmount,
-			})
-			totalAllocated += roundedAmount
+		splits[i] = domain.Split{
+			UserID: userID,
+			Amount: shares[i],
 		}
 	}
 
-	return e.CreateExpense(ctx, description, category, paidBy, amount, splits)
+	return e.CreateExpense(ctx, groupID, description, category, paidBy, amount, date, splits)
+}
+
+// CreateSplitExpense divides amount according to spec and creates the
+// expense with the resulting splits, and the receipt they came from if any
+func (e *expenseUseCase) CreateSplitExpense(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, date string, spec domain.SplitSpec) (*domain.Expense, error) {
+	strategy, err := newSplitStrategy(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// A receipt is kept with the expense, so it is priced in its currency
+	if len(spec.Receipt) > 0 {
+		if amount.Currency, err = e.expenseCurrency(ctx, groupID, amount.Currency); err != nil {
+			return nil, err
+		}
+		for i := range spec.Receipt {
+			spec.Receipt[i].Price.Currency = amount.Currency
+		}
+	}
+
+	splits, err := strategy.split(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.create(ctx, groupID, description, category, paidBy, amount, date, splits, spec.Receipt, "")
 }
 
 func (e *expenseUseCase) GetExpense(ctx context.Context, id string) (*domain.Expense, error) {
-	return e.expenseRepo.GetByID(ctx, id)
+	expense, err := e.expenseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeExpenseRead(ctx, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
 }
 
 func (e *expenseUseCase) GetAllExpenses(ctx context.Context) ([]*domain.Expense, error) {
-	return e.expenseRepo.GetAll(ctx)
+	expenses, err := e.expenseRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return visibleExpenses(ctx, expenses), nil
 }
 
 func (e *expenseUseCase) GetExpensesByUser(ctx context.Context, userID string) ([]*domain.Expense, error) {
@@ -133,27 +251,43 @@ func (e *expenseUseCase) GetExpensesByUser(ctx context.Context, userID string) (
 	if err != nil {
 		return nil, err
 	}
-	return e.expenseRepo.GetByUserID(ctx, userID)
+	expenses, err := e.expenseRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return visibleExpenses(ctx, expenses), nil
 }
 
 func (e *expenseUseCase) GetExpensesByDateRange(ctx context.Context, startDate, endDate string) ([]*domain.Expense, error) {
 	if startDate == "" || endDate == "" {
 		return nil, errors.New("both start_date and end_date are required")
 	}
-	return e.expenseRepo.GetByDateRange(ctx, startDate, endDate)
+	expenses, err := e.expenseRepo.GetByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return visibleExpenses(ctx, expenses), nil
 }
 
 func (e *expenseUseCase) GetExpensesByCategory(ctx context.Context, category string) ([]*domain.Expense, error) {
 	if category == "" {
 		return nil, errors.New("category is required")
 	}
-	return e.expenseRepo.GetByCategory(ctx, category)
+	expenses, err := e.expenseRepo.GetByCategory(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	return visibleExpenses(ctx, expenses), nil
 }
 
-func (e *expenseUseCase) GetExpensesByFilters(ctx context.Context, category, startDate, endDate string) ([]*domain.Expense, error) {
+func (e *expenseUseCase) GetExpensesByFilters(ctx context.Context, groupID, category, startDate, endDate string) ([]*domain.Expense, error) {
 	// If no filters provided, return all expenses
-	if category == "" && startDate == "" && endDate == "" {
-		return e.expenseRepo.GetAll(ctx)
+	if groupID == "" && category == "" && startDate == "" && endDate == "" {
+		expenses, err := e.expenseRepo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return visibleExpenses(ctx, expenses), nil
 	}
 
 	// Validate date range if provided
@@ -161,32 +295,115 @@ func (e *expenseUseCase) GetExpensesByFilters(ctx context.Context, category, sta
 		return nil, errors.New("both start_date and end_date must be provided together")
 	}
 
-	return e.expenseRepo.GetByFilters(ctx, category, startDate, endDate)
+	if groupID != "" {
+		if _, err := e.readableGroup(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	filter := domain.ExpenseFilter{GroupID: groupID, StartDate: startDate, EndDate: endDate}
+	if category != "" {
+		filter.Categories = []string{category}
+	}
+	expenses, err := e.expenseRepo.GetByFilters(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return visibleExpenses(ctx, expenses), nil
+}
+
+// ListExpenses returns one page of the expenses matching filter that the
+// acting user may read
+func (e *expenseUseCase) ListExpenses(ctx context.Context, filter domain.ExpenseFilter, page domain.PageRequest) (*domain.ExpensePage, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if filter.GroupID != "" {
+		if _, err := e.readableGroup(ctx, filter.GroupID); err != nil {
+			return nil, err
+		}
+	}
+	if filter.UserID != "" {
+		if _, err := e.userRepo.GetByID(ctx, filter.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	filter.VisibleTo, _ = domain.UserIDFromContext(ctx)
+	return e.expenseRepo.List(ctx, filter, page)
+}
+
+func (e *expenseUseCase) GetGroupExpenses(ctx context.Context, groupID string) ([]*domain.Expense, error) {
+	if _, err := e.readableGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+	expenses, err := e.expenseRepo.GetByGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return visibleExpenses(ctx, expenses), nil
 }
 
-func (e *expenseUseCase) UpdateExpense(ctx context.Context, id, description, category string, amount float64, splits []domain.Split) (*domain.Expense, error) {
+func (e *expenseUseCase) UpdateExpense(ctx context.Context, id, description, category string, amount domain.Money, date string, splits []domain.Split) (*domain.Expense, error) {
 	// Get existing expense
 	expense, err := e.expenseRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := authorizeExpenseEdit(ctx, expense); err != nil {
+		return nil, err
+	}
 
-	// Validate users in splits exist
+	// Validate users in splits exist. Deactivated users may stay in an
+	// expense they were already part of, but not be added to it.
 	for _, split := range splits {
-		_, err := e.userRepo.GetByID(ctx, split.UserID)
+		user, err := e.userRepo.GetByID(ctx, split.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsActive() && !isParticipant(expense, user.ID) {
+			return nil, fmt.Errorf("%s: %w", user.Name, domain.ErrUserDeactivated)
+		}
+	}
+
+	if err := e.checkGroupMembership(ctx, expense.GroupID, expense.PaidBy, splits); err != nil {
+		return nil, err
+	}
+
+	// An empty category keeps the current one
+	if category != "" {
+		if category, err = resolveCategory(ctx, e.categoryRepo, category); err != nil {
+			return nil, err
+		}
+	}
+
+	// A new currency only applies together with a new amount
+	currency := expense.Amount.Currency
+	if amount.IsPositive() && amount.Currency != "" {
+		currency, err = domain.ParseCurrency(string(amount.Currency))
 		if err != nil {
 			return nil, err
 		}
 	}
+	amount, splits = withCurrency(currency, amount, splits)
+
+	expenseDate, err := e.expenseDate(ctx, expense.GroupID, date)
+	if err != nil {
+		return nil, err
+	}
+	before := domain.SnapshotOf(expense)
 
 	// Update expense fields
-	err = expense.Update(description, category, amount, splits)
+	err = expense.Update(description, category, amount, expenseDate, splits)
 	if err != nil {
 		return nil, err
 	}
 
 	// Save to repository
-	err = e.expenseRepo.Update(ctx, expense)
+	err = e.expenseRepo.Update(ctx, expense, newExpenseEvent(ctx, expense.ID, domain.ExpenseUpdated, before, expense))
 	if err != nil {
 		return nil, err
 	}
@@ -194,83 +411,410 @@ func (e *expenseUseCase) UpdateExpense(ctx context.Context, id, description, cat
 	return expense, nil
 }
 
+// SetExpenseTags replaces an expense's tags
+func (e *expenseUseCase) SetExpenseTags(ctx context.Context, id string, tags []string) (*domain.Expense, error) {
+	tags, err := domain.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	expense, err := e.expenseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeExpenseEdit(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	before := domain.SnapshotOf(expense)
+	expense.Tags = tags
+	expense.UpdatedAt = time.Now()
+	if err := e.expenseRepo.Update(ctx, expense, newExpenseEvent(ctx, expense.ID, domain.ExpenseUpdated, before, expense)); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
 func (e *expenseUseCase) DeleteExpense(ctx context.Context, id string) error {
-	return e.expenseRepo.Delete(ctx, id)
+	expense, err := e.expenseRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeExpenseDelete(ctx, expense); err != nil {
+		return err
+	}
+	before := domain.SnapshotOf(expense)
+
+	return e.expenseRepo.Delete(ctx, id, newExpenseEvent(ctx, id, domain.ExpenseDeleted, before, nil))
 }
 
-func (e *expenseUseCase) CalculateBalances(ctx context.Context) (*domain.BalanceSummary, error) {
+// GetDeletedExpenses lists the trash, most recently deleted first
+func (e *expenseUseCase) GetDeletedExpenses(ctx context.Context) ([]*domain.Expense, error) {
+	expenses, err := e.expenseRepo.GetDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expenses = visibleExpenses(ctx, expenses)
+	sort.Slice(expenses, func(i, j int) bool {
+		return expenses[i].DeletedAt.After(*expenses[j].DeletedAt)
+	})
+	return expenses, nil
+}
+
+// RestoreExpense takes an expense back out of the trash. Only someone allowed
+// to delete the expense may restore it.
+func (e *expenseUseCase) RestoreExpense(ctx context.Context, id string) (*domain.Expense, error) {
+	expense, err := e.expenseRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeExpenseDelete(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	expense.DeletedAt = nil
+	if err := e.expenseRepo.Restore(ctx, id, newExpenseEvent(ctx, expense.ID, domain.ExpenseRestored, nil, expense)); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// PurgeDeletedExpenses permanently removes expenses that have been in the
+// trash for longer than olderThan. Their history is kept.
+func (e *expenseUseCase) PurgeDeletedExpenses(ctx context.Context, olderThan time.Duration) (int, error) {
+	return e.expenseRepo.Purge(ctx, time.Now().Add(-olderThan))
+}
+
+// GetExpenseHistory returns every recorded change to an expense, oldest
+// first. It stays readable after the expense is deleted, to anyone who was
+// ever part of the expense.
+func (e *expenseUseCase) GetExpenseHistory(ctx context.Context, id string) ([]*domain.ExpenseEvent, error) {
+	events, err := e.eventRepo.GetByExpenseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, domain.ErrExpenseNotFound
+	}
+	if err := authorizeHistoryRead(ctx, events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// newExpenseEvent describes a change to an expense for its history,
+// attributed to the acting user if there is one. It is written together with
+// the change.
+func newExpenseEvent(ctx context.Context, expenseID string, action domain.ExpenseAction, before *domain.ExpenseSnapshot, after *domain.Expense) *domain.ExpenseEvent {
+	event := &domain.ExpenseEvent{
+		ID:        uuid.New().String(),
+		ExpenseID: expenseID,
+		Action:    action,
+		Before:    before,
+		CreatedAt: time.Now(),
+	}
+	event.ActorID, _ = domain.UserIDFromContext(ctx)
+	if after != nil {
+		event.After = domain.SnapshotOf(after)
+	}
+	return event
+}
+
+// CalculateBalances works out everyone's balance across all expenses or, for
+// a signed-in user, across the expenses and payments they take part in
+func (e *expenseUseCase) CalculateBalances(ctx context.Context, strategy domain.SettlementStrategy) (*domain.BalanceSummary, error) {
+	if userID, ok := domain.UserIDFromContext(ctx); ok {
+		return e.userBalances(ctx, userID, strategy)
+	}
+
+	payments, err := e.paymentRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err = e.converter.convertPayments(ctx, payments, e.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, ok, err := e.aggregatedBalances(ctx, "", e.baseCurrency, strategy)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return summarizeBalances(e.baseCurrency, strategy, balances, payments, nil), nil
+	}
 
 	expenses, err := e.expenseRepo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	balanceMap := make(map[string]float64)
+	expenses, err = e.converter.convertExpenses(ctx, expenses, e.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, expense := range expenses {
-		balanceMap[expense.PaidBy] += expense.Amount
-		for _, split := range expense.Splits {
-			balanceMap[split.UserID] -= split.Amount
-		}
+	return buildBalanceSummary(e.baseCurrency, strategy, expenses, payments), nil
+}
+
+// userBalances works out the balances across the expenses and payments the
+// user takes part in
+func (e *expenseUseCase) userBalances(ctx context.Context, userID string, strategy domain.SettlementStrategy) (*domain.BalanceSummary, error) {
+	expenses, err := e.expenseRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := e.paymentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	var balances []domain.Balance
-	for userID, amount := range balanceMap {
-		balances = append(balances, domain.Balance{
-			UserID: userID,
-			Amount: amount,
-		})
+	expenses, err = e.converter.convertExpenses(ctx, expenses, e.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	payments, err = e.converter.convertPayments(ctx, payments, e.baseCurrency)
+	if err != nil {
+		return nil, err
 	}
 
-	var settlements []domain.Settlement
-	settlements = calculateSettlements(balances)
+	return buildBalanceSummary(e.baseCurrency, strategy, expenses, payments), nil
+}
 
-	return &domain.BalanceSummary{
-		Balances:    balances,
-		Settlements: settlements,
-	}, nil
+func (e *expenseUseCase) CalculateGroupBalances(ctx context.Context, groupID string, strategy domain.SettlementStrategy) (*domain.BalanceSummary, error) {
+	group, err := e.readableGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := e.paymentRepo.GetByGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err = e.converter.convertPayments(ctx, payments, group.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, ok, err := e.aggregatedBalances(ctx, groupID, group.BaseCurrency, strategy)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return summarizeBalances(group.BaseCurrency, strategy, balances, payments, nil), nil
+	}
+
+	expenses, err := e.expenseRepo.GetByGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
 
+	expenses, err = e.converter.convertExpenses(ctx, expenses, group.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildBalanceSummary(group.BaseCurrency, strategy, expenses, payments), nil
 }
 
-func calculateSettlements(balances []domain.Balance) []domain.Settlement {
-	var settlements []domain.Settlement
+// GetPairwiseBalances breaks a user's balance down by counterparty, across
+// all expenses or, when groupID is set, within that group. Signed-in users
+// see only their own ledger across all expenses, and any member's within a
+// group they belong to.
+func (e *expenseUseCase) GetPairwiseBalances(ctx context.Context, userID, groupID string) (*domain.PairwiseLedger, error) {
+	if _, err := e.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
 
-	var debtors []domain.Balance
-	var creditors []domain.Balance
+	currency := e.baseCurrency
+	var expenses []*domain.Expense
+	var payments []*domain.Payment
+	var err error
+	if groupID != "" {
+		group, err := e.readableGroup(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if !group.HasMember(userID) {
+			return nil, domain.ErrNotGroupMember
+		}
+		currency = group.BaseCurrency
 
-	for _, balance := range balances {
-		if balance.Amount < 0 {
-			debtors = append(debtors, balance)
+		if expenses, err = e.expenseRepo.GetByGroupID(ctx, groupID); err != nil {
+			return nil, err
 		}
-		if balance.Amount > 0 {
-			creditors = append(creditors, balance)
+		if payments, err = e.paymentRepo.GetByGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := authorizeUserTotalsRead(ctx, userID); err != nil {
+			return nil, err
+		}
+		if expenses, err = e.expenseRepo.GetByUserID(ctx, userID); err != nil {
+			return nil, err
+		}
+		if payments, err = e.paymentRepo.GetByUserID(ctx, userID); err != nil {
+			return nil, err
 		}
 	}
 
-	for len(debtors) > 0 && len(creditors) > 0 {
-		debtor := debtors[0]
-		creditor := creditors[0]
+	expenses, err = e.converter.convertExpenses(ctx, expenses, currency)
+	if err != nil {
+		return nil, err
+	}
+	payments, err = e.converter.convertPayments(ctx, payments, currency)
+	if err != nil {
+		return nil, err
+	}
 
-		settlementAmount := math.Min(-debtor.Amount, creditor.Amount)
+	return buildPairwiseLedger(userID, currency, expenses, payments), nil
+}
 
-		settlements = append(settlements, domain.Settlement{
-			From:   debtor.UserID,
-			To:     creditor.UserID,
-			Amount: settlementAmount,
-		})
+// readableGroup fetches a group the acting user may read
+func (e *expenseUseCase) readableGroup(ctx context.Context, groupID string) (*domain.Group, error) {
+	group, err := e.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGroupRead(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// expenseCurrency resolves the currency of a new expense: the requested one
+// if given, else the group's base currency, else the default base currency
+func (e *expenseUseCase) expenseCurrency(ctx context.Context, groupID string, requested domain.Currency) (domain.Currency, error) {
+	if requested != "" {
+		return domain.ParseCurrency(string(requested))
+	}
+	if groupID != "" {
+		group, err := e.groupRepo.GetByID(ctx, groupID)
+		if err != nil {
+			return "", err
+		}
+		return group.BaseCurrency, nil
+	}
+	return e.baseCurrency, nil
+}
+
+// activeUser fetches a user who may take part in a new expense
+func (e *expenseUseCase) activeUser(ctx context.Context, id string) (*domain.User, error) {
+	user, err := e.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, fmt.Errorf("%s: %w", user.Name, domain.ErrUserDeactivated)
+	}
+	return user, nil
+}
+
+// expenseLocation returns the time zone of the group's household, or the
+// server's for expenses outside a group
+func (e *expenseUseCase) expenseLocation(ctx context.Context, groupID string) (*time.Location, error) {
+	if groupID == "" {
+		return time.Local, nil
+	}
+	group, err := e.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return group.Location()
+}
 
-		debtors[0].Amount += settlementAmount
-		creditors[0].Amount -= settlementAmount
+// expenseDate parses a date given for an expense in the group's time zone.
+// An empty date parses to the zero time.
+func (e *expenseUseCase) expenseDate(ctx context.Context, groupID, date string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
+	}
+	loc, err := e.expenseLocation(ctx, groupID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return domain.ParseExpenseDate(date, loc, time.Now())
+}
 
-		if math.Abs(debtors[0].Amount) < 0.01 {
-			debtors = debtors[1:]
+// checkGroupMembership ensures the payer and every split participant belong to
+// the expense's group. Expenses outside a group are not restricted.
+func (e *expenseUseCase) checkGroupMembership(ctx context.Context, groupID, paidBy string, splits []domain.Split) error {
+	if groupID == "" {
+		return nil
+	}
+
+	group, err := e.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	if !group.HasMember(paidBy) {
+		return domain.ErrNotGroupMember
+	}
+	for _, split := range splits {
+		if !group.HasMember(split.UserID) {
+			return domain.ErrNotGroupMember
 		}
+	}
+	return nil
+}
 
-		if math.Abs(creditors[0].Amount) < 0.01 {
-			creditors = creditors[1:]
+// withCurrency stamps the expense currency on an amount and its splits, which
+// arrive from JSON without one
+func withCurrency(currency domain.Currency, amount domain.Money, splits []domain.Split) (domain.Money, []domain.Split) {
+	amount.Currency = currency
+	for i := range splits {
+		splits[i].Amount.Currency = currency
+	}
+	return amount, splits
+}
+
+func buildBalanceSummary(currency domain.Currency, strategy domain.SettlementStrategy, expenses []*domain.Expense, payments []*domain.Payment) *domain.BalanceSummary {
+	balanceMap := make(map[string]domain.Money)
+
+	for _, expense := range expenses {
+		balanceMap[expense.PaidBy] = balanceMap[expense.PaidBy].Add(expense.Amount)
+		for _, split := range expense.Splits {
+			balanceMap[split.UserID] = balanceMap[split.UserID].Sub(split.Amount)
 		}
 	}
-	return settlements
+
+	var shared sharedPairs
+	if strategy == domain.SettlementSharedOnly {
+		shared = buildSharedPairs(expenses, payments)
+	}
+
+	return summarizeBalances(currency, strategy, balanceMap, payments, shared)
+}
+
+// summarizeBalances nets payments into the expense balances and plans the
+// settlements
+func summarizeBalances(currency domain.Currency, strategy domain.SettlementStrategy, balanceMap map[string]domain.Money, payments []*domain.Payment, shared sharedPairs) *domain.BalanceSummary {
+	// A payment clears debt: the payer's balance rises and the payee's falls
+	for _, payment := range payments {
+		balanceMap[payment.From] = balanceMap[payment.From].Add(payment.Amount)
+		balanceMap[payment.To] = balanceMap[payment.To].Sub(payment.Amount)
+	}
+
+	var balances []domain.Balance
+	for userID, amount := range balanceMap {
+		balances = append(balances, domain.Balance{
+			UserID: userID,
+			Amount: amount,
+		})
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].UserID < balances[j].UserID
+	})
+
+	return &domain.BalanceSummary{
+		Currency:    currency,
+		Strategy:    strategy,
+		Balances:    balances,
+		Settlements: planSettlements(balances, strategy, shared),
+	}
 }
 
 func (e *expenseUseCase) GetUserStats(ctx context.Context, userID string) (*domain.UserStats, error) {
@@ -279,6 +823,27 @@ func (e *expenseUseCase) GetUserStats(ctx context.Context, userID string) (*doma
 	if err != nil {
 		return nil, err
 	}
+	if err := authorizeUserTotalsRead(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	payments, err := e.paymentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err = e.converter.convertPayments(ctx, payments, e.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, ok, err := e.aggregatedTotals(ctx, domain.TotalsQuery{UserID: userID, ByCategory: true}, e.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return userStatsFromTotals(userID, e.baseCurrency, totals, payments), nil
+	}
 
 	// Get all expenses
 	expenses, err := e.expenseRepo.GetAll(ctx)
@@ -286,35 +851,113 @@ func (e *expenseUseCase) GetUserStats(ctx context.Context, userID string) (*doma
 		return nil, err
 	}
 
+	expenses, err = e.converter.convertExpenses(ctx, expenses, e.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildUserStats(userID, e.baseCurrency, expenses, payments), nil
+}
+
+func (e *expenseUseCase) GetGroupUserStats(ctx context.Context, groupID, userID string) (*domain.UserStats, error) {
+	group, err := e.readableGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if !group.HasMember(userID) {
+		return nil, domain.ErrNotGroupMember
+	}
+
+	payments, err := e.paymentRepo.GetByGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err = e.converter.convertPayments(ctx, payments, group.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, ok, err := e.aggregatedTotals(ctx, domain.TotalsQuery{GroupID: groupID, UserID: userID, ByCategory: true}, group.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return userStatsFromTotals(userID, group.BaseCurrency, totals, payments), nil
+	}
+
+	expenses, err := e.expenseRepo.GetByGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err = e.converter.convertExpenses(ctx, expenses, group.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildUserStats(userID, group.BaseCurrency, expenses, payments), nil
+}
+
+func buildUserStats(userID string, currency domain.Currency, expenses []*domain.Expense, payments []*domain.Payment) *domain.UserStats {
 	stats := &domain.UserStats{
 		UserID:     userID,
-		ByCategory: make(map[string]float64),
+		Currency:   currency,
+		ByCategory: make(map[string]domain.Money),
 	}
 
 	// Calculate statistics
 	for _, expense := range expenses {
 		// Count expenses where user is payer
 		if expense.PaidBy == userID {
-			stats.TotalPaid += expense.Amount
+			stats.TotalPaid = stats.TotalPaid.Add(expense.Amount)
 			stats.ExpenseCount++
-			stats.ByCategory[expense.Category] += expense.Amount
+			stats.ByCategory[expense.Category] = stats.ByCategory[expense.Category].Add(expense.Amount)
 		}
 
 		// Count expenses where user owes
 		for _, split := range expense.Splits {
 			if split.UserID == userID {
-				stats.TotalOwed += split.Amount
+				stats.TotalOwed = stats.TotalOwed.Add(split.Amount)
 			}
 		}
 	}
 
-	// Calculate net balance (positive means others owe you, negative means you owe)
-	stats.NetBalance = stats.TotalPaid - stats.TotalOwed
+	addPaymentStats(stats, payments)
+	return stats
+}
 
-	return stats, nil
+// addPaymentStats adds the user's payments to their stats and works out
+// their net balance
+func addPaymentStats(stats *domain.UserStats, payments []*domain.Payment) {
+	for _, payment := range payments {
+		if payment.From == stats.UserID {
+			stats.PaymentsSent = stats.PaymentsSent.Add(payment.Amount)
+		}
+		if payment.To == stats.UserID {
+			stats.PaymentsReceived = stats.PaymentsReceived.Add(payment.Amount)
+		}
+	}
+
+	// Calculate net balance (positive means others owe you, negative means you owe)
+	stats.NetBalance = stats.TotalPaid.Sub(stats.TotalOwed).Add(stats.PaymentsSent).Sub(stats.PaymentsReceived)
 }
 
+// GetMonthlySummary sums a month's expenses across all groups or, for a
+// signed-in user, the expenses they take part in
 func (e *expenseUseCase) GetMonthlySummary(ctx context.Context, year, month int) (*domain.MonthlySummary, error) {
+	return e.monthlySummary(ctx, "", e.baseCurrency, year, month)
+}
+
+func (e *expenseUseCase) GetGroupMonthlySummary(ctx context.Context, groupID string, year, month int) (*domain.MonthlySummary, error) {
+	group, err := e.readableGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return e.monthlySummary(ctx, groupID, group.BaseCurrency, year, month)
+}
+
+func (e *expenseUseCase) monthlySummary(ctx context.Context, groupID string, currency domain.Currency, year, month int) (*domain.MonthlySummary, error) {
 	// Validate month
 	if month < 1 || month > 12 {
 		return nil, errors.New("month must be between 1 and 12")
@@ -328,37 +971,82 @@ func (e *expenseUseCase) GetMonthlySummary(ctx context.Context, year, month int)
 	startDateStr := startDate.Format("2006-01-02")
 	endDateStr := endDate.Format("2006-01-02")
 
+	summary := &domain.MonthlySummary{
+		Year:       year,
+		Month:      month,
+		Currency:   currency,
+		ByCategory: make(map[string]domain.Money),
+	}
+
+	// Outside a group, a signed-in user's summary covers only their expenses,
+	// which the aggregate cannot tell apart
+	userID, scoped := domain.UserIDFromContext(ctx)
+	scoped = scoped && groupID == ""
+
+	if !scoped {
+		query := domain.TotalsQuery{GroupID: groupID, StartDate: startDateStr, EndDate: endDateStr, ByCategory: true}
+		totals, ok, err := e.aggregatedTotals(ctx, query, currency)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			for _, total := range totals {
+				if total.ExpensesPaid == 0 {
+					continue
+				}
+				summary.TotalExpenses = summary.TotalExpenses.Add(total.Paid)
+				summary.ExpenseCount += total.ExpensesPaid
+				summary.ByCategory[total.Category] = summary.ByCategory[total.Category].Add(total.Paid)
+			}
+			finishMonthlySummary(summary, endDate.Day())
+			return summary, nil
+		}
+	}
+
 	// Get expenses for the month
-	expenses, err := e.expenseRepo.GetByDateRange(ctx, startDateStr, endDateStr)
+	var expenses []*domain.Expense
+	var err error
+	switch {
+	case groupID != "":
+		expenses, err = e.expenseRepo.GetByFilters(ctx, domain.ExpenseFilter{GroupID: groupID, StartDate: startDateStr, EndDate: endDateStr})
+	case scoped:
+		expenses, err = e.expenseRepo.GetByFilters(ctx, domain.ExpenseFilter{UserID: userID, StartDate: startDateStr, EndDate: endDateStr})
+	default:
+		expenses, err = e.expenseRepo.GetByDateRange(ctx, startDateStr, endDateStr)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	summary := &domain.MonthlySummary{
-		Year:       year,
-		Month:      month,
-		ByCategory: make(map[string]float64),
+	expenses, err = e.converter.convertExpenses(ctx, expenses, currency)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate summary
-	var topCategoryAmount float64
 	for _, expense := range expenses {
-		summary.TotalExpenses += expense.Amount
+		summary.TotalExpenses = summary.TotalExpenses.Add(expense.Amount)
 		summary.ExpenseCount++
-		summary.ByCategory[expense.Category] += expense.Amount
+		summary.ByCategory[expense.Category] = summary.ByCategory[expense.Category].Add(expense.Amount)
+	}
+
+	finishMonthlySummary(summary, endDate.Day())
+	return summary, nil
+}
 
-		// Track top category
-		if summary.ByCategory[expense.Category] > topCategoryAmount {
-			topCategoryAmount = summary.ByCategory[expense.Category]
-			summary.TopCategory = expense.Category
+// finishMonthlySummary picks the top category, the first by name among
+// equals, and works out the average per day
+func finishMonthlySummary(summary *domain.MonthlySummary, daysInMonth int) {
+	var topCategoryAmount domain.Money
+	for category, amount := range summary.ByCategory {
+		c := amount.Cmp(topCategoryAmount)
+		if c > 0 || c == 0 && summary.TopCategory != "" && category < summary.TopCategory {
+			topCategoryAmount = amount
+			summary.TopCategory = category
 		}
 	}
 
-	// Calculate average per day
-	daysInMonth := endDate.Day()
 	if summary.ExpenseCount > 0 {
-		summary.AveragePerDay = summary.TotalExpenses / float64(daysInMonth)
+		summary.AveragePerDay = summary.TotalExpenses.DivRound(int64(daysInMonth))
 	}
-
-	return summary, nil
 }
@@ -3,25 +3,35 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
 )
 
 type mockExpenseRepository struct {
 	expenses map[string]*domain.Expense
+	trash    map[string]*domain.Expense
 }
 
-func (m *mockExpenseRepository) Create(ctx context.Context, expense *domain.Expense) error {
+func (m *mockExpenseRepository) Create(ctx context.Context, expense *domain.Expense, event *domain.ExpenseEvent) error {
 	m.expenses[expense.ID] = expense
 	return nil
 }
 
+func (m *mockExpenseRepository) CreateBatch(ctx context.Context, expenses []*domain.Expense, events []*domain.ExpenseEvent) error {
+	for _, expense := range expenses {
+		m.expenses[expense.ID] = expense
+	}
+	return nil
+}
+
 func (m *mockExpenseRepository) GetByID(ctx context.Context, id string) (*domain.Expense, error) {
 	expense, ok := m.expenses[id]
 	if !ok {
-		return nil, errors.New("expense not found")
+		return nil, domain.ErrExpenseNotFound
 	}
 	return expense, nil
 }
@@ -51,9 +61,19 @@ func (m *mockExpenseRepository) GetByUserID(ctx context.Context, userID string)
 	return expenses, nil
 }
 
-func (m *mockExpenseRepository) Update(ctx context.Context, expense *domain.Expense) error {
+func (m *mockExpenseRepository) GetByGroupID(ctx context.Context, groupID string) ([]*domain.Expense, error) {
+	var expenses []*domain.Expense
+	for _, expense := range m.expenses {
+		if expense.GroupID == groupID {
+			expenses = append(expenses, expense)
+		}
+	}
+	return expenses, nil
+}
+
+func (m *mockExpenseRepository) Update(ctx context.Context, expense *domain.Expense, event *domain.ExpenseEvent) error {
 	if _, ok := m.expenses[expense.ID]; !ok {
-		return errors.New("expense not found")
+		return domain.ErrExpenseNotFound
 	}
 	m.expenses[expense.ID] = expense
 	return nil
@@ -80,21 +100,26 @@ func (m *mockExpenseRepository) GetByCategory(ctx context.Context, category stri
 	return expenses, nil
 }
 
-func (m *mockExpenseRepository) GetByFilters(ctx context.Context, category, startDate, endDate string) ([]*domain.Expense, error) {
+// GetByFilters only honours the group, category and date filters
+func (m *mockExpenseRepository) GetByFilters(ctx context.Context, filter domain.ExpenseFilter) ([]*domain.Expense, error) {
 	var expenses []*domain.Expense
 	for _, expense := range m.expenses {
 		match := true
 
-		if category != "" && expense.Category != category {
+		if filter.GroupID != "" && expense.GroupID != filter.GroupID {
 			match = false
 		}
 
-		if startDate != "" || endDate != "" {
+		if len(filter.Categories) > 0 && !slices.Contains(filter.Categories, expense.Category) {
+			match = false
+		}
+
+		if filter.StartDate != "" || filter.EndDate != "" {
 			dateStr := expense.Date.Format("2006-01-02")
-			if startDate != "" && dateStr < startDate {
+			if filter.StartDate != "" && dateStr < filter.StartDate {
 				match = false
 			}
-			if endDate != "" && dateStr > endDate {
+			if filter.EndDate != "" && dateStr > filter.EndDate {
 				match = false
 			}
 		}
@@ -106,21 +131,78 @@ func (m *mockExpenseRepository) GetByFilters(ctx context.Context, category, star
 	return expenses, nil
 }
 
-func (m *mockExpenseRepository) Delete(ctx context.Context, id string) error {
+// List ignores the filter and page, returning every expense on one page
+func (m *mockExpenseRepository) List(ctx context.Context, filter domain.ExpenseFilter, page domain.PageRequest) (*domain.ExpensePage, error) {
+	expenses, _ := m.GetAll(ctx)
+	return &domain.ExpensePage{Expenses: expenses}, nil
+}
+
+func (m *mockExpenseRepository) Delete(ctx context.Context, id string, event *domain.ExpenseEvent) error {
+	expense, ok := m.expenses[id]
+	if !ok {
+		return domain.ErrExpenseNotFound
+	}
+	deletedAt := time.Now()
+	expense.DeletedAt = &deletedAt
+	m.trash[id] = expense
 	delete(m.expenses, id)
 	return nil
 }
 
+func (m *mockExpenseRepository) GetDeleted(ctx context.Context) ([]*domain.Expense, error) {
+	var expenses []*domain.Expense
+	for _, expense := range m.trash {
+		expenses = append(expenses, expense)
+	}
+	return expenses, nil
+}
+
+func (m *mockExpenseRepository) GetDeletedByID(ctx context.Context, id string) (*domain.Expense, error) {
+	expense, ok := m.trash[id]
+	if !ok {
+		return nil, domain.ErrExpenseNotFound
+	}
+	return expense, nil
+}
+
+func (m *mockExpenseRepository) Restore(ctx context.Context, id string, event *domain.ExpenseEvent) error {
+	expense, ok := m.trash[id]
+	if !ok {
+		return domain.ErrExpenseNotFound
+	}
+	expense.DeletedAt = nil
+	m.expenses[id] = expense
+	delete(m.trash, id)
+	return nil
+}
+
+func (m *mockExpenseRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+	for id, expense := range m.trash {
+		if expense.DeletedAt.Before(before) {
+			delete(m.trash, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// inr returns a whole number of rupees as domain.Money
+func inr(rupees int64) domain.Money {
+	return domain.NewMoney(rupees*100, domain.DefaultCurrency)
+}
+
 func newMockExpenseRepository() *mockExpenseRepository {
 	return &mockExpenseRepository{
 		expenses: make(map[string]*domain.Expense),
+		trash:    make(map[string]*domain.Expense),
 	}
 }
 
 func TestExpenseUseCase_UpdateExpense(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test users
@@ -131,20 +213,20 @@ func TestExpenseUseCase_UpdateExpense(t *testing.T) {
 
 	// Create an expense
 	splits := []domain.Split{
-		{UserID: user1.ID, Amount: 50.0},
-		{UserID: user2.ID, Amount: 50.0},
+		{UserID: user1.ID, Amount: inr(50)},
+		{UserID: user2.ID, Amount: inr(50)},
 	}
-	expense, err := expenseUC.CreateExpense(ctx, "Dinner", "food", user1.ID, 100.0, splits)
+	expense, err := expenseUC.CreateExpense(ctx, "", "Dinner", "food", user1.ID, inr(100), "", splits)
 	if err != nil {
 		t.Fatalf("Failed to create expense: %v", err)
 	}
 
 	// Update the expense
 	newSplits := []domain.Split{
-		{UserID: user1.ID, Amount: 60.0},
-		{UserID: user2.ID, Amount: 40.0},
+		{UserID: user1.ID, Amount: inr(60)},
+		{UserID: user2.ID, Amount: inr(40)},
 	}
-	updatedExpense, err := expenseUC.UpdateExpense(ctx, expense.ID, "Updated Dinner", "restaurant", 100.0, newSplits)
+	updatedExpense, err := expenseUC.UpdateExpense(ctx, expense.ID, "Updated Dinner", "Dining", inr(100), "", newSplits)
 	if err != nil {
 		t.Fatalf("Failed to update expense: %v", err)
 	}
@@ -153,13 +235,13 @@ func TestExpenseUseCase_UpdateExpense(t *testing.T) {
 	if updatedExpense.Description != "Updated Dinner" {
 		t.Errorf("Expected description 'Updated Dinner', got: %v", updatedExpense.Description)
 	}
-	if updatedExpense.Category != "restaurant" {
-		t.Errorf("Expected category 'restaurant', got: %v", updatedExpense.Category)
+	if updatedExpense.Category != "dining" {
+		t.Errorf("Expected category 'dining', got: %v", updatedExpense.Category)
 	}
 	if len(updatedExpense.Splits) != 2 {
 		t.Errorf("Expected 2 splits, got: %v", len(updatedExpense.Splits))
 	}
-	if updatedExpense.Splits[0].Amount != 60.0 {
+	if updatedExpense.Splits[0].Amount != inr(60) {
 		t.Errorf("Expected split amount 60.0, got: %v", updatedExpense.Splits[0].Amount)
 	}
 }
@@ -167,13 +249,13 @@ func TestExpenseUseCase_UpdateExpense(t *testing.T) {
 func TestExpenseUseCase_UpdateExpense_NotFound(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	splits := []domain.Split{
-		{UserID: "user1", Amount: 50.0},
+		{UserID: "user1", Amount: inr(50)},
 	}
-	_, err := expenseUC.UpdateExpense(ctx, "nonexistent", "Test", "test", 50.0, splits)
+	_, err := expenseUC.UpdateExpense(ctx, "nonexistent", "Test", "test", inr(50), "", splits)
 	if err == nil {
 		t.Fatal("Expected error when updating non-existent expense, got nil")
 	}
@@ -182,7 +264,7 @@ func TestExpenseUseCase_UpdateExpense_NotFound(t *testing.T) {
 func TestExpenseUseCase_UpdateExpense_ValidationError(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test user
@@ -191,18 +273,18 @@ func TestExpenseUseCase_UpdateExpense_ValidationError(t *testing.T) {
 
 	// Create an expense
 	splits := []domain.Split{
-		{UserID: user1.ID, Amount: 100.0},
+		{UserID: user1.ID, Amount: inr(100)},
 	}
-	expense, err := expenseUC.CreateExpense(ctx, "Test", "test", user1.ID, 100.0, splits)
+	expense, err := expenseUC.CreateExpense(ctx, "", "Test", "other", user1.ID, inr(100), "", splits)
 	if err != nil {
 		t.Fatalf("Failed to create expense: %v", err)
 	}
 
 	// Try to update with invalid splits (sum doesn't match amount)
 	invalidSplits := []domain.Split{
-		{UserID: user1.ID, Amount: 50.0},
+		{UserID: user1.ID, Amount: inr(50)},
 	}
-	_, err = expenseUC.UpdateExpense(ctx, expense.ID, "Test", "test", 100.0, invalidSplits)
+	_, err = expenseUC.UpdateExpense(ctx, expense.ID, "Test", "other", inr(100), "", invalidSplits)
 	if err == nil {
 		t.Fatal("Expected validation error for invalid splits, got nil")
 	}
@@ -211,7 +293,7 @@ func TestExpenseUseCase_UpdateExpense_ValidationError(t *testing.T) {
 func TestExpenseUseCase_CreateExpenseWithEqualSplit(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test users
@@ -224,7 +306,7 @@ func TestExpenseUseCase_CreateExpenseWithEqualSplit(t *testing.T) {
 
 	// Create expense with equal split
 	userIDs := []string{user1.ID, user2.ID, user3.ID}
-	expense, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "Team Dinner", "food", user1.ID, 100.0, userIDs)
+	expense, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Team Dinner", "food", user1.ID, inr(100), "", userIDs)
 	if err != nil {
 		t.Fatalf("Failed to create expense with equal split: %v", err)
 	}
@@ -233,7 +315,7 @@ func TestExpenseUseCase_CreateExpenseWithEqualSplit(t *testing.T) {
 	if expense.Description != "Team Dinner" {
 		t.Errorf("Expected description 'Team Dinner', got: %v", expense.Description)
 	}
-	if expense.Amount != 100.0 {
+	if expense.Amount != inr(100) {
 		t.Errorf("Expected amount 100.0, got: %v", expense.Amount)
 	}
 
@@ -243,13 +325,13 @@ func TestExpenseUseCase_CreateExpenseWithEqualSplit(t *testing.T) {
 	}
 
 	// Calculate total of splits
-	var total float64
+	var total domain.Money
 	for _, split := range expense.Splits {
-		total += split.Amount
+		total = total.Add(split.Amount)
 	}
 
 	// Verify total matches expense amount
-	if total != 100.0 {
+	if total != inr(100) {
 		t.Errorf("Expected total splits to be 100.0, got: %v", total)
 	}
 }
@@ -257,11 +339,11 @@ func TestExpenseUseCase_CreateExpenseWithEqualSplit(t *testing.T) {
 func TestExpenseUseCase_CreateExpenseWithEqualSplit_NoUsers(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Try to create expense with no users
-	_, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "Test", "test", "user1", 100.0, []string{})
+	_, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Test", "test", "user1", inr(100), "", []string{})
 	if err == nil {
 		t.Fatal("Expected error when creating equal split with no users, got nil")
 	}
@@ -270,7 +352,7 @@ func TestExpenseUseCase_CreateExpenseWithEqualSplit_NoUsers(t *testing.T) {
 func TestExpenseUseCase_CreateExpenseWithEqualSplit_UnevenAmount(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test users
@@ -283,26 +365,34 @@ func TestExpenseUseCase_CreateExpenseWithEqualSplit_UnevenAmount(t *testing.T) {
 
 	// Create expense with amount that doesn't divide evenly (100 / 3 = 33.33...)
 	userIDs := []string{user1.ID, user2.ID, user3.ID}
-	expense, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "Uneven Split", "test", user1.ID, 100.0, userIDs)
+	expense, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Uneven Split", "other", user1.ID, inr(100), "", userIDs)
 	if err != nil {
 		t.Fatalf("Failed to create expense with uneven split: %v", err)
 	}
 
 	// Verify total still equals expense amount (rounding handled correctly)
-	var total float64
+	var total domain.Money
 	for _, split := range expense.Splits {
-		total += split.Amount
+		total = total.Add(split.Amount)
 	}
 
-	if total != 100.0 {
+	if total != inr(100) {
 		t.Errorf("Expected total splits to be 100.0 even with rounding, got: %v", total)
 	}
+
+	// Leftover paise go to the first users: 33.34 + 33.33 + 33.33
+	expected := []int64{3334, 3333, 3333}
+	for i, split := range expense.Splits {
+		if split.Amount.Minor != expected[i] {
+			t.Errorf("Expected split %d to be %d paise, got: %v", i, expected[i], split.Amount)
+		}
+	}
 }
 
 func TestExpenseUseCase_GetExpensesByCategory(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test user
@@ -310,9 +400,9 @@ func TestExpenseUseCase_GetExpensesByCategory(t *testing.T) {
 	_ = userRepo.Create(ctx, user1)
 
 	// Create expenses with different categories
-	_, _ = expenseUC.CreateExpense(ctx, "Groceries", "food", user1.ID, 100.0, []domain.Split{{UserID: user1.ID, Amount: 100.0}})
-	_, _ = expenseUC.CreateExpense(ctx, "Restaurant", "food", user1.ID, 50.0, []domain.Split{{UserID: user1.ID, Amount: 50.0}})
-	_, _ = expenseUC.CreateExpense(ctx, "Movie", "entertainment", user1.ID, 20.0, []domain.Split{{UserID: user1.ID, Amount: 20.0}})
+	_, _ = expenseUC.CreateExpense(ctx, "", "Groceries", "food", user1.ID, inr(100), "", []domain.Split{{UserID: user1.ID, Amount: inr(100)}})
+	_, _ = expenseUC.CreateExpense(ctx, "", "Restaurant", "food", user1.ID, inr(50), "", []domain.Split{{UserID: user1.ID, Amount: inr(50)}})
+	_, _ = expenseUC.CreateExpense(ctx, "", "Movie", "entertainment", user1.ID, inr(20), "", []domain.Split{{UserID: user1.ID, Amount: inr(20)}})
 
 	// Get expenses by category
 	expenses, err := expenseUC.GetExpensesByCategory(ctx, "food")
@@ -329,7 +419,7 @@ func TestExpenseUseCase_GetExpensesByCategory(t *testing.T) {
 func TestExpenseUseCase_GetExpensesByCategory_EmptyCategory(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Try to get expenses with empty category
@@ -342,7 +432,7 @@ func TestExpenseUseCase_GetExpensesByCategory_EmptyCategory(t *testing.T) {
 func TestExpenseUseCase_GetExpensesByFilters(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test user
@@ -350,11 +440,11 @@ func TestExpenseUseCase_GetExpensesByFilters(t *testing.T) {
 	_ = userRepo.Create(ctx, user1)
 
 	// Create test expenses
-	_, _ = expenseUC.CreateExpense(ctx, "Groceries", "food", user1.ID, 100.0, []domain.Split{{UserID: user1.ID, Amount: 100.0}})
-	_, _ = expenseUC.CreateExpense(ctx, "Movie", "entertainment", user1.ID, 20.0, []domain.Split{{UserID: user1.ID, Amount: 20.0}})
+	_, _ = expenseUC.CreateExpense(ctx, "", "Groceries", "food", user1.ID, inr(100), "", []domain.Split{{UserID: user1.ID, Amount: inr(100)}})
+	_, _ = expenseUC.CreateExpense(ctx, "", "Movie", "entertainment", user1.ID, inr(20), "", []domain.Split{{UserID: user1.ID, Amount: inr(20)}})
 
 	// Test with category filter only
-	expenses, err := expenseUC.GetExpensesByFilters(ctx, "food", "", "")
+	expenses, err := expenseUC.GetExpensesByFilters(ctx, "", "food", "", "")
 	if err != nil {
 		t.Fatalf("Failed to get expenses by filters: %v", err)
 	}
@@ -367,7 +457,7 @@ func TestExpenseUseCase_GetExpensesByFilters(t *testing.T) {
 func TestExpenseUseCase_GetExpensesByFilters_NoFilters(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test user
@@ -375,11 +465,11 @@ func TestExpenseUseCase_GetExpensesByFilters_NoFilters(t *testing.T) {
 	_ = userRepo.Create(ctx, user1)
 
 	// Create test expenses
-	_, _ = expenseUC.CreateExpense(ctx, "Expense 1", "food", user1.ID, 100.0, []domain.Split{{UserID: user1.ID, Amount: 100.0}})
-	_, _ = expenseUC.CreateExpense(ctx, "Expense 2", "entertainment", user1.ID, 50.0, []domain.Split{{UserID: user1.ID, Amount: 50.0}})
+	_, _ = expenseUC.CreateExpense(ctx, "", "Expense 1", "food", user1.ID, inr(100), "", []domain.Split{{UserID: user1.ID, Amount: inr(100)}})
+	_, _ = expenseUC.CreateExpense(ctx, "", "Expense 2", "entertainment", user1.ID, inr(50), "", []domain.Split{{UserID: user1.ID, Amount: inr(50)}})
 
 	// Get all expenses (no filters)
-	expenses, err := expenseUC.GetExpensesByFilters(ctx, "", "", "")
+	expenses, err := expenseUC.GetExpensesByFilters(ctx, "", "", "", "")
 	if err != nil {
 		t.Fatalf("Failed to get expenses: %v", err)
 	}
@@ -393,7 +483,7 @@ func TestExpenseUseCase_GetExpensesByFilters_NoFilters(t *testing.T) {
 func TestExpenseUseCase_GetUserStats(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test users
@@ -404,13 +494,13 @@ func TestExpenseUseCase_GetUserStats(t *testing.T) {
 
 	// Create expenses
 	// User1 paid 150 total (100 food + 50 entertainment)
-	_, _ = expenseUC.CreateExpense(ctx, "Groceries", "food", user1.ID, 100.0, []domain.Split{
-		{UserID: user1.ID, Amount: 50.0},
-		{UserID: user2.ID, Amount: 50.0},
+	_, _ = expenseUC.CreateExpense(ctx, "", "Groceries", "food", user1.ID, inr(100), "", []domain.Split{
+		{UserID: user1.ID, Amount: inr(50)},
+		{UserID: user2.ID, Amount: inr(50)},
 	})
-	_, _ = expenseUC.CreateExpense(ctx, "Movie", "entertainment", user1.ID, 50.0, []domain.Split{
-		{UserID: user1.ID, Amount: 25.0},
-		{UserID: user2.ID, Amount: 25.0},
+	_, _ = expenseUC.CreateExpense(ctx, "", "Movie", "entertainment", user1.ID, inr(50), "", []domain.Split{
+		{UserID: user1.ID, Amount: inr(25)},
+		{UserID: user2.ID, Amount: inr(25)},
 	})
 
 	// Get stats for user1
@@ -423,13 +513,13 @@ func TestExpenseUseCase_GetUserStats(t *testing.T) {
 	if stats.UserID != user1.ID {
 		t.Errorf("Expected user ID %s, got: %s", user1.ID, stats.UserID)
 	}
-	if stats.TotalPaid != 150.0 {
+	if stats.TotalPaid != inr(150) {
 		t.Errorf("Expected total paid 150.0, got: %v", stats.TotalPaid)
 	}
-	if stats.TotalOwed != 75.0 {
+	if stats.TotalOwed != inr(75) {
 		t.Errorf("Expected total owed 75.0, got: %v", stats.TotalOwed)
 	}
-	if stats.NetBalance != 75.0 {
+	if stats.NetBalance != inr(75) {
 		t.Errorf("Expected net balance 75.0, got: %v", stats.NetBalance)
 	}
 	if stats.ExpenseCount != 2 {
@@ -437,10 +527,10 @@ func TestExpenseUseCase_GetUserStats(t *testing.T) {
 	}
 
 	// Check category breakdown
-	if stats.ByCategory["food"] != 100.0 {
+	if stats.ByCategory["food"] != inr(100) {
 		t.Errorf("Expected food category 100.0, got: %v", stats.ByCategory["food"])
 	}
-	if stats.ByCategory["entertainment"] != 50.0 {
+	if stats.ByCategory["entertainment"] != inr(50) {
 		t.Errorf("Expected entertainment category 50.0, got: %v", stats.ByCategory["entertainment"])
 	}
 }
@@ -448,7 +538,7 @@ func TestExpenseUseCase_GetUserStats(t *testing.T) {
 func TestExpenseUseCase_GetUserStats_UserNotFound(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Try to get stats for non-existent user
@@ -461,7 +551,7 @@ func TestExpenseUseCase_GetUserStats_UserNotFound(t *testing.T) {
 func TestExpenseUseCase_GetMonthlySummary(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Create test user
@@ -469,9 +559,9 @@ func TestExpenseUseCase_GetMonthlySummary(t *testing.T) {
 	_ = userRepo.Create(ctx, user1)
 
 	// Create expenses with specific dates
-	expense1, _ := expenseUC.CreateExpense(ctx, "Groceries", "food", user1.ID, 100.0, []domain.Split{{UserID: user1.ID, Amount: 100.0}})
-	expense2, _ := expenseUC.CreateExpense(ctx, "Restaurant", "food", user1.ID, 50.0, []domain.Split{{UserID: user1.ID, Amount: 50.0}})
-	expense3, _ := expenseUC.CreateExpense(ctx, "Movie", "entertainment", user1.ID, 30.0, []domain.Split{{UserID: user1.ID, Amount: 30.0}})
+	expense1, _ := expenseUC.CreateExpense(ctx, "", "Groceries", "food", user1.ID, inr(100), "", []domain.Split{{UserID: user1.ID, Amount: inr(100)}})
+	expense2, _ := expenseUC.CreateExpense(ctx, "", "Restaurant", "food", user1.ID, inr(50), "", []domain.Split{{UserID: user1.ID, Amount: inr(50)}})
+	expense3, _ := expenseUC.CreateExpense(ctx, "", "Movie", "entertainment", user1.ID, inr(30), "", []domain.Split{{UserID: user1.ID, Amount: inr(30)}})
 
 	// Set dates to November 2025
 	expense1.Date = time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC)
@@ -491,7 +581,7 @@ func TestExpenseUseCase_GetMonthlySummary(t *testing.T) {
 	if summary.Month != 11 {
 		t.Errorf("Expected month 11, got: %v", summary.Month)
 	}
-	if summary.TotalExpenses != 180.0 {
+	if summary.TotalExpenses != inr(180) {
 		t.Errorf("Expected total expenses 180.0, got: %v", summary.TotalExpenses)
 	}
 	if summary.ExpenseCount != 3 {
@@ -499,10 +589,10 @@ func TestExpenseUseCase_GetMonthlySummary(t *testing.T) {
 	}
 
 	// Check category breakdown
-	if summary.ByCategory["food"] != 150.0 {
+	if summary.ByCategory["food"] != inr(150) {
 		t.Errorf("Expected food category 150.0, got: %v", summary.ByCategory["food"])
 	}
-	if summary.ByCategory["entertainment"] != 30.0 {
+	if summary.ByCategory["entertainment"] != inr(30) {
 		t.Errorf("Expected entertainment category 30.0, got: %v", summary.ByCategory["entertainment"])
 	}
 
@@ -512,7 +602,7 @@ func TestExpenseUseCase_GetMonthlySummary(t *testing.T) {
 	}
 
 	// Check average per day (180 / 30 days in November)
-	if summary.AveragePerDay != 6.0 {
+	if summary.AveragePerDay != inr(6) {
 		t.Errorf("Expected average per day 6.0, got: %v", summary.AveragePerDay)
 	}
 }
@@ -520,7 +610,7 @@ func TestExpenseUseCase_GetMonthlySummary(t *testing.T) {
 func TestExpenseUseCase_GetMonthlySummary_InvalidMonth(t *testing.T) {
 	expenseRepo := newMockExpenseRepository()
 	userRepo := newMockUserRepository()
-	expenseUC := NewExpenseUseCase(expenseRepo, userRepo)
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
 	ctx := context.Background()
 
 	// Try with invalid month
@@ -535,3 +625,180 @@ func TestExpenseUseCase_GetMonthlySummary_InvalidMonth(t *testing.T) {
 		t.Fatal("Expected error for month 0, got nil")
 	}
 }
+
+func TestExpenseUseCase_DeleteAndRestore(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "1", Name: "John", Email: "john@test.com"})
+	_ = userRepo.Create(ctx, &domain.User{ID: "2", Name: "Jane", Email: "jane@test.com"})
+	expense, _ := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Dinner", "food", "1", inr(100), "", []string{"1", "2"})
+
+	if err := expenseUC.DeleteExpense(ctx, "missing"); !errors.Is(err, domain.ErrExpenseNotFound) {
+		t.Errorf("Expected not found for unknown expense, got: %v", err)
+	}
+	if err := expenseUC.DeleteExpense(ctx, expense.ID); err != nil {
+		t.Fatalf("Failed to delete expense: %v", err)
+	}
+
+	balances, err := expenseUC.CalculateBalances(ctx, domain.SettlementMinimal)
+	if err != nil {
+		t.Fatalf("Failed to calculate balances: %v", err)
+	}
+	if len(balances.Settlements) != 0 {
+		t.Errorf("Expected deleted expense to be left out of balances, got: %v", balances.Settlements)
+	}
+
+	trash, _ := expenseUC.GetDeletedExpenses(ctx)
+	if len(trash) != 1 || trash[0].ID != expense.ID {
+		t.Fatalf("Expected expense in the trash, got: %v", trash)
+	}
+
+	restored, err := expenseUC.RestoreExpense(ctx, expense.ID)
+	if err != nil {
+		t.Fatalf("Failed to restore expense: %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("Expected restored expense to clear deleted_at, got: %v", restored.DeletedAt)
+	}
+	if _, err := expenseUC.GetExpense(ctx, expense.ID); err != nil {
+		t.Errorf("Expected restored expense to be readable, got: %v", err)
+	}
+	if _, err := expenseUC.RestoreExpense(ctx, expense.ID); !errors.Is(err, domain.ErrExpenseNotFound) {
+		t.Errorf("Expected not found restoring a live expense, got: %v", err)
+	}
+}
+
+func TestExpenseUseCase_PurgeDeletedExpenses(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "1", Name: "John", Email: "john@test.com"})
+	old, _ := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Old", "food", "1", inr(10), "", []string{"1"})
+	recent, _ := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Recent", "food", "1", inr(10), "", []string{"1"})
+	_ = expenseUC.DeleteExpense(ctx, old.ID)
+	_ = expenseUC.DeleteExpense(ctx, recent.ID)
+	longAgo := time.Now().AddDate(0, 0, -40)
+	expenseRepo.trash[old.ID].DeletedAt = &longAgo
+
+	purged, err := expenseUC.PurgeDeletedExpenses(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to purge: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 expense purged, got %d", purged)
+	}
+	trash, _ := expenseUC.GetDeletedExpenses(ctx)
+	if len(trash) != 1 || trash[0].ID != recent.ID {
+		t.Errorf("Expected only the recent deletion left in the trash, got: %v", trash)
+	}
+}
+
+func TestExpenseUseCase_CreateSplitExpense_Receipt(t *testing.T) {
+	userRepo := newMockUserRepository()
+	expenseUC := NewExpenseUseCase(newMockExpenseRepository(), memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "user1", Name: "User1", Email: "user1@test.com"})
+	_ = userRepo.Create(ctx, &domain.User{ID: "user2", Name: "User2", Email: "user2@test.com"})
+
+	items := []domain.ExpenseItem{
+		{Name: "Rice", Quantity: 2, Price: domain.Money{Minor: 3000}, Kind: domain.ItemProduct, UserIDs: []string{"user1", "user2"}},
+		{Name: "Paneer", Quantity: 1, Price: domain.Money{Minor: 4000}, Kind: domain.ItemProduct, UserIDs: []string{"user2"}},
+		{Name: "Tip", Quantity: 1, Price: domain.Money{Minor: 1000}, Kind: domain.ItemTip},
+	}
+	receipt := domain.SplitSpec{Mode: domain.SplitItemised, Receipt: items}
+	expense, err := expenseUC.CreateSplitExpense(ctx, "", "Dinner", "food", "user1", inr(110), "", receipt)
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	if len(expense.Items) != 3 {
+		t.Errorf("Expected 3 items, got %d", len(expense.Items))
+	}
+	if expense.Splits[0].Amount != inr(30+3) || expense.Splits[1].Amount != inr(70+7) {
+		t.Errorf("Expected 33.00 and 77.00, got %v", expense.Splits)
+	}
+
+	// Items must add up to the amount
+	if _, err := expenseUC.CreateSplitExpense(ctx, "", "Dinner", "food", "user1", inr(100), "", receipt); err == nil {
+		t.Error("Expected error when items do not add up to the amount")
+	}
+
+	// Explicit splits replace the receipt
+	updated, err := expenseUC.UpdateExpense(ctx, expense.ID, "", "", inr(110), "", []domain.Split{{UserID: "user1", Amount: inr(110)}})
+	if err != nil {
+		t.Fatalf("Failed to update expense: %v", err)
+	}
+	if len(updated.Items) != 0 {
+		t.Errorf("Expected items to be dropped, got %v", updated.Items)
+	}
+}
+
+func TestExpenseUseCase_ListExpenses_OnlyVisible(t *testing.T) {
+	userRepo := memory.NewUserMemoryRepository()
+	expenseUC := NewExpenseUseCase(memory.NewExpenseMemoryRepository(), memory.NewExpenseEventMemoryRepository(), userRepo, memory.NewGroupMemoryRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+	for _, userIDs := range [][]string{{"alice", "bob"}, {"alice", "carol"}, {"alice", "bob"}} {
+		if _, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Lunch", "food", userIDs[0], inr(10), "", userIDs); err != nil {
+			t.Fatalf("Failed to create expense: %v", err)
+		}
+	}
+
+	// Bob sees full pages of his own expenses, not pages thinned after the fact
+	page := domain.PageRequest{Limit: 2, Sort: domain.Sort{Field: domain.SortByDate, Order: domain.SortDesc}}
+	result, err := expenseUC.ListExpenses(domain.WithUserID(ctx, "bob"), domain.ExpenseFilter{}, page)
+	if err != nil {
+		t.Fatalf("ListExpenses() failed: %v", err)
+	}
+	if len(result.Expenses) != 2 || result.NextCursor != "" {
+		t.Errorf("Expected bob's two expenses on one page, got %d (next %q)", len(result.Expenses), result.NextCursor)
+	}
+
+	if _, err := expenseUC.ListExpenses(ctx, domain.ExpenseFilter{StartDate: "2024-02-01", EndDate: "2024-01-01"}, page); err == nil {
+		t.Error("Expected error for a start date after the end date")
+	}
+}
+
+func TestExpenseUseCase_SetExpenseTags(t *testing.T) {
+	userRepo := memory.NewUserMemoryRepository()
+	events := memory.NewExpenseEventMemoryRepository()
+	expenseUC := NewExpenseUseCase(memory.NewExpenseMemoryRepository().WithEvents(events), events, userRepo, memory.NewGroupMemoryRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+	expense, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Taxi", "travel", "alice", inr(30), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	tagged, err := expenseUC.SetExpenseTags(ctx, expense.ID, []string{"Trip", "goa", "trip"})
+	if err != nil {
+		t.Fatalf("SetExpenseTags() failed: %v", err)
+	}
+	if want := []string{"goa", "trip"}; !slices.Equal(tagged.Tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, tagged.Tags)
+	}
+
+	history, _ := expenseUC.GetExpenseHistory(ctx, expense.ID)
+	if last := history[len(history)-1]; last.Action != domain.ExpenseUpdated || len(last.Before.Tags) != 0 || len(last.After.Tags) != 2 {
+		t.Errorf("Expected the tag change in the history, got %+v", last)
+	}
+
+	if _, err := expenseUC.SetExpenseTags(ctx, expense.ID, []string{"not a tag"}); err == nil {
+		t.Error("Expected error for an invalid tag")
+	}
+	if _, err := expenseUC.SetExpenseTags(domain.WithUserID(ctx, "carol"), expense.ID, []string{"mine"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for a non-participant, got: %v", err)
+	}
+}
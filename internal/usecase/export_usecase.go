@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/export"
+)
+
+// ExportDataset selects what an export contains
+type ExportDataset string
+
+const (
+	ExportExpenses    ExportDataset = "expenses"
+	ExportUsers       ExportDataset = "users"
+	ExportBalances    ExportDataset = "balances"
+	ExportSettlements ExportDataset = "settlements"
+	ExportAll         ExportDataset = "all"
+)
+
+// ParseExportDataset accepts a dataset name; empty means expenses
+func ParseExportDataset(s string) (ExportDataset, error) {
+	switch dataset := ExportDataset(s); dataset {
+	case "":
+		return ExportExpenses, nil
+	case ExportExpenses, ExportUsers, ExportBalances, ExportSettlements, ExportAll:
+		return dataset, nil
+	default:
+		return "", fmt.Errorf("invalid export dataset %q: must be expenses, users, balances, settlements or all", s)
+	}
+}
+
+// ExportFilter narrows the expenses exported, as GetExpensesByFilters does.
+// GroupID also selects the group's balances.
+type ExportFilter struct {
+	GroupID   string
+	Category  string
+	StartDate string
+	EndDate   string
+}
+
+type ExportUseCase interface {
+	Export(ctx context.Context, w export.Writer, dataset ExportDataset, filter ExportFilter) error
+}
+
+type exportUseCase struct {
+	expenseUC ExpenseUseCase
+	userUC    UserUseCase
+}
+
+// NewExportUseCase creates the export use case. Data is read through the
+// other use cases, so exports only contain what the acting user may see.
+func NewExportUseCase(expenseUC ExpenseUseCase, userUC UserUseCase) ExportUseCase {
+	return &exportUseCase{
+		expenseUC: expenseUC,
+		userUC:    userUC,
+	}
+}
+
+// Export writes the tables of dataset to w, one table per dataset. It does
+// not close w.
+func (u *exportUseCase) Export(ctx context.Context, w export.Writer, dataset ExportDataset, filter ExportFilter) error {
+	datasets := []ExportDataset{dataset}
+	if dataset == ExportAll {
+		datasets = []ExportDataset{ExportExpenses, ExportUsers, ExportBalances, ExportSettlements}
+	}
+
+	// Balances and settlements come from the same summary
+	var summary *domain.BalanceSummary
+	for _, d := range datasets {
+		var table export.Table
+		var err error
+		switch d {
+		case ExportExpenses:
+			table, err = u.expenseTable(ctx, filter)
+		case ExportUsers:
+			table, err = u.userTable(ctx)
+		case ExportBalances, ExportSettlements:
+			if summary == nil {
+				if summary, err = u.balances(ctx, filter.GroupID); err != nil {
+					return err
+				}
+			}
+			table = balanceTable(summary)
+			if d == ExportSettlements {
+				table = settlementTable(summary)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := w.WriteTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expenseTable has one row per split, repeating the expense's columns, oldest
+// expense first
+func (u *exportUseCase) expenseTable(ctx context.Context, filter ExportFilter) (export.Table, error) {
+	expenses, err := u.expenseUC.GetExpensesByFilters(ctx, filter.GroupID, filter.Category, filter.StartDate, filter.EndDate)
+	if err != nil {
+		return export.Table{}, err
+	}
+	sort.Slice(expenses, func(i, j int) bool {
+		if !expenses[i].Date.Equal(expenses[j].Date) {
+			return expenses[i].Date.Before(expenses[j].Date)
+		}
+		return expenses[i].ID < expenses[j].ID
+	})
+
+	table := export.Table{
+		Name:    string(ExportExpenses),
+		Columns: []string{"expense_id", "date", "description", "category", "group_id", "paid_by", "amount", "currency", "split_user_id", "split_amount"},
+	}
+	for _, expense := range expenses {
+		for _, split := range expense.Splits {
+			table.Rows = append(table.Rows, []any{
+				expense.ID,
+				expense.Date,
+				expense.Description,
+				expense.Category,
+				expense.GroupID,
+				expense.PaidBy,
+				expense.Amount,
+				string(expense.Amount.Currency),
+				split.UserID,
+				split.Amount,
+			})
+		}
+	}
+	return table, nil
+}
+
+func (u *exportUseCase) userTable(ctx context.Context) (export.Table, error) {
+	users, err := u.userUC.GetAllUsers(ctx)
+	if err != nil {
+		return export.Table{}, err
+	}
+
+	table := export.Table{
+		Name:    string(ExportUsers),
+		Columns: []string{"id", "name", "email", "created_at"},
+	}
+	for _, user := range users {
+		table.Rows = append(table.Rows, []any{user.ID, user.Name, user.Email, user.CreatedAt})
+	}
+	return table, nil
+}
+
+func (u *exportUseCase) balances(ctx context.Context, groupID string) (*domain.BalanceSummary, error) {
+	if groupID != "" {
+		return u.expenseUC.CalculateGroupBalances(ctx, groupID, domain.SettlementMinimal)
+	}
+	return u.expenseUC.CalculateBalances(ctx, domain.SettlementMinimal)
+}
+
+func balanceTable(summary *domain.BalanceSummary) export.Table {
+	table := export.Table{
+		Name:    string(ExportBalances),
+		Columns: []string{"user_id", "balance", "currency"},
+	}
+	for _, balance := range summary.Balances {
+		table.Rows = append(table.Rows, []any{balance.UserID, balance.Amount, string(summary.Currency)})
+	}
+	return table
+}
+
+func settlementTable(summary *domain.BalanceSummary) export.Table {
+	table := export.Table{
+		Name:    string(ExportSettlements),
+		Columns: []string{"from", "to", "amount", "currency"},
+	}
+	for _, settlement := range summary.Settlements {
+		table.Rows = append(table.Rows, []any{settlement.From, settlement.To, settlement.Amount, string(summary.Currency)})
+	}
+	return table
+}
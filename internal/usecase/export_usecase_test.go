@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/export"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+func TestExportUseCase_Expenses(t *testing.T) {
+	userRepo := memory.NewUserMemoryRepository()
+	expenseUC := NewExpenseUseCase(memory.NewExpenseMemoryRepository(), memory.NewExpenseEventMemoryRepository(), userRepo, memory.NewGroupMemoryRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	exportUC := NewExportUseCase(expenseUC, NewUserUseCase(userRepo, memory.NewExpenseMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewGroupMemoryRepository(), memory.NewAuthMemoryRepository(), &capturingSender{}, time.Hour))
+
+	ctx := context.Background()
+	for _, id := range []string{"alice", "bob"} {
+		if err := userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"}); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	groceries, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Groceries", "food", "alice", inr(100), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	if _, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Taxi", "transport", "bob", inr(30), "", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, _ := export.NewWriter(export.FormatCSV, &buf)
+	if err := exportUC.Export(ctx, w, ExportExpenses, ExportFilter{Category: "food"}); err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and one row per split, got:\n%s", buf.String())
+	}
+	for i, userID := range []string{"alice", "bob"} {
+		fields := strings.Split(lines[i+1], ",")
+		if fields[0] != groceries.ID || fields[8] != userID || fields[9] != "50.00" {
+			t.Errorf("Unexpected row %d: %s", i+1, lines[i+1])
+		}
+	}
+}
+
+func TestExportUseCase_AllNeedsMultipleTables(t *testing.T) {
+	userRepo := memory.NewUserMemoryRepository()
+	expenseUC := NewExpenseUseCase(memory.NewExpenseMemoryRepository(), memory.NewExpenseEventMemoryRepository(), userRepo, memory.NewGroupMemoryRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	exportUC := NewExportUseCase(expenseUC, NewUserUseCase(userRepo, memory.NewExpenseMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewGroupMemoryRepository(), memory.NewAuthMemoryRepository(), &capturingSender{}, time.Hour))
+
+	var buf bytes.Buffer
+	w, _ := export.NewWriter(export.FormatNDJSON, &buf)
+	if err := exportUC.Export(context.Background(), w, ExportAll, ExportFilter{}); err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+
+	w, _ = export.NewWriter(export.FormatCSV, &buf)
+	if err := exportUC.Export(context.Background(), w, ExportAll, ExportFilter{}); err == nil {
+		t.Error("Expected error exporting every dataset as CSV")
+	}
+}
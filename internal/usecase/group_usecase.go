@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+type GroupUseCase interface {
+	CreateGroup(ctx context.Context, name string, baseCurrency domain.Currency, timezone string, memberIDs []string) (*domain.Group, error)
+	GetGroup(ctx context.Context, id string) (*domain.Group, error)
+	GetAllGroups(ctx context.Context) ([]*domain.Group, error)
+	GetGroupsByUser(ctx context.Context, userID string) ([]*domain.Group, error)
+	AddMember(ctx context.Context, groupID, userID string) (*domain.Group, error)
+	RemoveMember(ctx context.Context, groupID, userID string) (*domain.Group, error)
+}
+
+type groupUseCase struct {
+	groupRepo       repository.GroupRepository
+	userRepo        repository.UserRepository
+	defaultCurrency domain.Currency
+}
+
+// NewGroupUseCase creates the group use case. Groups created without a base
+// currency report in defaultCurrency.
+func NewGroupUseCase(groupRepo repository.GroupRepository, userRepo repository.UserRepository, defaultCurrency domain.Currency) GroupUseCase {
+	return &groupUseCase{
+		groupRepo:       groupRepo,
+		userRepo:        userRepo,
+		defaultCurrency: defaultCurrency,
+	}
+}
+
+func (g *groupUseCase) CreateGroup(ctx context.Context, name string, baseCurrency domain.Currency, timezone string, memberIDs []string) (*domain.Group, error) {
+	if baseCurrency == "" {
+		baseCurrency = g.defaultCurrency
+	}
+	baseCurrency, err := domain.ParseCurrency(string(baseCurrency))
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate all members exist, dropping duplicates
+	seen := make(map[string]bool)
+	var members []string
+	for _, userID := range memberIDs {
+		if seen[userID] {
+			continue
+		}
+		if _, err := g.userRepo.GetByID(ctx, userID); err != nil {
+			return nil, err
+		}
+		seen[userID] = true
+		members = append(members, userID)
+	}
+
+	group := &domain.Group{
+		ID:           uuid.New().String(),
+		Name:         name,
+		BaseCurrency: baseCurrency,
+		Timezone:     timezone,
+		Members:      members,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := g.groupRepo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (g *groupUseCase) GetGroup(ctx context.Context, id string) (*domain.Group, error) {
+	group, err := g.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGroupRead(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetAllGroups returns every group, or just the acting user's groups
+func (g *groupUseCase) GetAllGroups(ctx context.Context) ([]*domain.Group, error) {
+	if userID, ok := domain.UserIDFromContext(ctx); ok {
+		return g.groupRepo.GetByUserID(ctx, userID)
+	}
+	return g.groupRepo.GetAll(ctx)
+}
+
+// GetGroupsByUser returns the user's groups, leaving out any the acting user
+// is not also a member of
+func (g *groupUseCase) GetGroupsByUser(ctx context.Context, userID string) ([]*domain.Group, error) {
+	if _, err := g.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	groups, err := g.groupRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return visibleGroups(ctx, groups), nil
+}
+
+func (g *groupUseCase) AddMember(ctx context.Context, groupID, userID string) (*domain.Group, error) {
+	group, err := g.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGroupChange(ctx, group); err != nil {
+		return nil, err
+	}
+	user, err := g.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, domain.ErrUserDeactivated
+	}
+
+	if err := g.groupRepo.AddMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return g.groupRepo.GetByID(ctx, groupID)
+}
+
+func (g *groupUseCase) RemoveMember(ctx context.Context, groupID, userID string) (*domain.Group, error) {
+	group, err := g.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGroupChange(ctx, group); err != nil {
+		return nil, err
+	}
+	if !group.HasMember(userID) {
+		return nil, domain.ErrNotGroupMember
+	}
+
+	if err := g.groupRepo.RemoveMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return g.groupRepo.GetByID(ctx, groupID)
+}
@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+type mockGroupRepository struct {
+	groups map[string]*domain.Group
+}
+
+func (m *mockGroupRepository) Create(ctx context.Context, group *domain.Group) error {
+	m.groups[group.ID] = group
+	return nil
+}
+
+func (m *mockGroupRepository) GetByID(ctx context.Context, id string) (*domain.Group, error) {
+	group, ok := m.groups[id]
+	if !ok {
+		return nil, errors.New("group not found")
+	}
+	return group, nil
+}
+
+func (m *mockGroupRepository) GetAll(ctx context.Context) ([]*domain.Group, error) {
+	var groups []*domain.Group
+	for _, group := range m.groups {
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (m *mockGroupRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Group, error) {
+	var groups []*domain.Group
+	for _, group := range m.groups {
+		if group.HasMember(userID) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (m *mockGroupRepository) Update(ctx context.Context, group *domain.Group) error {
+	if _, ok := m.groups[group.ID]; !ok {
+		return errors.New("group not found")
+	}
+	m.groups[group.ID] = group
+	return nil
+}
+
+func (m *mockGroupRepository) AddMember(ctx context.Context, groupID, userID string) error {
+	group, ok := m.groups[groupID]
+	if !ok {
+		return errors.New("group not found")
+	}
+	if !group.HasMember(userID) {
+		group.Members = append(group.Members, userID)
+	}
+	return nil
+}
+
+func (m *mockGroupRepository) RemoveMember(ctx context.Context, groupID, userID string) error {
+	group, ok := m.groups[groupID]
+	if !ok {
+		return errors.New("group not found")
+	}
+	var members []string
+	for _, member := range group.Members {
+		if member != userID {
+			members = append(members, member)
+		}
+	}
+	group.Members = members
+	return nil
+}
+
+func newMockGroupRepository() *mockGroupRepository {
+	return &mockGroupRepository{
+		groups: make(map[string]*domain.Group),
+	}
+}
+
+func TestGroupUseCase_CreateGroup(t *testing.T) {
+	groupRepo := newMockGroupRepository()
+	userRepo := newMockUserRepository()
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "user1", Name: "User1", Email: "user1@test.com"})
+	_ = userRepo.Create(ctx, &domain.User{ID: "user2", Name: "User2", Email: "user2@test.com"})
+
+	group, err := groupUC.CreateGroup(ctx, "Flat 4B", "", "", []string{"user1", "user2", "user1"})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	if group.Name != "Flat 4B" {
+		t.Errorf("Expected name 'Flat 4B', got: %v", group.Name)
+	}
+	if len(group.Members) != 2 {
+		t.Errorf("Expected duplicate members to be dropped, got: %v", group.Members)
+	}
+}
+
+func TestGroupUseCase_CreateGroup_UnknownMember(t *testing.T) {
+	groupRepo := newMockGroupRepository()
+	userRepo := newMockUserRepository()
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_, err := groupUC.CreateGroup(ctx, "Flat 4B", "", "", []string{"ghost"})
+	if err == nil {
+		t.Fatal("Expected error when creating group with unknown member, got nil")
+	}
+}
+
+func TestGroupUseCase_RemoveMember_NotMember(t *testing.T) {
+	groupRepo := newMockGroupRepository()
+	userRepo := newMockUserRepository()
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "user1", Name: "User1", Email: "user1@test.com"})
+	group, _ := groupUC.CreateGroup(ctx, "Flat 4B", "", "", []string{"user1"})
+
+	_, err := groupUC.RemoveMember(ctx, group.ID, "user2")
+	if !errors.Is(err, domain.ErrNotGroupMember) {
+		t.Fatalf("Expected ErrNotGroupMember, got: %v", err)
+	}
+}
+
+func TestExpenseUseCase_GroupScopedBalances(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	groupRepo := newMockGroupRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+	flat, _ := groupUC.CreateGroup(ctx, "Flat", "", "", []string{"alice", "bob"})
+	trip, _ := groupUC.CreateGroup(ctx, "Ski Trip", "", "", []string{"alice", "carol"})
+
+	_, err := expenseUC.CreateExpenseWithEqualSplit(ctx, flat.ID, "Rent", "rent", "alice", inr(1000), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create flat expense: %v", err)
+	}
+	_, err = expenseUC.CreateExpenseWithEqualSplit(ctx, trip.ID, "Lift passes", "travel", "carol", inr(200), "", []string{"alice", "carol"})
+	if err != nil {
+		t.Fatalf("Failed to create trip expense: %v", err)
+	}
+
+	summary, err := expenseUC.CalculateGroupBalances(ctx, flat.ID, domain.SettlementMinimal)
+	if err != nil {
+		t.Fatalf("Failed to calculate group balances: %v", err)
+	}
+
+	balances := make(map[string]domain.Money)
+	for _, balance := range summary.Balances {
+		balances[balance.UserID] = balance.Amount
+	}
+	if _, ok := balances["carol"]; ok {
+		t.Errorf("Expected trip member carol to be absent from flat balances, got: %v", balances)
+	}
+	if balances["alice"] != inr(500) || balances["bob"] != inr(-500) {
+		t.Errorf("Expected alice +500 and bob -500, got: %v", balances)
+	}
+}
+
+func TestExpenseUseCase_CreateExpense_NonMember(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	groupRepo := newMockGroupRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "alice", Name: "Alice", Email: "alice@test.com"})
+	_ = userRepo.Create(ctx, &domain.User{ID: "mallory", Name: "Mallory", Email: "mallory@test.com"})
+	flat, _ := groupUC.CreateGroup(ctx, "Flat", "", "", []string{"alice"})
+
+	_, err := expenseUC.CreateExpense(ctx, flat.ID, "Snacks", "food", "alice", inr(10), "", []domain.Split{
+		{UserID: "alice", Amount: inr(5)},
+		{UserID: "mallory", Amount: inr(5)},
+	})
+	if !errors.Is(err, domain.ErrNotGroupMember) {
+		t.Fatalf("Expected ErrNotGroupMember, got: %v", err)
+	}
+}
+
+func TestExpenseUseCase_GroupBalances_ConvertsToBaseCurrency(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	groupRepo := newMockGroupRepository()
+	rates := memory.NewExchangeRateMemoryRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), rates, domain.DefaultCurrency)
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+	trip, err := groupUC.CreateGroup(ctx, "Paris", "INR", "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	_ = rates.Save(ctx, &domain.ExchangeRate{From: "EUR", To: "INR", Rate: "90", EffectiveDate: yesterday})
+
+	expense, err := expenseUC.CreateExpenseWithEqualSplit(ctx, trip.ID, "Museum", "travel", "alice", domain.NewMoney(2001, "EUR"), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	if expense.Amount.Currency != "EUR" {
+		t.Errorf("Expected expense to keep its EUR currency, got: %v", expense.Amount.Currency)
+	}
+
+	summary, err := expenseUC.CalculateGroupBalances(ctx, trip.ID, domain.SettlementMinimal)
+	if err != nil {
+		t.Fatalf("Failed to calculate group balances: %v", err)
+	}
+	if summary.Currency != "INR" {
+		t.Errorf("Expected balances in INR, got: %v", summary.Currency)
+	}
+
+	balances := make(map[string]domain.Money)
+	for _, balance := range summary.Balances {
+		balances[balance.UserID] = balance.Amount
+	}
+	// bob owes EUR 10.00 of the EUR 20.01 museum bill; alice covered his share
+	if balances["bob"] != domain.NewMoney(-90000, "INR") || balances["alice"] != domain.NewMoney(90000, "INR") {
+		t.Errorf("Expected alice +900.00 INR and bob -900.00 INR, got: %v", balances)
+	}
+}
+
+func TestExpenseUseCase_GroupBalances_MissingRate(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	groupRepo := newMockGroupRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "alice", Name: "Alice", Email: "alice@test.com"})
+	flat, _ := groupUC.CreateGroup(ctx, "Flat", "", "", []string{"alice"})
+
+	_, err := expenseUC.CreateExpenseWithEqualSplit(ctx, flat.ID, "Gift", "other", "alice", domain.NewMoney(1000, "USD"), "", []string{"alice"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	_, err = expenseUC.CalculateGroupBalances(ctx, flat.ID, domain.SettlementMinimal)
+	if !errors.Is(err, domain.ErrExchangeRateNotFound) {
+		t.Fatalf("Expected ErrExchangeRateNotFound, got: %v", err)
+	}
+}
+
+func TestExpenseUseCase_BackdatedExpenseInHouseholdTimezone(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	groupRepo := newMockGroupRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "alice", Name: "Alice", Email: "alice@test.com"})
+	if _, err := groupUC.CreateGroup(ctx, "Flat", "", "Mars/Olympus", []string{"alice"}); err == nil {
+		t.Error("Expected error for an unknown time zone")
+	}
+	flat, err := groupUC.CreateGroup(ctx, "Flat", "", "Asia/Kolkata", []string{"alice"})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	// Half past midnight on the 1st in Kolkata is still the previous month in UTC
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	now := time.Now().In(kolkata)
+	first := time.Date(now.Year(), now.Month()-1, 1, 0, 30, 0, 0, kolkata)
+	expense, err := expenseUC.CreateExpenseWithEqualSplit(ctx, flat.ID, "Rent", "rent", "alice", inr(100), first.UTC().Format(time.RFC3339), []string{"alice"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	if !expense.Date.Equal(first) {
+		t.Errorf("Expected date %v, got: %v", first, expense.Date)
+	}
+
+	summary, err := expenseUC.GetGroupMonthlySummary(ctx, flat.ID, first.Year(), int(first.Month()))
+	if err != nil {
+		t.Fatalf("Failed to get monthly summary: %v", err)
+	}
+	if summary.TotalExpenses != inr(100) {
+		t.Errorf("Expected the expense in %s, got total: %v", first.Format("2006-01"), summary.TotalExpenses)
+	}
+
+	// A date without an offset is read in the household's zone
+	today := now.Format("2006-01-02")
+	updated, err := expenseUC.UpdateExpense(ctx, expense.ID, "", "", domain.Money{}, today, nil)
+	if err != nil {
+		t.Fatalf("Failed to move expense: %v", err)
+	}
+	if got := updated.Date.In(kolkata).Format("2006-01-02 15:04"); got != today+" 00:00" {
+		t.Errorf("Expected midnight %s in Kolkata, got: %v", today, got)
+	}
+
+	for _, date := range []string{"15/03/2024", now.AddDate(-11, 0, 0).Format("2006-01-02"), now.AddDate(0, 0, 2).Format("2006-01-02")} {
+		if _, err := expenseUC.UpdateExpense(ctx, expense.ID, "", "", domain.Money{}, date, nil); err == nil {
+			t.Errorf("Expected error for date %q", date)
+		}
+	}
+
+	// Without a date the expense is dated now, on the household's clock
+	undated, err := expenseUC.CreateExpenseWithEqualSplit(ctx, flat.ID, "Milk", "groceries", "alice", inr(50), "", []string{"alice"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	if undated.Date.Location().String() != kolkata.String() {
+		t.Errorf("Expected the default date in Kolkata, got: %v", undated.Date)
+	}
+}
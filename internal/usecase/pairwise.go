@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"sort"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// buildPairwiseLedger works out what each counterparty owes userID. A split
+// owes the expense's payer, so only expenses the user paid for or took part
+// in with someone else contribute.
+func buildPairwiseLedger(userID string, currency domain.Currency, expenses []*domain.Expense, payments []*domain.Payment) *domain.PairwiseLedger {
+	sort.SliceStable(expenses, func(i, j int) bool {
+		return expenses[i].Date.Before(expenses[j].Date)
+	})
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].Date.Before(payments[j].Date)
+	})
+
+	counterparties := make(map[string]*domain.PairwiseBalance)
+	counterparty := func(id string) *domain.PairwiseBalance {
+		if counterparties[id] == nil {
+			counterparties[id] = &domain.PairwiseBalance{
+				UserID:   id,
+				Amount:   domain.NewMoney(0, currency),
+				Expenses: []domain.LedgerEntry{},
+				Payments: []domain.LedgerEntry{},
+			}
+		}
+		return counterparties[id]
+	}
+
+	for _, expense := range expenses {
+		for _, split := range expense.Splits {
+			var other string
+			var amount domain.Money
+			switch {
+			case split.UserID == expense.PaidBy:
+				continue
+			case expense.PaidBy == userID:
+				other, amount = split.UserID, split.Amount
+			case split.UserID == userID:
+				other, amount = expense.PaidBy, split.Amount.Neg()
+			default:
+				continue
+			}
+			balance := counterparty(other)
+			balance.Amount = balance.Amount.Add(amount)
+			balance.Expenses = append(balance.Expenses, domain.LedgerEntry{ID: expense.ID, Amount: amount})
+		}
+	}
+
+	// Paying someone back raises the payer's position against them
+	for _, payment := range payments {
+		var other string
+		var amount domain.Money
+		switch userID {
+		case payment.From:
+			other, amount = payment.To, payment.Amount
+		case payment.To:
+			other, amount = payment.From, payment.Amount.Neg()
+		default:
+			continue
+		}
+		balance := counterparty(other)
+		balance.Amount = balance.Amount.Add(amount)
+		balance.Payments = append(balance.Payments, domain.LedgerEntry{ID: payment.ID, Amount: amount})
+	}
+
+	ledger := &domain.PairwiseLedger{
+		UserID:         userID,
+		Currency:       currency,
+		Counterparties: make([]domain.PairwiseBalance, 0, len(counterparties)),
+	}
+	for _, balance := range counterparties {
+		ledger.Counterparties = append(ledger.Counterparties, *balance)
+	}
+	sort.Slice(ledger.Counterparties, func(i, j int) bool {
+		return ledger.Counterparties[i].UserID < ledger.Counterparties[j].UserID
+	})
+	return ledger
+}
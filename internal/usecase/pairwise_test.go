@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+func TestExpenseUseCase_GetPairwiseBalances(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	groupRepo := newMockGroupRepository()
+	paymentRepo := memory.NewPaymentMemoryRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), paymentRepo, memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	paymentUC := NewPaymentUseCase(paymentRepo, userRepo, groupRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+
+	rent, _ := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Rent", "rent", "alice", inr(300), "", []string{"alice", "bob", "carol"})
+	pizza, _ := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Pizza", "food", "bob", inr(40), "", []string{"alice", "bob"})
+	// carol and bob's shared taxi does not involve alice at all
+	_, _ = expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Taxi", "travel", "carol", inr(20), "", []string{"bob", "carol"})
+	payment, _ := paymentUC.CreatePayment(ctx, "", "carol", "alice", inr(60), "", "")
+
+	ledger, err := expenseUC.GetPairwiseBalances(ctx, "alice", "")
+	if err != nil {
+		t.Fatalf("Failed to get pairwise balances: %v", err)
+	}
+	if len(ledger.Counterparties) != 2 {
+		t.Fatalf("Expected 2 counterparties, got: %v", ledger.Counterparties)
+	}
+
+	bob, carol := ledger.Counterparties[0], ledger.Counterparties[1]
+	if bob.UserID != "bob" || bob.Amount != inr(80) {
+		t.Errorf("Expected bob to owe alice 80 (100 rent - 20 pizza), got %s %v", bob.UserID, bob.Amount)
+	}
+	contributions := make(map[string]domain.Money)
+	for _, entry := range bob.Expenses {
+		contributions[entry.ID] = entry.Amount
+	}
+	if len(contributions) != 2 || contributions[rent.ID] != inr(100) || contributions[pizza.ID] != inr(-20) {
+		t.Errorf("Expected rent +100 and pizza -20 to contribute to bob's balance, got: %v", bob.Expenses)
+	}
+
+	if carol.UserID != "carol" || carol.Amount != inr(40) {
+		t.Errorf("Expected carol to owe alice 40 after paying 60, got %s %v", carol.UserID, carol.Amount)
+	}
+	if len(carol.Payments) != 1 || carol.Payments[0].ID != payment.ID || carol.Payments[0].Amount != inr(-60) {
+		t.Errorf("Expected carol's payment to reduce her debt by 60, got: %v", carol.Payments)
+	}
+}
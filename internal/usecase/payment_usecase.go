@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+type PaymentUseCase interface {
+	CreatePayment(ctx context.Context, groupID, from, to string, amount domain.Money, date, note string) (*domain.Payment, error)
+	GetPayment(ctx context.Context, id string) (*domain.Payment, error)
+	GetAllPayments(ctx context.Context) ([]*domain.Payment, error)
+	GetPaymentsByUser(ctx context.Context, userID string) ([]*domain.Payment, error)
+	GetGroupPayments(ctx context.Context, groupID string) ([]*domain.Payment, error)
+	UpdatePayment(ctx context.Context, id string, amount domain.Money, date, note string) (*domain.Payment, error)
+	DeletePayment(ctx context.Context, id string) error
+}
+
+type paymentUseCase struct {
+	paymentRepo  repository.PaymentRepository
+	userRepo     repository.UserRepository
+	groupRepo    repository.GroupRepository
+	baseCurrency domain.Currency
+}
+
+// NewPaymentUseCase creates the payment use case. Payments outside a group
+// default to baseCurrency.
+func NewPaymentUseCase(paymentRepo repository.PaymentRepository, userRepo repository.UserRepository, groupRepo repository.GroupRepository, baseCurrency domain.Currency) PaymentUseCase {
+	return &paymentUseCase{
+		paymentRepo:  paymentRepo,
+		userRepo:     userRepo,
+		groupRepo:    groupRepo,
+		baseCurrency: baseCurrency,
+	}
+}
+
+func (p *paymentUseCase) CreatePayment(ctx context.Context, groupID, from, to string, amount domain.Money, date, note string) (*domain.Payment, error) {
+	for _, userID := range []string{from, to} {
+		if _, err := p.userRepo.GetByID(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	currency := p.baseCurrency
+	if groupID != "" {
+		group, err := p.groupRepo.GetByID(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if !group.HasMember(from) || !group.HasMember(to) {
+			return nil, domain.ErrNotGroupMember
+		}
+		currency = group.BaseCurrency
+	}
+	if amount.Currency != "" {
+		var err error
+		if currency, err = domain.ParseCurrency(string(amount.Currency)); err != nil {
+			return nil, err
+		}
+	}
+	amount.Currency = currency
+
+	paidOn := time.Now()
+	if date != "" {
+		var err error
+		if paidOn, err = parseDate(date); err != nil {
+			return nil, err
+		}
+	}
+
+	payment := &domain.Payment{
+		ID:        uuid.New().String(),
+		GroupID:   groupID,
+		From:      from,
+		To:        to,
+		Amount:    amount,
+		Date:      paidOn,
+		Note:      note,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := payment.Validate(); err != nil {
+		return nil, err
+	}
+	if err := authorizePaymentCreate(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	if err := p.paymentRepo.Create(ctx, payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (p *paymentUseCase) GetPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	payment, err := p.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizePaymentRead(ctx, payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// GetAllPayments returns every payment or, for a signed-in user, the
+// payments they sent or received
+func (p *paymentUseCase) GetAllPayments(ctx context.Context) ([]*domain.Payment, error) {
+	if userID, ok := domain.UserIDFromContext(ctx); ok {
+		return p.paymentRepo.GetByUserID(ctx, userID)
+	}
+	return p.paymentRepo.GetAll(ctx)
+}
+
+func (p *paymentUseCase) GetPaymentsByUser(ctx context.Context, userID string) ([]*domain.Payment, error) {
+	if _, err := p.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := authorizeUserTotalsRead(ctx, userID); err != nil {
+		return nil, err
+	}
+	return p.paymentRepo.GetByUserID(ctx, userID)
+}
+
+func (p *paymentUseCase) GetGroupPayments(ctx context.Context, groupID string) ([]*domain.Payment, error) {
+	group, err := p.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGroupRead(ctx, group); err != nil {
+		return nil, err
+	}
+	return p.paymentRepo.GetByGroupID(ctx, groupID)
+}
+
+func (p *paymentUseCase) UpdatePayment(ctx context.Context, id string, amount domain.Money, date, note string) (*domain.Payment, error) {
+	payment, err := p.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizePaymentChange(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	// The currency only changes along with a new amount that names one
+	if amount.Currency == "" {
+		amount.Currency = payment.Amount.Currency
+	}
+
+	var paidOn time.Time
+	if date != "" {
+		if paidOn, err = parseDate(date); err != nil {
+			return nil, err
+		}
+	}
+
+	updated := *payment
+	if err := updated.Update(amount, paidOn, note); err != nil {
+		return nil, err
+	}
+
+	if err := p.paymentRepo.Update(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (p *paymentUseCase) DeletePayment(ctx context.Context, id string) error {
+	payment, err := p.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizePaymentChange(ctx, payment); err != nil {
+		return err
+	}
+	return p.paymentRepo.Delete(ctx, id)
+}
+
+func parseDate(date string) (time.Time, error) {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", date)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+func TestPaymentUseCase_CreatePayment_Validation(t *testing.T) {
+	userRepo := newMockUserRepository()
+	groupRepo := newMockGroupRepository()
+	paymentUC := NewPaymentUseCase(memory.NewPaymentMemoryRepository(), userRepo, groupRepo, domain.DefaultCurrency)
+	groupUC := NewGroupUseCase(groupRepo, userRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+	flat, _ := groupUC.CreateGroup(ctx, "Flat", "", "", []string{"alice", "bob"})
+
+	tests := []struct {
+		name    string
+		groupID string
+		from    string
+		to      string
+		amount  domain.Money
+		date    string
+		wantErr error
+	}{
+		{name: "self payment", from: "alice", to: "alice", amount: inr(10)},
+		{name: "zero amount", from: "alice", to: "bob", amount: inr(0)},
+		{name: "unknown payee", from: "alice", to: "ghost", amount: inr(10)},
+		{name: "bad date", from: "alice", to: "bob", amount: inr(10), date: "31/01/2025"},
+		{name: "outside group", groupID: flat.ID, from: "carol", to: "alice", amount: inr(10), wantErr: domain.ErrNotGroupMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := paymentUC.CreatePayment(ctx, tt.groupID, tt.from, tt.to, tt.amount, tt.date, "")
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestExpenseUseCase_CalculateBalances_NetsPayments(t *testing.T) {
+	expenseRepo := newMockExpenseRepository()
+	userRepo := newMockUserRepository()
+	groupRepo := newMockGroupRepository()
+	paymentRepo := memory.NewPaymentMemoryRepository()
+	expenseUC := NewExpenseUseCase(expenseRepo, memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), paymentRepo, memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	paymentUC := NewPaymentUseCase(paymentRepo, userRepo, groupRepo, domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "alice", Name: "Alice", Email: "alice@test.com"})
+	_ = userRepo.Create(ctx, &domain.User{ID: "bob", Name: "Bob", Email: "bob@test.com"})
+
+	_, err := expenseUC.CreateExpenseWithEqualSplit(ctx, "", "Groceries", "food", "alice", inr(100), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	// Bob pays back part of what he owes
+	payment, err := paymentUC.CreatePayment(ctx, "", "bob", "alice", inr(30), "2025-01-15", "UPI")
+	if err != nil {
+		t.Fatalf("Failed to create payment: %v", err)
+	}
+
+	summary, err := expenseUC.CalculateBalances(ctx, domain.SettlementMinimal)
+	if err != nil {
+		t.Fatalf("Failed to calculate balances: %v", err)
+	}
+	balances := make(map[string]domain.Money)
+	for _, balance := range summary.Balances {
+		balances[balance.UserID] = balance.Amount
+	}
+	if balances["alice"] != inr(20) || balances["bob"] != inr(-20) {
+		t.Errorf("Expected alice +20 and bob -20 after payment, got: %v", balances)
+	}
+	if len(summary.Settlements) != 1 || summary.Settlements[0].Amount != inr(20) {
+		t.Errorf("Expected a single settlement of 20, got: %v", summary.Settlements)
+	}
+
+	stats, err := expenseUC.GetUserStats(ctx, "bob")
+	if err != nil {
+		t.Fatalf("Failed to get user stats: %v", err)
+	}
+	if stats.PaymentsSent != inr(30) || stats.NetBalance != inr(-20) {
+		t.Errorf("Expected bob to have sent 30 with net -20, got sent %v net %v", stats.PaymentsSent, stats.NetBalance)
+	}
+
+	// Settling the rest clears every balance
+	if _, err := paymentUC.UpdatePayment(ctx, payment.ID, inr(50), "", ""); err != nil {
+		t.Fatalf("Failed to update payment: %v", err)
+	}
+	summary, _ = expenseUC.CalculateBalances(ctx, domain.SettlementMinimal)
+	for _, balance := range summary.Balances {
+		if !balance.Amount.IsZero() {
+			t.Errorf("Expected zero balance for %s, got %v", balance.UserID, balance.Amount)
+		}
+	}
+	if len(summary.Settlements) != 0 {
+		t.Errorf("Expected no settlements, got: %v", summary.Settlements)
+	}
+}
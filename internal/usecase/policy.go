@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// Expense access policy. The acting user comes from the request context, set
+// by the auth middleware. Calls without one come from inside the process
+// (background jobs, CLI tools) and are not restricted.
+
+// isParticipant reports whether the user paid for or has a split in the expense
+func isParticipant(expense *domain.Expense, userID string) bool {
+	if expense.PaidBy == userID {
+		return true
+	}
+	for _, split := range expense.Splits {
+		if split.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeExpenseRead allows the payer and participants to see an expense
+func authorizeExpenseRead(ctx context.Context, expense *domain.Expense) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || isParticipant(expense, userID) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeExpenseCreate allows users to add only expenses they pay for or
+// take part in
+func authorizeExpenseCreate(ctx context.Context, expense *domain.Expense) error {
+	return authorizeExpenseRead(ctx, expense)
+}
+
+// authorizeExpenseEdit allows the payer and participants to edit an expense
+func authorizeExpenseEdit(ctx context.Context, expense *domain.Expense) error {
+	return authorizeExpenseRead(ctx, expense)
+}
+
+// authorizeExpenseDelete allows only the payer to delete an expense
+func authorizeExpenseDelete(ctx context.Context, expense *domain.Expense) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || expense.PaidBy == userID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeHistoryRead allows anyone who was part of the expense at any point
+// in its history to read that history
+func authorizeHistoryRead(ctx context.Context, events []*domain.ExpenseEvent) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, event := range events {
+		for _, snapshot := range []*domain.ExpenseSnapshot{event.Before, event.After} {
+			if snapshot != nil && snapshot.Involves(userID) {
+				return nil
+			}
+		}
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeRecurringRead allows the payer and participants to see a
+// recurring expense
+func authorizeRecurringRead(ctx context.Context, recurring *domain.RecurringExpense) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || recurring.PaidBy == userID {
+		return nil
+	}
+	for _, split := range recurring.Splits {
+		if split.UserID == userID {
+			return nil
+		}
+	}
+	for _, participant := range recurring.UserIDs {
+		if participant == userID {
+			return nil
+		}
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeRecurringCreate allows users to set up only recurring expenses
+// they pay for or take part in
+func authorizeRecurringCreate(ctx context.Context, recurring *domain.RecurringExpense) error {
+	return authorizeRecurringRead(ctx, recurring)
+}
+
+// authorizeRecurringDelete allows only the payer to stop a recurring expense
+func authorizeRecurringDelete(ctx context.Context, recurring *domain.RecurringExpense) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || recurring.PaidBy == userID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// visibleExpenses drops the expenses the acting user may not read
+func visibleExpenses(ctx context.Context, expenses []*domain.Expense) []*domain.Expense {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return expenses
+	}
+
+	visible := make([]*domain.Expense, 0, len(expenses))
+	for _, expense := range expenses {
+		if isParticipant(expense, userID) {
+			visible = append(visible, expense)
+		}
+	}
+	return visible
+}
+
+// authorizePaymentRead allows the payer and the payee to see a payment
+func authorizePaymentRead(ctx context.Context, payment *domain.Payment) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || payment.From == userID || payment.To == userID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizePaymentCreate allows only the payer or the payee to record a
+// payment, so nobody can settle other people's debts
+func authorizePaymentCreate(ctx context.Context, payment *domain.Payment) error {
+	return authorizePaymentRead(ctx, payment)
+}
+
+// authorizePaymentChange allows only the payer to edit or delete a payment
+func authorizePaymentChange(ctx context.Context, payment *domain.Payment) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || payment.From == userID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeAttachmentDelete allows the uploader and the expense's payer to
+// remove an attachment
+func authorizeAttachmentDelete(ctx context.Context, expense *domain.Expense, attachment *domain.Attachment) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || attachment.UploadedBy == userID || expense.PaidBy == userID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeUserChange allows signed-in users to deactivate or delete only
+// their own account or a guest. Other accounts are managed with homiesctl.
+func authorizeUserChange(ctx context.Context, user *domain.User) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || userID == user.ID || user.Guest {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeUserUpdate allows signed-in users to change only their own name
+// and email. The email signs them in, so changing someone else's would hand
+// over their account.
+func authorizeUserUpdate(ctx context.Context, user *domain.User) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || userID == user.ID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeUserMerge allows signed-in users to merge into their own account a
+// duplicate they are also signed in as, given by signedInAs. Guests can't sign
+// in, so they are merged by claiming them.
+func authorizeUserMerge(ctx context.Context, from, into *domain.User, signedInAs string) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if userID == into.ID && signedInAs == from.ID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeGuestClaim allows signed-in users to start claiming a guest they
+// share one of groups with, the guest's groups
+func authorizeGuestClaim(ctx context.Context, groups []*domain.Group) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, group := range groups {
+		if group.HasMember(userID) {
+			return nil
+		}
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeUnconfirmedClaim keeps signed-in users from claiming a guest
+// without the email's owner confirming it
+func authorizeUnconfirmedClaim(ctx context.Context) error {
+	if _, ok := domain.UserIDFromContext(ctx); ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// authorizeGroupRead allows only a group's members to see its expenses,
+// payments, balances and stats
+func authorizeGroupRead(ctx context.Context, group *domain.Group) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok || group.HasMember(userID) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// authorizeGroupChange allows only a group's members to add or remove
+// members, so nobody can join a group to read it
+func authorizeGroupChange(ctx context.Context, group *domain.Group) error {
+	return authorizeGroupRead(ctx, group)
+}
+
+// visibleGroups drops the groups the acting user is not a member of
+func visibleGroups(ctx context.Context, groups []*domain.Group) []*domain.Group {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return groups
+	}
+
+	visible := make([]*domain.Group, 0, len(groups))
+	for _, group := range groups {
+		if group.HasMember(userID) {
+			visible = append(visible, group)
+		}
+	}
+	return visible
+}
+
+// authorizeBudget allows a group's members to see and change its budgets, and
+// users their own. Budgets outside a group and user are everyone's, and each
+// user sees just their own spending against them. groups are the acting
+// user's groups.
+func authorizeBudget(ctx context.Context, budget *domain.Budget, groups []*domain.Group) error {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if budget.GroupID != "" {
+		for _, group := range groups {
+			if group.ID == budget.GroupID && group.HasMember(userID) {
+				return nil
+			}
+		}
+		return domain.ErrForbidden
+	}
+	if budget.UserID == "" || budget.UserID == userID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// visibleBudgets drops the budgets the acting user may not see
+func visibleBudgets(ctx context.Context, budgets []*domain.Budget, groups []*domain.Group) []*domain.Budget {
+	visible := make([]*domain.Budget, 0, len(budgets))
+	for _, budget := range budgets {
+		if authorizeBudget(ctx, budget, groups) == nil {
+			visible = append(visible, budget)
+		}
+	}
+	return visible
+}
+
+// visibleBudgetAlerts drops the alerts the acting user may not see. Alerts on
+// budgets outside a group and user report every household's spending, so
+// signed-in users see none of them.
+func visibleBudgetAlerts(ctx context.Context, alerts []*domain.BudgetAlert, groups []*domain.Group) []*domain.BudgetAlert {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return alerts
+	}
+
+	visible := make([]*domain.BudgetAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.GroupID == "" && alert.UserID != userID {
+			continue
+		}
+		if authorizeBudget(ctx, &domain.Budget{UserID: alert.UserID, GroupID: alert.GroupID}, groups) == nil {
+			visible = append(visible, alert)
+		}
+	}
+	return visible
+}
+
+// authorizeUserTotalsRead allows signed-in users to see only their own stats
+// and balances across every group. Within a group, members see each other's.
+func authorizeUserTotalsRead(ctx context.Context, userID string) error {
+	actorID, ok := domain.UserIDFromContext(ctx)
+	if !ok || actorID == userID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
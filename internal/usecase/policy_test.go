@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// newPolicyFixture creates a rent expense paid by alice and split with bob;
+// carol is a user with no part in it
+func newPolicyFixture(t *testing.T) (ExpenseUseCase, *domain.Expense) {
+	t.Helper()
+	expenseUC := newTestRepos(t, "alice", "bob", "carol").expenseUseCase()
+	expense, err := expenseUC.CreateExpenseWithEqualSplit(domain.WithUserID(context.Background(), "alice"), "", "Rent", "rent", "alice", inr(100), "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	return expenseUC, expense
+}
+
+func TestExpensePolicy_Read(t *testing.T) {
+	tests := []struct {
+		user      string
+		wantError error
+	}{
+		{user: "alice", wantError: nil},
+		{user: "bob", wantError: nil},
+		{user: "carol", wantError: domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			expenseUC, expense := newPolicyFixture(t)
+			ctx := domain.WithUserID(context.Background(), tt.user)
+
+			_, err := expenseUC.GetExpense(ctx, expense.ID)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("Expected error %v, got %v", tt.wantError, err)
+			}
+
+			expenses, err := expenseUC.GetAllExpenses(ctx)
+			if err != nil {
+				t.Fatalf("Failed to list expenses: %v", err)
+			}
+			if visible := len(expenses) == 1; visible != (tt.wantError == nil) {
+				t.Errorf("Expected expense visible in list: %v, got %d expenses", tt.wantError == nil, len(expenses))
+			}
+		})
+	}
+}
+
+func TestExpensePolicy_Edit(t *testing.T) {
+	tests := []struct {
+		user      string
+		wantError error
+	}{
+		{user: "alice", wantError: nil},
+		{user: "bob", wantError: nil},
+		{user: "carol", wantError: domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			expenseUC, expense := newPolicyFixture(t)
+			ctx := domain.WithUserID(context.Background(), tt.user)
+
+			splits := []domain.Split{{UserID: "alice", Amount: inr(60)}, {UserID: "bob", Amount: inr(60)}}
+			_, err := expenseUC.UpdateExpense(ctx, expense.ID, "Rent and water", "rent", inr(120), "", splits)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("Expected error %v, got %v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestExpensePolicy_Create(t *testing.T) {
+	tests := []struct {
+		user      string
+		wantError error
+	}{
+		{user: "alice", wantError: nil},
+		{user: "bob", wantError: nil},
+		{user: "carol", wantError: domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			repos := newTestRepos(t, "alice", "bob", "carol")
+			recurringUC := NewRecurringExpenseUseCase(repos.recurring, repos.expenseUseCase(), repos.users, repos.groups, repos.categories, domain.DefaultCurrency)
+			ctx := domain.WithUserID(context.Background(), tt.user)
+
+			_, err := repos.expenseUseCase().CreateExpenseWithEqualSplit(ctx, "", "Rent", "rent", "alice", inr(100), "", []string{"alice", "bob"})
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("Expected error %v creating an expense, got %v", tt.wantError, err)
+			}
+			_, err = recurringUC.CreateRecurringExpense(ctx, "", "Rent", "rent", "alice", inr(100), nil, []string{"alice", "bob"}, "FREQ=MONTHLY", "2024-01-01", "")
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("Expected error %v creating a recurring expense, got %v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestExpensePolicy_Delete(t *testing.T) {
+	tests := []struct {
+		user      string
+		wantError error
+	}{
+		{user: "alice", wantError: nil},
+		{user: "bob", wantError: domain.ErrForbidden},
+		{user: "carol", wantError: domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			expenseUC, expense := newPolicyFixture(t)
+			ctx := domain.WithUserID(context.Background(), tt.user)
+
+			err := expenseUC.DeleteExpense(ctx, expense.ID)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("Expected error %v, got %v", tt.wantError, err)
+			}
+
+			_, err = expenseUC.GetExpense(context.Background(), expense.ID)
+			if deleted := err != nil; deleted != (tt.wantError == nil) {
+				t.Errorf("Expected expense deleted: %v, got error %v", tt.wantError == nil, err)
+			}
+		})
+	}
+}
+
+func TestExpensePolicy_NoActingUser(t *testing.T) {
+	expenseUC, expense := newPolicyFixture(t)
+	ctx := context.Background()
+
+	if _, err := expenseUC.GetExpense(ctx, expense.ID); err != nil {
+		t.Errorf("Expected internal callers to read any expense, got: %v", err)
+	}
+	if err := expenseUC.DeleteExpense(ctx, expense.ID); err != nil {
+		t.Errorf("Expected internal callers to delete any expense, got: %v", err)
+	}
+}
+
+func TestGroupPolicy_Read(t *testing.T) {
+	repos := newTestRepos(t, "alice", "bob", "carol")
+	expenseUC := repos.expenseUseCase()
+	groupUC := NewGroupUseCase(repos.groups, repos.users, domain.DefaultCurrency)
+
+	flat, err := groupUC.CreateGroup(context.Background(), "Flat", "", "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		user      string
+		wantError error
+	}{
+		{user: "alice", wantError: nil},
+		{user: "bob", wantError: nil},
+		{user: "carol", wantError: domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			ctx := domain.WithUserID(context.Background(), tt.user)
+
+			if _, err := expenseUC.GetGroupExpenses(ctx, flat.ID); !errors.Is(err, tt.wantError) {
+				t.Errorf("GetGroupExpenses: expected error %v, got %v", tt.wantError, err)
+			}
+			if _, err := expenseUC.CalculateGroupBalances(ctx, flat.ID, domain.SettlementMinimal); !errors.Is(err, tt.wantError) {
+				t.Errorf("CalculateGroupBalances: expected error %v, got %v", tt.wantError, err)
+			}
+			if _, err := expenseUC.GetGroupUserStats(ctx, flat.ID, "bob"); !errors.Is(err, tt.wantError) {
+				t.Errorf("GetGroupUserStats: expected error %v, got %v", tt.wantError, err)
+			}
+			if _, err := expenseUC.GetPairwiseBalances(ctx, "bob", flat.ID); !errors.Is(err, tt.wantError) {
+				t.Errorf("GetPairwiseBalances: expected error %v, got %v", tt.wantError, err)
+			}
+			if _, err := expenseUC.GetGroupMonthlySummary(ctx, flat.ID, now.Year(), int(now.Month())); !errors.Is(err, tt.wantError) {
+				t.Errorf("GetGroupMonthlySummary: expected error %v, got %v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestGroupPolicy_Membership(t *testing.T) {
+	repos := newTestRepos(t, "alice", "bob", "carol")
+	groupUC := NewGroupUseCase(repos.groups, repos.users, domain.DefaultCurrency)
+
+	flat, err := groupUC.CreateGroup(context.Background(), "Flat", "", "", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	if _, err := groupUC.CreateGroup(context.Background(), "Trip", "", "", []string{"carol"}); err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	alice := domain.WithUserID(context.Background(), "alice")
+	carol := domain.WithUserID(context.Background(), "carol")
+
+	if _, err := groupUC.GetGroup(carol, flat.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("GetGroup: expected ErrForbidden for a non-member, got %v", err)
+	}
+	if _, err := groupUC.AddMember(carol, flat.ID, "carol"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("AddMember: expected ErrForbidden for a non-member, got %v", err)
+	}
+	if _, err := groupUC.RemoveMember(carol, flat.ID, "bob"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("RemoveMember: expected ErrForbidden for a non-member, got %v", err)
+	}
+
+	groups, err := groupUC.GetAllGroups(carol)
+	if err != nil {
+		t.Fatalf("Failed to list groups: %v", err)
+	}
+	if len(groups) != 1 || groups[0].Name != "Trip" {
+		t.Errorf("Expected carol to see only her group, got %v", groups)
+	}
+	if groups, err = groupUC.GetGroupsByUser(carol, "alice"); err != nil {
+		t.Fatalf("Failed to list groups: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("Expected carol to see none of alice's groups, got %v", groups)
+	}
+
+	if _, err := groupUC.AddMember(alice, flat.ID, "carol"); err != nil {
+		t.Errorf("AddMember: expected a member to add carol, got %v", err)
+	}
+	if _, err := groupUC.GetGroup(carol, flat.ID); err != nil {
+		t.Errorf("GetGroup: expected carol to see the group once added, got %v", err)
+	}
+}
+
+func TestPaymentPolicy(t *testing.T) {
+	repos := newTestRepos(t, "alice", "bob", "carol")
+	paymentUC := NewPaymentUseCase(repos.payments, repos.users, repos.groups, domain.DefaultCurrency)
+	ctx := context.Background()
+	alice := domain.WithUserID(ctx, "alice")
+	bob := domain.WithUserID(ctx, "bob")
+	carol := domain.WithUserID(ctx, "carol")
+
+	if _, err := paymentUC.CreatePayment(carol, "", "alice", "bob", inr(50), "", ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("CreatePayment: expected ErrForbidden for someone else's payment, got %v", err)
+	}
+	payment, err := paymentUC.CreatePayment(alice, "", "alice", "bob", inr(50), "", "")
+	if err != nil {
+		t.Fatalf("Failed to create payment: %v", err)
+	}
+
+	tests := []struct {
+		user       context.Context
+		name       string
+		readError  error
+		writeError error
+	}{
+		{user: carol, name: "carol", readError: domain.ErrForbidden, writeError: domain.ErrForbidden},
+		{user: bob, name: "bob", readError: nil, writeError: domain.ErrForbidden},
+		// alice goes last, as her delete removes the payment
+		{user: alice, name: "alice", readError: nil, writeError: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := paymentUC.GetPayment(tt.user, payment.ID); !errors.Is(err, tt.readError) {
+				t.Errorf("GetPayment: expected error %v, got %v", tt.readError, err)
+			}
+			if _, err := paymentUC.UpdatePayment(tt.user, payment.ID, inr(60), "", "corrected"); !errors.Is(err, tt.writeError) {
+				t.Errorf("UpdatePayment: expected error %v, got %v", tt.writeError, err)
+			}
+			if err := paymentUC.DeletePayment(tt.user, payment.ID); !errors.Is(err, tt.writeError) {
+				t.Errorf("DeletePayment: expected error %v, got %v", tt.writeError, err)
+			}
+		})
+	}
+}
+
+func TestExpensePolicy_Totals(t *testing.T) {
+	expenseUC, _ := newPolicyFixture(t)
+	now := time.Now()
+
+	alice := domain.WithUserID(context.Background(), "alice")
+	carol := domain.WithUserID(context.Background(), "carol")
+
+	summary, err := expenseUC.CalculateBalances(carol, domain.SettlementMinimal)
+	if err != nil {
+		t.Fatalf("Failed to calculate balances: %v", err)
+	}
+	if len(summary.Balances) != 0 {
+		t.Errorf("Expected carol to see no balances, got %v", summary.Balances)
+	}
+	if summary, err = expenseUC.CalculateBalances(alice, domain.SettlementMinimal); err != nil {
+		t.Fatalf("Failed to calculate balances: %v", err)
+	}
+	if len(summary.Balances) != 2 {
+		t.Errorf("Expected alice to see her and bob's balances, got %v", summary.Balances)
+	}
+
+	monthly, err := expenseUC.GetMonthlySummary(carol, now.Year(), int(now.Month()))
+	if err != nil {
+		t.Fatalf("Failed to get monthly summary: %v", err)
+	}
+	if monthly.ExpenseCount != 0 {
+		t.Errorf("Expected carol's summary to be empty, got %d expenses", monthly.ExpenseCount)
+	}
+	if monthly, err = expenseUC.GetMonthlySummary(alice, now.Year(), int(now.Month())); err != nil {
+		t.Fatalf("Failed to get monthly summary: %v", err)
+	}
+	if monthly.ExpenseCount != 1 || monthly.TotalExpenses != inr(100) {
+		t.Errorf("Expected alice's summary to hold the rent, got %d expenses totalling %v", monthly.ExpenseCount, monthly.TotalExpenses)
+	}
+
+	if _, err := expenseUC.GetUserStats(alice, "alice"); err != nil {
+		t.Errorf("Expected alice to see her own stats, got %v", err)
+	}
+	if _, err := expenseUC.GetUserStats(carol, "alice"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for another user's stats, got %v", err)
+	}
+	if _, err := expenseUC.GetPairwiseBalances(carol, "alice", ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for another user's ledger, got %v", err)
+	}
+}
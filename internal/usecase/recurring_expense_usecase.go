@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository"
+)
+
+type RecurringExpenseUseCase interface {
+	CreateRecurringExpense(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, splits []domain.Split, userIDs []string, schedule, startDate, endDate string) (*domain.RecurringExpense, error)
+	GetRecurringExpense(ctx context.Context, id string) (*domain.RecurringExpense, error)
+	GetAllRecurringExpenses(ctx context.Context) ([]*domain.RecurringExpense, error)
+	DeleteRecurringExpense(ctx context.Context, id string) error
+	MaterializeDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type recurringExpenseUseCase struct {
+	recurringRepo repository.RecurringExpenseRepository
+	expenseUC     ExpenseUseCase
+	userRepo      repository.UserRepository
+	groupRepo     repository.GroupRepository
+	categoryRepo  repository.CategoryRepository
+	baseCurrency  domain.Currency
+}
+
+// NewRecurringExpenseUseCase creates the recurring expense use case. Due
+// occurrences become ordinary expenses through expenseUC.
+func NewRecurringExpenseUseCase(recurringRepo repository.RecurringExpenseRepository, expenseUC ExpenseUseCase, userRepo repository.UserRepository, groupRepo repository.GroupRepository, categoryRepo repository.CategoryRepository, baseCurrency domain.Currency) RecurringExpenseUseCase {
+	return &recurringExpenseUseCase{
+		recurringRepo: recurringRepo,
+		expenseUC:     expenseUC,
+		userRepo:      userRepo,
+		groupRepo:     groupRepo,
+		categoryRepo:  categoryRepo,
+		baseCurrency:  baseCurrency,
+	}
+}
+
+func (u *recurringExpenseUseCase) CreateRecurringExpense(ctx context.Context, groupID, description, category, paidBy string, amount domain.Money, splits []domain.Split, userIDs []string, schedule, startDate, endDate string) (*domain.RecurringExpense, error) {
+	participants := append([]string{paidBy}, userIDs...)
+	for _, split := range splits {
+		participants = append(participants, split.UserID)
+	}
+	for _, userID := range participants {
+		if _, err := u.userRepo.GetByID(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	currency := u.baseCurrency
+	if groupID != "" {
+		group, err := u.groupRepo.GetByID(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		for _, userID := range participants {
+			if !group.HasMember(userID) {
+				return nil, domain.ErrNotGroupMember
+			}
+		}
+		currency = group.BaseCurrency
+	}
+	if amount.Currency != "" {
+		var err error
+		if currency, err = domain.ParseCurrency(string(amount.Currency)); err != nil {
+			return nil, err
+		}
+	}
+	amount, splits = withCurrency(currency, amount, splits)
+
+	category, err := resolveCategory(ctx, u.categoryRepo, category)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedSchedule, err := domain.ParseSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	recurring := &domain.RecurringExpense{
+		ID:          uuid.New().String(),
+		GroupID:     groupID,
+		Description: description,
+		Amount:      amount,
+		Category:    category,
+		PaidBy:      paidBy,
+		Splits:      splits,
+		UserIDs:     userIDs,
+		Schedule:    parsedSchedule,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if startDate == "" {
+		return nil, errors.New("start_date is required")
+	}
+	if recurring.StartDate, err = parseDate(startDate); err != nil {
+		return nil, err
+	}
+	if endDate != "" {
+		end, err := parseDate(endDate)
+		if err != nil {
+			return nil, err
+		}
+		recurring.EndDate = &end
+	}
+
+	if err := recurring.Validate(); err != nil {
+		return nil, err
+	}
+	if err := authorizeRecurringCreate(ctx, recurring); err != nil {
+		return nil, err
+	}
+
+	if err := u.recurringRepo.Create(ctx, recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+func (u *recurringExpenseUseCase) GetRecurringExpense(ctx context.Context, id string) (*domain.RecurringExpense, error) {
+	recurring, err := u.recurringRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeRecurringRead(ctx, recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+func (u *recurringExpenseUseCase) GetAllRecurringExpenses(ctx context.Context) ([]*domain.RecurringExpense, error) {
+	all, err := u.recurringRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*domain.RecurringExpense, 0, len(all))
+	for _, recurring := range all {
+		if authorizeRecurringRead(ctx, recurring) == nil {
+			visible = append(visible, recurring)
+		}
+	}
+	return visible, nil
+}
+
+// DeleteRecurringExpense stops future occurrences; expenses already created
+// from the template are kept
+func (u *recurringExpenseUseCase) DeleteRecurringExpense(ctx context.Context, id string) error {
+	recurring, err := u.recurringRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeRecurringDelete(ctx, recurring); err != nil {
+		return err
+	}
+	return u.recurringRepo.Delete(ctx, id)
+}
+
+// MaterializeDue creates an expense for every occurrence due by now and
+// returns how many were created. Each expense is keyed on its occurrence, so
+// reruns and concurrent schedulers never duplicate one, and the template only
+// moves past an occurrence once its expense exists. An occurrence whose
+// expense fails to create is reported and tried again on the next run.
+func (u *recurringExpenseUseCase) MaterializeDue(ctx context.Context, now time.Time) (int, error) {
+	all, err := u.recurringRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var errs []error
+	for _, recurring := range all {
+		for _, date := range recurring.DueOccurrences(now) {
+			_, err := u.expenseUC.CreateOccurrence(ctx, recurring, date)
+			if err == nil {
+				created++
+			} else if !errors.Is(err, domain.ErrOccurrenceExists) {
+				errs = append(errs, fmt.Errorf("recurring expense %s on %s: %w", recurring.ID, date.Format("2006-01-02"), err))
+				break
+			}
+
+			if _, err := u.recurringRepo.ClaimOccurrence(ctx, recurring.ID, date); err != nil {
+				errs = append(errs, err)
+				break
+			}
+		}
+	}
+	return created, errors.Join(errs...)
+}
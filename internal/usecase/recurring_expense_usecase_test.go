@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+func TestRecurringExpenseUseCase_MaterializeDue(t *testing.T) {
+	userRepo := memory.NewUserMemoryRepository()
+	groupRepo := memory.NewGroupMemoryRepository()
+	expenseUC := NewExpenseUseCase(memory.NewExpenseMemoryRepository(), memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	recurringRepo := memory.NewRecurringExpenseMemoryRepository()
+	recurringUC := NewRecurringExpenseUseCase(recurringRepo, expenseUC, userRepo, groupRepo, memory.NewCategoryMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+
+	rent, err := recurringUC.CreateRecurringExpense(ctx, "", "Rent", "rent", "alice", inr(1000), nil, []string{"alice", "bob"}, "FREQ=MONTHLY;BYMONTHDAY=1", "2025-01-01", "")
+	if err != nil {
+		t.Fatalf("Failed to create recurring expense: %v", err)
+	}
+	splits := []domain.Split{{UserID: "alice", Amount: inr(300)}, {UserID: "bob", Amount: inr(200)}}
+	_, err = recurringUC.CreateRecurringExpense(ctx, "", "Internet", "utilities", "bob", inr(500), splits, nil, "FREQ=MONTHLY;BYMONTHDAY=5", "2025-01-01", "2025-02-28")
+	if err != nil {
+		t.Fatalf("Failed to create recurring expense: %v", err)
+	}
+
+	now := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	created, err := recurringUC.MaterializeDue(ctx, now)
+	if err != nil {
+		t.Fatalf("MaterializeDue failed: %v", err)
+	}
+	// Rent for January to March, internet for January and February only
+	if created != 5 {
+		t.Errorf("Expected 5 expenses created, got %d", created)
+	}
+
+	// A restarted scheduler must not create them again
+	created, err = recurringUC.MaterializeDue(ctx, now)
+	if err != nil {
+		t.Fatalf("MaterializeDue failed: %v", err)
+	}
+	if created != 0 {
+		t.Errorf("Expected no duplicates on rerun, got %d new expenses", created)
+	}
+
+	expenses, _ := expenseUC.GetAllExpenses(ctx)
+	if len(expenses) != 5 {
+		t.Fatalf("Expected 5 expenses, got %d", len(expenses))
+	}
+	for _, expense := range expenses {
+		if expense.Description == "Internet" && expense.Splits[0].Amount != inr(300) {
+			t.Errorf("Expected fixed splits to be copied, got %v", expense.Splits)
+		}
+	}
+
+	stored, _ := recurringUC.GetRecurringExpense(ctx, rent.ID)
+	if stored.LastOccurrence == nil || !stored.LastOccurrence.Equal(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected last occurrence 2025-03-01, got %v", stored.LastOccurrence)
+	}
+}
+
+func TestRecurringExpenseUseCase_MaterializeDue_Retries(t *testing.T) {
+	userRepo := memory.NewUserMemoryRepository()
+	groupRepo := memory.NewGroupMemoryRepository()
+	expenseUC := NewExpenseUseCase(memory.NewExpenseMemoryRepository(), memory.NewExpenseEventMemoryRepository(), userRepo, groupRepo, memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	recurringUC := NewRecurringExpenseUseCase(memory.NewRecurringExpenseMemoryRepository(), expenseUC, userRepo, groupRepo, memory.NewCategoryMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob"} {
+		_ = userRepo.Create(ctx, &domain.User{ID: id, Name: id, Email: id + "@test.com"})
+	}
+	rent, err := recurringUC.CreateRecurringExpense(ctx, "", "Rent", "rent", "alice", inr(1000), nil, []string{"alice", "bob"}, "FREQ=MONTHLY;BYMONTHDAY=1", "2025-01-01", "")
+	if err != nil {
+		t.Fatalf("Failed to create recurring expense: %v", err)
+	}
+
+	// A scheduler that created January's rent and crashed before moving the
+	// template on must not create it again
+	january := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := expenseUC.CreateOccurrence(ctx, rent, january); err != nil {
+		t.Fatalf("CreateOccurrence() failed: %v", err)
+	}
+	if _, err := expenseUC.CreateOccurrence(ctx, rent, january); !errors.Is(err, domain.ErrOccurrenceExists) {
+		t.Fatalf("Expected ErrOccurrenceExists, got %v", err)
+	}
+	created, err := recurringUC.MaterializeDue(ctx, time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil || created != 0 {
+		t.Fatalf("Expected January to be skipped, got %d created, err %v", created, err)
+	}
+
+	// February fails while bob is deactivated and is kept for the next run
+	bob, _ := userRepo.GetByID(ctx, "bob")
+	deactivatedAt := time.Now()
+	bob.DeactivatedAt = &deactivatedAt
+	february := time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC)
+	if created, err := recurringUC.MaterializeDue(ctx, february); err == nil || created != 0 {
+		t.Fatalf("Expected February to fail, got %d created, err %v", created, err)
+	}
+	bob.DeactivatedAt = nil
+	if created, err := recurringUC.MaterializeDue(ctx, february); err != nil || created != 1 {
+		t.Fatalf("Expected February to be created on retry, got %d created, err %v", created, err)
+	}
+
+	expenses, _ := expenseUC.GetAllExpenses(ctx)
+	if len(expenses) != 2 {
+		t.Errorf("Expected 2 expenses, got %d", len(expenses))
+	}
+}
+
+func TestRecurringExpenseUseCase_CreateValidation(t *testing.T) {
+	userRepo := memory.NewUserMemoryRepository()
+	recurringUC := NewRecurringExpenseUseCase(memory.NewRecurringExpenseMemoryRepository(), nil, userRepo, memory.NewGroupMemoryRepository(), memory.NewCategoryMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+	_ = userRepo.Create(ctx, &domain.User{ID: "alice", Name: "alice", Email: "alice@test.com"})
+
+	tests := []struct {
+		name      string
+		schedule  string
+		startDate string
+		endDate   string
+		userIDs   []string
+	}{
+		{name: "bad schedule", schedule: "FREQ=SOMETIMES", startDate: "2025-01-01", userIDs: []string{"alice"}},
+		{name: "missing start", schedule: "FREQ=DAILY", userIDs: []string{"alice"}},
+		{name: "end before start", schedule: "FREQ=DAILY", startDate: "2025-02-01", endDate: "2025-01-01", userIDs: []string{"alice"}},
+		{name: "no participants", schedule: "FREQ=DAILY", startDate: "2025-01-01"},
+		{name: "unknown user", schedule: "FREQ=DAILY", startDate: "2025-01-01", userIDs: []string{"nobody"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := recurringUC.CreateRecurringExpense(ctx, "", "Rent", "rent", "alice", inr(100), nil, tt.userIDs, tt.schedule, tt.startDate, tt.endDate)
+			if err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+// testRepos is a full set of memory repositories, linked to each other the
+// way the database's foreign keys and cascades link the tables
+type testRepos struct {
+	users       *memory.UserMemoryRepository
+	expenses    *memory.ExpenseMemoryRepository
+	events      *memory.ExpenseEventMemoryRepository
+	attachments *memory.AttachmentMemoryRepository
+	groups      *memory.GroupMemoryRepository
+	categories  *memory.CategoryMemoryRepository
+	budgets     *memory.BudgetMemoryRepository
+	payments    *memory.PaymentMemoryRepository
+	recurring   *memory.RecurringExpenseMemoryRepository
+	auth        *memory.AuthMemoryRepository
+	rates       *memory.ExchangeRateMemoryRepository
+}
+
+// newTestRepos creates the repositories with a user for each of userIDs,
+// named after their ID
+func newTestRepos(t *testing.T, userIDs ...string) *testRepos {
+	t.Helper()
+	r := &testRepos{
+		events:      memory.NewExpenseEventMemoryRepository(),
+		attachments: memory.NewAttachmentMemoryRepository(),
+		groups:      memory.NewGroupMemoryRepository(),
+		budgets:     memory.NewBudgetMemoryRepository(),
+		payments:    memory.NewPaymentMemoryRepository(),
+		recurring:   memory.NewRecurringExpenseMemoryRepository(),
+		auth:        memory.NewAuthMemoryRepository(),
+		rates:       memory.NewExchangeRateMemoryRepository(),
+	}
+	r.expenses = memory.NewExpenseMemoryRepository().WithEvents(r.events).WithAttachments(r.attachments)
+	r.categories = memory.NewCategoryMemoryRepository().WithRecords(r.expenses, r.recurring).WithBudgets(r.budgets)
+	r.users = memory.NewUserMemoryRepository().WithRecords(r.expenses, r.payments, r.groups, r.recurring)
+
+	for _, id := range userIDs {
+		if err := r.users.Create(context.Background(), &domain.User{ID: id, Name: id, Email: id + "@test.com"}); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	return r
+}
+
+// expenseUseCase returns an expense use case over the repositories
+func (r *testRepos) expenseUseCase() ExpenseUseCase {
+	return NewExpenseUseCase(r.expenses, r.events, r.users, r.groups, r.categories, r.payments, r.rates, domain.DefaultCurrency)
+}
@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// exactSettlementLimit is the most open balances planned by exhaustive search.
+// The search table has 2^n entries, so larger groups use a heuristic instead.
+const exactSettlementLimit = 16
+
+// sharedPairs records which users have shared an expense or payment
+type sharedPairs map[string]map[string]bool
+
+func (s sharedPairs) link(a, b string) {
+	if a == b {
+		return
+	}
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		if s[pair[0]] == nil {
+			s[pair[0]] = make(map[string]bool)
+		}
+		s[pair[0]][pair[1]] = true
+	}
+}
+
+// neighbours returns the users sharing with userID, sorted for determinism
+func (s sharedPairs) neighbours(userID string) []string {
+	var users []string
+	for other := range s[userID] {
+		users = append(users, other)
+	}
+	sort.Strings(users)
+	return users
+}
+
+func buildSharedPairs(expenses []*domain.Expense, payments []*domain.Payment) sharedPairs {
+	shared := make(sharedPairs)
+	for _, expense := range expenses {
+		participants := []string{expense.PaidBy}
+		for _, split := range expense.Splits {
+			participants = append(participants, split.UserID)
+		}
+		for i, a := range participants {
+			for _, b := range participants[i+1:] {
+				shared.link(a, b)
+			}
+		}
+	}
+	for _, payment := range payments {
+		shared.link(payment.From, payment.To)
+	}
+	return shared
+}
+
+// planSettlements suggests transfers that bring every balance to zero. The
+// plan depends only on the balances, never on map iteration order.
+func planSettlements(balances []domain.Balance, strategy domain.SettlementStrategy, shared sharedPairs) []domain.Settlement {
+	var open []domain.Balance
+	for _, balance := range balances {
+		if !balance.Amount.IsZero() {
+			open = append(open, balance)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].UserID < open[j].UserID
+	})
+
+	switch strategy {
+	case domain.SettlementGreedy:
+		return settleGreedy(open)
+	case domain.SettlementSharedOnly:
+		return settleAlongSharedPairs(open, shared)
+	default:
+		return settleMinimal(open)
+	}
+}
+
+// settleGreedy repeatedly pairs the largest debtor with the largest creditor.
+// A zero-sum set of n balances settles in at most n-1 transfers.
+func settleGreedy(open []domain.Balance) []domain.Settlement {
+	var debtors, creditors []domain.Balance
+	for _, balance := range open {
+		if balance.Amount.IsNegative() {
+			debtors = append(debtors, domain.Balance{UserID: balance.UserID, Amount: balance.Amount.Neg()})
+		} else {
+			creditors = append(creditors, balance)
+		}
+	}
+
+	var settlements []domain.Settlement
+	for len(debtors) > 0 && len(creditors) > 0 {
+		sortLargestFirst(debtors)
+		sortLargestFirst(creditors)
+
+		amount := debtors[0].Amount.Min(creditors[0].Amount)
+		settlements = append(settlements, domain.Settlement{
+			From:   debtors[0].UserID,
+			To:     creditors[0].UserID,
+			Amount: amount,
+		})
+
+		debtors[0].Amount = debtors[0].Amount.Sub(amount)
+		creditors[0].Amount = creditors[0].Amount.Sub(amount)
+		if debtors[0].Amount.IsZero() {
+			debtors = debtors[1:]
+		}
+		if creditors[0].Amount.IsZero() {
+			creditors = creditors[1:]
+		}
+	}
+	return settlements
+}
+
+func sortLargestFirst(balances []domain.Balance) {
+	sort.SliceStable(balances, func(i, j int) bool {
+		if c := balances[i].Amount.Cmp(balances[j].Amount); c != 0 {
+			return c > 0
+		}
+		return balances[i].UserID < balances[j].UserID
+	})
+}
+
+// settleMinimal minimises the number of transfers. n balances that split into
+// k independent zero-sum groups need exactly n-k transfers, so the plan finds
+// the partition with the most groups and settles each group greedily.
+func settleMinimal(open []domain.Balance) []domain.Settlement {
+	if len(open) > exactSettlementLimit {
+		return settleHeuristic(open)
+	}
+
+	var settlements []domain.Settlement
+	for _, group := range zeroSumPartition(open) {
+		settlements = append(settlements, settleGreedy(group)...)
+	}
+	return settlements
+}
+
+// zeroSumPartition splits balances into as many zero-sum groups as possible.
+// groups[mask] is the most zero-sum groups the subset mask can be peeled into
+// one member at a time, counting each zero-sum prefix along the way.
+func zeroSumPartition(open []domain.Balance) [][]domain.Balance {
+	n := len(open)
+	full := 1<<n - 1
+
+	sums := make([]int64, full+1)
+	groups := make([]int8, full+1)
+	for mask := 1; mask <= full; mask++ {
+		low := bits.TrailingZeros(uint(mask))
+		sums[mask] = sums[mask&(mask-1)] + open[low].Amount.Minor
+
+		var best int8
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && groups[mask^(1<<i)] > best {
+				best = groups[mask^(1<<i)]
+			}
+		}
+		if sums[mask] == 0 {
+			best++
+		}
+		groups[mask] = best
+	}
+
+	// Walk back from the full set; each zero-sum subset reached closes a group
+	var partition [][]domain.Balance
+	var current []domain.Balance
+	for mask := full; mask != 0; {
+		gain := int8(0)
+		if sums[mask] == 0 {
+			gain = 1
+			if len(current) > 0 {
+				partition = append(partition, current)
+				current = nil
+			}
+		}
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && groups[mask^(1<<i)]+gain == groups[mask] {
+				current = append(current, open[i])
+				mask ^= 1 << i
+				break
+			}
+		}
+	}
+	if len(current) > 0 {
+		partition = append(partition, current)
+	}
+	return partition
+}
+
+// settleHeuristic settles exactly matching debtor/creditor pairs directly,
+// since each such pair is its own zero-sum group, then settles the rest greedily
+func settleHeuristic(open []domain.Balance) []domain.Settlement {
+	var settlements []domain.Settlement
+	matched := make([]bool, len(open))
+	for i, debtor := range open {
+		if !debtor.Amount.IsNegative() || matched[i] {
+			continue
+		}
+		for j, creditor := range open {
+			if !matched[j] && creditor.Amount == debtor.Amount.Neg() {
+				settlements = append(settlements, domain.Settlement{
+					From:   debtor.UserID,
+					To:     creditor.UserID,
+					Amount: creditor.Amount,
+				})
+				matched[i], matched[j] = true, true
+				break
+			}
+		}
+	}
+
+	var rest []domain.Balance
+	for i, balance := range open {
+		if !matched[i] {
+			rest = append(rest, balance)
+		}
+	}
+	return append(settlements, settleGreedy(rest)...)
+}
+
+// settleAlongSharedPairs only suggests transfers between users who already
+// share an expense or payment. A debtor pays the nearest reachable creditor,
+// relaying through shared contacts when the two have never shared directly.
+func settleAlongSharedPairs(open []domain.Balance, shared sharedPairs) []domain.Settlement {
+	remaining := make(map[string]domain.Money)
+	for _, balance := range open {
+		remaining[balance.UserID] = balance.Amount
+	}
+
+	transfers := make(map[[2]string]domain.Money)
+	stuck := make(map[string]bool)
+	for {
+		var debtors []domain.Balance
+		for _, balance := range open {
+			if amount := remaining[balance.UserID]; amount.IsNegative() && !stuck[balance.UserID] {
+				debtors = append(debtors, domain.Balance{UserID: balance.UserID, Amount: amount.Neg()})
+			}
+		}
+		if len(debtors) == 0 {
+			break
+		}
+		sortLargestFirst(debtors)
+		debtor := debtors[0]
+
+		path := pathToCreditor(debtor.UserID, shared, remaining)
+		if path == nil {
+			stuck[debtor.UserID] = true
+			continue
+		}
+		creditor := path[len(path)-1]
+
+		amount := debtor.Amount.Min(remaining[creditor])
+		for i := 0; i+1 < len(path); i++ {
+			addTransfer(transfers, path[i], path[i+1], amount)
+		}
+		remaining[debtor.UserID] = remaining[debtor.UserID].Add(amount)
+		remaining[creditor] = remaining[creditor].Sub(amount)
+	}
+
+	var settlements []domain.Settlement
+	for pair, amount := range transfers {
+		settlements = append(settlements, domain.Settlement{From: pair[0], To: pair[1], Amount: amount})
+	}
+	sort.Slice(settlements, func(i, j int) bool {
+		if settlements[i].From != settlements[j].From {
+			return settlements[i].From < settlements[j].From
+		}
+		return settlements[i].To < settlements[j].To
+	})
+	return settlements
+}
+
+// pathToCreditor finds the shortest chain of shared contacts from a debtor to
+// someone still owed money, or nil if none is reachable
+func pathToCreditor(from string, shared sharedPairs, remaining map[string]domain.Money) []string {
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		user := queue[0]
+		queue = queue[1:]
+		if remaining[user].IsPositive() {
+			var path []string
+			for at := user; at != ""; at = previous[at] {
+				path = append([]string{at}, path...)
+			}
+			return path
+		}
+		for _, next := range shared.neighbours(user) {
+			if _, seen := previous[next]; !seen {
+				previous[next] = user
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// addTransfer records a transfer, netting it against one in the other direction
+func addTransfer(transfers map[[2]string]domain.Money, from, to string, amount domain.Money) {
+	reverse := [2]string{to, from}
+	if existing, ok := transfers[reverse]; ok {
+		switch c := existing.Cmp(amount); {
+		case c > 0:
+			transfers[reverse] = existing.Sub(amount)
+			return
+		case c == 0:
+			delete(transfers, reverse)
+			return
+		default:
+			delete(transfers, reverse)
+			amount = amount.Sub(existing)
+		}
+	}
+	pair := [2]string{from, to}
+	transfers[pair] = transfers[pair].Add(amount)
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+func balancesOf(amounts map[string]int64) []domain.Balance {
+	var balances []domain.Balance
+	for userID, rupees := range amounts {
+		balances = append(balances, domain.Balance{UserID: userID, Amount: inr(rupees)})
+	}
+	return balances
+}
+
+// assertSettles checks the settlements bring every balance to exactly zero
+func assertSettles(t *testing.T, balances []domain.Balance, settlements []domain.Settlement) {
+	t.Helper()
+	remaining := make(map[string]domain.Money)
+	for _, balance := range balances {
+		remaining[balance.UserID] = balance.Amount
+	}
+	for _, settlement := range settlements {
+		if !settlement.Amount.IsPositive() {
+			t.Errorf("Expected positive settlement amount, got %v", settlement)
+		}
+		remaining[settlement.From] = remaining[settlement.From].Add(settlement.Amount)
+		remaining[settlement.To] = remaining[settlement.To].Sub(settlement.Amount)
+	}
+	for userID, amount := range remaining {
+		if !amount.IsZero() {
+			t.Errorf("Expected %s to be settled, %v remains", userID, amount)
+		}
+	}
+}
+
+func TestPlanSettlements_TransferCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		balances map[string]int64
+		strategy domain.SettlementStrategy
+		want     int
+	}{
+		{
+			name:     "already settled",
+			balances: map[string]int64{"a": 0, "b": 0},
+			strategy: domain.SettlementMinimal,
+			want:     0,
+		},
+		{
+			name:     "single debt",
+			balances: map[string]int64{"a": -50, "b": 50},
+			strategy: domain.SettlementMinimal,
+			want:     1,
+		},
+		{
+			// Two independent groups: greedy crosses them, minimal keeps them apart
+			name:     "independent groups greedy",
+			balances: map[string]int64{"a": -10, "b": 6, "c": 4, "d": -8, "e": 8},
+			strategy: domain.SettlementGreedy,
+			want:     4,
+		},
+		{
+			name:     "independent groups minimal",
+			balances: map[string]int64{"a": -10, "b": 6, "c": 4, "d": -8, "e": 8},
+			strategy: domain.SettlementMinimal,
+			want:     3,
+		},
+		{
+			name:     "one creditor",
+			balances: map[string]int64{"a": -10, "b": -20, "c": -30, "d": 60},
+			strategy: domain.SettlementMinimal,
+			want:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances := balancesOf(tt.balances)
+			settlements := planSettlements(balances, tt.strategy, nil)
+			if len(settlements) != tt.want {
+				t.Errorf("Expected %d transfers, got %d: %v", tt.want, len(settlements), settlements)
+			}
+			assertSettles(t, balances, settlements)
+		})
+	}
+}
+
+func TestPlanSettlements_Deterministic(t *testing.T) {
+	amounts := map[string]int64{"a": -30, "b": -30, "c": 20, "d": 20, "e": 20}
+	first := planSettlements(balancesOf(amounts), domain.SettlementMinimal, nil)
+	for i := 0; i < 20; i++ {
+		again := planSettlements(balancesOf(amounts), domain.SettlementMinimal, nil)
+		if !reflect.DeepEqual(first, again) {
+			t.Fatalf("Expected identical plans, got %v and %v", first, again)
+		}
+	}
+}
+
+func TestPlanSettlements_LargeGroupUsesHeuristic(t *testing.T) {
+	amounts := make(map[string]int64)
+	for i := 0; i < exactSettlementLimit+4; i++ {
+		id := string(rune('a' + i))
+		amounts[id] = int64(i + 1)
+		amounts[id+"-debtor"] = -int64(i + 1)
+	}
+
+	balances := balancesOf(amounts)
+	settlements := planSettlements(balances, domain.SettlementMinimal, nil)
+	if len(settlements) != len(amounts)/2 {
+		t.Errorf("Expected matching pairs to settle directly in %d transfers, got %d", len(amounts)/2, len(settlements))
+	}
+	assertSettles(t, balances, settlements)
+}
+
+func TestPlanSettlements_SharedOnly(t *testing.T) {
+	// alice owes carol, but they only know each other through bob
+	shared := make(sharedPairs)
+	shared.link("alice", "bob")
+	shared.link("bob", "carol")
+
+	balances := balancesOf(map[string]int64{"alice": -40, "bob": 0, "carol": 40})
+	settlements := planSettlements(balances, domain.SettlementSharedOnly, shared)
+
+	want := []domain.Settlement{
+		{From: "alice", To: "bob", Amount: inr(40)},
+		{From: "bob", To: "carol", Amount: inr(40)},
+	}
+	if !reflect.DeepEqual(settlements, want) {
+		t.Errorf("Expected %v, got %v", want, settlements)
+	}
+	assertSettles(t, balances, settlements)
+}
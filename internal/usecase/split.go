@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+)
+
+// hundredPercent is 100% in the hundredths SplitWeight uses
+const hundredPercent = 100_00
+
+// splitStrategy divides an expense amount into per-user splits that sum to
+// exactly that amount. Rounding is deterministic: leftover minor units go to
+// users in the order they were given.
+type splitStrategy interface {
+	split(amount domain.Money) ([]domain.Split, error)
+}
+
+// newSplitStrategy returns the strategy for spec.Mode
+func newSplitStrategy(spec domain.SplitSpec) (splitStrategy, error) {
+	if len(spec.Receipt) > 0 {
+		if spec.Mode != domain.SplitItemised {
+			return nil, fmt.Errorf("a receipt can only be split itemised, not %s", spec.Mode)
+		}
+		if len(spec.Items) > 0 || len(spec.UserIDs) > 0 {
+			return nil, errors.New("give either a receipt or items and user_ids for an itemised split, not both")
+		}
+		return receiptSplit{items: spec.Receipt}, nil
+	}
+
+	switch spec.Mode {
+	case domain.SplitExact, "":
+		return exactSplit{splits: spec.Splits}, nil
+	case domain.SplitEqual:
+		return equalSplit{userIDs: spec.UserIDs}, nil
+	case domain.SplitPercentage:
+		return percentageSplit{weights: spec.Weights}, nil
+	case domain.SplitShares:
+		return sharesSplit{weights: spec.Weights}, nil
+	case domain.SplitItemised:
+		return itemisedSplit{items: spec.Items, restUserIDs: spec.UserIDs}, nil
+	default:
+		return nil, fmt.Errorf("invalid split mode %q", spec.Mode)
+	}
+}
+
+type exactSplit struct {
+	splits []domain.Split
+}
+
+func (s exactSplit) split(amount domain.Money) ([]domain.Split, error) {
+	splits := make([]domain.Split, len(s.splits))
+	copy(splits, s.splits)
+	return splits, nil
+}
+
+type equalSplit struct {
+	userIDs []string
+}
+
+func (s equalSplit) split(amount domain.Money) ([]domain.Split, error) {
+	if len(s.userIDs) == 0 {
+		return nil, errors.New("at least one user is required for equal split")
+	}
+
+	shares := amount.Allocate(len(s.userIDs))
+	splits := make([]domain.Split, len(s.userIDs))
+	for i, userID := range s.userIDs {
+		splits[i] = domain.Split{UserID: userID, Amount: shares[i]}
+	}
+	return splits, nil
+}
+
+type percentageSplit struct {
+	weights []domain.SplitWeight
+}
+
+func (s percentageSplit) split(amount domain.Money) ([]domain.Split, error) {
+	var total int64
+	for _, weight := range s.weights {
+		total += weight.Hundredths
+	}
+	if total != hundredPercent {
+		return nil, errors.New("split percentages must add up to 100")
+	}
+	return splitByWeight(amount, s.weights)
+}
+
+type sharesSplit struct {
+	weights []domain.SplitWeight
+}
+
+func (s sharesSplit) split(amount domain.Money) ([]domain.Split, error) {
+	return splitByWeight(amount, s.weights)
+}
+
+func splitByWeight(amount domain.Money, weights []domain.SplitWeight) ([]domain.Split, error) {
+	if len(weights) == 0 {
+		return nil, errors.New("at least one user is required")
+	}
+
+	values := make([]int64, len(weights))
+	for i, weight := range weights {
+		if weight.Hundredths <= 0 {
+			return nil, fmt.Errorf("split weight for user %s must be greater than zero", weight.UserID)
+		}
+		values[i] = weight.Hundredths
+	}
+
+	parts, err := amount.AllocateWeighted(values)
+	if err != nil {
+		return nil, err
+	}
+	splits := make([]domain.Split, len(weights))
+	for i, weight := range weights {
+		splits[i] = domain.Split{UserID: weight.UserID, Amount: parts[i]}
+	}
+	return mergeSplits(splits), nil
+}
+
+// itemisedSplit charges each item equally to its users, then splits whatever
+// the items do not cover equally between restUserIDs
+type itemisedSplit struct {
+	items       []domain.SplitItem
+	restUserIDs []string
+}
+
+func (s itemisedSplit) split(amount domain.Money) ([]domain.Split, error) {
+	if len(s.items) == 0 {
+		return nil, errors.New("at least one item is required for itemised split")
+	}
+
+	var splits []domain.Split
+	rest := amount
+	for _, item := range s.items {
+		item.Amount.Currency = amount.Currency
+		if !item.Amount.IsPositive() {
+			return nil, fmt.Errorf("item %q amount must be greater than zero", item.Description)
+		}
+		shares, err := equalSplit{userIDs: item.UserIDs}.split(item.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("item %q: %w", item.Description, err)
+		}
+		splits = append(splits, shares...)
+		rest = rest.Sub(item.Amount)
+	}
+
+	switch {
+	case rest.IsNegative():
+		return nil, errors.New("items add up to more than the expense amount")
+	case rest.IsPositive():
+		if len(s.restUserIDs) == 0 {
+			return nil, errors.New("user_ids are required to split the amount not covered by items")
+		}
+		shares, _ := equalSplit{userIDs: s.restUserIDs}.split(rest)
+		splits = append(splits, shares...)
+	}
+	return mergeSplits(splits), nil
+}
+
+// receiptSplit derives splits from an itemised receipt; see domain.ItemSplits
+type receiptSplit struct {
+	items []domain.ExpenseItem
+}
+
+func (s receiptSplit) split(amount domain.Money) ([]domain.Split, error) {
+	return domain.ItemSplits(s.items)
+}
+
+// mergeSplits combines splits for the same user, keeping first-seen order
+func mergeSplits(splits []domain.Split) []domain.Split {
+	index := make(map[string]int)
+	var merged []domain.Split
+	for _, split := range splits {
+		if i, ok := index[split.UserID]; ok {
+			merged[i].Amount = merged[i].Amount.Add(split.Amount)
+			continue
+		}
+		index[split.UserID] = len(merged)
+		merged = append(merged, split)
+	}
+	return merged
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+func paise(minor int64) domain.Money {
+	return domain.NewMoney(minor, domain.DefaultCurrency)
+}
+
+func TestSplitStrategies(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  domain.Money
+		spec    domain.SplitSpec
+		want    []domain.Split
+		wantErr bool
+	}{
+		{
+			name:   "exact",
+			amount: inr(100),
+			spec: domain.SplitSpec{Mode: domain.SplitExact, Splits: []domain.Split{
+				{UserID: "a", Amount: inr(70)}, {UserID: "b", Amount: inr(30)},
+			}},
+			want: []domain.Split{{UserID: "a", Amount: inr(70)}, {UserID: "b", Amount: inr(30)}},
+		},
+		{
+			name:   "equal gives leftover cents to the first users",
+			amount: inr(100),
+			spec:   domain.SplitSpec{Mode: domain.SplitEqual, UserIDs: []string{"a", "b", "c"}},
+			want: []domain.Split{
+				{UserID: "a", Amount: paise(3334)}, {UserID: "b", Amount: paise(3333)}, {UserID: "c", Amount: paise(3333)},
+			},
+		},
+		{
+			name:    "equal without users",
+			amount:  inr(100),
+			spec:    domain.SplitSpec{Mode: domain.SplitEqual},
+			wantErr: true,
+		},
+		{
+			name:   "percentage",
+			amount: inr(100),
+			spec: domain.SplitSpec{Mode: domain.SplitPercentage, Weights: []domain.SplitWeight{
+				{UserID: "a", Hundredths: 6000}, {UserID: "b", Hundredths: 4000},
+			}},
+			want: []domain.Split{{UserID: "a", Amount: inr(60)}, {UserID: "b", Amount: inr(40)}},
+		},
+		{
+			name:   "percentage rounds to the cent",
+			amount: paise(1000),
+			spec: domain.SplitSpec{Mode: domain.SplitPercentage, Weights: []domain.SplitWeight{
+				{UserID: "a", Hundredths: 3333}, {UserID: "b", Hundredths: 3333}, {UserID: "c", Hundredths: 3334},
+			}},
+			want: []domain.Split{
+				{UserID: "a", Amount: paise(333)}, {UserID: "b", Amount: paise(333)}, {UserID: "c", Amount: paise(334)},
+			},
+		},
+		{
+			name:   "percentage not totalling 100",
+			amount: inr(100),
+			spec: domain.SplitSpec{Mode: domain.SplitPercentage, Weights: []domain.SplitWeight{
+				{UserID: "a", Hundredths: 6000}, {UserID: "b", Hundredths: 3000},
+			}},
+			wantErr: true,
+		},
+		{
+			name:   "shares",
+			amount: inr(100),
+			spec: domain.SplitSpec{Mode: domain.SplitShares, Weights: []domain.SplitWeight{
+				{UserID: "a", Hundredths: 200}, {UserID: "b", Hundredths: 100}, {UserID: "c", Hundredths: 100},
+			}},
+			want: []domain.Split{{UserID: "a", Amount: inr(50)}, {UserID: "b", Amount: inr(25)}, {UserID: "c", Amount: inr(25)}},
+		},
+		{
+			name:   "shares with a zero weight",
+			amount: inr(100),
+			spec: domain.SplitSpec{Mode: domain.SplitShares, Weights: []domain.SplitWeight{
+				{UserID: "a", Hundredths: 100}, {UserID: "b", Hundredths: 0},
+			}},
+			wantErr: true,
+		},
+		{
+			name:   "itemised splits the rest equally",
+			amount: inr(100),
+			spec: domain.SplitSpec{
+				Mode:    domain.SplitItemised,
+				UserIDs: []string{"a", "b"},
+				Items: []domain.SplitItem{
+					{Description: "wine", Amount: inr(40), UserIDs: []string{"b"}},
+					{Description: "dessert", Amount: inr(20), UserIDs: []string{"a", "c"}},
+				},
+			},
+			want: []domain.Split{{UserID: "b", Amount: inr(60)}, {UserID: "a", Amount: inr(30)}, {UserID: "c", Amount: inr(10)}},
+		},
+		{
+			name:   "itemised items over the amount",
+			amount: inr(10),
+			spec: domain.SplitSpec{Mode: domain.SplitItemised, Items: []domain.SplitItem{
+				{Description: "wine", Amount: inr(40), UserIDs: []string{"b"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:   "itemised rest without users",
+			amount: inr(100),
+			spec: domain.SplitSpec{Mode: domain.SplitItemised, Items: []domain.SplitItem{
+				{Description: "wine", Amount: inr(40), UserIDs: []string{"b"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:   "itemised receipt",
+			amount: inr(110),
+			spec: domain.SplitSpec{Mode: domain.SplitItemised, Receipt: []domain.ExpenseItem{
+				{Name: "rice", Quantity: 2, Price: inr(30), Kind: domain.ItemProduct, UserIDs: []string{"a", "b"}},
+				{Name: "paneer", Quantity: 1, Price: inr(40), Kind: domain.ItemProduct, UserIDs: []string{"b"}},
+				{Name: "tip", Quantity: 1, Price: inr(10), Kind: domain.ItemTip},
+			}},
+			want: []domain.Split{{UserID: "a", Amount: inr(33)}, {UserID: "b", Amount: inr(77)}},
+		},
+		{
+			name:   "receipt and items",
+			amount: inr(100),
+			spec: domain.SplitSpec{
+				Mode:    domain.SplitItemised,
+				Items:   []domain.SplitItem{{Description: "wine", Amount: inr(40), UserIDs: []string{"b"}}},
+				Receipt: []domain.ExpenseItem{{Name: "rice", Quantity: 1, Price: inr(100), Kind: domain.ItemProduct, UserIDs: []string{"a"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "receipt split equally",
+			amount: inr(100),
+			spec: domain.SplitSpec{Mode: domain.SplitEqual, UserIDs: []string{"a"}, Receipt: []domain.ExpenseItem{
+				{Name: "rice", Quantity: 1, Price: inr(100), Kind: domain.ItemProduct, UserIDs: []string{"a"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown mode",
+			amount:  inr(100),
+			spec:    domain.SplitSpec{Mode: "random"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := newSplitStrategy(tt.spec)
+			var got []domain.Split
+			if err == nil {
+				got, err = strategy.split(tt.amount)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExpenseUseCase_CreateSplitExpense(t *testing.T) {
+	userRepo := newMockUserRepository()
+	expenseUC := NewExpenseUseCase(newMockExpenseRepository(), memory.NewExpenseEventMemoryRepository(), userRepo, newMockGroupRepository(), memory.NewCategoryMemoryRepository(), memory.NewPaymentMemoryRepository(), memory.NewExchangeRateMemoryRepository(), domain.DefaultCurrency)
+	ctx := context.Background()
+
+	_ = userRepo.Create(ctx, &domain.User{ID: "user1", Name: "User1", Email: "user1@test.com"})
+	_ = userRepo.Create(ctx, &domain.User{ID: "user2", Name: "User2", Email: "user2@test.com"})
+
+	spec := domain.SplitSpec{Mode: domain.SplitShares, Weights: []domain.SplitWeight{
+		{UserID: "user1", Hundredths: 150}, {UserID: "user2", Hundredths: 100},
+	}}
+	expense, err := expenseUC.CreateSplitExpense(ctx, "", "Groceries", "food", "user1", domain.Money{Minor: 10001}, "", spec)
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	if expense.Splits[0].Amount != paise(6001) || expense.Splits[1].Amount != paise(4000) {
+		t.Errorf("Expected 60.01 and 40.00, got %v", expense.Splits)
+	}
+}
@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
+)
+
+type lifecycleFixture struct {
+	users       UserUseCase
+	expenses    ExpenseUseCase
+	expenseRepo *memory.ExpenseMemoryRepository
+	payments    *memory.PaymentMemoryRepository
+	groups      *memory.GroupMemoryRepository
+	auth        *memory.AuthMemoryRepository
+	claims      *capturingSender
+}
+
+func newLifecycleFixture(t *testing.T) *lifecycleFixture {
+	t.Helper()
+	repos := newTestRepos(t, "alice", "bob", "bob2")
+	claims := &capturingSender{}
+	return &lifecycleFixture{
+		users:       NewUserUseCase(repos.users, repos.expenses, repos.payments, repos.groups, repos.auth, claims, time.Hour),
+		expenses:    repos.expenseUseCase(),
+		expenseRepo: repos.expenses,
+		payments:    repos.payments,
+		groups:      repos.groups,
+		auth:        repos.auth,
+		claims:      claims,
+	}
+}
+
+func TestUserUseCase_DeactivateUser(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := context.Background()
+
+	user, err := f.users.DeactivateUser(ctx, "bob")
+	if err != nil {
+		t.Fatalf("DeactivateUser() failed: %v", err)
+	}
+	if user.IsActive() {
+		t.Fatal("Expected bob to be deactivated")
+	}
+
+	_, err = f.expenses.CreateExpenseWithEqualSplit(ctx, "", "Dinner", "food", "alice", inr(100), "", []string{"alice", "bob"})
+	if !errors.Is(err, domain.ErrUserDeactivated) {
+		t.Fatalf("Expected ErrUserDeactivated, got %v", err)
+	}
+
+	if _, err := f.users.ReactivateUser(ctx, "bob"); err != nil {
+		t.Fatalf("ReactivateUser() failed: %v", err)
+	}
+	if _, err := f.expenses.CreateExpenseWithEqualSplit(ctx, "", "Dinner", "food", "alice", inr(100), "", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Expected reactivated user to be split with, got %v", err)
+	}
+}
+
+func TestUserUseCase_DeactivateUser_EndsSessions(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := context.Background()
+
+	session := &domain.Session{TokenHash: hashToken("bob-token"), UserID: "bob", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	if err := f.auth.CreateSession(ctx, session); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	if _, err := f.users.DeactivateUser(ctx, "bob"); err != nil {
+		t.Fatalf("DeactivateUser() failed: %v", err)
+	}
+	if _, err := f.auth.GetSession(ctx, session.TokenHash); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected bob's session to be deleted, got %v", err)
+	}
+}
+
+func TestUserUseCase_DeactivateUser_OtherUser(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := domain.WithUserID(context.Background(), "alice")
+
+	if _, err := f.users.DeactivateUser(ctx, "bob"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestUserUseCase_DeleteUser(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.expenses.CreateExpenseWithEqualSplit(ctx, "", "Dinner", "food", "alice", inr(100), "", []string{"alice", "bob"}); err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+
+	if err := f.users.DeleteUser(ctx, "bob"); !errors.Is(err, domain.ErrUserHasBalance) {
+		t.Fatalf("Expected ErrUserHasBalance, got %v", err)
+	}
+
+	payment := &domain.Payment{ID: "p1", From: "bob", To: "alice", Amount: inr(50), Date: time.Now()}
+	if err := f.payments.Create(ctx, payment); err != nil {
+		t.Fatalf("Failed to create payment: %v", err)
+	}
+	if err := f.users.DeleteUser(ctx, "bob"); err != nil {
+		t.Fatalf("DeleteUser() failed once settled: %v", err)
+	}
+	bob, err := f.users.GetUser(ctx, "bob")
+	if err != nil {
+		t.Fatalf("Expected a user with history to be kept, got %v", err)
+	}
+	if bob.IsActive() {
+		t.Error("Expected a settled user with history to be deactivated")
+	}
+
+	if err := f.users.DeleteUser(ctx, "bob2"); err != nil {
+		t.Fatalf("DeleteUser() failed for a user without history: %v", err)
+	}
+	if _, err := f.users.GetUser(ctx, "bob2"); err == nil {
+		t.Fatal("Expected deleted user to be gone")
+	}
+}
+
+func TestUserUseCase_MergeUsers(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := context.Background()
+
+	dinner, err := f.expenses.CreateExpenseWithEqualSplit(ctx, "", "Dinner", "food", "bob2", inr(90), "", []string{"alice", "bob", "bob2"})
+	if err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	payment := &domain.Payment{ID: "p1", From: "alice", To: "bob2", Amount: inr(10), Date: time.Now()}
+	if err := f.payments.Create(ctx, payment); err != nil {
+		t.Fatalf("Failed to create payment: %v", err)
+	}
+
+	if _, err := f.users.MergeUsers(ctx, "bob2", "bob2", ""); err == nil {
+		t.Fatal("Expected merging a user into themselves to fail")
+	}
+
+	kept, err := f.users.MergeUsers(ctx, "bob2", "bob", "")
+	if err != nil {
+		t.Fatalf("MergeUsers() failed: %v", err)
+	}
+	if kept.ID != "bob" {
+		t.Errorf("Expected kept user bob, got %s", kept.ID)
+	}
+	if _, err := f.users.GetUser(ctx, "bob2"); err == nil {
+		t.Error("Expected duplicate user to be deleted")
+	}
+
+	left, err := f.expenseRepo.GetByFilters(ctx, domain.ExpenseFilter{UserID: "bob2"})
+	if err != nil {
+		t.Fatalf("GetByFilters() failed: %v", err)
+	}
+	if len(left) != 0 {
+		t.Errorf("Expected no expenses left for the duplicate user, got %d", len(left))
+	}
+	moved, err := f.expenseRepo.GetByFilters(ctx, domain.ExpenseFilter{PaidBy: "bob"})
+	if err != nil {
+		t.Fatalf("GetByFilters() failed: %v", err)
+	}
+	if len(moved) != 1 || len(moved[0].Splits) != 2 {
+		t.Fatalf("Expected one expense paid by bob with two splits, got %+v", moved)
+	}
+	for _, split := range moved[0].Splits {
+		if split.UserID == "bob" && split.Amount != inr(60) {
+			t.Errorf("Expected bob's two shares to be combined into %s, got %s", inr(60), split.Amount)
+		}
+	}
+
+	payments, err := f.payments.GetByUserID(ctx, "bob")
+	if err != nil {
+		t.Fatalf("GetByUserID() failed: %v", err)
+	}
+	if len(payments) != 1 || payments[0].To != "bob" {
+		t.Errorf("Expected the payment to move to bob, got %+v", payments)
+	}
+
+	history, err := f.expenses.GetExpenseHistory(ctx, dinner.ID)
+	if err != nil {
+		t.Fatalf("GetExpenseHistory() failed: %v", err)
+	}
+	if len(history) != 2 || history[1].Action != domain.ExpenseUpdated {
+		t.Fatalf("Expected the merge to be recorded as an update, got %+v", history)
+	}
+	if merged := history[1]; merged.Before.PaidBy != "bob2" || merged.After.PaidBy != "bob" {
+		t.Errorf("Expected the payer to change from bob2 to bob, got %s to %s", merged.Before.PaidBy, merged.After.PaidBy)
+	}
+}
+
+func TestUserUseCase_MergeUsers_SignedIn(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := context.Background()
+
+	session := &domain.Session{TokenHash: hashToken("bob2-token"), UserID: "bob2", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	if err := f.auth.CreateSession(ctx, session); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// The duplicate can't push itself into someone else's account
+	if _, err := f.users.MergeUsers(domain.WithUserID(ctx, "bob2"), "bob2", "bob", ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden merging from the duplicate, got %v", err)
+	}
+	// The kept account must show it also owns the duplicate
+	if _, err := f.users.MergeUsers(domain.WithUserID(ctx, "bob"), "bob2", "bob", ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden without a token, got %v", err)
+	}
+	if _, err := f.users.MergeUsers(domain.WithUserID(ctx, "alice"), "bob2", "alice", "bob2-token"); err != nil {
+		t.Fatalf("Expected a merge with the duplicate's token to succeed, got %v", err)
+	}
+}
+
+func TestUserUseCase_GuestSharesExpenses(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := context.Background()
+
+	guest, err := f.users.CreateGuest(ctx, "Dave", "+91 98450 00000")
+	if err != nil {
+		t.Fatalf("CreateGuest() failed: %v", err)
+	}
+	if !guest.Guest || guest.Email != "" {
+		t.Fatalf("Expected a guest without email, got %+v", guest)
+	}
+
+	if _, err := f.expenses.CreateExpenseWithEqualSplit(ctx, "", "Dinner", "food", guest.ID, inr(100), "", []string{"alice", guest.ID}); err != nil {
+		t.Fatalf("Expected a guest to pay and share, got %v", err)
+	}
+
+	summary, err := f.expenses.CalculateBalances(ctx, domain.SettlementMinimal)
+	if err != nil {
+		t.Fatalf("CalculateBalances() failed: %v", err)
+	}
+	if len(summary.Settlements) != 1 || summary.Settlements[0].From != "alice" || summary.Settlements[0].To != guest.ID {
+		t.Errorf("Expected alice to settle up with the guest, got %+v", summary.Settlements)
+	}
+}
+
+// newDinnerGuest creates a guest who shares a dinner with alice, in a group
+// with her
+func newDinnerGuest(t *testing.T, f *lifecycleFixture, name string) *domain.User {
+	t.Helper()
+	ctx := context.Background()
+
+	guest, err := f.users.CreateGuest(ctx, name, "")
+	if err != nil {
+		t.Fatalf("CreateGuest() failed: %v", err)
+	}
+	group := &domain.Group{ID: "dinner", Name: "Dinner", BaseCurrency: domain.DefaultCurrency, Members: []string{"alice", guest.ID}}
+	if err := f.groups.Create(ctx, group); err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	if _, err := f.expenses.CreateExpenseWithEqualSplit(ctx, group.ID, "Dinner", "food", "alice", inr(100), "", []string{"alice", guest.ID}); err != nil {
+		t.Fatalf("Failed to create expense: %v", err)
+	}
+	return guest
+}
+
+func TestUserUseCase_ClaimGuest(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := context.Background()
+	guest := newDinnerGuest(t, f, "Dave")
+	alice := domain.WithUserID(ctx, "alice")
+
+	// Only the guest's groups' members may ask, and nobody signed in may
+	// skip the confirmation
+	if err := f.users.RequestGuestClaim(domain.WithUserID(ctx, "bob"), guest.ID, "bob@test.com"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden outside the guest's groups, got %v", err)
+	}
+	if _, err := f.users.ClaimGuest(alice, guest.ID, "dave@test.com"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden claiming without confirmation, got %v", err)
+	}
+
+	if err := f.users.RequestGuestClaim(alice, guest.ID, "dave@test.com"); err != nil {
+		t.Fatalf("RequestGuestClaim() failed: %v", err)
+	}
+	unchanged, err := f.users.GetUser(ctx, guest.ID)
+	if err != nil {
+		t.Fatalf("GetUser() failed: %v", err)
+	}
+	if !unchanged.Guest {
+		t.Fatal("Expected the guest to stay a guest until the claim is confirmed")
+	}
+
+	user, err := f.users.ConfirmGuestClaim(ctx, f.claims.token)
+	if err != nil {
+		t.Fatalf("ConfirmGuestClaim() failed: %v", err)
+	}
+	if user.ID != guest.ID || user.Guest || user.Email != "dave@test.com" {
+		t.Errorf("Expected the guest to become a user in place, got %+v", user)
+	}
+	if _, err := f.users.ConfirmGuestClaim(ctx, f.claims.token); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken confirming twice, got %v", err)
+	}
+	if err := f.users.RequestGuestClaim(alice, guest.ID, "dave@test.com"); !errors.Is(err, domain.ErrNotGuest) {
+		t.Errorf("Expected ErrNotGuest on a second claim, got %v", err)
+	}
+}
+
+func TestUserUseCase_ClaimGuest_ExistingAccount(t *testing.T) {
+	f := newLifecycleFixture(t)
+	ctx := context.Background()
+	guest := newDinnerGuest(t, f, "Bob at dinner")
+
+	// A guest can't be merged straight into an account, even one's own
+	if _, err := f.users.MergeUsers(domain.WithUserID(ctx, "alice"), guest.ID, "alice", ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden merging a guest, got %v", err)
+	}
+
+	if err := f.users.RequestGuestClaim(domain.WithUserID(ctx, "alice"), guest.ID, "bob@test.com"); err != nil {
+		t.Fatalf("RequestGuestClaim() failed: %v", err)
+	}
+	user, err := f.users.ConfirmGuestClaim(ctx, f.claims.token)
+	if err != nil {
+		t.Fatalf("ConfirmGuestClaim() failed: %v", err)
+	}
+	if user.ID != "bob" {
+		t.Errorf("Expected the guest to be merged into bob, got %s", user.ID)
+	}
+	expenses, err := f.expenseRepo.GetByFilters(ctx, domain.ExpenseFilter{Participant: "bob"})
+	if err != nil {
+		t.Fatalf("GetByFilters() failed: %v", err)
+	}
+	if len(expenses) != 1 {
+		t.Errorf("Expected bob to take over the guest's expense, got %d", len(expenses))
+	}
+}
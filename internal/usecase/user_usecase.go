@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,20 +12,52 @@ import (
 	"github.com/pavanrkadave/homies/internal/repository"
 )
 
+// GuestClaimSender delivers the token that confirms a guest claim to the
+// owner of the claimed email
+type GuestClaimSender interface {
+	SendGuestClaim(ctx context.Context, guest *domain.User, email, token string, expiresAt time.Time) error
+}
+
 type UserUseCase interface {
 	CreateUser(ctx context.Context, name, email string) (*domain.User, error)
 	GetUser(ctx context.Context, id string) (*domain.User, error)
 	GetAllUsers(ctx context.Context) ([]*domain.User, error)
+	ListUsers(ctx context.Context, page domain.PageRequest) (*domain.UserPage, error)
 	UpdateUser(ctx context.Context, id, name, email string) (*domain.User, error)
+	DeactivateUser(ctx context.Context, id string) (*domain.User, error)
+	ReactivateUser(ctx context.Context, id string) (*domain.User, error)
+	DeleteUser(ctx context.Context, id string) error
+	MergeUsers(ctx context.Context, fromID, intoID, fromToken string) (*domain.User, error)
+	CreateGuest(ctx context.Context, name, contact string) (*domain.User, error)
+	RequestGuestClaim(ctx context.Context, guestID, email string) error
+	ConfirmGuestClaim(ctx context.Context, token string) (*domain.User, error)
+	ClaimGuest(ctx context.Context, guestID, email string) (*domain.User, error)
 }
 
 type userUseCase struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	expenseRepo repository.ExpenseRepository
+	paymentRepo repository.PaymentRepository
+	groupRepo   repository.GroupRepository
+	authRepo    repository.AuthRepository
+	claims      GuestClaimSender
+	claimTTL    time.Duration
 }
 
-func NewUserUseCase(userRepo repository.UserRepository) UserUseCase {
+// NewUserUseCase creates the user use case. Expenses and payments are read to
+// check a user is settled up before they are deleted; sessions prove who owns
+// an account that is merged away. A guest may be claimed by their groups'
+// members, and claims are confirmed with a token sent through claims that
+// lasts claimTTL.
+func NewUserUseCase(userRepo repository.UserRepository, expenseRepo repository.ExpenseRepository, paymentRepo repository.PaymentRepository, groupRepo repository.GroupRepository, authRepo repository.AuthRepository, claims GuestClaimSender, claimTTL time.Duration) UserUseCase {
 	return &userUseCase{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		expenseRepo: expenseRepo,
+		paymentRepo: paymentRepo,
+		groupRepo:   groupRepo,
+		authRepo:    authRepo,
+		claims:      claims,
+		claimTTL:    claimTTL,
 	}
 }
 
@@ -65,12 +100,22 @@ func (u *userUseCase) GetAllUsers(ctx context.Context) ([]*domain.User, error) {
 	return users, nil
 }
 
+func (u *userUseCase) ListUsers(ctx context.Context, page domain.PageRequest) (*domain.UserPage, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+	return u.userRepo.List(ctx, page)
+}
+
 func (u *userUseCase) UpdateUser(ctx context.Context, id, name, email string) (*domain.User, error) {
 	// Get existing user
 	user, err := u.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := authorizeUserUpdate(ctx, user); err != nil {
+		return nil, err
+	}
 
 	// Check if email is being changed and if new email already exists
 	if email != user.Email {
@@ -97,3 +142,282 @@ func (u *userUseCase) UpdateUser(ctx context.Context, id, name, email string) (*
 
 	return user, nil
 }
+
+// DeactivateUser marks a user as having left. Their expenses and payments are
+// kept, but they can't be part of new expenses.
+func (u *userUseCase) DeactivateUser(ctx context.Context, id string) (*domain.User, error) {
+	return u.setDeactivated(ctx, id, true)
+}
+
+// ReactivateUser undoes DeactivateUser
+func (u *userUseCase) ReactivateUser(ctx context.Context, id string) (*domain.User, error) {
+	return u.setDeactivated(ctx, id, false)
+}
+
+func (u *userUseCase) setDeactivated(ctx context.Context, id string, deactivated bool) (*domain.User, error) {
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeUserChange(ctx, user); err != nil {
+		return nil, err
+	}
+	if user.IsActive() != deactivated {
+		return user, nil
+	}
+
+	updated := *user
+	updated.DeactivatedAt = nil
+	if deactivated {
+		now := time.Now()
+		updated.DeactivatedAt = &now
+	}
+	updated.UpdatedAt = time.Now()
+	if err := u.userRepo.Update(ctx, &updated); err != nil {
+		return nil, err
+	}
+	if deactivated {
+		if err := u.authRepo.DeleteUserSessions(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return &updated, nil
+}
+
+// DeleteUser removes a user who is settled up. One who takes part in recorded
+// expenses or payments is deactivated instead, so shared history stays
+// intact; one with an outstanding balance is refused.
+func (u *userUseCase) DeleteUser(ctx context.Context, id string) error {
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeUserChange(ctx, user); err != nil {
+		return err
+	}
+
+	expenses, err := u.expenseRepo.GetByFilters(ctx, domain.ExpenseFilter{UserID: id})
+	if err != nil {
+		return err
+	}
+	payments, err := u.paymentRepo.GetByUserID(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, balance := range userNetBalances(id, expenses, payments) {
+		if !balance.IsZero() {
+			return fmt.Errorf("%w: %s", domain.ErrUserHasBalance, balance)
+		}
+	}
+	if len(expenses) > 0 || len(payments) > 0 {
+		_, err := u.setDeactivated(ctx, id, true)
+		return err
+	}
+
+	return u.userRepo.Delete(ctx, id)
+}
+
+// MergeUsers folds a duplicate account into the one that is kept: every
+// expense, split, payment and group membership of fromID moves to intoID, and
+// fromID is deleted. It returns the kept user. fromToken is a session token of
+// fromID, which a signed-in user needs to show the duplicate is theirs.
+func (u *userUseCase) MergeUsers(ctx context.Context, fromID, intoID, fromToken string) (*domain.User, error) {
+	if fromID == "" || intoID == "" {
+		return nil, errors.New("both users are required")
+	}
+	if fromID == intoID {
+		return nil, errors.New("cannot merge a user into themselves")
+	}
+
+	from, err := u.userRepo.GetByID(ctx, fromID)
+	if err != nil {
+		return nil, err
+	}
+	into, err := u.userRepo.GetByID(ctx, intoID)
+	if err != nil {
+		return nil, err
+	}
+	return u.merge(ctx, from, into, u.sessionUserID(ctx, fromToken))
+}
+
+// sessionUserID returns the user an unexpired session token belongs to, or
+// "" for any other token
+func (u *userUseCase) sessionUserID(ctx context.Context, token string) string {
+	if token == "" {
+		return ""
+	}
+	session, err := u.authRepo.GetSession(ctx, hashToken(token))
+	if err != nil || session.Expired(time.Now()) {
+		return ""
+	}
+	return session.UserID
+}
+
+// merge folds from into into. signedInAs is the user the caller holds a
+// session for besides their own, if any.
+func (u *userUseCase) merge(ctx context.Context, from, into *domain.User, signedInAs string) (*domain.User, error) {
+	if err := authorizeUserMerge(ctx, from, into, signedInAs); err != nil {
+		return nil, err
+	}
+	return u.mergeInto(ctx, from, into)
+}
+
+// mergeInto folds from into into once the caller is known to be allowed to
+func (u *userUseCase) mergeInto(ctx context.Context, from, into *domain.User) (*domain.User, error) {
+	if !into.IsActive() {
+		return nil, fmt.Errorf("cannot merge into %s: %w", into.ID, domain.ErrUserDeactivated)
+	}
+
+	actorID, _ := domain.UserIDFromContext(ctx)
+	if err := u.userRepo.Merge(ctx, from.ID, into.ID, actorID); err != nil {
+		return nil, err
+	}
+	return u.userRepo.GetByID(ctx, into.ID)
+}
+
+// CreateGuest adds someone without an account, by name and an optional
+// contact, who can then pay and share in expenses
+func (u *userUseCase) CreateGuest(ctx context.Context, name, contact string) (*domain.User, error) {
+	now := time.Now()
+	guest := &domain.User{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		Contact:   strings.TrimSpace(contact),
+		Guest:     true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := guest.Validate(); err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.Create(ctx, guest); err != nil {
+		return nil, err
+	}
+	return guest, nil
+}
+
+// RequestGuestClaim starts turning a guest into a full user with the given
+// email. Only someone who shares a group with the guest may ask, and nothing
+// changes until the owner of the email confirms with the token sent to it,
+// see ConfirmGuestClaim.
+func (u *userUseCase) RequestGuestClaim(ctx context.Context, guestID, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return errors.New("user email is required")
+	}
+	guest, err := u.userRepo.GetByID(ctx, guestID)
+	if err != nil {
+		return err
+	}
+	if !guest.Guest {
+		return domain.ErrNotGuest
+	}
+	groups, err := u.groupRepo.GetByUserID(ctx, guest.ID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeGuestClaim(ctx, groups); err != nil {
+		return err
+	}
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	claim := &domain.GuestClaim{
+		TokenHash: tokenHash,
+		GuestID:   guest.ID,
+		Email:     email,
+		CreatedAt: now,
+		ExpiresAt: now.Add(u.claimTTL),
+	}
+	if err := u.authRepo.CreateGuestClaim(ctx, claim); err != nil {
+		return err
+	}
+	return u.claims.SendGuestClaim(ctx, guest, email, token, claim.ExpiresAt)
+}
+
+// ConfirmGuestClaim completes a claim with the token sent to its email, which
+// proves the email's owner agrees to it. It returns the claimed user.
+func (u *userUseCase) ConfirmGuestClaim(ctx context.Context, token string) (*domain.User, error) {
+	claim, err := u.authRepo.ConsumeGuestClaim(ctx, hashToken(token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	guest, err := u.userRepo.GetByID(ctx, claim.GuestID)
+	if err != nil {
+		return nil, err
+	}
+	return u.claim(ctx, guest, claim.Email)
+}
+
+// ClaimGuest turns a guest into a full user with the given email at once. It
+// is for administrators; signed-in users go through RequestGuestClaim.
+func (u *userUseCase) ClaimGuest(ctx context.Context, guestID, email string) (*domain.User, error) {
+	if err := authorizeUnconfirmedClaim(ctx); err != nil {
+		return nil, err
+	}
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, errors.New("user email is required")
+	}
+	guest, err := u.userRepo.GetByID(ctx, guestID)
+	if err != nil {
+		return nil, err
+	}
+	return u.claim(ctx, guest, email)
+}
+
+// claim turns the guest into a full user with the email, keeping their
+// history. If an account already has the email, the guest is merged into it;
+// otherwise the guest becomes a user in place and can sign in with the email.
+func (u *userUseCase) claim(ctx context.Context, guest *domain.User, email string) (*domain.User, error) {
+	if !guest.Guest {
+		return nil, domain.ErrNotGuest
+	}
+
+	if existing, err := u.userRepo.GetByEmail(ctx, email); err == nil && existing != nil {
+		return u.mergeInto(ctx, guest, existing)
+	}
+
+	claimed := *guest
+	claimed.Email = email
+	claimed.Guest = false
+	claimed.UpdatedAt = time.Now()
+	if err := claimed.Validate(); err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.Update(ctx, &claimed); err != nil {
+		return nil, err
+	}
+	return &claimed, nil
+}
+
+// userNetBalances returns what the user is owed, negative if they owe, in
+// each currency they have expenses or payments in
+func userNetBalances(userID string, expenses []*domain.Expense, payments []*domain.Payment) map[domain.Currency]domain.Money {
+	balances := make(map[domain.Currency]domain.Money)
+	add := func(amount domain.Money) {
+		balances[amount.Currency] = balances[amount.Currency].Add(amount)
+	}
+	for _, expense := range expenses {
+		if expense.PaidBy == userID {
+			add(expense.Amount)
+		}
+		for _, split := range expense.Splits {
+			if split.UserID == userID {
+				add(split.Amount.Neg())
+			}
+		}
+	}
+	for _, payment := range payments {
+		if payment.From == userID {
+			add(payment.Amount)
+		}
+		if payment.To == userID {
+			add(payment.Amount.Neg())
+		}
+	}
+	return balances
+}
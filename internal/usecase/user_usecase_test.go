@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/pavanrkadave/homies/internal/domain"
+	"github.com/pavanrkadave/homies/internal/repository/memory"
 )
 
 type mockUserRepository struct {
@@ -42,6 +44,12 @@ func (m *mockUserRepository) GetAll(ctx context.Context) ([]*domain.User, error)
 	return users, nil
 }
 
+// List ignores the page, returning every user on one page
+func (m *mockUserRepository) List(ctx context.Context, page domain.PageRequest) (*domain.UserPage, error) {
+	users, _ := m.GetAll(ctx)
+	return &domain.UserPage{Users: users}, nil
+}
+
 func (m *mockUserRepository) Update(ctx context.Context, user *domain.User) error {
 	if _, ok := m.users[user.ID]; !ok {
 		return errors.New("user not found")
@@ -50,6 +58,20 @@ func (m *mockUserRepository) Update(ctx context.Context, user *domain.User) erro
 	return nil
 }
 
+// Delete refuses nothing; the use case checks for history first
+func (m *mockUserRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.users[id]; !ok {
+		return errors.New("user not found")
+	}
+	delete(m.users, id)
+	return nil
+}
+
+// Merge only deletes fromID; it keeps no records to move
+func (m *mockUserRepository) Merge(ctx context.Context, fromID, intoID, actorID string) error {
+	return m.Delete(ctx, fromID)
+}
+
 func newMockUserRepository() *mockUserRepository {
 	return &mockUserRepository{
 		users: make(map[string]*domain.User),
@@ -59,7 +81,7 @@ func newMockUserRepository() *mockUserRepository {
 func Test_userUseCase_CreateUser(t *testing.T) {
 
 	repo := newMockUserRepository()
-	userUseCase := NewUserUseCase(repo)
+	userUseCase := NewUserUseCase(repo, newMockExpenseRepository(), memory.NewPaymentMemoryRepository(), memory.NewGroupMemoryRepository(), memory.NewAuthMemoryRepository(), &capturingSender{}, time.Hour)
 	ctx := context.Background()
 
 	user, err := userUseCase.CreateUser(ctx, "Pavan", "pavan@email.com")
@@ -78,7 +100,7 @@ func Test_userUseCase_CreateUser(t *testing.T) {
 
 func Test_userUseCase_CreateUser_ValidationError(t *testing.T) {
 	repo := newMockUserRepository()
-	userUseCase := NewUserUseCase(repo)
+	userUseCase := NewUserUseCase(repo, newMockExpenseRepository(), memory.NewPaymentMemoryRepository(), memory.NewGroupMemoryRepository(), memory.NewAuthMemoryRepository(), &capturingSender{}, time.Hour)
 	ctx := context.Background()
 
 	_, err := userUseCase.CreateUser(ctx, "", "pavan@email.com")
@@ -94,7 +116,7 @@ func Test_userUseCase_CreateUser_ValidationError(t *testing.T) {
 
 func Test_userUseCase_UpdateUser(t *testing.T) {
 	repo := newMockUserRepository()
-	userUseCase := NewUserUseCase(repo)
+	userUseCase := NewUserUseCase(repo, newMockExpenseRepository(), memory.NewPaymentMemoryRepository(), memory.NewGroupMemoryRepository(), memory.NewAuthMemoryRepository(), &capturingSender{}, time.Hour)
 	ctx := context.Background()
 
 	// First, create a user
@@ -124,7 +146,7 @@ func Test_userUseCase_UpdateUser(t *testing.T) {
 
 func Test_userUseCase_UpdateUser_UserNotFound(t *testing.T) {
 	repo := newMockUserRepository()
-	userUseCase := NewUserUseCase(repo)
+	userUseCase := NewUserUseCase(repo, newMockExpenseRepository(), memory.NewPaymentMemoryRepository(), memory.NewGroupMemoryRepository(), memory.NewAuthMemoryRepository(), &capturingSender{}, time.Hour)
 	ctx := context.Background()
 
 	// Try to update a user that doesn't exist
@@ -136,7 +158,7 @@ func Test_userUseCase_UpdateUser_UserNotFound(t *testing.T) {
 
 func Test_userUseCase_UpdateUser_EmailAlreadyExists(t *testing.T) {
 	repo := newMockUserRepository()
-	userUseCase := NewUserUseCase(repo)
+	userUseCase := NewUserUseCase(repo, newMockExpenseRepository(), memory.NewPaymentMemoryRepository(), memory.NewGroupMemoryRepository(), memory.NewAuthMemoryRepository(), &capturingSender{}, time.Hour)
 	ctx := context.Background()
 
 	// Create two users
@@ -153,3 +175,24 @@ func Test_userUseCase_UpdateUser_EmailAlreadyExists(t *testing.T) {
 		t.Fatalf("Expected ErrEmailAlreadyExists, got: %v", err)
 	}
 }
+
+func Test_userUseCase_UpdateUser_OtherUser(t *testing.T) {
+	repo := newMockUserRepository()
+	userUseCase := NewUserUseCase(repo, newMockExpenseRepository(), memory.NewPaymentMemoryRepository(), memory.NewGroupMemoryRepository(), memory.NewAuthMemoryRepository(), &capturingSender{}, time.Hour)
+	ctx := context.Background()
+
+	// Create two users
+	user1, _ := userUseCase.CreateUser(ctx, "User1", "user1@email.com")
+	user2, _ := userUseCase.CreateUser(ctx, "User2", "user2@email.com")
+
+	// Signed in as user2, try to take over user1's email
+	_, err := userUseCase.UpdateUser(domain.WithUserID(ctx, user2.ID), user1.ID, "User1", "attacker@email.com")
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden, got: %v", err)
+	}
+
+	// Signed in as user1, the update goes through
+	if _, err := userUseCase.UpdateUser(domain.WithUserID(ctx, user1.ID), user1.ID, "User One", "user1@email.com"); err != nil {
+		t.Fatalf("userUseCase.UpdateUser returned error: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+-- Create groups table
+CREATE TABLE IF NOT EXISTS groups (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Create group members table
+CREATE TABLE IF NOT EXISTS group_members (
+    group_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, user_id),
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+    );
+
+-- Scope expenses to a group (NULL keeps legacy expenses in the shared pool)
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS group_id VARCHAR(36) REFERENCES groups(id);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_group_members_user_id ON group_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_expenses_group_id ON expenses(group_id);
@@ -0,0 +1,14 @@
+-- Record the currency each expense was paid in
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'INR';
+
+-- Reporting currency for each group
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS base_currency VARCHAR(3) NOT NULL DEFAULT 'INR';
+
+-- Create historical exchange rates table
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(18, 8) NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL,
+    PRIMARY KEY (from_currency, to_currency, effective_date)
+    );
@@ -0,0 +1,21 @@
+-- Create payments table for recorded settle-ups between users
+CREATE TABLE IF NOT EXISTS payments (
+    id VARCHAR(36) PRIMARY KEY,
+    group_id VARCHAR(36) REFERENCES groups(id),
+    from_user_id VARCHAR(36) NOT NULL,
+    to_user_id VARCHAR(36) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'INR',
+    date TIMESTAMP NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (from_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (to_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (from_user_id <> to_user_id)
+    );
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_payments_from_user_id ON payments(from_user_id);
+CREATE INDEX IF NOT EXISTS idx_payments_to_user_id ON payments(to_user_id);
+CREATE INDEX IF NOT EXISTS idx_payments_group_id ON payments(group_id);
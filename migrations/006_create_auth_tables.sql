@@ -0,0 +1,29 @@
+-- Create password credentials table (users without a row log in by magic link)
+CREATE TABLE IF NOT EXISTS user_credentials (
+    user_id VARCHAR(36) PRIMARY KEY,
+    password_hash TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+    );
+
+-- Create sessions table; only a SHA-256 hash of each bearer token is stored
+CREATE TABLE IF NOT EXISTS sessions (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+    );
+
+-- Create single-use magic link tokens table
+CREATE TABLE IF NOT EXISTS magic_links (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+    );
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
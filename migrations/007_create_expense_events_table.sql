@@ -0,0 +1,27 @@
+-- Create append-only change history for expenses. expense_id has no foreign
+-- key so the history outlives the expense it describes.
+CREATE TABLE IF NOT EXISTS expense_events (
+    seq BIGSERIAL UNIQUE,
+    id VARCHAR(36) PRIMARY KEY,
+    expense_id VARCHAR(36) NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('created', 'updated', 'deleted')),
+    actor_id VARCHAR(36),
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_expense_events_expense_id ON expense_events(expense_id, created_at);
+
+-- Reject edits to recorded history
+CREATE OR REPLACE FUNCTION expense_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'expense_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS expense_events_no_change ON expense_events;
+CREATE TRIGGER expense_events_no_change
+    BEFORE UPDATE OR DELETE ON expense_events
+    FOR EACH ROW EXECUTE FUNCTION expense_events_append_only();
@@ -0,0 +1,10 @@
+-- Deleted expenses stay in the table until purged; every live query filters
+-- on deleted_at IS NULL
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_deleted_at ON expenses(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Allow restores in the change history
+ALTER TABLE expense_events DROP CONSTRAINT IF EXISTS expense_events_action_check;
+ALTER TABLE expense_events ADD CONSTRAINT expense_events_action_check
+    CHECK (action IN ('created', 'updated', 'deleted', 'restored'));
@@ -0,0 +1,26 @@
+-- Create recurring expense templates
+CREATE TABLE IF NOT EXISTS recurring_expenses (
+    id VARCHAR(36) PRIMARY KEY,
+    group_id VARCHAR(36) REFERENCES groups(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'INR',
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    paid_by VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    schedule TEXT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    last_occurrence DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+    );
+
+-- Fixed splits carry an amount; equal splits list only the users
+CREATE TABLE IF NOT EXISTS recurring_expense_splits (
+    recurring_expense_id VARCHAR(36) NOT NULL REFERENCES recurring_expenses(id) ON DELETE CASCADE,
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount DECIMAL(10, 2),
+    position INT NOT NULL,
+    PRIMARY KEY (recurring_expense_id, user_id)
+    );
@@ -0,0 +1,21 @@
+-- Itemised receipt lines; an expense's splits are derived from them
+CREATE TABLE IF NOT EXISTS expense_items (
+    id SERIAL PRIMARY KEY,
+    expense_id VARCHAR(36) NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name TEXT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    price DECIMAL(10, 2) NOT NULL CHECK (price > 0),
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('item', 'tax', 'tip')),
+    UNIQUE (expense_id, position)
+    );
+
+-- Users each item is charged to; tax and tip lines have none
+CREATE TABLE IF NOT EXISTS expense_item_users (
+    item_id INT NOT NULL REFERENCES expense_items(id) ON DELETE CASCADE,
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (item_id, user_id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_expense_items_expense_id ON expense_items(expense_id);
@@ -0,0 +1,14 @@
+-- Attachment metadata; file contents live in blob storage. expense_id has no
+-- foreign key so rows outlive a purged expense until their blobs are removed.
+CREATE TABLE IF NOT EXISTS expense_attachments (
+    id VARCHAR(36) PRIMARY KEY,
+    expense_id VARCHAR(36) NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    storage_key TEXT NOT NULL UNIQUE,
+    uploaded_by VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS idx_expense_attachments_expense_id ON expense_attachments(expense_id);
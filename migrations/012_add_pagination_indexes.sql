@@ -0,0 +1,7 @@
+-- Keyset pagination orders lists by a sort column with the ID as tie-breaker
+CREATE INDEX IF NOT EXISTS idx_expenses_date_id ON expenses(date, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_expenses_amount_id ON expenses(amount, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_expenses_created_at_id ON expenses(created_at, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users(name, id);
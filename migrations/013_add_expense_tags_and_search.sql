@@ -0,0 +1,7 @@
+-- Tags are stored on the expense itself; the GIN index serves "tags @> ..."
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_expenses_tags ON expenses USING GIN (tags);
+
+-- Full-text search on descriptions. The 'simple' configuration does no
+-- stemming, so it works for descriptions in any language.
+CREATE INDEX IF NOT EXISTS idx_expenses_description_search ON expenses USING GIN (to_tsvector('simple', description));
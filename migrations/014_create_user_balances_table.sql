@@ -0,0 +1,28 @@
+-- Running totals of what each user paid and owes per group and currency,
+-- kept up to date in the same transaction as every expense change. group_id
+-- is '' for expenses outside a group.
+CREATE TABLE IF NOT EXISTS user_balances (
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    group_id VARCHAR(36) NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL,
+    paid DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    owed DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    expenses_paid INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, group_id, currency)
+    );
+
+-- Backfill from the expenses recorded so far
+INSERT INTO user_balances (user_id, group_id, currency, paid, owed, expenses_paid)
+SELECT user_id, group_id, currency, SUM(paid), SUM(owed), SUM(expenses_paid)
+FROM (
+    SELECT paid_by AS user_id, COALESCE(group_id, '') AS group_id, currency, amount AS paid, 0 AS owed, 1 AS expenses_paid
+    FROM expenses
+    WHERE deleted_at IS NULL
+    UNION ALL
+    SELECT s.user_id, COALESCE(e.group_id, ''), e.currency, 0, s.amount, 0
+    FROM splits s
+    JOIN expenses e ON e.id = s.expense_id
+    WHERE e.deleted_at IS NULL
+) AS entries
+GROUP BY user_id, group_id, currency
+ON CONFLICT (user_id, group_id, currency) DO NOTHING;
@@ -0,0 +1,14 @@
+-- Each household reads expense dates in its own time zone; '' uses the
+-- server's TIMEZONE
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Keep expense dates as instants so they can be read in any zone. Dates so far
+-- were written as the server's wall-clock time, which is UTC in the Docker
+-- setup.
+DO $$
+BEGIN
+    IF (SELECT data_type FROM information_schema.columns
+        WHERE table_name = 'expenses' AND column_name = 'date') = 'timestamp without time zone' THEN
+        ALTER TABLE expenses ALTER COLUMN date TYPE TIMESTAMPTZ USING date AT TIME ZONE 'UTC';
+    END IF;
+END $$;
@@ -0,0 +1,24 @@
+-- Deactivated users keep their history but can't join new expenses
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
+
+-- Deleting a user must not take shared expenses and payments with it; the
+-- delete is refused while any refer to them
+ALTER TABLE expenses DROP CONSTRAINT IF EXISTS expenses_paid_by_fkey;
+ALTER TABLE expenses ADD CONSTRAINT expenses_paid_by_fkey
+    FOREIGN KEY (paid_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE splits DROP CONSTRAINT IF EXISTS splits_user_id_fkey;
+ALTER TABLE splits ADD CONSTRAINT splits_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE expense_item_users DROP CONSTRAINT IF EXISTS expense_item_users_user_id_fkey;
+ALTER TABLE expense_item_users ADD CONSTRAINT expense_item_users_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_from_user_id_fkey;
+ALTER TABLE payments ADD CONSTRAINT payments_from_user_id_fkey
+    FOREIGN KEY (from_user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_to_user_id_fkey;
+ALTER TABLE payments ADD CONSTRAINT payments_to_user_id_fkey
+    FOREIGN KEY (to_user_id) REFERENCES users(id) ON DELETE RESTRICT;
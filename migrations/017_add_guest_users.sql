@@ -0,0 +1,9 @@
+-- Guests are people without an account: a name, an optional contact and no
+-- email until they are claimed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS guest BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS contact VARCHAR(255);
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_or_guest;
+ALTER TABLE users ADD CONSTRAINT users_email_or_guest
+    CHECK ((guest AND email IS NULL) OR (NOT guest AND email IS NOT NULL));
@@ -0,0 +1,69 @@
+-- Managed expense categories. Expenses refer to them by name, which is
+-- lowercase with single spaces, so renames follow through ON UPDATE CASCADE.
+CREATE TABLE IF NOT EXISTS categories (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    parent_id VARCHAR(36) REFERENCES categories(id) ON DELETE RESTRICT,
+    icon VARCHAR(32) NOT NULL DEFAULT '',
+    color VARCHAR(7) NOT NULL DEFAULT '',
+    monthly_budget DECIMAL(10, 2) CHECK (monthly_budget > 0),
+    budget_currency VARCHAR(3),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((monthly_budget IS NULL) = (budget_currency IS NULL))
+    );
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+-- Normalise the free-text categories recorded so far: case and spacing, the
+-- usual singular and plural spellings, and '' for uncategorised
+UPDATE expenses SET category = LOWER(REGEXP_REPLACE(BTRIM(category), '\s+', ' ', 'g'));
+UPDATE recurring_expenses SET category = LOWER(REGEXP_REPLACE(BTRIM(category), '\s+', ' ', 'g'));
+
+CREATE TEMPORARY TABLE category_spellings (spelling VARCHAR(50) PRIMARY KEY, name VARCHAR(50) NOT NULL);
+INSERT INTO category_spellings (spelling, name) VALUES
+    ('', 'other'),
+    ('misc', 'other'),
+    ('miscellaneous', 'other'),
+    ('general', 'other'),
+    ('grocery', 'groceries'),
+    ('restaurant', 'dining'),
+    ('restaurants', 'dining'),
+    ('dining out', 'dining'),
+    ('eating out', 'dining'),
+    ('utility', 'utilities'),
+    ('bills', 'utilities'),
+    ('transportation', 'transport'),
+    ('taxi', 'transport'),
+    ('trip', 'travel'),
+    ('trips', 'travel'),
+    ('medical', 'health'),
+    ('healthcare', 'health');
+
+UPDATE expenses e SET category = s.name FROM category_spellings s WHERE e.category = s.spelling;
+UPDATE recurring_expenses r SET category = s.name FROM category_spellings s WHERE r.category = s.spelling;
+DROP TABLE category_spellings;
+
+-- The default categories, then every other category already in use
+INSERT INTO categories (id, name)
+SELECT md5('category:' || name)::uuid::text, name
+FROM (VALUES ('food'), ('groceries'), ('dining'), ('rent'), ('housing'), ('utilities'), ('transport'),
+             ('travel'), ('entertainment'), ('shopping'), ('health'), ('other')) AS defaults(name)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO categories (id, name)
+SELECT md5('category:' || category)::uuid::text, category
+FROM (SELECT category FROM expenses UNION SELECT category FROM recurring_expenses) AS used
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE recurring_expenses ALTER COLUMN category SET DEFAULT 'other';
+
+ALTER TABLE expenses DROP CONSTRAINT IF EXISTS expenses_category_fkey;
+ALTER TABLE expenses ADD CONSTRAINT expenses_category_fkey
+    FOREIGN KEY (category) REFERENCES categories(name) ON UPDATE CASCADE ON DELETE RESTRICT;
+
+ALTER TABLE recurring_expenses DROP CONSTRAINT IF EXISTS recurring_expenses_category_fkey;
+ALTER TABLE recurring_expenses ADD CONSTRAINT recurring_expenses_category_fkey
+    FOREIGN KEY (category) REFERENCES categories(name) ON UPDATE CASCADE ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_category ON expenses(category);
@@ -0,0 +1,39 @@
+-- Spending caps per category and period, for the household or one user's
+-- share, optionally within one group. Budgets follow their category through a
+-- rename and go with it, or with their user, when deleted.
+CREATE TABLE IF NOT EXISTS budgets (
+    id VARCHAR(36) PRIMARY KEY,
+    category VARCHAR(50) NOT NULL REFERENCES categories(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    period VARCHAR(10) NOT NULL CHECK (period IN ('monthly', 'yearly')),
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    user_id VARCHAR(36) REFERENCES users(id) ON DELETE CASCADE,
+    group_id VARCHAR(36) REFERENCES groups(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- One budget per category, period and scope
+CREATE UNIQUE INDEX IF NOT EXISTS idx_budgets_scope
+    ON budgets(category, period, COALESCE(user_id, ''), COALESCE(group_id, ''));
+
+-- Alerts raised when an expense took spending past a threshold. budget_id has
+-- no foreign key so alerts outlive their budget, and is NULL for a category's
+-- own monthly budget.
+CREATE TABLE IF NOT EXISTS budget_alerts (
+    id VARCHAR(36) PRIMARY KEY,
+    budget_id VARCHAR(36),
+    category VARCHAR(50) NOT NULL,
+    period VARCHAR(10) NOT NULL,
+    user_id VARCHAR(36),
+    group_id VARCHAR(36),
+    expense_id VARCHAR(36) NOT NULL,
+    period_start DATE NOT NULL,
+    threshold INTEGER NOT NULL CHECK (threshold > 0),
+    amount DECIMAL(10, 2) NOT NULL,
+    spent DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS idx_budget_alerts_created_at ON budget_alerts(created_at);
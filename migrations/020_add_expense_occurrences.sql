@@ -0,0 +1,8 @@
+-- Link each expense created from a recurring expense to the occurrence it is
+-- for. The unique key lets a scheduler that crashed or failed part way simply
+-- try the occurrence again: an occurrence already created is refused.
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS recurring_id VARCHAR(36);
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS occurrence_date DATE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_expenses_occurrence
+    ON expenses(recurring_id, occurrence_date) WHERE recurring_id IS NOT NULL;
@@ -0,0 +1,10 @@
+-- Create single-use guest claim tokens table; a guest only changes once the
+-- owner of the email confirms the claim
+CREATE TABLE IF NOT EXISTS guest_claims (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    guest_id VARCHAR(36) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (guest_id) REFERENCES users(id) ON DELETE CASCADE
+    );
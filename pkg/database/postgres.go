@@ -10,13 +10,14 @@ import (
 
 func NewPostgresDB(cfg *config.Config) (*sql.DB, error) {
 	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s timezone=%s",
 		cfg.Database.Host,
 		cfg.Database.Port,
 		cfg.Database.User,
 		cfg.Database.Password,
 		cfg.Database.DBName,
 		cfg.Database.SSLMode,
+		cfg.Server.Timezone,
 	)
 
 	db, err := sql.Open("postgres", dsn)
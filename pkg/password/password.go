@@ -0,0 +1,72 @@
+// Package password hashes and verifies user passwords with PBKDF2-SHA256.
+package password
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	scheme     = "pbkdf2-sha256"
+	iterations = 600000
+	saltLength = 16
+	keyLength  = 32
+)
+
+var ErrMalformedHash = errors.New("malformed password hash")
+
+// Hash returns an encoded hash of the form
+// pbkdf2-sha256$<iterations>$<salt>$<key>, so the cost can be raised later
+// without invalidating existing hashes
+func Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, keyLength)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return strings.Join([]string{
+		scheme,
+		strconv.Itoa(iterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// Verify reports whether password matches an encoded hash
+func Verify(password, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != scheme {
+		return false, ErrMalformedHash
+	}
+
+	rounds, err := strconv.Atoi(parts[1])
+	if err != nil || rounds <= 0 {
+		return false, ErrMalformedHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false, ErrMalformedHash
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false, ErrMalformedHash
+	}
+
+	got, err := pbkdf2.Key(sha256.New, password, salt, rounds, len(want))
+	if err != nil {
+		return false, fmt.Errorf("failed to hash password: %w", err)
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}